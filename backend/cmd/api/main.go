@@ -10,9 +10,13 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
+	"github.com/htchan/WebHistory/internal/auth"
 	"github.com/htchan/WebHistory/internal/config"
+	"github.com/htchan/WebHistory/internal/events"
+	"github.com/htchan/WebHistory/internal/metrics"
 	"github.com/htchan/WebHistory/internal/repository/sqlc"
 	"github.com/htchan/WebHistory/internal/router/website"
+	"github.com/htchan/WebHistory/internal/telemetry"
 	"github.com/htchan/WebHistory/internal/utils"
 
 	"github.com/go-chi/chi/v5"
@@ -40,6 +44,16 @@ func main() {
 		log.Fatal().Err(err).Msg("load config failed")
 	}
 
+	tp, err := telemetry.SetupTracerProvider(context.Background(), conf.TraceConfig)
+	if err != nil {
+		// tracing is optional, keep running without exporting traces
+		log.Error().Err(err).Msg("init tracer failed, traces are not exported")
+		tp, _ = telemetry.SetupTracerProvider(context.Background(), config.TraceConfig{
+			TraceServiceName: conf.TraceConfig.TraceServiceName,
+			TraceExporter:    telemetry.ExporterNone,
+		})
+	}
+
 	if err = utils.Migrate(&conf.DatabaseConfig); err != nil {
 		log.Fatal().Err(err).Msg("failed to migrate")
 	}
@@ -51,9 +65,42 @@ func main() {
 
 	defer db.Close()
 
+	if err = metrics.RegisterDBStats(db, conf.DatabaseConfig.Database); err != nil {
+		log.Error().Err(err).Msg("register db stats metrics failed")
+	}
+
 	rpo := sqlc.NewRepo(db, &conf.WebsiteConfig)
+
+	pubsub, err := events.NewPostgresPubSub(db, utils.PostgresConnString(&conf.DatabaseConfig))
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to listen website events")
+	}
+
+	defer pubsub.Close()
+
+	authenticator, err := auth.New(&conf.UserServiceConfig, rpo)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create authenticator")
+	}
+
 	r := chi.NewRouter()
-	website.AddRoutes(r, rpo, conf)
+	r.Use(metrics.Middleware)
+	r.Use(telemetry.Middleware(conf.TraceConfig.TraceServiceName))
+	website.AddRoutes(r, rpo, pubsub, authenticator, conf)
+
+	// metrics are kept off the public listener
+	metricsServer := http.Server{
+		Addr:    conf.BinConfig.MetricsAddr,
+		Handler: metrics.Handler(),
+	}
+
+	go func() {
+		log.Debug().Str("addr", metricsServer.Addr).Msg("start metrics server")
+
+		if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Error().Err(err).Msg("metrics server stopped")
+		}
+	}()
 
 	server := http.Server{
 		Addr:         conf.BinConfig.Addr,
@@ -80,4 +127,6 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 	server.Shutdown(ctx)
+	metricsServer.Shutdown(ctx)
+	tp.Shutdown(ctx)
 }
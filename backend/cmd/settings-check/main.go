@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/htchan/WebHistory/internal/archive"
+	"github.com/htchan/WebHistory/internal/config"
+	"github.com/htchan/WebHistory/internal/model"
+	"github.com/htchan/WebHistory/internal/repository/sqlc"
+	"github.com/htchan/WebHistory/internal/settingcheck"
+	"github.com/htchan/WebHistory/internal/utils"
+)
+
+func loadSettings(path string) ([]model.WebsiteSetting, error) {
+	if path != "" {
+		return settingcheck.LoadSettings(path)
+	}
+
+	conf, err := config.LoadDatabaseConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := utils.OpenDatabase(conf)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	return sqlc.NewRepo(db, &config.WebsiteConfig{}).FindWebsiteSettings()
+}
+
+func loader(goldenPath string, fromArchive bool) (settingcheck.Loader, error) {
+	if !fromArchive {
+		return settingcheck.FileLoader(filepath.Dir(goldenPath)), nil
+	}
+
+	conf, err := config.LoadArchiveConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := archive.NewStore(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return settingcheck.ArchiveLoader(archive.New(store, conf)), nil
+}
+
+func main() {
+	goldenPath := flag.String("golden", "test/settings_golden.json", "golden file of expected parse result")
+	settingsPath := flag.String("settings", "", "json file of website settings, load from database if empty")
+	fromArchive := flag.Bool("archive", false, "load snapshots from response archive by archive key instead of file")
+	update := flag.Bool("update", false, "rewrite golden file with current parse result")
+	flag.Parse()
+
+	settings, err := loadSettings(*settingsPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load website settings failed: %v\n", err)
+		os.Exit(2)
+	}
+
+	expectations, err := settingcheck.LoadGolden(*goldenPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load golden file failed: %v\n", err)
+		os.Exit(2)
+	}
+
+	load, err := loader(*goldenPath, *fromArchive)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "init snapshot loader failed: %v\n", err)
+		os.Exit(2)
+	}
+
+	results := settingcheck.Check(context.Background(), settings, expectations, load)
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "DOMAIN\tSTATUS\tSNAPSHOT")
+	for _, result := range results {
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", result.Domain, result.Status, result.Snapshot)
+	}
+	writer.Flush()
+
+	if *update {
+		if err := settingcheck.SaveGolden(*goldenPath, settingcheck.Updated(results)); err != nil {
+			fmt.Fprintf(os.Stderr, "update golden file failed: %v\n", err)
+			os.Exit(2)
+		}
+
+		return
+	}
+
+	regressed := settingcheck.Regressed(results)
+	for _, result := range regressed {
+		if result.Err != nil {
+			fmt.Printf("\n%s (%s): %v\n", result.Domain, result.Snapshot, result.Err)
+		} else {
+			fmt.Printf("\n%s (%s) (-want +got):\n%s", result.Domain, result.Snapshot, result.Diff())
+		}
+	}
+
+	if len(regressed) > 0 {
+		os.Exit(1)
+	}
+}
@@ -2,45 +2,27 @@ package main
 
 import (
 	"context"
+	"net/http"
 	"os"
 	"syscall"
 	"time"
 
+	"github.com/htchan/WebHistory/internal/archive"
 	"github.com/htchan/WebHistory/internal/config"
+	"github.com/htchan/WebHistory/internal/events"
 	"github.com/htchan/WebHistory/internal/executor"
 	"github.com/htchan/WebHistory/internal/jobs/websiteupdate"
+	"github.com/htchan/WebHistory/internal/metrics"
 	"github.com/htchan/WebHistory/internal/repository/sqlc"
+	"github.com/htchan/WebHistory/internal/service"
+	"github.com/htchan/WebHistory/internal/telemetry"
 	"github.com/htchan/WebHistory/internal/utils"
+	"github.com/htchan/WebHistory/internal/workerstatus"
 	shutdown "github.com/htchan/goshutdown"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
-	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/exporters/jaeger"
-	"go.opentelemetry.io/otel/sdk/resource"
-	tracesdk "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
 )
 
-// TODO: move tracer to helper
-func tracerProvider(conf config.TraceConfig) (*tracesdk.TracerProvider, error) {
-	exp, err := jaeger.New(jaeger.WithCollectorEndpoint(jaeger.WithEndpoint(conf.TraceURL)))
-	if err != nil {
-		return nil, err
-	}
-
-	tp := tracesdk.NewTracerProvider(
-		tracesdk.WithBatcher(exp),
-		tracesdk.WithResource(resource.NewWithAttributes(
-			semconv.SchemaURL,
-			semconv.ServiceNameKey.String(conf.TraceServiceName),
-		)),
-	)
-
-	otel.SetTracerProvider(tp)
-
-	return tp, nil
-}
-
 func main() {
 	outputPath := os.Getenv("OUTPUT_PATH")
 	if outputPath != "" {
@@ -63,9 +45,14 @@ func main() {
 		log.Fatal().Err(err).Msg("load config failed")
 	}
 
-	tp, err := tracerProvider(conf.TraceConfig)
+	tp, err := telemetry.SetupTracerProvider(context.Background(), conf.TraceConfig)
 	if err != nil {
-		log.Error().Err(err).Msg("init tracer failed")
+		// tracing is optional, keep running without exporting traces
+		log.Error().Err(err).Msg("init tracer failed, traces are not exported")
+		tp, _ = telemetry.SetupTracerProvider(context.Background(), config.TraceConfig{
+			TraceServiceName: conf.TraceConfig.TraceServiceName,
+			TraceExporter:    telemetry.ExporterNone,
+		})
 	}
 
 	if err = utils.Migrate(&conf.DatabaseConfig); err != nil {
@@ -81,7 +68,39 @@ func main() {
 		log.Fatal().Err(err).Msg("failed to open database")
 	}
 
+	if err = metrics.RegisterDBStats(db, conf.DatabaseConfig.Database); err != nil {
+		log.Error().Err(err).Msg("register db stats metrics failed")
+	}
+
 	rpo := sqlc.NewRepo(db, &conf.WebsiteConfig)
+	service.SetEventPublisher(events.NewPostgresPublisher(db))
+	service.SetURLPolicy(service.NewURLPolicy(&conf.WebsiteConfig))
+
+	metricsServer := http.Server{
+		Addr:    conf.BinConfig.MetricsAddr,
+		Handler: metrics.Handler(),
+	}
+
+	go func() {
+		log.Debug().Str("addr", metricsServer.Addr).Msg("start metrics server")
+
+		if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Error().Err(err).Msg("metrics server stopped")
+		}
+	}()
+
+	if conf.ArchiveConfig.Driver != "" {
+		store, err := archive.NewStore(&conf.ArchiveConfig)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to init response archive")
+		}
+
+		responseArchive := archive.New(store, &conf.ArchiveConfig)
+		service.SetResponseArchive(responseArchive)
+		go responseArchive.Start()
+
+		shutdownHandler.Register("archive", responseArchive.Stop)
+	}
 
 	exec := executor.NewExecutor(conf.BinConfig.WorkerExecutorCount)
 
@@ -90,9 +109,22 @@ func main() {
 	exec.Register(websiteUpdateScheduler.Publisher())
 	go websiteUpdateScheduler.Start()
 
+	// start polling update requests of newly created websites
+	requestPoller := websiteupdate.SetupRequestPoller(rpo, websiteUpdateScheduler, &conf.BinConfig)
+	go requestPoller.Start()
+
+	// report executor status for admin api
+	statusReporter := workerstatus.NewReporter(rpo, exec, &conf.BinConfig)
+	go statusReporter.Start()
+
+	shutdownHandler.Register("workerstatus.Reporter", statusReporter.Stop)
+	shutdownHandler.Register("websiteupdate.RequestPoller", requestPoller.Stop)
 	shutdownHandler.Register("websiteupdate.Scheduler", websiteUpdateScheduler.Stop)
 	shutdownHandler.Register("executor", exec.Stop)
 	shutdownHandler.Register("database", db.Close)
+	shutdownHandler.Register("metrics server", func() error {
+		return metricsServer.Shutdown(context.Background())
+	})
 	shutdownHandler.Register("tracer", func() error {
 		return tp.Shutdown(context.Background())
 	})
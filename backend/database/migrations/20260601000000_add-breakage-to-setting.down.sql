@@ -0,0 +1,2 @@
+alter table website_settings drop column consecutive_failures;
+alter table website_settings drop column broken_at;
@@ -0,0 +1,5 @@
+alter table website_settings
+  add consecutive_failures integer default 0;
+
+alter table website_settings
+  add broken_at timestamp;
@@ -0,0 +1 @@
+alter table websites drop column previous_content;
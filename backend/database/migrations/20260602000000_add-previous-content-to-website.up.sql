@@ -0,0 +1,2 @@
+alter table websites
+  add previous_content text;
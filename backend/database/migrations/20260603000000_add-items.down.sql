@@ -0,0 +1,3 @@
+alter table websites drop column items;
+alter table website_settings drop column item_name_goquery_selector;
+alter table website_settings drop column item_link_goquery_selector;
@@ -0,0 +1,8 @@
+alter table websites
+  add items text;
+
+alter table website_settings
+  add item_name_goquery_selector text;
+
+alter table website_settings
+  add item_link_goquery_selector text;
@@ -0,0 +1 @@
+alter table user_websites drop column last_read_item;
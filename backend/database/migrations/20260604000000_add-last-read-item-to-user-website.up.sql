@@ -0,0 +1,2 @@
+alter table user_websites
+  add last_read_item text;
@@ -0,0 +1,2 @@
+alter table user_websites drop column snooze_until;
+alter table user_websites drop column muted;
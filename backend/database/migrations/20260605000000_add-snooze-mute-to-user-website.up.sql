@@ -0,0 +1,5 @@
+alter table user_websites
+  add snooze_until timestamp;
+
+alter table user_websites
+  add muted boolean default false;
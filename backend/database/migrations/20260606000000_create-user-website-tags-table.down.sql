@@ -0,0 +1,4 @@
+drop index if exists user_website_tags__user_website_and_tag;
+drop index if exists user_website_tags__user_and_tag;
+
+drop table if exists user_website_tags;
@@ -0,0 +1,8 @@
+create table user_website_tags (
+    user_uuid varchar(64),
+    website_uuid varchar(64),
+    tag text
+);
+
+create unique index user_website_tags__user_website_and_tag on user_website_tags(user_uuid, website_uuid, tag);
+create index user_website_tags__user_and_tag on user_website_tags(user_uuid, tag);
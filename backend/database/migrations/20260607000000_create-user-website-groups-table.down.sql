@@ -0,0 +1,2 @@
+drop index if exists user_websites__user_and_group_name;
+drop table if exists user_website_groups;
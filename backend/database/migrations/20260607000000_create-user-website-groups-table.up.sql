@@ -0,0 +1,8 @@
+create table user_website_groups (
+    user_uuid varchar(64),
+    group_name text,
+    position integer
+);
+
+create unique index user_website_groups__user_and_group_name on user_website_groups(user_uuid, group_name);
+create index user_websites__user_and_group_name on user_websites(user_uuid, group_name);
@@ -0,0 +1,7 @@
+drop index if exists user_websites__user_and_access_time;
+drop index if exists websites__url_trgm;
+drop index if exists websites__title_trgm;
+drop index if exists websites__host;
+drop index if exists websites__health;
+
+alter table websites drop column if exists health;
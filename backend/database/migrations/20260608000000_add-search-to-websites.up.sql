@@ -0,0 +1,9 @@
+create extension if not exists pg_trgm;
+
+alter table websites add column health varchar(16);
+
+create index websites__health on websites(health);
+create index websites__host on websites((substring(url from '://([^/:]+)')));
+create index websites__title_trgm on websites using gin (title gin_trgm_ops);
+create index websites__url_trgm on websites using gin (url gin_trgm_ops);
+create index user_websites__user_and_access_time on user_websites(user_uuid, access_time);
@@ -0,0 +1,3 @@
+drop index if exists website_update_requests__create_time;
+drop index if exists website_update_requests__uuid;
+drop table if exists website_update_requests;
@@ -0,0 +1,9 @@
+create table website_update_requests (
+    uuid varchar(64),
+    website_uuid varchar(64),
+    trace_context text,
+    create_time timestamp
+);
+
+create unique index website_update_requests__uuid on website_update_requests(uuid);
+create index website_update_requests__create_time on website_update_requests(create_time);
@@ -0,0 +1,6 @@
+drop index if exists api_tokens__user;
+drop index if exists api_tokens__token_hash;
+drop table if exists api_tokens;
+drop index if exists users__username;
+drop index if exists users__uuid;
+drop table if exists users;
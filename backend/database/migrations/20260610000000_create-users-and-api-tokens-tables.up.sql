@@ -0,0 +1,20 @@
+create table users (
+    uuid varchar(64),
+    username varchar(64),
+    password_hash text,
+    create_time timestamp
+);
+
+create unique index users__uuid on users(uuid);
+create unique index users__username on users(username);
+
+create table api_tokens (
+    uuid varchar(64),
+    user_uuid varchar(64),
+    name text,
+    token_hash varchar(64),
+    create_time timestamp
+);
+
+create unique index api_tokens__token_hash on api_tokens(token_hash);
+create index api_tokens__user on api_tokens(user_uuid);
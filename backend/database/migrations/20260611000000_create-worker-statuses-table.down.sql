@@ -0,0 +1,2 @@
+drop index if exists worker_statuses__worker_id;
+drop table if exists worker_statuses;
@@ -0,0 +1,10 @@
+create table worker_statuses (
+    worker_id varchar(64),
+    executor_count integer,
+    busy_executors integer,
+    queue_depth integer,
+    start_time timestamp,
+    heartbeat_time timestamp
+);
+
+create unique index worker_statuses__worker_id on worker_statuses(worker_id);
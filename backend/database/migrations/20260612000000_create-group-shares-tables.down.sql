@@ -0,0 +1,6 @@
+drop index if exists group_share_subscriptions__user;
+drop index if exists group_share_subscriptions__share_and_user;
+drop table if exists group_share_subscriptions;
+drop index if exists group_shares__owner_and_group_name;
+drop index if exists group_shares__uuid;
+drop table if exists group_shares;
@@ -0,0 +1,20 @@
+create table group_shares (
+    uuid varchar(64),
+    owner_uuid varchar(64),
+    group_name text,
+    mode varchar(16),
+    create_time timestamp
+);
+
+create unique index group_shares__uuid on group_shares(uuid);
+create unique index group_shares__owner_and_group_name on group_shares(owner_uuid, group_name);
+
+create table group_share_subscriptions (
+    share_uuid varchar(64),
+    user_uuid varchar(64),
+    group_name text,
+    create_time timestamp
+);
+
+create unique index group_share_subscriptions__share_and_user on group_share_subscriptions(share_uuid, user_uuid);
+create index group_share_subscriptions__user on group_share_subscriptions(user_uuid);
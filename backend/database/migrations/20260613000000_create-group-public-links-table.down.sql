@@ -0,0 +1,3 @@
+drop index if exists group_public_links__owner_and_group_name;
+drop index if exists group_public_links__token;
+drop table if exists group_public_links;
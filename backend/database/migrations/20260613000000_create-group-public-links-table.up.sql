@@ -0,0 +1,9 @@
+create table group_public_links (
+    token varchar(64),
+    owner_uuid varchar(64),
+    group_name text,
+    create_time timestamp
+);
+
+create unique index group_public_links__token on group_public_links(token);
+create unique index group_public_links__owner_and_group_name on group_public_links(owner_uuid, group_name);
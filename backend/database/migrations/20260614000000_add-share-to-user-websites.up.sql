@@ -0,0 +1,2 @@
+alter table user_websites
+  add share_uuid varchar(64);
@@ -0,0 +1,3 @@
+drop index if exists import_jobs__create_time;
+drop index if exists import_jobs__uuid;
+drop table if exists import_jobs;
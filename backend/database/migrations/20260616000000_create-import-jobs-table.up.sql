@@ -0,0 +1,13 @@
+create table import_jobs (
+    uuid varchar(64),
+    user_uuid varchar(64),
+    status varchar(16),
+    total integer,
+    results text,
+    error text,
+    create_time timestamp,
+    finish_time timestamp
+);
+
+create unique index import_jobs__uuid on import_jobs(uuid);
+create index import_jobs__create_time on import_jobs(create_time);
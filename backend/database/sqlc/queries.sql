@@ -1,16 +1,16 @@
 -- name: CreateWebsite :one
 INSERT INTO websites
-(uuid, url, title, content, update_time)
+(uuid, url, title, content, update_time, items, health)
 VALUES
-($1, $2, $3, $4, $5)
+($1, $2, $3, $4, $5, $6, $7)
 ON CONFLICT (url) DO
 UPDATE SET url=$2
 RETURNING *;
 
 -- name: UpdateWebsite :one
 UPDATE websites SET
-url=$1, title=$2, content=$3, update_time=$4
-WHERE uuid=$5
+url=$1, title=$2, content=$3, previous_content=$4, items=$5, update_time=$6, health=$7
+WHERE uuid=$8
 RETURNING *;
 
 -- name: DeleteWebsite :exec
@@ -24,17 +24,17 @@ SELECT * from websites WHERE uuid=$1;
 
 -- name: CreateUserWebsite :one
 INSERT INTO user_websites
-(user_uuid, website_uuid, access_time, group_name)
+(user_uuid, website_uuid, access_time, group_name, share_uuid)
 VALUES
-($1, $2, $3, $4)
+($1, $2, $3, $4, $5)
 ON CONFLICT(user_uuid, website_uuid) DO
 UPDATE SET user_uuid=$1, website_uuid=$2
 RETURNing *;
 
 -- name: UpdateUserWebsite :one
 UPDATE user_websites SET
-access_time=$1, group_name=$2
-WHERE user_uuid=$3 and website_uuid=$4
+access_time=$1, group_name=$2, last_read_item=$3, snooze_until=$4, muted=$5
+WHERE user_uuid=$6 and website_uuid=$7
 RETURNING *;
 
 -- name: DeleteUserWebsite :exec
@@ -42,21 +42,146 @@ DELETE FROM user_websites
 where user_uuid=$1 and website_uuid=$2;
 
 -- name: ListUserWebsites :many
-SELECT website_uuid, user_uuid, access_time, group_name,
-uuid, url, title, update_time 
+SELECT website_uuid, user_uuid, access_time, group_name, last_read_item, snooze_until, muted, share_uuid,
+uuid, url, title, update_time, items, health 
 FROM user_websites JOIN websites ON user_websites.website_uuid=websites.uuid 
 WHERE user_uuid=$1
-ORDER BY (update_time > access_time) DESC, update_time DESC, access_time DESC;
+ORDER BY (
+  update_time > access_time
+  AND NOT coalesce(muted, false)
+  AND (snooze_until IS NULL OR snooze_until <= (now() at time zone 'utc'))
+) DESC, update_time DESC, access_time DESC;
+
+-- name: SearchUserWebsiteGroups :many
+SELECT group_name
+FROM (
+  SELECT group_name, row_number() OVER (
+    ORDER BY
+      CASE WHEN sqlc.arg(sort_key)::text = '' THEN sort_unread END DESC,
+      CASE WHEN sqlc.arg(sort_key)::text = '' THEN sort_update_time END DESC,
+      CASE WHEN sqlc.arg(sort_key)::text = '' THEN sort_access_time END DESC,
+      CASE WHEN sqlc.arg(sort_key)::text = 'update_time' AND NOT sqlc.arg(sort_desc)::boolean THEN sort_update_time END,
+      CASE WHEN sqlc.arg(sort_key)::text = 'update_time' AND sqlc.arg(sort_desc)::boolean THEN sort_update_time END DESC,
+      CASE WHEN sqlc.arg(sort_key)::text = 'access_time' AND NOT sqlc.arg(sort_desc)::boolean THEN sort_access_time END,
+      CASE WHEN sqlc.arg(sort_key)::text = 'access_time' AND sqlc.arg(sort_desc)::boolean THEN sort_access_time END DESC,
+      CASE WHEN sqlc.arg(sort_key)::text = 'title' AND NOT sqlc.arg(sort_desc)::boolean THEN sort_title END,
+      CASE WHEN sqlc.arg(sort_key)::text = 'title' AND sqlc.arg(sort_desc)::boolean THEN sort_title END DESC,
+      sort_uuid
+  ) AS sort_position
+  FROM (
+    SELECT group_name,
+    coalesce(
+      update_time > access_time
+      AND NOT coalesce(muted, false)
+      AND (snooze_until IS NULL OR snooze_until <= (now() at time zone 'utc')),
+      false
+    ) AS sort_unread,
+    coalesce(update_time, '0001-01-01') AS sort_update_time,
+    coalesce(access_time, '0001-01-01') AS sort_access_time,
+    lower(coalesce(title, '')) COLLATE "C" AS sort_title,
+    website_uuid COLLATE "C" AS sort_uuid
+    FROM user_websites JOIN websites ON user_websites.website_uuid=websites.uuid
+    WHERE user_uuid=sqlc.arg(user_uuid)
+    AND (sqlc.narg(search)::text IS NULL OR title ILIKE sqlc.narg(search) OR url ILIKE sqlc.narg(search))
+    AND (sqlc.narg(host)::text IS NULL OR substring(url from '://([^/:]+)') = sqlc.narg(host))
+    AND (NOT sqlc.arg(updated_only)::boolean OR update_time > access_time)
+    AND (sqlc.narg(health)::text IS NULL OR coalesce(health, 'unknown') = sqlc.narg(health))
+    AND (
+      cardinality(sqlc.arg(tags)::text[]) = 0
+      OR (
+        SELECT count(DISTINCT tag) FROM user_website_tags
+        WHERE user_website_tags.user_uuid=user_websites.user_uuid
+        AND user_website_tags.website_uuid=user_websites.website_uuid
+        AND tag = ANY(sqlc.arg(tags)::text[])
+      ) >= CASE WHEN sqlc.arg(match_all_tags)::boolean THEN cardinality(sqlc.arg(tags)::text[]) ELSE 1 END
+    )
+    AND (cardinality(sqlc.arg(group_names)::text[]) = 0 OR group_name = ANY(sqlc.arg(group_names)::text[]))
+  ) AS searched
+) AS positioned
+GROUP BY group_name
+ORDER BY min(sort_position);
+
+-- name: SearchUserWebsites :many
+SELECT website_uuid, user_uuid, access_time, group_name, last_read_item, snooze_until, muted, share_uuid,
+uuid, url, title, update_time, items, health
+FROM (
+  SELECT website_uuid, user_uuid, access_time, group_name, last_read_item, snooze_until, muted, share_uuid,
+  uuid, url, title, update_time, items, health,
+  coalesce(
+    update_time > access_time
+    AND NOT coalesce(muted, false)
+    AND (snooze_until IS NULL OR snooze_until <= (now() at time zone 'utc')),
+    false
+  ) AS sort_unread,
+  coalesce(update_time, '0001-01-01') AS sort_update_time,
+  coalesce(access_time, '0001-01-01') AS sort_access_time,
+  lower(coalesce(title, '')) COLLATE "C" AS sort_title,
+  website_uuid COLLATE "C" AS sort_uuid
+  FROM user_websites JOIN websites ON user_websites.website_uuid=websites.uuid
+  WHERE user_uuid=sqlc.arg(user_uuid)
+  AND (sqlc.narg(search)::text IS NULL OR title ILIKE sqlc.narg(search) OR url ILIKE sqlc.narg(search))
+  AND (sqlc.narg(host)::text IS NULL OR substring(url from '://([^/:]+)') = sqlc.narg(host))
+  AND (NOT sqlc.arg(updated_only)::boolean OR update_time > access_time)
+  AND (sqlc.narg(health)::text IS NULL OR coalesce(health, 'unknown') = sqlc.narg(health))
+  AND (
+    cardinality(sqlc.arg(tags)::text[]) = 0
+    OR (
+      SELECT count(DISTINCT tag) FROM user_website_tags
+      WHERE user_website_tags.user_uuid=user_websites.user_uuid
+      AND user_website_tags.website_uuid=user_websites.website_uuid
+      AND tag = ANY(sqlc.arg(tags)::text[])
+    ) >= CASE WHEN sqlc.arg(match_all_tags)::boolean THEN cardinality(sqlc.arg(tags)::text[]) ELSE 1 END
+  )
+  AND (cardinality(sqlc.arg(group_names)::text[]) = 0 OR group_name = ANY(sqlc.arg(group_names)::text[]))
+) AS searched
+WHERE sqlc.narg(cursor_uuid)::text IS NULL
+OR (sqlc.arg(sort_key)::text = 'update_time' AND (
+  CASE WHEN sqlc.arg(sort_desc)::boolean THEN sort_update_time < sqlc.arg(cursor_update_time)::timestamp
+  ELSE sort_update_time > sqlc.arg(cursor_update_time)::timestamp END
+  OR (sort_update_time = sqlc.arg(cursor_update_time)::timestamp AND sort_uuid > sqlc.narg(cursor_uuid))
+))
+OR (sqlc.arg(sort_key)::text = 'access_time' AND (
+  CASE WHEN sqlc.arg(sort_desc)::boolean THEN sort_access_time < sqlc.arg(cursor_access_time)::timestamp
+  ELSE sort_access_time > sqlc.arg(cursor_access_time)::timestamp END
+  OR (sort_access_time = sqlc.arg(cursor_access_time)::timestamp AND sort_uuid > sqlc.narg(cursor_uuid))
+))
+OR (sqlc.arg(sort_key)::text = 'title' AND (
+  CASE WHEN sqlc.arg(sort_desc)::boolean THEN sort_title < lower(sqlc.arg(cursor_title)::text)
+  ELSE sort_title > lower(sqlc.arg(cursor_title)::text) END
+  OR (sort_title = lower(sqlc.arg(cursor_title)::text) AND sort_uuid > sqlc.narg(cursor_uuid))
+))
+OR (sqlc.arg(sort_key)::text = '' AND (
+  sort_unread < sqlc.arg(cursor_unread)::boolean
+  OR (sort_unread = sqlc.arg(cursor_unread)::boolean AND (
+    sort_update_time < sqlc.arg(cursor_update_time)::timestamp
+    OR (sort_update_time = sqlc.arg(cursor_update_time)::timestamp AND (
+      sort_access_time < sqlc.arg(cursor_access_time)::timestamp
+      OR (sort_access_time = sqlc.arg(cursor_access_time)::timestamp AND sort_uuid > sqlc.narg(cursor_uuid))
+    ))
+  ))
+))
+ORDER BY
+  CASE WHEN sqlc.arg(sort_key)::text = '' THEN sort_unread END DESC,
+  CASE WHEN sqlc.arg(sort_key)::text = '' THEN sort_update_time END DESC,
+  CASE WHEN sqlc.arg(sort_key)::text = '' THEN sort_access_time END DESC,
+  CASE WHEN sqlc.arg(sort_key)::text = 'update_time' AND NOT sqlc.arg(sort_desc)::boolean THEN sort_update_time END,
+  CASE WHEN sqlc.arg(sort_key)::text = 'update_time' AND sqlc.arg(sort_desc)::boolean THEN sort_update_time END DESC,
+  CASE WHEN sqlc.arg(sort_key)::text = 'access_time' AND NOT sqlc.arg(sort_desc)::boolean THEN sort_access_time END,
+  CASE WHEN sqlc.arg(sort_key)::text = 'access_time' AND sqlc.arg(sort_desc)::boolean THEN sort_access_time END DESC,
+  CASE WHEN sqlc.arg(sort_key)::text = 'title' AND NOT sqlc.arg(sort_desc)::boolean THEN sort_title END,
+  CASE WHEN sqlc.arg(sort_key)::text = 'title' AND sqlc.arg(sort_desc)::boolean THEN sort_title END DESC,
+  sort_uuid
+LIMIT sqlc.narg(page_limit);
 
 -- name: ListUserWebsitesByGroup :many
-SELECT website_uuid, user_uuid, access_time, group_name ,
-uuid, url, title, update_time 
+SELECT website_uuid, user_uuid, access_time, group_name, last_read_item, snooze_until, muted, share_uuid ,
+uuid, url, title, update_time, items, health 
 FROM user_websites JOIN websites ON user_websites.website_uuid=websites.uuid 
 WHERE user_uuid=$1 and group_name=$2;
 
 -- name: GetUserWebsite :one
-SELECT website_uuid, user_uuid, access_time, group_name ,
-uuid, url, title, update_time 
+SELECT website_uuid, user_uuid, access_time, group_name, last_read_item, snooze_until, muted, share_uuid ,
+uuid, url, title, update_time, items, health 
 FROM user_websites JOIN websites ON user_websites.website_uuid=websites.uuid 
 WHERE user_uuid=$1 and website_uuid=$2;
 
@@ -67,4 +192,267 @@ FROM website_settings;
 -- name: GetWebsiteSetting :one
 SELECT *
 FROM website_settings 
-WHERE domain=$1;
\ No newline at end of file
+WHERE domain=$1;
+
+-- name: RecordWebsiteSettingParse :one
+UPDATE website_settings SET
+consecutive_failures=CASE WHEN sqlc.arg(success)::boolean THEN 0
+  ELSE coalesce(website_settings.consecutive_failures, 0) + 1 END,
+broken_at=CASE WHEN sqlc.arg(success)::boolean THEN NULL
+  WHEN website_settings.broken_at IS NULL AND sqlc.arg(threshold)::integer > 0
+    AND coalesce(website_settings.consecutive_failures, 0) + 1 >= sqlc.arg(threshold)::integer
+  THEN sqlc.arg(now)::timestamp
+  ELSE website_settings.broken_at END
+FROM (
+  SELECT domain, broken_at FROM website_settings
+  WHERE domain=sqlc.arg(domain)
+  FOR UPDATE
+) AS previous
+WHERE website_settings.domain=previous.domain
+RETURNING website_settings.*, previous.broken_at AS previous_broken_at;
+
+-- name: CreateUserWebsiteTag :exec
+INSERT INTO user_website_tags
+(user_uuid, website_uuid, tag)
+VALUES
+($1, $2, $3)
+ON CONFLICT(user_uuid, website_uuid, tag) DO NOTHING;
+
+-- name: DeleteUserWebsiteTag :exec
+DELETE FROM user_website_tags
+WHERE user_uuid=$1 and website_uuid=$2 and tag=$3;
+
+-- name: DeleteUserWebsiteTags :exec
+DELETE FROM user_website_tags
+WHERE user_uuid=$1 and website_uuid=$2;
+
+-- name: ListUserWebsiteTags :many
+SELECT * FROM user_website_tags
+WHERE user_uuid=$1
+ORDER BY tag;
+
+-- name: ListUserTags :many
+SELECT DISTINCT tag FROM user_website_tags
+WHERE user_uuid=$1
+ORDER BY tag;
+
+-- name: RenameUserTag :exec
+UPDATE user_website_tags SET tag=$3
+WHERE user_uuid=$1 and tag=$2 and website_uuid NOT IN (
+  SELECT website_uuid FROM user_website_tags WHERE user_uuid=$1 and tag=$3
+);
+
+-- name: DeleteUserTag :exec
+DELETE FROM user_website_tags
+WHERE user_uuid=$1 and tag=$2;
+
+-- name: UpdateUserWebsitesGroupName :exec
+UPDATE user_websites SET group_name=$3
+WHERE user_uuid=$1 and group_name=$2;
+
+-- name: MoveUserWebsites :exec
+UPDATE user_websites SET group_name=$2
+WHERE user_uuid=$1 and website_uuid = ANY(sqlc.arg(website_uuids)::varchar[]);
+
+-- name: ListUserWebsiteGroups :many
+SELECT group_name FROM user_website_groups
+WHERE user_uuid=$1
+ORDER BY position;
+
+-- name: CreateUserWebsiteGroup :exec
+INSERT INTO user_website_groups
+(user_uuid, group_name, position)
+VALUES
+($1, $2, $3);
+
+-- name: RenameWebsitePlaceholderGroups :exec
+UPDATE user_websites SET group_name=sqlc.arg(group_name)
+WHERE website_uuid=sqlc.arg(website_uuid) AND group_name=sqlc.arg(placeholder);
+
+-- name: RenameUserWebsiteGroup :exec
+UPDATE user_website_groups SET group_name=$3
+WHERE user_uuid=$1 and group_name=$2 and NOT EXISTS (
+  SELECT 1 FROM user_website_groups WHERE user_uuid=$1 and group_name=$3
+);
+
+-- name: DeleteUserWebsiteGroup :exec
+DELETE FROM user_website_groups
+WHERE user_uuid=$1 and group_name=$2;
+
+-- name: DeleteUserWebsiteGroups :exec
+DELETE FROM user_website_groups
+WHERE user_uuid=$1;
+
+-- name: CreateWebsiteUpdateRequest :exec
+INSERT INTO website_update_requests
+(uuid, website_uuid, trace_context, create_time)
+VALUES
+($1, $2, $3, $4);
+
+-- name: ClaimWebsiteUpdateRequests :many
+DELETE FROM website_update_requests
+WHERE uuid IN (
+  SELECT uuid FROM website_update_requests
+  ORDER BY create_time
+  LIMIT $1
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: NotifyWebsiteEvent :exec
+SELECT pg_notify('website_events', sqlc.arg(payload)::text);
+
+-- name: CreateUser :exec
+INSERT INTO users
+(uuid, username, password_hash, create_time)
+VALUES
+($1, $2, $3, $4);
+
+-- name: GetUserByUsername :one
+SELECT * FROM users
+WHERE username=$1;
+
+-- name: CreateAPIToken :exec
+INSERT INTO api_tokens
+(uuid, user_uuid, name, token_hash, create_time)
+VALUES
+($1, $2, $3, $4, $5);
+
+-- name: GetAPITokenByHash :one
+SELECT * FROM api_tokens
+WHERE token_hash=$1;
+
+-- name: ListAPITokens :many
+SELECT * FROM api_tokens
+WHERE user_uuid=$1
+ORDER BY create_time;
+
+-- name: DeleteAPIToken :exec
+DELETE FROM api_tokens
+WHERE user_uuid=$1 and uuid=$2;
+
+-- name: CountWebsiteSubscribers :many
+SELECT website_uuid, count(*) AS subscriber_count FROM user_websites
+GROUP BY website_uuid;
+
+-- name: CountWebsiteUpdateRequests :one
+SELECT count(*) FROM website_update_requests;
+
+-- name: UpsertWorkerStatus :exec
+INSERT INTO worker_statuses
+(worker_id, executor_count, busy_executors, queue_depth, start_time, heartbeat_time)
+VALUES
+($1, $2, $3, $4, $5, $6)
+ON CONFLICT (worker_id) DO
+UPDATE SET executor_count=$2, busy_executors=$3, queue_depth=$4, heartbeat_time=$6;
+
+-- name: ListWorkerStatuses :many
+SELECT * FROM worker_statuses
+ORDER BY worker_id;
+
+-- name: DeleteWorkerStatus :exec
+DELETE FROM worker_statuses
+WHERE worker_id=$1;
+
+-- name: CreateGroupShare :one
+INSERT INTO group_shares
+(uuid, owner_uuid, group_name, mode, create_time)
+VALUES
+($1, $2, $3, $4, $5)
+ON CONFLICT (owner_uuid, group_name) DO
+UPDATE SET mode=$4
+RETURNING *;
+
+-- name: GetGroupShare :one
+SELECT * FROM group_shares
+WHERE uuid=$1;
+
+-- name: GetGroupShareByGroup :one
+SELECT * FROM group_shares
+WHERE owner_uuid=$1 and group_name=$2;
+
+-- name: ListGroupShares :many
+SELECT * FROM group_shares
+WHERE owner_uuid=$1
+ORDER BY group_name;
+
+-- name: RenameGroupShare :exec
+UPDATE group_shares SET group_name=$3
+WHERE owner_uuid=$1 and group_name=$2 and NOT EXISTS (
+  SELECT 1 FROM group_shares WHERE owner_uuid=$1 and group_name=$3
+);
+
+-- name: DeleteGroupShare :exec
+DELETE FROM group_shares
+WHERE uuid=$1;
+
+-- name: CreateGroupShareSubscription :exec
+INSERT INTO group_share_subscriptions
+(share_uuid, user_uuid, group_name, create_time)
+VALUES
+($1, $2, $3, $4)
+ON CONFLICT (share_uuid, user_uuid) DO
+UPDATE SET group_name=$3;
+
+-- name: ListGroupShareSubscriptions :many
+SELECT * FROM group_share_subscriptions
+WHERE share_uuid=$1
+ORDER BY create_time;
+
+-- name: ListUserGroupShareSubscriptions :many
+SELECT * FROM group_share_subscriptions
+WHERE user_uuid=$1
+ORDER BY create_time;
+
+-- name: DeleteGroupShareSubscription :exec
+DELETE FROM group_share_subscriptions
+WHERE share_uuid=$1 and user_uuid=$2;
+
+-- name: DeleteGroupShareSubscriptions :exec
+DELETE FROM group_share_subscriptions
+WHERE share_uuid=$1;
+
+-- name: CreateGroupPublicLink :one
+INSERT INTO group_public_links
+(token, owner_uuid, group_name, create_time)
+VALUES
+($1, $2, $3, $4)
+ON CONFLICT (owner_uuid, group_name) DO
+UPDATE SET token=$1, create_time=$4
+RETURNING *;
+
+-- name: GetGroupPublicLink :one
+SELECT * FROM group_public_links
+WHERE token=$1;
+
+-- name: GetGroupPublicLinkByGroup :one
+SELECT * FROM group_public_links
+WHERE owner_uuid=$1 and group_name=$2;
+
+-- name: RenameGroupPublicLink :exec
+UPDATE group_public_links SET group_name=$3
+WHERE owner_uuid=$1 and group_name=$2 and NOT EXISTS (
+  SELECT 1 FROM group_public_links WHERE owner_uuid=$1 and group_name=$3
+);
+
+-- name: DeleteGroupPublicLink :exec
+DELETE FROM group_public_links
+WHERE owner_uuid=$1 and group_name=$2;
+
+-- name: CreateImportJob :exec
+INSERT INTO import_jobs
+(uuid, user_uuid, status, total, create_time)
+VALUES
+($1, $2, $3, $4, $5);
+
+-- name: UpdateImportJob :exec
+UPDATE import_jobs SET status=$2, results=$3, error=$4, finish_time=$5
+WHERE uuid=$1;
+
+-- name: GetImportJob :one
+SELECT * FROM import_jobs
+WHERE uuid=$1 and user_uuid=$2;
+
+-- name: DeleteImportJobs :exec
+DELETE FROM import_jobs
+WHERE create_time < $1;
@@ -16,10 +16,93 @@ SET xmloption = content;
 SET client_min_messages = warning;
 SET row_security = off;
 
+--
+-- Name: pg_trgm; Type: EXTENSION; Schema: -; Owner: -
+--
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm WITH SCHEMA public;
+
+
 SET default_tablespace = '';
 
 SET default_table_access_method = heap;
 
+--
+-- Name: api_tokens; Type: TABLE; Schema: public; Owner: test
+--
+
+CREATE TABLE public.api_tokens (
+    uuid character varying(64),
+    user_uuid character varying(64),
+    name text,
+    token_hash character varying(64),
+    create_time timestamp without time zone
+);
+
+
+ALTER TABLE public.api_tokens OWNER TO test;
+
+--
+-- Name: group_public_links; Type: TABLE; Schema: public; Owner: test
+--
+
+CREATE TABLE public.group_public_links (
+    token character varying(64),
+    owner_uuid character varying(64),
+    group_name text,
+    create_time timestamp without time zone
+);
+
+
+ALTER TABLE public.group_public_links OWNER TO test;
+
+--
+-- Name: group_share_subscriptions; Type: TABLE; Schema: public; Owner: test
+--
+
+CREATE TABLE public.group_share_subscriptions (
+    share_uuid character varying(64),
+    user_uuid character varying(64),
+    group_name text,
+    create_time timestamp without time zone
+);
+
+
+ALTER TABLE public.group_share_subscriptions OWNER TO test;
+
+--
+-- Name: group_shares; Type: TABLE; Schema: public; Owner: test
+--
+
+CREATE TABLE public.group_shares (
+    uuid character varying(64),
+    owner_uuid character varying(64),
+    group_name text,
+    mode character varying(16),
+    create_time timestamp without time zone
+);
+
+
+ALTER TABLE public.group_shares OWNER TO test;
+
+--
+-- Name: import_jobs; Type: TABLE; Schema: public; Owner: test
+--
+
+CREATE TABLE public.import_jobs (
+    uuid character varying(64),
+    user_uuid character varying(64),
+    status character varying(16),
+    total integer,
+    results text,
+    error text,
+    create_time timestamp without time zone,
+    finish_time timestamp without time zone
+);
+
+
+ALTER TABLE public.import_jobs OWNER TO test;
+
 --
 -- Name: user_websites; Type: TABLE; Schema: public; Owner: test
 --
@@ -28,12 +111,56 @@ CREATE TABLE public.user_websites (
     website_uuid character varying(64),
     user_uuid character varying(64),
     access_time timestamp without time zone,
-    group_name text
+    group_name text,
+    last_read_item text,
+    snooze_until timestamp without time zone,
+    muted boolean DEFAULT false,
+    share_uuid character varying(64)
 );
 
 
 ALTER TABLE public.user_websites OWNER TO test;
 
+--
+-- Name: user_website_groups; Type: TABLE; Schema: public; Owner: test
+--
+
+CREATE TABLE public.user_website_groups (
+    user_uuid character varying(64),
+    group_name text,
+    "position" integer
+);
+
+
+ALTER TABLE public.user_website_groups OWNER TO test;
+
+--
+-- Name: user_website_tags; Type: TABLE; Schema: public; Owner: test
+--
+
+CREATE TABLE public.user_website_tags (
+    user_uuid character varying(64),
+    website_uuid character varying(64),
+    tag text
+);
+
+
+ALTER TABLE public.user_website_tags OWNER TO test;
+
+--
+-- Name: users; Type: TABLE; Schema: public; Owner: test
+--
+
+CREATE TABLE public.users (
+    uuid character varying(64),
+    username character varying(64),
+    password_hash text,
+    create_time timestamp without time zone
+);
+
+
+ALTER TABLE public.users OWNER TO test;
+
 --
 -- Name: website_settings; Type: TABLE; Schema: public; Owner: test
 --
@@ -43,12 +170,30 @@ CREATE TABLE public.website_settings (
     focus_index_from integer,
     focus_index_to integer,
     title_goquery_selector text,
-    date_goquery_selector text
+    date_goquery_selector text,
+    consecutive_failures integer DEFAULT 0,
+    broken_at timestamp without time zone,
+    item_name_goquery_selector text,
+    item_link_goquery_selector text
 );
 
 
 ALTER TABLE public.website_settings OWNER TO test;
 
+--
+-- Name: website_update_requests; Type: TABLE; Schema: public; Owner: test
+--
+
+CREATE TABLE public.website_update_requests (
+    uuid character varying(64),
+    website_uuid character varying(64),
+    trace_context text,
+    create_time timestamp without time zone
+);
+
+
+ALTER TABLE public.website_update_requests OWNER TO test;
+
 --
 -- Name: websites; Type: TABLE; Schema: public; Owner: test
 --
@@ -58,12 +203,136 @@ CREATE TABLE public.websites (
     url text,
     title text,
     content text,
-    update_time timestamp without time zone
+    update_time timestamp without time zone,
+    previous_content text,
+    items text,
+    health character varying(16)
 );
 
 
 ALTER TABLE public.websites OWNER TO test;
 
+--
+-- Name: worker_statuses; Type: TABLE; Schema: public; Owner: test
+--
+
+CREATE TABLE public.worker_statuses (
+    worker_id character varying(64),
+    executor_count integer,
+    busy_executors integer,
+    queue_depth integer,
+    start_time timestamp without time zone,
+    heartbeat_time timestamp without time zone
+);
+
+
+ALTER TABLE public.worker_statuses OWNER TO test;
+
+--
+-- Name: api_tokens__token_hash; Type: INDEX; Schema: public; Owner: test
+--
+
+CREATE UNIQUE INDEX api_tokens__token_hash ON public.api_tokens USING btree (token_hash);
+
+
+--
+-- Name: api_tokens__user; Type: INDEX; Schema: public; Owner: test
+--
+
+CREATE INDEX api_tokens__user ON public.api_tokens USING btree (user_uuid);
+
+
+--
+-- Name: group_public_links__owner_and_group_name; Type: INDEX; Schema: public; Owner: test
+--
+
+CREATE UNIQUE INDEX group_public_links__owner_and_group_name ON public.group_public_links USING btree (owner_uuid, group_name);
+
+
+--
+-- Name: group_public_links__token; Type: INDEX; Schema: public; Owner: test
+--
+
+CREATE UNIQUE INDEX group_public_links__token ON public.group_public_links USING btree (token);
+
+
+--
+-- Name: group_share_subscriptions__share_and_user; Type: INDEX; Schema: public; Owner: test
+--
+
+CREATE UNIQUE INDEX group_share_subscriptions__share_and_user ON public.group_share_subscriptions USING btree (share_uuid, user_uuid);
+
+
+--
+-- Name: group_share_subscriptions__user; Type: INDEX; Schema: public; Owner: test
+--
+
+CREATE INDEX group_share_subscriptions__user ON public.group_share_subscriptions USING btree (user_uuid);
+
+
+--
+-- Name: group_shares__owner_and_group_name; Type: INDEX; Schema: public; Owner: test
+--
+
+CREATE UNIQUE INDEX group_shares__owner_and_group_name ON public.group_shares USING btree (owner_uuid, group_name);
+
+
+--
+-- Name: group_shares__uuid; Type: INDEX; Schema: public; Owner: test
+--
+
+CREATE UNIQUE INDEX group_shares__uuid ON public.group_shares USING btree (uuid);
+
+
+--
+-- Name: import_jobs__create_time; Type: INDEX; Schema: public; Owner: test
+--
+
+CREATE INDEX import_jobs__create_time ON public.import_jobs USING btree (create_time);
+
+
+--
+-- Name: import_jobs__uuid; Type: INDEX; Schema: public; Owner: test
+--
+
+CREATE UNIQUE INDEX import_jobs__uuid ON public.import_jobs USING btree (uuid);
+
+
+--
+-- Name: user_website_groups__user_and_group_name; Type: INDEX; Schema: public; Owner: test
+--
+
+CREATE UNIQUE INDEX user_website_groups__user_and_group_name ON public.user_website_groups USING btree (user_uuid, group_name);
+
+
+--
+-- Name: user_website_tags__user_and_tag; Type: INDEX; Schema: public; Owner: test
+--
+
+CREATE INDEX user_website_tags__user_and_tag ON public.user_website_tags USING btree (user_uuid, tag);
+
+
+--
+-- Name: user_website_tags__user_website_and_tag; Type: INDEX; Schema: public; Owner: test
+--
+
+CREATE UNIQUE INDEX user_website_tags__user_website_and_tag ON public.user_website_tags USING btree (user_uuid, website_uuid, tag);
+
+
+--
+-- Name: user_websites__user_and_access_time; Type: INDEX; Schema: public; Owner: test
+--
+
+CREATE INDEX user_websites__user_and_access_time ON public.user_websites USING btree (user_uuid, access_time);
+
+
+--
+-- Name: user_websites__user_and_group_name; Type: INDEX; Schema: public; Owner: test
+--
+
+CREATE INDEX user_websites__user_and_group_name ON public.user_websites USING btree (user_uuid, group_name);
+
+
 --
 -- Name: user_websites__user_and_uuid; Type: INDEX; Schema: public; Owner: test
 --
@@ -71,6 +340,20 @@ ALTER TABLE public.websites OWNER TO test;
 CREATE UNIQUE INDEX user_websites__user_and_uuid ON public.user_websites USING btree (user_uuid, website_uuid);
 
 
+--
+-- Name: users__username; Type: INDEX; Schema: public; Owner: test
+--
+
+CREATE UNIQUE INDEX users__username ON public.users USING btree (username);
+
+
+--
+-- Name: users__uuid; Type: INDEX; Schema: public; Owner: test
+--
+
+CREATE UNIQUE INDEX users__uuid ON public.users USING btree (uuid);
+
+
 --
 -- Name: website_settings__domain; Type: INDEX; Schema: public; Owner: test
 --
@@ -78,6 +361,41 @@ CREATE UNIQUE INDEX user_websites__user_and_uuid ON public.user_websites USING b
 CREATE UNIQUE INDEX website_settings__domain ON public.website_settings USING btree (domain);
 
 
+--
+-- Name: website_update_requests__create_time; Type: INDEX; Schema: public; Owner: test
+--
+
+CREATE INDEX website_update_requests__create_time ON public.website_update_requests USING btree (create_time);
+
+
+--
+-- Name: website_update_requests__uuid; Type: INDEX; Schema: public; Owner: test
+--
+
+CREATE UNIQUE INDEX website_update_requests__uuid ON public.website_update_requests USING btree (uuid);
+
+
+--
+-- Name: websites__health; Type: INDEX; Schema: public; Owner: test
+--
+
+CREATE INDEX websites__health ON public.websites USING btree (health);
+
+
+--
+-- Name: websites__host; Type: INDEX; Schema: public; Owner: test
+--
+
+CREATE INDEX websites__host ON public.websites USING btree ("substring"(url, '://([^/:]+)'::text));
+
+
+--
+-- Name: websites__title_trgm; Type: INDEX; Schema: public; Owner: test
+--
+
+CREATE INDEX websites__title_trgm ON public.websites USING gin (title public.gin_trgm_ops);
+
+
 --
 -- Name: websites__url; Type: INDEX; Schema: public; Owner: test
 --
@@ -85,6 +403,13 @@ CREATE UNIQUE INDEX website_settings__domain ON public.website_settings USING bt
 CREATE UNIQUE INDEX websites__url ON public.websites USING btree (url);
 
 
+--
+-- Name: websites__url_trgm; Type: INDEX; Schema: public; Owner: test
+--
+
+CREATE INDEX websites__url_trgm ON public.websites USING gin (url public.gin_trgm_ops);
+
+
 --
 -- Name: websites__uuid; Type: INDEX; Schema: public; Owner: test
 --
@@ -92,6 +417,13 @@ CREATE UNIQUE INDEX websites__url ON public.websites USING btree (url);
 CREATE UNIQUE INDEX websites__uuid ON public.websites USING btree (uuid);
 
 
+--
+-- Name: worker_statuses__worker_id; Type: INDEX; Schema: public; Owner: test
+--
+
+CREATE UNIQUE INDEX worker_statuses__worker_id ON public.worker_statuses USING btree (worker_id);
+
+
 --
 -- PostgreSQL database dump complete
 --
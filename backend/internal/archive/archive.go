@@ -0,0 +1,171 @@
+package archive
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/htchan/WebHistory/internal/config"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+var (
+	ErrNotFound      = errors.New("archive object not found")
+	ErrUnknownDriver = errors.New("unknown archive driver")
+)
+
+type Object struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// Domain returns the website setting domain which the object was archived under
+func (obj Object) Domain() string {
+	domain, _, _ := strings.Cut(obj.Key, "/")
+	return domain
+}
+
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+	List(ctx context.Context, prefix string) ([]Object, error)
+}
+
+// Archive keeps fetched responses in a content-addressed store, so that
+// identical responses of a domain are stored only once
+type Archive struct {
+	store Store
+	conf  *config.ArchiveConfig
+	stop  chan struct{}
+}
+
+func New(store Store, conf *config.ArchiveConfig) *Archive {
+	return &Archive{
+		store: store,
+		conf:  conf,
+		stop:  make(chan struct{}),
+	}
+}
+
+func NewStore(conf *config.ArchiveConfig) (Store, error) {
+	switch conf.Driver {
+	case DriverLocal:
+		return NewLocalStore(conf.Dir), nil
+	case DriverS3:
+		return NewS3Store(conf), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDriver, conf.Driver)
+	}
+}
+
+func Key(domain string, body []byte) string {
+	return fmt.Sprintf("%s/%x.html", domain, sha256.Sum256(body))
+}
+
+// Save stores body under domain and returns its archive key
+func (a *Archive) Save(ctx context.Context, domain string, body []byte) (string, error) {
+	key := Key(domain, body)
+	if err := a.store.Put(ctx, key, body); err != nil {
+		return "", fmt.Errorf("save archive fail: %w", err)
+	}
+
+	return key, nil
+}
+
+func (a *Archive) Load(ctx context.Context, key string) ([]byte, error) {
+	return a.store.Get(ctx, key)
+}
+
+// Snapshots lists archived responses of domain from newest to oldest
+func (a *Archive) Snapshots(ctx context.Context, domain string) ([]Object, error) {
+	objs, err := a.store.List(ctx, domain+"/")
+	if err != nil {
+		return nil, fmt.Errorf("list snapshots fail: %w", err)
+	}
+
+	sort.SliceStable(objs, func(i, j int) bool {
+		return objs[i].ModTime.After(objs[j].ModTime)
+	})
+
+	return objs, nil
+}
+
+// expired returns objects violating the retention rules, which are objects
+// older than MaxAge and objects beyond the newest MaxSnapshots of each domain
+func (a *Archive) expired(objs []Object, now time.Time) []Object {
+	sort.SliceStable(objs, func(i, j int) bool {
+		return objs[i].ModTime.After(objs[j].ModTime)
+	})
+
+	var result []Object
+	domainCount := make(map[string]int)
+	for _, obj := range objs {
+		domainCount[obj.Domain()]++
+
+		if a.conf.MaxAge > 0 && now.Sub(obj.ModTime) > a.conf.MaxAge {
+			result = append(result, obj)
+		} else if a.conf.MaxSnapshots > 0 && domainCount[obj.Domain()] > a.conf.MaxSnapshots {
+			result = append(result, obj)
+		}
+	}
+
+	return result
+}
+
+// Prune deletes archived responses violating the retention rules and returns number of deleted objects
+func (a *Archive) Prune(ctx context.Context, now time.Time) (int, error) {
+	objs, err := a.store.List(ctx, "")
+	if err != nil {
+		return 0, fmt.Errorf("prune archive fail: %w", err)
+	}
+
+	deleted := 0
+	for _, obj := range a.expired(objs, now) {
+		if err := a.store.Delete(ctx, obj.Key); err != nil {
+			return deleted, fmt.Errorf("prune archive fail: %w", err)
+		}
+
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+func (a *Archive) Start() {
+	if a.conf.PruneInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(a.conf.PruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.stop:
+			return
+		case <-ticker.C:
+			deleted, err := a.Prune(context.Background(), time.Now().UTC())
+			if err != nil {
+				log.Error().Err(err).Int("deleted", deleted).Msg("prune archive failed")
+			} else {
+				log.Info().Int("deleted", deleted).Msg("prune archive success")
+			}
+		}
+	}
+}
+
+func (a *Archive) Stop() error {
+	close(a.stop)
+	return nil
+}
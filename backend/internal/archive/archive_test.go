@@ -0,0 +1,165 @@
+package archive
+
+import (
+	"context"
+	"flag"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/htchan/WebHistory/internal/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	leak := flag.Bool("leak", false, "check for memory leaks")
+	flag.Parse()
+
+	if *leak {
+		goleak.VerifyTestMain(m)
+	} else {
+		os.Exit(m.Run())
+	}
+}
+
+func TestKey(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t,
+		"example.com/2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824.html",
+		Key("example.com", []byte("hello")),
+	)
+	assert.Equal(t, Key("example.com", []byte("hello")), Key("example.com", []byte("hello")))
+	assert.NotEqual(t, Key("example.com", []byte("hello")), Key("example.com", []byte("world")))
+}
+
+func TestNewStore(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		conf    *config.ArchiveConfig
+		want    Store
+		wantErr error
+	}{
+		{
+			name: "local store",
+			conf: &config.ArchiveConfig{Driver: DriverLocal, Dir: "dir"},
+			want: &LocalStore{dir: "dir"},
+		},
+		{
+			name:    "unknown driver",
+			conf:    &config.ArchiveConfig{Driver: "ftp"},
+			wantErr: ErrUnknownDriver,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			store, err := NewStore(test.conf)
+			assert.ErrorIs(t, err, test.wantErr)
+			assert.Equal(t, test.want, store)
+		})
+	}
+}
+
+func TestArchive_SaveLoad(t *testing.T) {
+	t.Parallel()
+
+	a := New(NewLocalStore(t.TempDir()), &config.ArchiveConfig{})
+
+	key, err := a.Save(context.Background(), "example.com", []byte("<html></html>"))
+	assert.NoError(t, err)
+	assert.Equal(t, Key("example.com", []byte("<html></html>")), key)
+
+	data, err := a.Load(context.Background(), key)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("<html></html>"), data)
+
+	_, err = a.Load(context.Background(), "example.com/unknown.html")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	snapshots, err := a.Snapshots(context.Background(), "example.com")
+	assert.NoError(t, err)
+	if assert.Len(t, snapshots, 1) {
+		assert.Equal(t, key, snapshots[0].Key)
+		assert.Equal(t, int64(len("<html></html>")), snapshots[0].Size)
+	}
+}
+
+func TestArchive_expired(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2000, 1, 10, 0, 0, 0, 0, time.UTC)
+	objs := []Object{
+		{Key: "a.com/1.html", ModTime: time.Date(2000, 1, 9, 0, 0, 0, 0, time.UTC)},
+		{Key: "a.com/2.html", ModTime: time.Date(2000, 1, 8, 0, 0, 0, 0, time.UTC)},
+		{Key: "a.com/3.html", ModTime: time.Date(2000, 1, 7, 0, 0, 0, 0, time.UTC)},
+		{Key: "b.com/1.html", ModTime: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	tests := []struct {
+		name string
+		conf *config.ArchiveConfig
+		want []string
+	}{
+		{
+			name: "keep everything without retention rules",
+			conf: &config.ArchiveConfig{},
+			want: nil,
+		},
+		{
+			name: "expire objects older than max age",
+			conf: &config.ArchiveConfig{MaxAge: 48 * time.Hour},
+			want: []string{"a.com/3.html", "b.com/1.html"},
+		},
+		{
+			name: "expire objects beyond max snapshots of each domain",
+			conf: &config.ArchiveConfig{MaxSnapshots: 1},
+			want: []string{"a.com/2.html", "a.com/3.html"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			a := New(nil, test.conf)
+			var got []string
+			for _, obj := range a.expired(append([]Object{}, objs...), now) {
+				got = append(got, obj.Key)
+			}
+
+			assert.ElementsMatch(t, test.want, got)
+		})
+	}
+}
+
+func TestArchive_Prune(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	a := New(NewLocalStore(dir), &config.ArchiveConfig{MaxAge: time.Hour})
+
+	oldKey, _ := a.Save(context.Background(), "example.com", []byte("old"))
+	newKey, _ := a.Save(context.Background(), "example.com", []byte("new"))
+
+	oldTime := time.Now().Add(-2 * time.Hour)
+	os.Chtimes(NewLocalStore(dir).path(oldKey), oldTime, oldTime)
+
+	deleted, err := a.Prune(context.Background(), time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	_, err = a.Load(context.Background(), oldKey)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = a.Load(context.Background(), newKey)
+	assert.NoError(t, err)
+}
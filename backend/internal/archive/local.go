@@ -0,0 +1,100 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type LocalStore struct {
+	dir string
+}
+
+var _ Store = (*LocalStore)(nil)
+
+func NewLocalStore(dir string) *LocalStore {
+	return &LocalStore{dir: dir}
+}
+
+func (store *LocalStore) path(key string) string {
+	return filepath.Join(store.dir, filepath.FromSlash(key))
+}
+
+func (store *LocalStore) Put(ctx context.Context, key string, data []byte) error {
+	path := store.path(key)
+
+	// content addressed object only need to refresh its mod time if it already exists
+	if _, err := os.Stat(path); err == nil {
+		now := time.Now()
+		return os.Chtimes(path, now, now)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create archive dir fail: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+func (store *LocalStore) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := os.ReadFile(store.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+
+	return data, err
+}
+
+func (store *LocalStore) Delete(ctx context.Context, key string) error {
+	err := os.Remove(store.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+func (store *LocalStore) List(ctx context.Context, prefix string) ([]Object, error) {
+	var objs []Object
+
+	err := filepath.WalkDir(store.dir, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(store.dir, path)
+		if err != nil {
+			return err
+		}
+
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		objs = append(objs, Object{Key: key, Size: info.Size(), ModTime: info.ModTime().UTC()})
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list archive fail: %w", err)
+	}
+
+	return objs, nil
+}
@@ -0,0 +1,208 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/htchan/WebHistory/internal/config"
+)
+
+// S3Store keeps objects in a bucket of S3 compatible storage (e.g. MinIO),
+// addressing the bucket in path style and signing requests with AWS signature v4
+type S3Store struct {
+	client    *http.Client
+	endpoint  string
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	now       func() time.Time
+}
+
+var _ Store = (*S3Store)(nil)
+
+func NewS3Store(conf *config.ArchiveConfig) *S3Store {
+	return &S3Store{
+		client:    &http.Client{Timeout: 30 * time.Second},
+		endpoint:  strings.TrimSuffix(conf.S3Endpoint, "/"),
+		region:    conf.S3Region,
+		bucket:    conf.S3Bucket,
+		accessKey: conf.S3AccessKey,
+		secretKey: conf.S3SecretKey,
+		now:       time.Now,
+	}
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		for _, value := range query[key] {
+			parts = append(parts, url.QueryEscape(key)+"="+strings.ReplaceAll(url.QueryEscape(value), "+", "%20"))
+		}
+	}
+
+	return strings.Join(parts, "&")
+}
+
+// sign adds the headers required by AWS signature v4 to req
+func (store *S3Store) sign(req *http.Request, payload []byte) {
+	now := store.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := fmt.Sprintf(
+		"host:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n",
+		req.URL.Host, payloadHash, amzDate,
+	)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, store.region)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+store.secretKey), date)
+	signingKey = hmacSHA256(signingKey, store.region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		store.accessKey, scope, signedHeaders, hex.EncodeToString(hmacSHA256(signingKey, stringToSign)),
+	))
+}
+
+func (store *S3Store) do(ctx context.Context, method, key string, query url.Values, payload []byte) ([]byte, error) {
+	u, err := url.Parse(fmt.Sprintf("%s/%s/%s", store.endpoint, store.bucket, key))
+	if err != nil {
+		return nil, fmt.Errorf("parse s3 url fail: %w", err)
+	}
+	u.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("create s3 request fail: %w", err)
+	}
+	store.sign(req, payload)
+
+	resp, err := store.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 request fail: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read s3 response fail: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	} else if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("s3 %s %s fail: status %d: %s", method, key, resp.StatusCode, body)
+	}
+
+	return body, nil
+}
+
+func (store *S3Store) Put(ctx context.Context, key string, data []byte) error {
+	_, err := store.do(ctx, http.MethodPut, key, nil, data)
+	return err
+}
+
+func (store *S3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	return store.do(ctx, http.MethodGet, key, nil, nil)
+}
+
+func (store *S3Store) Delete(ctx context.Context, key string) error {
+	_, err := store.do(ctx, http.MethodDelete, key, nil, nil)
+	if err == ErrNotFound {
+		return nil
+	}
+
+	return err
+}
+
+func (store *S3Store) List(ctx context.Context, prefix string) ([]Object, error) {
+	var (
+		objs  []Object
+		token string
+	)
+
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		body, err := store.do(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, fmt.Errorf("list s3 objects fail: %w", err)
+		}
+
+		var result listBucketResult
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("parse s3 objects fail: %w", err)
+		}
+
+		for _, content := range result.Contents {
+			objs = append(objs, Object{Key: content.Key, Size: content.Size, ModTime: content.LastModified.UTC()})
+		}
+
+		if !result.IsTruncated {
+			return objs, nil
+		}
+
+		token = result.NextContinuationToken
+	}
+}
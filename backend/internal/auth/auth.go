@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/htchan/WebHistory/internal/config"
+	"github.com/htchan/WebHistory/internal/repository"
+)
+
+const (
+	AuthenticatorGRPC  = "grpc"
+	AuthenticatorLocal = "local"
+)
+
+var (
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrUserExists         = errors.New("user already exists")
+	ErrUnavailable        = errors.New("authentication service unavailable")
+)
+
+// Authenticator resolves the user uuid of a token from Authorization header,
+// it returns ErrUnauthorized for rejected token and ErrUnavailable if token
+// cannot be verified at the moment
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (string, error)
+}
+
+// New creates the authenticator selected in config, result of remote
+// authenticator is cached
+func New(conf *config.UserServiceConfig, rpo repository.Repostory) (Authenticator, error) {
+	var (
+		authenticator Authenticator
+		err           error
+	)
+
+	switch conf.Authenticator {
+	case AuthenticatorGRPC:
+		var grpcAuth *GRPCAuthenticator
+		grpcAuth, err = NewGRPCAuthenticator(conf)
+		if err == nil {
+			authenticator = NewCachedAuthenticator(grpcAuth, conf.CacheTTL, conf.NegativeCacheTTL)
+		}
+	case AuthenticatorLocal:
+		authenticator, err = NewLocalAuthenticator(conf, rpo)
+	default:
+		err = fmt.Errorf("unknown authenticator: %s", conf.Authenticator)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("create authenticator: %w", err)
+	}
+
+	return authenticator, nil
+}
+
+func trimBearer(token string) string {
+	if len(token) > len("Bearer ") && strings.EqualFold(token[:len("Bearer ")], "Bearer ") {
+		return token[len("Bearer "):]
+	}
+
+	return token
+}
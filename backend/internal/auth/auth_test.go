@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/htchan/WebHistory/internal/config"
+	"github.com/htchan/WebHistory/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	leak := flag.Bool("leak", false, "check for memory leaks")
+	flag.Parse()
+
+	if *leak {
+		goleak.VerifyTestMain(m)
+	} else {
+		os.Exit(m.Run())
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		conf      config.UserServiceConfig
+		wantLocal bool
+		wantError bool
+	}{
+		{
+			name:      "local authenticator",
+			conf:      config.UserServiceConfig{Authenticator: AuthenticatorLocal, JWTSecret: "secret"},
+			wantLocal: true,
+		},
+		{
+			name:      "local authenticator without secret",
+			conf:      config.UserServiceConfig{Authenticator: AuthenticatorLocal},
+			wantError: true,
+		},
+		{
+			name:      "grpc authenticator without addr",
+			conf:      config.UserServiceConfig{Authenticator: AuthenticatorGRPC},
+			wantError: true,
+		},
+		{
+			name:      "unknown authenticator",
+			conf:      config.UserServiceConfig{Authenticator: "unknown"},
+			wantError: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			authenticator, err := New(&test.conf, repository.NewInMemRepo(nil, nil, nil, nil))
+			assert.Equal(t, test.wantError, err != nil)
+			_, isLocal := authenticator.(*LocalAuthenticator)
+			assert.Equal(t, test.wantLocal, isLocal)
+		})
+	}
+}
+
+func Test_trimBearer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		token string
+		want  string
+	}{
+		{name: "token with bearer prefix", token: "Bearer token", want: "token"},
+		{name: "token with lower case prefix", token: "bearer token", want: "token"},
+		{name: "token without prefix", token: "token", want: "token"},
+		{name: "bearer only", token: "Bearer ", want: "Bearer "},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.want, trimBearer(test.token))
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("open after consecutive failures", func(t *testing.T) {
+		t.Parallel()
+
+		now := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+		b := newCircuitBreaker(2, time.Minute)
+		b.now = func() time.Time { return now }
+
+		b.Failure()
+		assert.True(t, b.Allow())
+		b.Failure()
+		assert.False(t, b.Allow())
+
+		now = now.Add(time.Minute)
+		assert.True(t, b.Allow(), "allow trial call after cooldown")
+		assert.False(t, b.Allow(), "reject other calls during trial call")
+
+		b.Failure()
+		assert.False(t, b.Allow(), "reopen after failed trial call")
+
+		now = now.Add(time.Minute)
+		assert.True(t, b.Allow())
+		b.Success()
+		assert.True(t, b.Allow(), "close after successful trial call")
+		assert.True(t, b.Allow())
+	})
+
+	t.Run("success reset failures", func(t *testing.T) {
+		t.Parallel()
+
+		b := newCircuitBreaker(2, time.Minute)
+		b.Failure()
+		b.Success()
+		b.Failure()
+		assert.True(t, b.Allow())
+	})
+
+	t.Run("disabled by non positive threshold", func(t *testing.T) {
+		t.Parallel()
+
+		b := newCircuitBreaker(0, time.Minute)
+		b.Failure()
+		b.Failure()
+		assert.True(t, b.Allow())
+	})
+}
@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+const maxCacheEntries = 10000
+
+type cacheEntry struct {
+	userUUID string
+	err      error
+	expireAt time.Time
+}
+
+// CachedAuthenticator caches the result of authenticator by token, rejected
+// token is cached for negativeTTL while error of unavailable authenticator
+// is never cached
+type CachedAuthenticator struct {
+	authenticator Authenticator
+	ttl           time.Duration
+	negativeTTL   time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	now     func() time.Time
+}
+
+var _ Authenticator = &CachedAuthenticator{}
+
+func NewCachedAuthenticator(authenticator Authenticator, ttl, negativeTTL time.Duration) *CachedAuthenticator {
+	return &CachedAuthenticator{
+		authenticator: authenticator,
+		ttl:           ttl,
+		negativeTTL:   negativeTTL,
+		entries:       make(map[string]cacheEntry),
+		now:           time.Now,
+	}
+}
+
+func (auth *CachedAuthenticator) Authenticate(ctx context.Context, token string) (string, error) {
+	// raw token is not kept in memory
+	sum := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(sum[:])
+
+	if entry, ok := auth.get(key); ok {
+		return entry.userUUID, entry.err
+	}
+
+	userUUID, err := auth.authenticator.Authenticate(ctx, token)
+	if err == nil {
+		auth.set(key, cacheEntry{userUUID: userUUID}, auth.ttl)
+	} else if errors.Is(err, ErrUnauthorized) {
+		auth.set(key, cacheEntry{err: ErrUnauthorized}, auth.negativeTTL)
+	}
+
+	return userUUID, err
+}
+
+func (auth *CachedAuthenticator) get(key string) (cacheEntry, bool) {
+	auth.mu.Lock()
+	defer auth.mu.Unlock()
+
+	entry, ok := auth.entries[key]
+	if !ok || !auth.now().Before(entry.expireAt) {
+		return cacheEntry{}, false
+	}
+
+	return entry, true
+}
+
+func (auth *CachedAuthenticator) set(key string, entry cacheEntry, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	auth.mu.Lock()
+	defer auth.mu.Unlock()
+
+	now := auth.now()
+	if len(auth.entries) >= maxCacheEntries {
+		auth.prune(now)
+	}
+
+	entry.expireAt = now.Add(ttl)
+	auth.entries[key] = entry
+}
+
+// prune removes expired entries, all entries are dropped if cache is still
+// full of valid entries
+func (auth *CachedAuthenticator) prune(now time.Time) {
+	for key, entry := range auth.entries {
+		if !now.Before(entry.expireAt) {
+			delete(auth.entries, key)
+		}
+	}
+
+	if len(auth.entries) >= maxCacheEntries {
+		auth.entries = make(map[string]cacheEntry)
+	}
+}
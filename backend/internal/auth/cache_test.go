@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingAuthenticator struct {
+	users map[string]string
+	err   error
+	calls int
+}
+
+func (auth *countingAuthenticator) Authenticate(ctx context.Context, token string) (string, error) {
+	auth.calls++
+	if auth.err != nil {
+		return "", auth.err
+	}
+	if userUUID, ok := auth.users[token]; ok {
+		return userUUID, nil
+	}
+	return "", ErrUnauthorized
+}
+
+func TestCachedAuthenticator_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		err       error
+		token     string
+		elapse    time.Duration
+		wantUser  string
+		wantError error
+		wantCalls int
+	}{
+		{
+			name:      "cache valid token",
+			token:     "valid",
+			elapse:    59 * time.Second,
+			wantUser:  "abc",
+			wantCalls: 1,
+		},
+		{
+			name:      "valid token expired",
+			token:     "valid",
+			elapse:    time.Minute,
+			wantUser:  "abc",
+			wantCalls: 2,
+		},
+		{
+			name:      "cache rejected token",
+			token:     "invalid",
+			elapse:    9 * time.Second,
+			wantError: ErrUnauthorized,
+			wantCalls: 1,
+		},
+		{
+			name:      "rejected token expired",
+			token:     "invalid",
+			elapse:    10 * time.Second,
+			wantError: ErrUnauthorized,
+			wantCalls: 2,
+		},
+		{
+			name:      "not cache unavailable error",
+			err:       fmt.Errorf("%w: timeout", ErrUnavailable),
+			token:     "valid",
+			wantError: ErrUnavailable,
+			wantCalls: 2,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			now := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+			inner := &countingAuthenticator{users: map[string]string{"valid": "abc"}, err: test.err}
+			cached := NewCachedAuthenticator(inner, time.Minute, 10*time.Second)
+			cached.now = func() time.Time { return now }
+
+			cached.Authenticate(context.Background(), test.token)
+			now = now.Add(test.elapse)
+			userUUID, err := cached.Authenticate(context.Background(), test.token)
+
+			assert.Equal(t, test.wantUser, userUUID)
+			assert.ErrorIs(t, err, test.wantError)
+			assert.Equal(t, test.wantCalls, inner.calls)
+		})
+	}
+}
+
+func TestCachedAuthenticator_prune(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	cached := NewCachedAuthenticator(&countingAuthenticator{}, time.Minute, time.Minute)
+	cached.entries["expired"] = cacheEntry{expireAt: now}
+	cached.entries["valid"] = cacheEntry{expireAt: now.Add(time.Second)}
+
+	cached.prune(now)
+	assert.Len(t, cached.entries, 1)
+	assert.Contains(t, cached.entries, "valid")
+}
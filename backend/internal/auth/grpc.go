@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/htchan/UserService/backend/pkg/grpc"
+	"github.com/htchan/WebHistory/internal/config"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// userServiceClient is the part of UserService used for authentication
+type userServiceClient interface {
+	Authenticate(ctx context.Context, token string) (string, error)
+}
+
+type grpcUserServiceClient struct {
+	client       grpc.Client
+	serviceToken string
+}
+
+func (c grpcUserServiceClient) Authenticate(ctx context.Context, token string) (string, error) {
+	params := grpc.NewAuthenticateParams(token, c.serviceToken, "")
+	result, err := c.client.Authenticate(ctx, params)
+	if err != nil {
+		// UserService answers rejected token with plain error
+		switch status.Code(err) {
+		case codes.Unknown, codes.Unauthenticated, codes.PermissionDenied, codes.NotFound, codes.InvalidArgument:
+			return "", fmt.Errorf("%w: %v", ErrUnauthorized, err)
+		}
+		return "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+
+	zerolog.Ctx(ctx).Debug().Str("result", result.String()).Msg("authenticate result")
+	if result.Result == nil {
+		return "", nil
+	}
+
+	return *result.Result, nil
+}
+
+// GRPCAuthenticator authenticates token with the external UserService,
+// calls are bounded by timeout and stop for a while once UserService keeps
+// failing
+type GRPCAuthenticator struct {
+	dial    func() userServiceClient
+	timeout time.Duration
+	breaker *circuitBreaker
+
+	dialOnce sync.Once
+	dialed   chan struct{}
+	client   userServiceClient
+}
+
+var _ Authenticator = &GRPCAuthenticator{}
+
+func NewGRPCAuthenticator(conf *config.UserServiceConfig) (*GRPCAuthenticator, error) {
+	if conf.Addr == "" || conf.Token == "" {
+		return nil, errors.New("user service addr and token are required")
+	}
+
+	dial := func() userServiceClient {
+		return grpcUserServiceClient{client: grpc.NewClient(conf.Addr), serviceToken: conf.Token}
+	}
+
+	return newGRPCAuthenticator(dial, conf), nil
+}
+
+func newGRPCAuthenticator(dial func() userServiceClient, conf *config.UserServiceConfig) *GRPCAuthenticator {
+	return &GRPCAuthenticator{
+		dial:    dial,
+		timeout: conf.Timeout,
+		breaker: newCircuitBreaker(conf.BreakerThreshold, conf.BreakerCooldown),
+		dialed:  make(chan struct{}),
+	}
+}
+
+// connectedClient waits for the connection to UserService, the dial blocks
+// until UserService is reachable so it keeps going in background
+func (auth *GRPCAuthenticator) connectedClient(ctx context.Context) (userServiceClient, error) {
+	auth.dialOnce.Do(func() {
+		go func() {
+			auth.client = auth.dial()
+			close(auth.dialed)
+		}()
+	})
+
+	select {
+	case <-auth.dialed:
+		return auth.client, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w: connect user service: %v", ErrUnavailable, ctx.Err())
+	}
+}
+
+func (auth *GRPCAuthenticator) Authenticate(ctx context.Context, token string) (string, error) {
+	if token == "" {
+		return "", ErrUnauthorized
+	}
+
+	if !auth.breaker.Allow() {
+		return "", fmt.Errorf("%w: circuit breaker open", ErrUnavailable)
+	}
+
+	if auth.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, auth.timeout)
+		defer cancel()
+	}
+
+	userUUID, err := auth.authenticate(ctx, token)
+	if errors.Is(err, ErrUnavailable) {
+		auth.breaker.Failure()
+		return "", err
+	}
+
+	auth.breaker.Success()
+	if err != nil {
+		return "", err
+	}
+	if userUUID == "" {
+		return "", ErrUnauthorized
+	}
+
+	return userUUID, nil
+}
+
+func (auth *GRPCAuthenticator) authenticate(ctx context.Context, token string) (string, error) {
+	client, err := auth.connectedClient(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return client.Authenticate(ctx, token)
+}
@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/htchan/WebHistory/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubUserServiceClient struct {
+	userUUID string
+	err      error
+	calls    int
+}
+
+func (c *stubUserServiceClient) Authenticate(ctx context.Context, token string) (string, error) {
+	c.calls++
+	return c.userUUID, c.err
+}
+
+func TestGRPCAuthenticator_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	conf := &config.UserServiceConfig{Timeout: time.Second, BreakerThreshold: 2, BreakerCooldown: time.Minute}
+
+	tests := []struct {
+		name      string
+		client    *stubUserServiceClient
+		token     string
+		wantUser  string
+		wantError error
+	}{
+		{
+			name:     "happy flow",
+			client:   &stubUserServiceClient{userUUID: "abc"},
+			token:    "token",
+			wantUser: "abc",
+		},
+		{
+			name:      "empty token",
+			client:    &stubUserServiceClient{userUUID: "abc"},
+			wantError: ErrUnauthorized,
+		},
+		{
+			name:      "empty result",
+			client:    &stubUserServiceClient{},
+			token:     "token",
+			wantError: ErrUnauthorized,
+		},
+		{
+			name:      "rejected token",
+			client:    &stubUserServiceClient{err: fmt.Errorf("%w: token not found", ErrUnauthorized)},
+			token:     "token",
+			wantError: ErrUnauthorized,
+		},
+		{
+			name:      "user service unavailable",
+			client:    &stubUserServiceClient{err: fmt.Errorf("%w: connection refused", ErrUnavailable)},
+			token:     "token",
+			wantError: ErrUnavailable,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			auth := newGRPCAuthenticator(func() userServiceClient { return test.client }, conf)
+			userUUID, err := auth.Authenticate(context.Background(), test.token)
+			assert.Equal(t, test.wantUser, userUUID)
+			assert.ErrorIs(t, err, test.wantError)
+		})
+	}
+}
+
+func TestGRPCAuthenticator_circuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	client := &stubUserServiceClient{err: fmt.Errorf("%w: connection refused", ErrUnavailable)}
+	conf := &config.UserServiceConfig{Timeout: time.Second, BreakerThreshold: 2, BreakerCooldown: time.Minute}
+	auth := newGRPCAuthenticator(func() userServiceClient { return client }, conf)
+
+	for i := 0; i < 3; i++ {
+		_, err := auth.Authenticate(context.Background(), "token")
+		assert.ErrorIs(t, err, ErrUnavailable)
+	}
+	assert.Equal(t, 2, client.calls, "stop calling user service once breaker open")
+}
+
+func TestGRPCAuthenticator_connectTimeout(t *testing.T) {
+	t.Parallel()
+
+	blockDial := make(chan struct{})
+	t.Cleanup(func() { close(blockDial) })
+
+	client := &stubUserServiceClient{userUUID: "abc"}
+	conf := &config.UserServiceConfig{Timeout: 10 * time.Millisecond}
+	auth := newGRPCAuthenticator(func() userServiceClient {
+		<-blockDial
+		return client
+	}, conf)
+
+	_, err := auth.Authenticate(context.Background(), "token")
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.Equal(t, 0, client.calls)
+}
@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/htchan/WebHistory/internal/config"
+	"github.com/htchan/WebHistory/internal/model"
+	"github.com/htchan/WebHistory/internal/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dummyHash is compared against when user does not exist, so login of
+// unknown user takes as long as login with wrong password
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
+// LocalAuthenticator authenticates users stored in database, it accepts
+// both signed login token and personal api token
+type LocalAuthenticator struct {
+	rpo               repository.Repostory
+	secret            []byte
+	tokenTTL          time.Duration
+	allowRegistration bool
+}
+
+var _ Authenticator = &LocalAuthenticator{}
+
+func NewLocalAuthenticator(conf *config.UserServiceConfig, rpo repository.Repostory) (*LocalAuthenticator, error) {
+	if conf.JWTSecret == "" {
+		return nil, errors.New("jwt secret is required")
+	}
+
+	return &LocalAuthenticator{
+		rpo:               rpo,
+		secret:            []byte(conf.JWTSecret),
+		tokenTTL:          conf.JWTTTL,
+		allowRegistration: conf.AllowRegistration,
+	}, nil
+}
+
+func (auth *LocalAuthenticator) AllowRegistration() bool {
+	return auth.allowRegistration
+}
+
+// Register creates user with bcrypt hashed password
+func (auth *LocalAuthenticator) Register(username, password string) (*model.User, error) {
+	username, err := model.NormalizeUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if len(password) < model.MinPasswordLength {
+		return nil, model.ErrInvalidPassword
+	}
+
+	if _, err := auth.rpo.FindUserByUsername(username); err == nil {
+		return nil, ErrUserExists
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("hash password: %w", err)
+	}
+
+	user := model.NewUser(username, string(hash))
+	if err := auth.rpo.CreateUser(&user); errors.Is(err, repository.ErrDuplicate) {
+		return nil, ErrUserExists
+	} else if err != nil {
+		return nil, fmt.Errorf("create user: %w", err)
+	}
+
+	return &user, nil
+}
+
+// Login verifies password of user and issues a signed login token
+func (auth *LocalAuthenticator) Login(username, password string) (string, error) {
+	username, err := model.NormalizeUsername(username)
+	if err != nil {
+		return "", ErrInvalidCredentials
+	}
+
+	user, err := auth.rpo.FindUserByUsername(username)
+	if err != nil {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return "", ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return "", ErrInvalidCredentials
+	}
+
+	now := time.Now().UTC()
+	claims := jwt.RegisteredClaims{
+		Subject:   user.UUID,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(auth.tokenTTL)),
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(auth.secret)
+}
+
+func (auth *LocalAuthenticator) Authenticate(ctx context.Context, token string) (string, error) {
+	token = trimBearer(token)
+	if token == "" {
+		return "", ErrUnauthorized
+	}
+
+	if strings.HasPrefix(token, model.APITokenPrefix) {
+		apiToken, err := auth.rpo.FindAPIToken(model.HashAPIToken(token))
+		if err != nil {
+			return "", ErrUnauthorized
+		}
+
+		return apiToken.UserUUID, nil
+	}
+
+	var claims jwt.RegisteredClaims
+	_, err := jwt.ParseWithClaims(
+		token, &claims,
+		func(*jwt.Token) (interface{}, error) { return auth.secret, nil },
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+	)
+	if err != nil || claims.ExpiresAt == nil || claims.Subject == "" {
+		return "", ErrUnauthorized
+	}
+
+	return claims.Subject, nil
+}
+
+// CreateAPIToken creates a personal api token of user, the token is only
+// returned here and cannot be retrieved again
+func (auth *LocalAuthenticator) CreateAPIToken(userUUID, name string) (*model.APIToken, string, error) {
+	apiToken, token, err := model.NewAPIToken(userUUID, strings.TrimSpace(name))
+	if err != nil {
+		return nil, "", fmt.Errorf("generate api token: %w", err)
+	}
+
+	if err := auth.rpo.CreateAPIToken(&apiToken); err != nil {
+		return nil, "", fmt.Errorf("create api token: %w", err)
+	}
+
+	return &apiToken, token, nil
+}
+
+func (auth *LocalAuthenticator) APITokens(userUUID string) ([]model.APIToken, error) {
+	return auth.rpo.FindAPITokens(userUUID)
+}
+
+func (auth *LocalAuthenticator) DeleteAPIToken(userUUID, tokenUUID string) error {
+	return auth.rpo.DeleteAPIToken(userUUID, tokenUUID)
+}
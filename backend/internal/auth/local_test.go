@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/htchan/WebHistory/internal/config"
+	"github.com/htchan/WebHistory/internal/model"
+	"github.com/htchan/WebHistory/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLocalAuthenticator(t *testing.T) *LocalAuthenticator {
+	t.Helper()
+
+	auth, err := NewLocalAuthenticator(
+		&config.UserServiceConfig{JWTSecret: "secret", JWTTTL: time.Hour},
+		repository.NewInMemRepo(nil, nil, nil, nil),
+	)
+	if err != nil {
+		t.Fatalf("create authenticator: %v", err)
+	}
+
+	return auth
+}
+
+func TestLocalAuthenticator_Register(t *testing.T) {
+	t.Parallel()
+
+	auth := newTestLocalAuthenticator(t)
+	user, err := auth.Register(" user ", "password")
+	assert.NoError(t, err)
+	assert.Equal(t, "user", user.Username)
+	assert.NotEqual(t, "password", user.PasswordHash)
+
+	_, err = auth.Register("user", "password")
+	assert.ErrorIs(t, err, ErrUserExists)
+
+	_, err = auth.Register("another", "short")
+	assert.ErrorIs(t, err, model.ErrInvalidPassword)
+
+	_, err = auth.Register(" ", "password")
+	assert.ErrorIs(t, err, model.ErrInvalidUsername)
+}
+
+func TestLocalAuthenticator_Login(t *testing.T) {
+	t.Parallel()
+
+	auth := newTestLocalAuthenticator(t)
+	user, err := auth.Register("user", "password")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		username  string
+		password  string
+		wantError error
+	}{
+		{name: "happy flow", username: "user", password: "password"},
+		{name: "wrong password", username: "user", password: "wrong password", wantError: ErrInvalidCredentials},
+		{name: "unknown user", username: "unknown", password: "password", wantError: ErrInvalidCredentials},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			token, err := auth.Login(test.username, test.password)
+			assert.ErrorIs(t, err, test.wantError)
+			if test.wantError != nil {
+				return
+			}
+
+			userUUID, err := auth.Authenticate(context.Background(), "Bearer "+token)
+			assert.NoError(t, err)
+			assert.Equal(t, user.UUID, userUUID)
+		})
+	}
+}
+
+func TestLocalAuthenticator_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	auth := newTestLocalAuthenticator(t)
+	apiToken, token, err := auth.CreateAPIToken("user-uuid", "cli")
+	assert.NoError(t, err)
+
+	sign := func(claims jwt.RegisteredClaims, secret string) string {
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		if err != nil {
+			t.Fatalf("sign token: %v", err)
+		}
+		return signed
+	}
+	expireAt := jwt.NewNumericDate(time.Now().Add(time.Hour))
+
+	tests := []struct {
+		name      string
+		token     string
+		want      string
+		wantError error
+	}{
+		{
+			name:  "api token",
+			token: token,
+			want:  apiToken.UserUUID,
+		},
+		{
+			name:  "login token",
+			token: sign(jwt.RegisteredClaims{Subject: "user-uuid", ExpiresAt: expireAt}, "secret"),
+			want:  "user-uuid",
+		},
+		{
+			name:      "unknown api token",
+			token:     model.APITokenPrefix + "unknown",
+			wantError: ErrUnauthorized,
+		},
+		{
+			name:      "login token signed by other secret",
+			token:     sign(jwt.RegisteredClaims{Subject: "user-uuid", ExpiresAt: expireAt}, "other"),
+			wantError: ErrUnauthorized,
+		},
+		{
+			name: "expired login token",
+			token: sign(jwt.RegisteredClaims{
+				Subject: "user-uuid", ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+			}, "secret"),
+			wantError: ErrUnauthorized,
+		},
+		{
+			name:      "login token without expiry",
+			token:     sign(jwt.RegisteredClaims{Subject: "user-uuid"}, "secret"),
+			wantError: ErrUnauthorized,
+		},
+		{
+			name:      "empty token",
+			token:     "",
+			wantError: ErrUnauthorized,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			userUUID, err := auth.Authenticate(context.Background(), test.token)
+			assert.ErrorIs(t, err, test.wantError)
+			assert.Equal(t, test.want, userUUID)
+		})
+	}
+}
+
+func TestLocalAuthenticator_APITokens(t *testing.T) {
+	t.Parallel()
+
+	auth := newTestLocalAuthenticator(t)
+	apiToken, token, err := auth.CreateAPIToken("user-uuid", " cli ")
+	assert.NoError(t, err)
+	assert.Equal(t, "cli", apiToken.Name)
+
+	tokens, err := auth.APITokens("user-uuid")
+	assert.NoError(t, err)
+	assert.Equal(t, []model.APIToken{*apiToken}, tokens)
+
+	assert.NoError(t, auth.DeleteAPIToken("user-uuid", apiToken.UUID))
+	_, err = auth.Authenticate(context.Background(), token)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+}
@@ -0,0 +1,33 @@
+package auth
+
+type Role string
+
+const (
+	RoleUser  Role = "user"
+	RoleAdmin Role = "admin"
+)
+
+// Roles assigns role to authenticated user, users listed as admin get the
+// admin role and everyone else is a normal user
+type Roles struct {
+	admins map[string]bool
+}
+
+func NewRoles(adminUserUUIDs []string) Roles {
+	admins := make(map[string]bool, len(adminUserUUIDs))
+	for _, userUUID := range adminUserUUIDs {
+		if userUUID != "" {
+			admins[userUUID] = true
+		}
+	}
+
+	return Roles{admins: admins}
+}
+
+func (roles Roles) Of(userUUID string) Role {
+	if roles.admins[userUUID] {
+		return RoleAdmin
+	}
+
+	return RoleUser
+}
@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoles_Of(t *testing.T) {
+	t.Parallel()
+
+	roles := NewRoles([]string{"admin-uuid", ""})
+	tests := []struct {
+		name     string
+		userUUID string
+		want     Role
+	}{
+		{name: "admin user", userUUID: "admin-uuid", want: RoleAdmin},
+		{name: "normal user", userUUID: "user-uuid", want: RoleUser},
+		{name: "empty user uuid", userUUID: "", want: RoleUser},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.want, roles.Of(test.userUUID))
+		})
+	}
+}
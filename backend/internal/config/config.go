@@ -16,15 +16,18 @@ type APIConfig struct {
 }
 
 type APIBinConfig struct {
-	Addr           string        `env:"ADDR"`
-	ReadTimeout    time.Duration `env:"API_READ_TIMEOUT" envDefault:"5s"`
-	WriteTimeout   time.Duration `env:"API_WRITE_TIMEOUT" envDefault:"5s"`
-	IdleTimeout    time.Duration `env:"API_IDLE_TIMEOUT" envDefault:"5s"`
-	APIRoutePrefix string        `env:"WEB_WATCHER_API_ROUTE_PREFIX" envDefault:"/api/web-watcher"`
+	Addr            string        `env:"ADDR"`
+	ReadTimeout     time.Duration `env:"API_READ_TIMEOUT" envDefault:"5s"`
+	WriteTimeout    time.Duration `env:"API_WRITE_TIMEOUT" envDefault:"5s"`
+	IdleTimeout     time.Duration `env:"API_IDLE_TIMEOUT" envDefault:"5s"`
+	APIRoutePrefix  string        `env:"WEB_WATCHER_API_ROUTE_PREFIX" envDefault:"/api/web-watcher"`
+	StrictGroupName bool          `env:"WEB_WATCHER_STRICT_GROUP_NAME" envDefault:"true"`
+	MetricsAddr     string        `env:"API_METRICS_ADDR" envDefault:":9091"`
 }
 
 type WorkerConfig struct {
 	BinConfig      WorkerBinConfig
+	ArchiveConfig  ArchiveConfig
 	DatabaseConfig DatabaseConfig
 	TraceConfig    TraceConfig
 	WebsiteConfig  WebsiteConfig
@@ -34,11 +37,17 @@ type WorkerBinConfig struct {
 	WebsiteUpdateSleepInterval time.Duration `env:"WEBSITE_UPDATE_SLEEP_INTERVAL"`
 	WorkerExecutorCount        int           `env:"WORKER_EXECUTOR_COUNT"`
 	ExecAtBeginning            bool          `env:"EXEC_AT_BEGINNING"`
+	MetricsAddr                string        `env:"WORKER_METRICS_ADDR" envDefault:":9090"`
+	UpdateRequestPollInterval  time.Duration `env:"WEBSITE_UPDATE_REQUEST_POLL_INTERVAL" envDefault:"5s"`
+	UpdateRequestBatchSize     int           `env:"WEBSITE_UPDATE_REQUEST_BATCH_SIZE" envDefault:"10"`
+	HeartbeatInterval          time.Duration `env:"WORKER_HEARTBEAT_INTERVAL" envDefault:"30s"`
 }
 
 type TraceConfig struct {
 	TraceURL         string `env:"TRACE_URL"`
 	TraceServiceName string `env:"TRACE_SERVICE_NAME"`
+	TraceExporter    string `env:"TRACE_EXPORTER" envDefault:"otlp-grpc"`
+	TraceInsecure    bool   `env:"TRACE_INSECURE" envDefault:"true"`
 }
 
 type DatabaseConfig struct {
@@ -50,14 +59,42 @@ type DatabaseConfig struct {
 	Database string `env:"PSQL_NAME,required"`
 }
 
+type ArchiveConfig struct {
+	Driver        string        `env:"ARCHIVE_DRIVER"`
+	Dir           string        `env:"ARCHIVE_DIR" envDefault:"/archive"`
+	S3Endpoint    string        `env:"ARCHIVE_S3_ENDPOINT"`
+	S3Region      string        `env:"ARCHIVE_S3_REGION" envDefault:"us-east-1"`
+	S3Bucket      string        `env:"ARCHIVE_S3_BUCKET"`
+	S3AccessKey   string        `env:"ARCHIVE_S3_ACCESS_KEY"`
+	S3SecretKey   string        `env:"ARCHIVE_S3_SECRET_KEY"`
+	MaxAge        time.Duration `env:"ARCHIVE_MAX_AGE"`
+	MaxSnapshots  int           `env:"ARCHIVE_MAX_SNAPSHOTS"`
+	PruneInterval time.Duration `env:"ARCHIVE_PRUNE_INTERVAL" envDefault:"24h"`
+}
+
 type UserServiceConfig struct {
-	Addr  string `env:"USER_SERVICE_ADDR,required"`
-	Token string `env:"USER_SERVICE_TOKEN,required"`
+	Authenticator     string        `env:"AUTHENTICATOR" envDefault:"grpc"`
+	Addr              string        `env:"USER_SERVICE_ADDR"`
+	Token             string        `env:"USER_SERVICE_TOKEN"`
+	Timeout           time.Duration `env:"USER_SERVICE_TIMEOUT" envDefault:"3s"`
+	BreakerThreshold  int           `env:"USER_SERVICE_BREAKER_THRESHOLD" envDefault:"5"`
+	BreakerCooldown   time.Duration `env:"USER_SERVICE_BREAKER_COOLDOWN" envDefault:"30s"`
+	CacheTTL          time.Duration `env:"AUTH_CACHE_TTL" envDefault:"1m"`
+	NegativeCacheTTL  time.Duration `env:"AUTH_NEGATIVE_CACHE_TTL" envDefault:"10s"`
+	JWTSecret         string        `env:"AUTH_JWT_SECRET"`
+	JWTTTL            time.Duration `env:"AUTH_JWT_TTL" envDefault:"24h"`
+	AllowRegistration bool          `env:"AUTH_ALLOW_REGISTRATION"`
+	AdminUsers        []string      `env:"AUTH_ADMIN_USERS" envSeparator:","`
 }
 
 type WebsiteConfig struct {
-	Separator     string `env:"WEB_WATCHER_SEPARATOR" envDefault:"\n"`
-	MaxDateLength int    `env:"WEB_WATCHER_DATE_MAX_LENGTH" envDefault:"2"`
+	Separator         string   `env:"WEB_WATCHER_SEPARATOR" envDefault:"\n"`
+	MaxDateLength     int      `env:"WEB_WATCHER_DATE_MAX_LENGTH" envDefault:"2"`
+	BreakageThreshold int      `env:"WEB_WATCHER_BREAKAGE_THRESHOLD" envDefault:"3"`
+	LatestItemCount   int      `env:"WEB_WATCHER_LATEST_ITEM_COUNT" envDefault:"5"`
+	AllowedURLSchemes []string `env:"WEB_WATCHER_ALLOWED_URL_SCHEMES" envDefault:"http,https" envSeparator:","`
+	URLAllowList      []string `env:"WEB_WATCHER_URL_ALLOW_LIST" envSeparator:","`
+	URLDenyList       []string `env:"WEB_WATCHER_URL_DENY_LIST" envSeparator:","`
 }
 
 func LoadAPIConfig() (*APIConfig, error) {
@@ -87,6 +124,7 @@ func LoadWorkerConfig() (*WorkerConfig, error) {
 	loadConfigFuncs := []func() error{
 		func() error { return env.Parse(&conf) },
 		func() error { return env.Parse(&conf.BinConfig) },
+		func() error { return env.Parse(&conf.ArchiveConfig) },
 		func() error { return env.Parse(&conf.DatabaseConfig) },
 		func() error { return env.Parse(&conf.TraceConfig) },
 		func() error { return env.Parse(&conf.WebsiteConfig) },
@@ -100,3 +138,21 @@ func LoadWorkerConfig() (*WorkerConfig, error) {
 
 	return &conf, nil
 }
+
+func LoadDatabaseConfig() (*DatabaseConfig, error) {
+	var conf DatabaseConfig
+	if err := env.Parse(&conf); err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+
+	return &conf, nil
+}
+
+func LoadArchiveConfig() (*ArchiveConfig, error) {
+	var conf ArchiveConfig
+	if err := env.Parse(&conf); err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+
+	return &conf, nil
+}
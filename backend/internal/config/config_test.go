@@ -41,10 +41,16 @@ func Test_LoadAPIConfig(t *testing.T) {
 			},
 			expectedConf: &APIConfig{
 				BinConfig: APIBinConfig{
-					ReadTimeout:    5 * time.Second,
-					WriteTimeout:   5 * time.Second,
-					IdleTimeout:    5 * time.Second,
-					APIRoutePrefix: "/api/web-watcher",
+					ReadTimeout:     5 * time.Second,
+					WriteTimeout:    5 * time.Second,
+					IdleTimeout:     5 * time.Second,
+					APIRoutePrefix:  "/api/web-watcher",
+					StrictGroupName: true,
+					MetricsAddr:     ":9091",
+				},
+				TraceConfig: TraceConfig{
+					TraceExporter: "otlp-grpc",
+					TraceInsecure: true,
 				},
 				DatabaseConfig: DatabaseConfig{
 					Driver:   "postgres",
@@ -55,11 +61,22 @@ func Test_LoadAPIConfig(t *testing.T) {
 					Database: "name",
 				},
 				UserServiceConfig: UserServiceConfig{
-					Addr: "user_serv_addr", Token: "user_serv_token",
+					Authenticator:    "grpc",
+					Addr:             "user_serv_addr",
+					Token:            "user_serv_token",
+					Timeout:          3 * time.Second,
+					BreakerThreshold: 5,
+					BreakerCooldown:  30 * time.Second,
+					CacheTTL:         time.Minute,
+					NegativeCacheTTL: 10 * time.Second,
+					JWTTTL:           24 * time.Hour,
 				},
 				WebsiteConfig: WebsiteConfig{
-					Separator:     "\n",
-					MaxDateLength: 2,
+					Separator:         "\n",
+					MaxDateLength:     2,
+					BreakageThreshold: 3,
+					LatestItemCount:   5,
+					AllowedURLSchemes: []string{"http", "https"},
 				},
 			},
 			expectError: false,
@@ -67,23 +84,42 @@ func Test_LoadAPIConfig(t *testing.T) {
 		{
 			name: "happy flow without default",
 			envMap: map[string]string{
-				"WEB_WATCHER_SEPARATOR":        ",",
-				"WEB_WATCHER_DATE_MAX_LENGTH":  "10",
-				"ADDR":                         "addr",
-				"API_READ_TIMEOUT":             "1s",
-				"API_WRITE_TIMEOUT":            "1s",
-				"API_IDLE_TIMEOUT":             "1s",
-				"WEB_WATCHER_API_ROUTE_PREFIX": "prefix",
-				"TRACE_URL":                    "trace_url",
-				"TRACE_SERVICE_NAME":           "trace_service_name",
-				"DRIVER":                       "driver",
-				"PSQL_HOST":                    "host",
-				"PSQL_PORT":                    "port",
-				"PSQL_USER":                    "user",
-				"PSQL_PASSWORD":                "password",
-				"PSQL_NAME":                    "name",
-				"USER_SERVICE_ADDR":            "user_serv_addr",
-				"USER_SERVICE_TOKEN":           "user_serv_token",
+				"WEB_WATCHER_SEPARATOR":           ",",
+				"WEB_WATCHER_DATE_MAX_LENGTH":     "10",
+				"WEB_WATCHER_BREAKAGE_THRESHOLD":  "5",
+				"WEB_WATCHER_LATEST_ITEM_COUNT":   "10",
+				"WEB_WATCHER_ALLOWED_URL_SCHEMES": "https",
+				"WEB_WATCHER_URL_ALLOW_LIST":      "intranet.local,10.1.0.0/16",
+				"WEB_WATCHER_URL_DENY_LIST":       "*.internal",
+				"ADDR":                            "addr",
+				"API_READ_TIMEOUT":                "1s",
+				"API_WRITE_TIMEOUT":               "1s",
+				"API_IDLE_TIMEOUT":                "1s",
+				"WEB_WATCHER_API_ROUTE_PREFIX":    "prefix",
+				"WEB_WATCHER_STRICT_GROUP_NAME":   "false",
+				"API_METRICS_ADDR":                "metrics_addr",
+				"TRACE_URL":                       "trace_url",
+				"TRACE_SERVICE_NAME":              "trace_service_name",
+				"TRACE_EXPORTER":                  "stdout",
+				"TRACE_INSECURE":                  "false",
+				"DRIVER":                          "driver",
+				"PSQL_HOST":                       "host",
+				"PSQL_PORT":                       "port",
+				"PSQL_USER":                       "user",
+				"PSQL_PASSWORD":                   "password",
+				"PSQL_NAME":                       "name",
+				"USER_SERVICE_ADDR":               "user_serv_addr",
+				"USER_SERVICE_TOKEN":              "user_serv_token",
+				"AUTHENTICATOR":                   "local",
+				"AUTH_JWT_SECRET":                 "jwt_secret",
+				"AUTH_JWT_TTL":                    "1h",
+				"AUTH_ALLOW_REGISTRATION":         "true",
+				"AUTH_ADMIN_USERS":                "admin_1,admin_2",
+				"USER_SERVICE_TIMEOUT":            "1s",
+				"USER_SERVICE_BREAKER_THRESHOLD":  "3",
+				"USER_SERVICE_BREAKER_COOLDOWN":   "1m",
+				"AUTH_CACHE_TTL":                  "5m",
+				"AUTH_NEGATIVE_CACHE_TTL":         "1m",
 			},
 			expectedConf: &APIConfig{
 				BinConfig: APIBinConfig{
@@ -92,10 +128,12 @@ func Test_LoadAPIConfig(t *testing.T) {
 					WriteTimeout:   1 * time.Second,
 					IdleTimeout:    1 * time.Second,
 					APIRoutePrefix: "prefix",
+					MetricsAddr:    "metrics_addr",
 				},
 				TraceConfig: TraceConfig{
 					TraceURL:         "trace_url",
 					TraceServiceName: "trace_service_name",
+					TraceExporter:    "stdout",
 				},
 				DatabaseConfig: DatabaseConfig{
 					Driver:   "driver",
@@ -106,11 +144,27 @@ func Test_LoadAPIConfig(t *testing.T) {
 					Database: "name",
 				},
 				UserServiceConfig: UserServiceConfig{
-					Addr: "user_serv_addr", Token: "user_serv_token",
+					Authenticator:     "local",
+					Addr:              "user_serv_addr",
+					Token:             "user_serv_token",
+					Timeout:           time.Second,
+					BreakerThreshold:  3,
+					BreakerCooldown:   time.Minute,
+					CacheTTL:          5 * time.Minute,
+					NegativeCacheTTL:  time.Minute,
+					JWTSecret:         "jwt_secret",
+					JWTTTL:            time.Hour,
+					AllowRegistration: true,
+					AdminUsers:        []string{"admin_1", "admin_2"},
 				},
 				WebsiteConfig: WebsiteConfig{
-					Separator:     ",",
-					MaxDateLength: 10,
+					Separator:         ",",
+					MaxDateLength:     10,
+					BreakageThreshold: 5,
+					LatestItemCount:   10,
+					AllowedURLSchemes: []string{"https"},
+					URLAllowList:      []string{"intranet.local", "10.1.0.0/16"},
+					URLDenyList:       []string{"*.internal"},
 				},
 			},
 			expectError: false,
@@ -162,6 +216,19 @@ func Test_LoadWorkerConfig(t *testing.T) {
 				BinConfig: WorkerBinConfig{
 					WebsiteUpdateSleepInterval: 10 * time.Second,
 					WorkerExecutorCount:        10,
+					MetricsAddr:                ":9090",
+					UpdateRequestPollInterval:  5 * time.Second,
+					UpdateRequestBatchSize:     10,
+					HeartbeatInterval:          30 * time.Second,
+				},
+				ArchiveConfig: ArchiveConfig{
+					Dir:           "/archive",
+					S3Region:      "us-east-1",
+					PruneInterval: 24 * time.Hour,
+				},
+				TraceConfig: TraceConfig{
+					TraceExporter: "otlp-grpc",
+					TraceInsecure: true,
 				},
 				DatabaseConfig: DatabaseConfig{
 					Driver:   "postgres",
@@ -172,8 +239,11 @@ func Test_LoadWorkerConfig(t *testing.T) {
 					Database: "name",
 				},
 				WebsiteConfig: WebsiteConfig{
-					Separator:     "\n",
-					MaxDateLength: 2,
+					Separator:         "\n",
+					MaxDateLength:     2,
+					BreakageThreshold: 3,
+					LatestItemCount:   5,
+					AllowedURLSchemes: []string{"http", "https"},
 				},
 			},
 			expectError: false,
@@ -181,27 +251,62 @@ func Test_LoadWorkerConfig(t *testing.T) {
 		{
 			name: "happy flow without default",
 			envMap: map[string]string{
-				"WEB_WATCHER_SEPARATOR":         ",",
-				"WEB_WATCHER_DATE_MAX_LENGTH":   "10",
-				"WEBSITE_UPDATE_SLEEP_INTERVAL": "10s",
-				"WORKER_EXECUTOR_COUNT":         "10",
-				"TRACE_URL":                     "trace_url",
-				"TRACE_SERVICE_NAME":            "trace_service_name",
-				"DRIVER":                        "driver",
-				"PSQL_HOST":                     "host",
-				"PSQL_PORT":                     "port",
-				"PSQL_USER":                     "user",
-				"PSQL_PASSWORD":                 "password",
-				"PSQL_NAME":                     "name",
+				"WEB_WATCHER_SEPARATOR":                ",",
+				"WEB_WATCHER_DATE_MAX_LENGTH":          "10",
+				"WEB_WATCHER_BREAKAGE_THRESHOLD":       "5",
+				"WEB_WATCHER_LATEST_ITEM_COUNT":        "10",
+				"WEBSITE_UPDATE_SLEEP_INTERVAL":        "10s",
+				"WORKER_EXECUTOR_COUNT":                "10",
+				"WORKER_METRICS_ADDR":                  "metrics_addr",
+				"WEBSITE_UPDATE_REQUEST_POLL_INTERVAL": "1s",
+				"WEBSITE_UPDATE_REQUEST_BATCH_SIZE":    "20",
+				"WORKER_HEARTBEAT_INTERVAL":            "1m",
+				"ARCHIVE_DRIVER":                       "s3",
+				"ARCHIVE_DIR":                          "archive_dir",
+				"ARCHIVE_S3_ENDPOINT":                  "s3_endpoint",
+				"ARCHIVE_S3_REGION":                    "s3_region",
+				"ARCHIVE_S3_BUCKET":                    "s3_bucket",
+				"ARCHIVE_S3_ACCESS_KEY":                "s3_access_key",
+				"ARCHIVE_S3_SECRET_KEY":                "s3_secret_key",
+				"ARCHIVE_MAX_AGE":                      "720h",
+				"ARCHIVE_MAX_SNAPSHOTS":                "5",
+				"ARCHIVE_PRUNE_INTERVAL":               "1h",
+				"TRACE_URL":                            "trace_url",
+				"TRACE_SERVICE_NAME":                   "trace_service_name",
+				"TRACE_EXPORTER":                       "stdout",
+				"TRACE_INSECURE":                       "false",
+				"DRIVER":                               "driver",
+				"PSQL_HOST":                            "host",
+				"PSQL_PORT":                            "port",
+				"PSQL_USER":                            "user",
+				"PSQL_PASSWORD":                        "password",
+				"PSQL_NAME":                            "name",
 			},
 			expectedConf: &WorkerConfig{
 				BinConfig: WorkerBinConfig{
 					WebsiteUpdateSleepInterval: 10 * time.Second,
 					WorkerExecutorCount:        10,
+					MetricsAddr:                "metrics_addr",
+					UpdateRequestPollInterval:  time.Second,
+					UpdateRequestBatchSize:     20,
+					HeartbeatInterval:          time.Minute,
+				},
+				ArchiveConfig: ArchiveConfig{
+					Driver:        "s3",
+					Dir:           "archive_dir",
+					S3Endpoint:    "s3_endpoint",
+					S3Region:      "s3_region",
+					S3Bucket:      "s3_bucket",
+					S3AccessKey:   "s3_access_key",
+					S3SecretKey:   "s3_secret_key",
+					MaxAge:        720 * time.Hour,
+					MaxSnapshots:  5,
+					PruneInterval: time.Hour,
 				},
 				TraceConfig: TraceConfig{
 					TraceURL:         "trace_url",
 					TraceServiceName: "trace_service_name",
+					TraceExporter:    "stdout",
 				},
 				DatabaseConfig: DatabaseConfig{
 					Driver:   "driver",
@@ -212,8 +317,11 @@ func Test_LoadWorkerConfig(t *testing.T) {
 					Database: "name",
 				},
 				WebsiteConfig: WebsiteConfig{
-					Separator:     ",",
-					MaxDateLength: 10,
+					Separator:         ",",
+					MaxDateLength:     10,
+					BreakageThreshold: 5,
+					LatestItemCount:   10,
+					AllowedURLSchemes: []string{"http", "https"},
 				},
 			},
 			expectError: false,
@@ -0,0 +1,48 @@
+package events
+
+import (
+	"context"
+	"time"
+)
+
+type Type string
+
+const (
+	WebsiteCreated       Type = "website_created"
+	WebsiteUpdated       Type = "website_updated"
+	WebsiteDeleted       Type = "website_deleted"
+	WebsiteHealthChanged Type = "health_changed"
+)
+
+// Event notifies change of website, event with UserUUID concerns the user
+// only, otherwise it concerns all users watching the website
+type Event struct {
+	Type        Type      `json:"type"`
+	WebsiteUUID string    `json:"website_uuid"`
+	UserUUID    string    `json:"user_uuid,omitempty"`
+	Health      string    `json:"health,omitempty"`
+	Time        time.Time `json:"time"`
+}
+
+func NewEvent(eventType Type, websiteUUID string) Event {
+	return Event{
+		Type:        eventType,
+		WebsiteUUID: websiteUUID,
+		Time:        time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+type Subscriber interface {
+	// Subscribe returns channel receiving events published afterward,
+	// the channel is closed once ctx is done
+	Subscribe(ctx context.Context) (<-chan Event, error)
+}
+
+type PubSub interface {
+	Publisher
+	Subscriber
+}
@@ -0,0 +1,91 @@
+package events
+
+import (
+	"context"
+	"flag"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	leak := flag.Bool("leak", false, "check for memory leaks")
+	flag.Parse()
+
+	if *leak {
+		goleak.VerifyTestMain(m)
+	} else {
+		os.Exit(m.Run())
+	}
+}
+
+func TestNewEvent(t *testing.T) {
+	t.Parallel()
+
+	event := NewEvent(WebsiteUpdated, "uuid")
+	assert.Equal(t, WebsiteUpdated, event.Type)
+	assert.Equal(t, "uuid", event.WebsiteUUID)
+	assert.WithinDuration(t, time.Now(), event.Time, time.Minute)
+}
+
+func TestInMemPubSub(t *testing.T) {
+	t.Parallel()
+
+	t.Run("deliver event to all subscribers", func(t *testing.T) {
+		t.Parallel()
+
+		pubsub := NewInMemPubSub()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sub1, err := pubsub.Subscribe(ctx)
+		assert.NoError(t, err)
+		sub2, err := pubsub.Subscribe(ctx)
+		assert.NoError(t, err)
+
+		event := Event{Type: WebsiteUpdated, WebsiteUUID: "uuid"}
+		assert.NoError(t, pubsub.Publish(context.Background(), event))
+
+		assert.Equal(t, event, <-sub1)
+		assert.Equal(t, event, <-sub2)
+	})
+
+	t.Run("close channel once context is done", func(t *testing.T) {
+		t.Parallel()
+
+		pubsub := NewInMemPubSub()
+		ctx, cancel := context.WithCancel(context.Background())
+
+		sub, err := pubsub.Subscribe(ctx)
+		assert.NoError(t, err)
+		cancel()
+
+		_, ok := <-sub
+		assert.False(t, ok)
+		assert.NoError(t, pubsub.Publish(context.Background(), Event{Type: WebsiteUpdated}))
+	})
+
+	t.Run("drop event for subscriber falling behind", func(t *testing.T) {
+		t.Parallel()
+
+		pubsub := NewInMemPubSub()
+		ctx, cancel := context.WithCancel(context.Background())
+
+		sub, err := pubsub.Subscribe(ctx)
+		assert.NoError(t, err)
+
+		for i := 0; i < subscriberBufferSize+1; i++ {
+			assert.NoError(t, pubsub.Publish(context.Background(), Event{Type: WebsiteUpdated}))
+		}
+		cancel()
+
+		count := 0
+		for range sub {
+			count++
+		}
+		assert.Equal(t, subscriberBufferSize, count)
+	})
+}
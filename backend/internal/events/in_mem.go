@@ -0,0 +1,55 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+// subscriberBufferSize is the number of events buffered for each subscriber,
+// events are dropped for subscriber falling behind instead of blocking publisher
+const subscriberBufferSize = 64
+
+// InMemPubSub delivers events to subscribers in the same process
+type InMemPubSub struct {
+	lock        sync.RWMutex
+	subscribers map[chan Event]struct{}
+}
+
+var _ PubSub = (*InMemPubSub)(nil)
+
+func NewInMemPubSub() *InMemPubSub {
+	return &InMemPubSub{subscribers: make(map[chan Event]struct{})}
+}
+
+func (pubsub *InMemPubSub) Publish(ctx context.Context, event Event) error {
+	pubsub.lock.RLock()
+	defer pubsub.lock.RUnlock()
+
+	for subscriber := range pubsub.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+		}
+	}
+
+	return nil
+}
+
+func (pubsub *InMemPubSub) Subscribe(ctx context.Context) (<-chan Event, error) {
+	subscriber := make(chan Event, subscriberBufferSize)
+
+	pubsub.lock.Lock()
+	pubsub.subscribers[subscriber] = struct{}{}
+	pubsub.lock.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		pubsub.lock.Lock()
+		delete(pubsub.subscribers, subscriber)
+		close(subscriber)
+		pubsub.lock.Unlock()
+	}()
+
+	return subscriber, nil
+}
@@ -0,0 +1,114 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/htchan/WebHistory/internal/sqlc"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// postgresChannel is the channel notified in NotifyWebsiteEvent query
+const postgresChannel = "website_events"
+
+// PostgresPublisher publishes events by postgres NOTIFY, so that events
+// published by worker are received by api
+type PostgresPublisher struct {
+	queries *sqlc.Queries
+}
+
+var _ Publisher = (*PostgresPublisher)(nil)
+
+func NewPostgresPublisher(db *sql.DB) *PostgresPublisher {
+	return &PostgresPublisher{queries: sqlc.New(db)}
+}
+
+func (publisher *PostgresPublisher) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal event fail: %w", err)
+	}
+
+	if err := publisher.queries.NotifyWebsiteEvent(ctx, string(payload)); err != nil {
+		return fmt.Errorf("notify event fail: %w", err)
+	}
+
+	return nil
+}
+
+// PostgresPubSub listens to events published by PostgresPublisher and
+// dispatches them to subscribers in the process
+type PostgresPubSub struct {
+	*PostgresPublisher
+	listener *pq.Listener
+	local    *InMemPubSub
+	stop     chan struct{}
+	wg       sync.WaitGroup
+}
+
+var _ PubSub = (*PostgresPubSub)(nil)
+
+func NewPostgresPubSub(db *sql.DB, connStr string) (*PostgresPubSub, error) {
+	listener := pq.NewListener(connStr, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Error().Err(err).Int("listener_event", int(ev)).Msg("website events listener error")
+		}
+	})
+	if err := listener.Listen(postgresChannel); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("listen website events fail: %w", err)
+	}
+
+	pubsub := &PostgresPubSub{
+		PostgresPublisher: NewPostgresPublisher(db),
+		listener:          listener,
+		local:             NewInMemPubSub(),
+		stop:              make(chan struct{}),
+	}
+
+	pubsub.wg.Add(1)
+	go pubsub.dispatch()
+
+	return pubsub, nil
+}
+
+func (pubsub *PostgresPubSub) dispatch() {
+	defer pubsub.wg.Done()
+
+	for {
+		select {
+		case <-pubsub.stop:
+			return
+		case notification := <-pubsub.listener.Notify:
+			// nil notification is sent after reconnect, events sent during
+			// disconnection are lost
+			if notification == nil {
+				continue
+			}
+
+			var event Event
+			if err := json.Unmarshal([]byte(notification.Extra), &event); err != nil {
+				log.Error().Err(err).Str("payload", notification.Extra).Msg("parse website event failed")
+				continue
+			}
+
+			pubsub.local.Publish(context.Background(), event)
+		}
+	}
+}
+
+func (pubsub *PostgresPubSub) Subscribe(ctx context.Context) (<-chan Event, error) {
+	return pubsub.local.Subscribe(ctx)
+}
+
+func (pubsub *PostgresPubSub) Close() error {
+	close(pubsub.stop)
+	pubsub.wg.Wait()
+
+	return pubsub.listener.Close()
+}
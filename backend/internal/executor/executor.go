@@ -4,8 +4,10 @@ import (
 	"context"
 	"reflect"
 	"sync"
+	"sync/atomic"
 
 	"github.com/google/uuid"
+	"github.com/htchan/WebHistory/internal/metrics"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
@@ -17,6 +19,8 @@ type ExecutorImpl struct {
 	workerWg      sync.WaitGroup
 	publisherWg   sync.WaitGroup
 	jobPublishers []JobTrigger
+	busyCount     atomic.Int32
+	queueDepth    atomic.Int32
 }
 
 var _ Executor = (*ExecutorImpl)(nil)
@@ -47,6 +51,8 @@ func (executor *ExecutorImpl) Start() {
 				}
 
 				jobQueue <- jobExec
+				executor.queueDepth.Store(int32(len(jobQueue)))
+				metrics.ExecutorQueueDepth.Set(float64(len(jobQueue)))
 			}
 		}()
 	}
@@ -65,6 +71,11 @@ func (executor *ExecutorImpl) Start() {
 						continue
 					}
 
+					executor.queueDepth.Store(int32(len(jobQueue)))
+					metrics.ExecutorQueueDepth.Set(float64(len(jobQueue)))
+					executor.busyCount.Add(1)
+					metrics.ExecutorBusyWorkers.Inc()
+
 					job, params := jobExec.Job, jobExec.Params
 
 					jobUUID := uuid.Must(uuid.NewUUID()).String()
@@ -82,6 +93,9 @@ func (executor *ExecutorImpl) Start() {
 					} else {
 						zerolog.Ctx(ctx).Info().Msg("execute job success")
 					}
+
+					executor.busyCount.Add(-1)
+					metrics.ExecutorBusyWorkers.Dec()
 				}
 			}
 		}()
@@ -92,6 +106,14 @@ func (executor *ExecutorImpl) Start() {
 	executor.workerWg.Wait()
 }
 
+func (executor *ExecutorImpl) Status() Status {
+	return Status{
+		ExecutorCount: executor.executorCount,
+		BusyExecutors: int(executor.busyCount.Load()),
+		QueueDepth:    int(executor.queueDepth.Load()),
+	}
+}
+
 func (executor *ExecutorImpl) Stop() error {
 	executor.publisherWg.Wait()
 
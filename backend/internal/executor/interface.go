@@ -8,6 +8,14 @@ type Executor interface {
 	Start()
 	Register(...JobTrigger)
 	Stop() error
+	Status() Status
+}
+
+// Status is the usage of executors at the moment
+type Status struct {
+	ExecutorCount int
+	BusyExecutors int
+	QueueDepth    int
 }
 
 type Job interface {
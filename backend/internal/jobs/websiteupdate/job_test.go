@@ -2,6 +2,8 @@ package websiteupdate
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/htchan/WebHistory/internal/model"
 	"github.com/htchan/WebHistory/internal/repository"
 	"github.com/htchan/WebHistory/internal/repository/mockrepo"
+	"github.com/htchan/WebHistory/internal/service"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -49,6 +52,20 @@ func TestNewJob(t *testing.T) {
 func TestJob_Execute(t *testing.T) {
 	t.Parallel()
 
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte(`<html><head><title>title</title></head><body><p class="date">1</p></body></html>`))
+	}))
+	t.Cleanup(server.Close)
+
+	service.SetURLPolicy(service.NewURLPolicy(&config.WebsiteConfig{URLAllowList: []string{"127.0.0.1"}}))
+	t.Cleanup(func() { service.SetURLPolicy(service.NewURLPolicy(&config.WebsiteConfig{})) })
+
+	setting := &model.WebsiteSetting{
+		Domain:               "127.0.0.1",
+		TitleGoquerySelector: "title",
+		DatesGoquerySelector: ".date",
+	}
+
 	type jobArgs struct {
 		getRepo       func(*gomock.Controller) repository.Repostory
 		sleepInterval time.Duration
@@ -66,14 +83,14 @@ func TestJob_Execute(t *testing.T) {
 		wantSleep time.Duration
 		wantError error
 	}{
-		// TODO: create interface and mock service to speed up this test
 		{
 			name: "happy flow",
 			jobArgs: jobArgs{
 				getRepo: func(c *gomock.Controller) repository.Repostory {
 					rpo := mockrepo.NewMockRepostory(c)
-					rpo.EXPECT().FindWebsiteSetting("google.com").
-						Return(&model.WebsiteSetting{}, nil)
+					rpo.EXPECT().FindWebsiteSetting("127.0.0.1").Return(setting, nil)
+					rpo.EXPECT().UpdateWebsite(gomock.Any()).Return(nil)
+					rpo.EXPECT().RenameWebsitePlaceholderGroups("uuid", server.URL, "title").Return(nil)
 
 					return rpo
 				},
@@ -85,7 +102,7 @@ func TestJob_Execute(t *testing.T) {
 				},
 				params: Params{
 					Web: &model.Website{
-						UUID: "uuid", URL: "https://google.com",
+						UUID: "uuid", URL: server.URL,
 						Conf: &config.WebsiteConfig{Separator: ","},
 					},
 					Cleanup: func() {},
@@ -94,6 +111,36 @@ func TestJob_Execute(t *testing.T) {
 			wantSleep: 100 * time.Millisecond,
 			wantError: nil,
 		},
+		{
+			name: "record parse failure of website setting",
+			jobArgs: jobArgs{
+				getRepo: func(c *gomock.Controller) repository.Repostory {
+					rpo := mockrepo.NewMockRepostory(c)
+					rpo.EXPECT().FindWebsiteSetting("127.0.0.1").
+						Return(&model.WebsiteSetting{Domain: "127.0.0.1"}, nil)
+					rpo.EXPECT().RecordWebsiteSettingParse("127.0.0.1", false, 3, gomock.Any()).
+						Return(&model.WebsiteSetting{Domain: "127.0.0.1", ConsecutiveFailures: 1}, false, nil)
+					rpo.EXPECT().UpdateWebsite(gomock.Any()).Return(nil)
+
+					return rpo
+				},
+				sleepInterval: 100 * time.Millisecond,
+			},
+			args: args{
+				getCtx: func() context.Context {
+					return context.WithValue(context.Background(), "job_uuid", "uuid")
+				},
+				params: Params{
+					Web: &model.Website{
+						UUID: "uuid", URL: server.URL,
+						Conf: &config.WebsiteConfig{Separator: ",", BreakageThreshold: 3},
+					},
+					Cleanup: func() {},
+				},
+			},
+			wantSleep: 100 * time.Millisecond,
+			wantError: nil,
+		},
 		{
 			name: "invalid params type",
 			jobArgs: jobArgs{
@@ -0,0 +1,101 @@
+package websiteupdate
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/htchan/WebHistory/internal/config"
+	"github.com/htchan/WebHistory/internal/repository"
+	"github.com/htchan/WebHistory/internal/telemetry"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestPoller deploys update job for websites requested out of schedule,
+// e.g. website just created by api
+type RequestPoller struct {
+	scheduler *Scheduler
+	rpo       repository.Repostory
+	interval  time.Duration
+	batchSize int
+	stop      chan struct{}
+	deployWg  sync.WaitGroup
+}
+
+func NewRequestPoller(scheduler *Scheduler, rpo repository.Repostory, conf *config.WorkerBinConfig) *RequestPoller {
+	return &RequestPoller{
+		scheduler: scheduler,
+		rpo:       rpo,
+		interval:  conf.UpdateRequestPollInterval,
+		batchSize: conf.UpdateRequestBatchSize,
+		stop:      make(chan struct{}),
+	}
+}
+
+func (poller *RequestPoller) Start() {
+	ticker := time.NewTicker(poller.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-poller.stop:
+			return
+		case <-ticker.C:
+			poller.poll()
+		}
+	}
+}
+
+// poll claims pending requests and deploys update job for each of them,
+// request of deleted website is dropped
+func (poller *RequestPoller) poll() {
+	logger := log.With().
+		Str("scheduler", "websiteupdate").
+		Str("operation", "poll-update-requests").
+		Logger()
+
+	reqs, err := poller.rpo.ClaimWebsiteUpdateRequests(poller.batchSize)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to claim website update requests")
+
+		return
+	}
+
+	for _, req := range reqs {
+		req := req
+
+		web, err := poller.rpo.FindWebsite(req.WebsiteUUID)
+		if err != nil {
+			logger.Error().Err(err).Str("website_uuid", req.WebsiteUUID).
+				Msg("failed to find requested website")
+
+			continue
+		}
+
+		params := Params{Web: web}
+		spanCtx := trace.SpanContextFromContext(
+			telemetry.ExtractTraceContext(context.Background(), req.TraceContext),
+		)
+		if spanCtx.IsValid() {
+			params.SpanContext = &spanCtx
+		}
+
+		poller.deployWg.Add(1)
+
+		go func() {
+			defer poller.deployWg.Done()
+			err := poller.scheduler.DeployJob(params)
+			if err != nil {
+				logger.Error().Err(err).Str("website", web.URL).
+					Msg("failed to deploy job to update requested website")
+			}
+		}()
+	}
+}
+
+func (poller *RequestPoller) Stop() error {
+	close(poller.stop)
+	poller.deployWg.Wait()
+	return nil
+}
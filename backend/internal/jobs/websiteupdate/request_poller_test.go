@@ -0,0 +1,138 @@
+package websiteupdate
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/htchan/WebHistory/internal/config"
+	"github.com/htchan/WebHistory/internal/executor"
+	"github.com/htchan/WebHistory/internal/model"
+	"github.com/htchan/WebHistory/internal/repository"
+	"github.com/htchan/WebHistory/internal/repository/mockrepo"
+	"github.com/htchan/WebHistory/internal/telemetry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRequestPoller(t *testing.T) {
+	t.Parallel()
+
+	scheduler := NewScheduler(nil, &config.WorkerBinConfig{})
+	conf := &config.WorkerBinConfig{UpdateRequestPollInterval: time.Second, UpdateRequestBatchSize: 5}
+
+	got := NewRequestPoller(scheduler, nil, conf)
+	assert.Equal(t, scheduler, got.scheduler)
+	assert.Equal(t, time.Second, got.interval)
+	assert.Equal(t, 5, got.batchSize)
+	assert.NotNil(t, got.stop)
+}
+
+func TestRequestPoller_poll(t *testing.T) {
+	t.Parallel()
+
+	_, err := telemetry.SetupTracerProvider(context.Background(), config.TraceConfig{TraceExporter: telemetry.ExporterNone})
+	if err != nil {
+		t.Fatalf("setup tracer provider fail: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		getRepo     func(*gomock.Controller) repository.Repostory
+		wantWebs    []string
+		wantTraceID string
+	}{
+		{
+			name: "deploy job for requested websites",
+			getRepo: func(c *gomock.Controller) repository.Repostory {
+				rpo := mockrepo.NewMockRepostory(c)
+				rpo.EXPECT().ClaimWebsiteUpdateRequests(10).Return([]model.WebsiteUpdateRequest{
+					{
+						WebsiteUUID: "1",
+						TraceContext: map[string]string{
+							"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+						},
+					},
+					{WebsiteUUID: "deleted"},
+				}, nil)
+				rpo.EXPECT().FindWebsite("1").Return(&model.Website{UUID: "1", URL: "http://testing.com/1"}, nil)
+				rpo.EXPECT().FindWebsite("deleted").Return(nil, errors.New("not found"))
+
+				return rpo
+			},
+			wantWebs:    []string{"1"},
+			wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name: "deploy nothing if claim failed",
+			getRepo: func(c *gomock.Controller) repository.Repostory {
+				rpo := mockrepo.NewMockRepostory(c)
+				rpo.EXPECT().ClaimWebsiteUpdateRequests(10).Return(nil, errors.New("some error"))
+
+				return rpo
+			},
+			wantWebs: nil,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			scheduler := &Scheduler{
+				stop:      make(chan struct{}),
+				jobChan:   make(executor.JobTrigger),
+				hostLocks: make(map[string]*sync.Mutex),
+			}
+			poller := NewRequestPoller(scheduler, test.getRepo(ctrl), &config.WorkerBinConfig{UpdateRequestBatchSize: 10})
+
+			var (
+				gotWebs     []string
+				gotTraceIDs []string
+			)
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for exec := range scheduler.jobChan {
+					params := exec.Params.(Params)
+					gotWebs = append(gotWebs, params.Web.UUID)
+					if params.SpanContext != nil {
+						gotTraceIDs = append(gotTraceIDs, params.SpanContext.TraceID().String())
+					}
+					params.Cleanup()
+				}
+			}()
+
+			poller.poll()
+			assert.NoError(t, poller.Stop())
+			close(scheduler.jobChan)
+			<-done
+
+			assert.Equal(t, test.wantWebs, gotWebs)
+			if test.wantTraceID != "" {
+				assert.Equal(t, []string{test.wantTraceID}, gotTraceIDs)
+			}
+		})
+	}
+}
+
+func TestRequestPoller_Stop(t *testing.T) {
+	t.Parallel()
+
+	poller := NewRequestPoller(nil, nil, &config.WorkerBinConfig{UpdateRequestPollInterval: time.Millisecond})
+	started := make(chan struct{})
+	go func() {
+		poller.Start()
+		close(started)
+	}()
+
+	assert.NoError(t, poller.Stop())
+	<-started
+}
@@ -12,3 +12,7 @@ func Setup(rpo repository.Repostory, conf *config.WorkerBinConfig) *Scheduler {
 
 	return scheduler
 }
+
+func SetupRequestPoller(rpo repository.Repostory, scheduler *Scheduler, conf *config.WorkerBinConfig) *RequestPoller {
+	return NewRequestPoller(scheduler, rpo, conf)
+}
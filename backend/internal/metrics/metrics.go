@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "webhistory"
+
+var (
+	HTTPRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of handled http requests by route.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of handled http requests by route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	ExecutorQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "executor",
+		Name:      "queue_depth",
+		Help:      "Number of jobs waiting for an idle worker.",
+	})
+
+	ExecutorBusyWorkers = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "executor",
+		Name:      "busy_workers",
+		Help:      "Number of workers executing a job.",
+	})
+
+	WebsiteFetchTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "website",
+		Name:      "fetch_total",
+		Help:      "Number of website fetches by host and response status.",
+	}, []string{"host", "status"})
+
+	WebsiteFetchDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "website",
+		Name:      "fetch_duration_seconds",
+		Help:      "Latency of website fetches by host.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"host"})
+
+	WebsiteUpdatesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "website",
+		Name:      "updates_total",
+		Help:      "Number of detected website updates by host.",
+	}, []string{"host"})
+
+	WebsiteParseFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "website",
+		Name:      "parse_failures_total",
+		Help:      "Number of responses parsed without title or dates by website setting domain.",
+	}, []string{"domain"})
+
+	WebsiteSettingBroken = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "website_setting",
+		Name:      "broken",
+		Help:      "Whether the website setting is flagged broken (1) or not (0) by domain.",
+	}, []string{"domain"})
+)
+
+// FetchStatusError is the status label of fetches failed before receiving a response
+const FetchStatusError = "error"
+
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// RegisterDBStats exposes sql.DBStats of db as gauges
+func RegisterDBStats(db *sql.DB, dbName string) error {
+	return prometheus.Register(collectors.NewDBStatsCollector(db, dbName))
+}
+
+func ObserveFetch(host string, statusCode int, duration time.Duration) {
+	status := FetchStatusError
+	if statusCode > 0 {
+		status = strconv.Itoa(statusCode)
+	}
+
+	WebsiteFetchTotal.WithLabelValues(host, status).Inc()
+	WebsiteFetchDuration.WithLabelValues(host).Observe(duration.Seconds())
+}
+
+// Middleware records request count and latency labelled by the matched chi route pattern
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(res http.ResponseWriter, req *http.Request) {
+			start := time.Now()
+			ww := middleware.NewWrapResponseWriter(res, req.ProtoMajor)
+			next.ServeHTTP(ww, req)
+
+			route := "unknown"
+			if rctx := chi.RouteContext(req.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			HTTPRequestsTotal.WithLabelValues(req.Method, route, strconv.Itoa(status)).Inc()
+			HTTPRequestDuration.WithLabelValues(req.Method, route).Observe(time.Since(start).Seconds())
+		},
+	)
+}
@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	leak := flag.Bool("leak", false, "check for memory leaks")
+	flag.Parse()
+
+	if *leak {
+		goleak.VerifyTestMain(m)
+	} else {
+		os.Exit(m.Run())
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		wantRoute   string
+		wantStatus  string
+		wantCounter float64
+	}{
+		{
+			name:        "label request by route pattern",
+			path:        "/websites/some-uuid",
+			wantRoute:   "/websites/{webUUID}",
+			wantStatus:  "200",
+			wantCounter: 1,
+		},
+		{
+			name:        "label unmatched request as unknown",
+			path:        "/not-exist",
+			wantRoute:   "unknown",
+			wantStatus:  "404",
+			wantCounter: 1,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.Use(Middleware)
+			r.Get("/websites/{webUUID}", func(res http.ResponseWriter, req *http.Request) {})
+
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(
+				t, test.wantCounter,
+				testutil.ToFloat64(HTTPRequestsTotal.WithLabelValues(http.MethodGet, test.wantRoute, test.wantStatus)),
+			)
+		})
+	}
+}
+
+func TestObserveFetch(t *testing.T) {
+	tests := []struct {
+		name       string
+		host       string
+		statusCode int
+		wantStatus string
+	}{
+		{
+			name:       "label fetch by status code",
+			host:       "observe-fetch.com",
+			statusCode: 200,
+			wantStatus: "200",
+		},
+		{
+			name:       "label fetch without response as error",
+			host:       "observe-fetch.com",
+			statusCode: 0,
+			wantStatus: FetchStatusError,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			ObserveFetch(test.host, test.statusCode, time.Second)
+
+			assert.Equal(
+				t, float64(1),
+				testutil.ToFloat64(WebsiteFetchTotal.WithLabelValues(test.host, test.wantStatus)),
+			)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"github.com/htchan/WebHistory/pkg/api"
+)
+
+// GroupPublicLink publishes group of owner to anyone knowing the token
+// without authentication
+type GroupPublicLink struct {
+	Token      string    `json:"token"`
+	OwnerUUID  string    `json:"-"`
+	GroupName  string    `json:"group_name"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+// NewGroupPublicLink generates an unguessable token for group of owner
+func NewGroupPublicLink(ownerUUID, groupName string) (GroupPublicLink, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return GroupPublicLink{}, err
+	}
+
+	return GroupPublicLink{
+		Token:      base64.RawURLEncoding.EncodeToString(secret),
+		OwnerUUID:  ownerUUID,
+		GroupName:  groupName,
+		CreateTime: time.Now().UTC().Truncate(time.Second),
+	}, nil
+}
+
+func (link GroupPublicLink) ToAPI() api.GroupPublicLink {
+	return api.GroupPublicLink{
+		Token:      link.Token,
+		GroupName:  link.GroupName,
+		CreateTime: link.CreateTime,
+	}
+}
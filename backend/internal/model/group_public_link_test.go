@@ -0,0 +1,29 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewGroupPublicLink(t *testing.T) {
+	t.Parallel()
+
+	link, err := NewGroupPublicLink("owner", "comics")
+	if err != nil {
+		t.Fatalf("NewGroupPublicLink() error = %v", err)
+	}
+	if len(link.Token) < 40 {
+		t.Errorf("got short token: %v", link.Token)
+	}
+	if link.OwnerUUID != "owner" || link.GroupName != "comics" {
+		t.Errorf("got link: %+v", link)
+	}
+	if time.Since(link.CreateTime) > time.Minute {
+		t.Errorf("got create time: %v", link.CreateTime)
+	}
+
+	another, _ := NewGroupPublicLink("owner", "comics")
+	if another.Token == link.Token {
+		t.Errorf("token is not random: %v", link.Token)
+	}
+}
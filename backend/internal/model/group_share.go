@@ -0,0 +1,89 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/htchan/WebHistory/pkg/api"
+)
+
+type GroupShareMode string
+
+// read only share is maintained by owner only, subscribers of
+// collaborative share can add and remove websites as well
+const (
+	GroupShareReadOnly      GroupShareMode = "read_only"
+	GroupShareCollaborative GroupShareMode = "collaborative"
+)
+
+var ErrInvalidGroupShareMode = errors.New("invalid group share mode")
+
+// GroupShare publishes group of owner to other users, websites of the
+// group are synced to the group of every subscriber
+type GroupShare struct {
+	UUID       string         `json:"uuid"`
+	OwnerUUID  string         `json:"owner_uuid"`
+	GroupName  string         `json:"group_name"`
+	Mode       GroupShareMode `json:"mode"`
+	CreateTime time.Time      `json:"create_time"`
+}
+
+// GroupShareSubscription links share to group of subscriber, which mirrors
+// the shared group
+type GroupShareSubscription struct {
+	ShareUUID  string    `json:"share_uuid"`
+	UserUUID   string    `json:"-"`
+	GroupName  string    `json:"group_name"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+func (share GroupShare) ToAPI() api.GroupShare {
+	return api.GroupShare{
+		UUID:       share.UUID,
+		OwnerUUID:  share.OwnerUUID,
+		GroupName:  share.GroupName,
+		Mode:       string(share.Mode),
+		CreateTime: share.CreateTime,
+	}
+}
+
+func (sub GroupShareSubscription) ToAPI() api.GroupShareSubscription {
+	return api.GroupShareSubscription{
+		ShareUUID:  sub.ShareUUID,
+		GroupName:  sub.GroupName,
+		CreateTime: sub.CreateTime,
+	}
+}
+
+func NewGroupShare(ownerUUID, groupName string, mode GroupShareMode) GroupShare {
+	return GroupShare{
+		UUID:       uuid.New().String(),
+		OwnerUUID:  ownerUUID,
+		GroupName:  groupName,
+		Mode:       mode,
+		CreateTime: time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+func NewGroupShareSubscription(shareUUID, userUUID, groupName string) GroupShareSubscription {
+	return GroupShareSubscription{
+		ShareUUID:  shareUUID,
+		UserUUID:   userUUID,
+		GroupName:  groupName,
+		CreateTime: time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+// ParseGroupShareMode parses mode of share, share is read only if mode is
+// not specified
+func ParseGroupShareMode(mode string) (GroupShareMode, error) {
+	switch GroupShareMode(mode) {
+	case "", GroupShareReadOnly:
+		return GroupShareReadOnly, nil
+	case GroupShareCollaborative:
+		return GroupShareCollaborative, nil
+	default:
+		return "", ErrInvalidGroupShareMode
+	}
+}
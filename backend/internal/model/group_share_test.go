@@ -0,0 +1,52 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewGroupShare(t *testing.T) {
+	t.Parallel()
+
+	share := NewGroupShare("owner", "comics", GroupShareCollaborative)
+	if share.UUID == "" {
+		t.Errorf("share uuid is empty")
+	}
+	if share.OwnerUUID != "owner" || share.GroupName != "comics" || share.Mode != GroupShareCollaborative {
+		t.Errorf("got share: %+v", share)
+	}
+	if time.Since(share.CreateTime) > time.Minute {
+		t.Errorf("got create time: %v", share.CreateTime)
+	}
+}
+
+func TestParseGroupShareMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		mode      string
+		want      GroupShareMode
+		wantError error
+	}{
+		{name: "default read only", mode: "", want: GroupShareReadOnly},
+		{name: "read only", mode: "read_only", want: GroupShareReadOnly},
+		{name: "collaborative", mode: "collaborative", want: GroupShareCollaborative},
+		{name: "unknown mode", mode: "public", wantError: ErrInvalidGroupShareMode},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseGroupShareMode(test.mode)
+			if err != test.wantError {
+				t.Errorf("got error: %v; want: %v", err, test.wantError)
+			}
+			if got != test.want {
+				t.Errorf("got mode: %v; want: %v", got, test.want)
+			}
+		})
+	}
+}
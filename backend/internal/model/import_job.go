@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// ImportJob is an import of user running in background, it is stored so that
+// its status can be queried from any api instance. Results keeps the json
+// encoded results of the import once it completes
+type ImportJob struct {
+	ID         string
+	UserUUID   string
+	Status     string
+	Total      int
+	Results    string
+	Error      string
+	CreateTime time.Time
+	FinishTime time.Time
+}
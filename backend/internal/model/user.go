@@ -0,0 +1,96 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/htchan/WebHistory/pkg/api"
+)
+
+const (
+	MinPasswordLength = 8
+	MaxUsernameLength = 64
+	// APITokenPrefix distinguishes personal api token from login token
+	APITokenPrefix = "wh_"
+)
+
+var (
+	ErrInvalidUsername = errors.New("invalid username")
+	ErrInvalidPassword = errors.New("invalid password")
+)
+
+// User is an account of the built-in local authentication
+type User struct {
+	UUID         string
+	Username     string
+	PasswordHash string
+	CreateTime   time.Time
+}
+
+// APIToken is a long-lived personal token of user, only the hash of
+// token is stored
+type APIToken struct {
+	UUID       string    `json:"uuid"`
+	UserUUID   string    `json:"-"`
+	Name       string    `json:"name"`
+	TokenHash  string    `json:"-"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+func (token APIToken) ToAPI() api.APIToken {
+	return api.APIToken{
+		UUID:       token.UUID,
+		Name:       token.Name,
+		CreateTime: token.CreateTime,
+	}
+}
+
+// NormalizeUsername trims username and checks if it is a valid username
+func NormalizeUsername(username string) (string, error) {
+	username = strings.TrimSpace(username)
+	if username == "" || utf8.RuneCountInString(username) > MaxUsernameLength {
+		return "", ErrInvalidUsername
+	}
+
+	return username, nil
+}
+
+func NewUser(username, passwordHash string) User {
+	return User{
+		UUID:         uuid.New().String(),
+		Username:     username,
+		PasswordHash: passwordHash,
+		CreateTime:   time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+// NewAPIToken generates a random token for user, the token is returned
+// alongside its record as it cannot be recovered from the record
+func NewAPIToken(userUUID, name string) (APIToken, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return APIToken{}, "", err
+	}
+
+	token := APITokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	return APIToken{
+		UUID:       uuid.New().String(),
+		UserUUID:   userUUID,
+		Name:       name,
+		TokenHash:  HashAPIToken(token),
+		CreateTime: time.Now().UTC().Truncate(time.Second),
+	}, token, nil
+}
+
+func HashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeUsername(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		username  string
+		want      string
+		wantError error
+	}{
+		{name: "trim spaces", username: " user ", want: "user"},
+		{name: "empty username", username: " ", wantError: ErrInvalidUsername},
+		{name: "too long username", username: strings.Repeat("a", MaxUsernameLength+1), wantError: ErrInvalidUsername},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NormalizeUsername(test.username)
+			if err != test.wantError {
+				t.Errorf("got error: %v; want: %v", err, test.wantError)
+			}
+			if got != test.want {
+				t.Errorf("got username: %v; want: %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNewAPIToken(t *testing.T) {
+	t.Parallel()
+
+	apiToken, token, err := NewAPIToken("user-uuid", "cli")
+	if err != nil {
+		t.Fatalf("got error: %v", err)
+	}
+	if !strings.HasPrefix(token, APITokenPrefix) {
+		t.Errorf("got token without prefix: %v", token)
+	}
+	if apiToken.TokenHash != HashAPIToken(token) || apiToken.TokenHash == token {
+		t.Errorf("got token hash: %v", apiToken.TokenHash)
+	}
+	if apiToken.UserUUID != "user-uuid" || apiToken.Name != "cli" || apiToken.UUID == "" {
+		t.Errorf("got api token: %+v", apiToken)
+	}
+
+	_, another, _ := NewAPIToken("user-uuid", "cli")
+	if another == token {
+		t.Errorf("got same token twice")
+	}
+}
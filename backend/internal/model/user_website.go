@@ -3,28 +3,116 @@ package model
 import (
 	"encoding/json"
 	"time"
+
+	"github.com/htchan/WebHistory/pkg/api"
 )
 
 type UserWebsite struct {
-	WebsiteUUID string
-	UserUUID    string
-	GroupName   string
-	AccessTime  time.Time
-	Website     Website
+	WebsiteUUID  string
+	UserUUID     string
+	GroupName    string
+	AccessTime   time.Time
+	LastReadItem string
+	SnoozeUntil  time.Time
+	Muted        bool
+	Tags         []string
+	ShareUUID    string
+	Website      Website
 }
 
 type UserWebsites []UserWebsite
 
+// NewUserWebsite groups website by its title, website not fetched yet is
+// grouped by placeholder until its title is known
 func NewUserWebsite(web Website, userUUID string) UserWebsite {
+	groupName := web.Title
+	if groupName == "" {
+		groupName = web.PlaceholderGroupName()
+	}
+
 	return UserWebsite{
 		WebsiteUUID: web.UUID,
 		UserUUID:    userUUID,
-		GroupName:   web.Title,
+		GroupName:   groupName,
 		AccessTime:  time.Now().UTC().Truncate(time.Second),
 		Website:     web,
 	}
 }
 
+func (web UserWebsite) HasTag(tag string) bool {
+	for _, t := range web.Tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FilterByTags keeps websites having all of the tags if matchAll is true,
+// otherwise keeps websites having any of the tags
+func (webs UserWebsites) FilterByTags(tags []string, matchAll bool) UserWebsites {
+	if len(tags) == 0 {
+		return webs
+	}
+
+	var result UserWebsites
+	for _, web := range webs {
+		if web.hasTags(tags, matchAll) {
+			result = append(result, web)
+		}
+	}
+
+	return result
+}
+
+func (web UserWebsite) hasTags(tags []string, matchAll bool) bool {
+	if len(tags) == 0 {
+		return true
+	}
+
+	matched := 0
+	for _, tag := range tags {
+		if web.HasTag(tag) {
+			matched++
+		}
+	}
+
+	return (matchAll && matched == len(tags)) || (!matchAll && matched > 0)
+}
+
+func (web UserWebsite) Snoozed(now time.Time) bool {
+	return web.SnoozeUntil.After(now)
+}
+
+// Notifiable reports if update of website should be notified to user,
+// snoozed website becomes notifiable again once the snooze expires
+func (web UserWebsite) Notifiable(now time.Time) bool {
+	return !web.Muted && !web.Snoozed(now)
+}
+
+// UnreadCount counts items seen after the last read item, or seen after
+// access time if user has not marked any existing item as read
+func (web UserWebsite) UnreadCount() int {
+	items := web.Website.sortedItems()
+	if web.LastReadItem != "" {
+		for i, item := range items {
+			if item.Key() == web.LastReadItem {
+				return i
+			}
+		}
+	}
+
+	count := 0
+	for _, item := range items {
+		if item.FirstSeen.After(web.AccessTime) {
+			count++
+		}
+	}
+
+	return count
+}
+
 func (webs UserWebsites) WebsiteGroups() WebsiteGroups {
 	indexMap := make(map[string]int)
 	var groups WebsiteGroups
@@ -42,30 +130,52 @@ func (webs UserWebsites) WebsiteGroups() WebsiteGroups {
 	return groups
 }
 
+// ToAPI converts website to the representation in API response
+func (web UserWebsite) ToAPI() api.UserWebsite {
+	var snoozeUntil string
+	if !web.SnoozeUntil.IsZero() {
+		snoozeUntil = web.SnoozeUntil.Format("2006-01-02T15:04:05 MST")
+	}
+
+	return api.UserWebsite{
+		UUID:         web.WebsiteUUID,
+		UserUUID:     web.UserUUID,
+		URL:          web.Website.URL,
+		Title:        web.Website.Title,
+		GroupName:    web.GroupName,
+		UpdateTime:   web.Website.UpdateTime.Format("2006-01-02T15:04:05 MST"),
+		AccessTime:   web.AccessTime.Format("2006-01-02T15:04:05 MST"),
+		Health:       web.Website.Health,
+		LastReadItem: web.LastReadItem,
+		SnoozeUntil:  snoozeUntil,
+		Muted:        web.Muted,
+		Tags:         web.Tags,
+		UnreadCount:  web.UnreadCount(),
+		Items:        itemsToAPI(web.Website.LatestItems()),
+	}
+}
+
+func (webs UserWebsites) ToAPI() []api.UserWebsite {
+	result := make([]api.UserWebsite, len(webs))
+	for i, web := range webs {
+		result[i] = web.ToAPI()
+	}
+
+	return result
+}
+
 func (web UserWebsite) MarshalJSON() ([]byte, error) {
-	return json.Marshal(&struct {
-		UUID       string `json:"uuid"`
-		UserUUID   string `json:"user_uuid"`
-		URL        string `json:"url"`
-		Title      string `json:"title"`
-		GroupName  string `json:"group_name"`
-		UpdateTime string `json:"update_time"`
-		AccessTime string `json:"access_time"`
-	}{
-		UUID:       web.WebsiteUUID,
-		UserUUID:   web.UserUUID,
-		URL:        web.Website.URL,
-		Title:      web.Website.Title,
-		GroupName:  web.GroupName,
-		UpdateTime: web.Website.UpdateTime.Format("2006-01-02T15:04:05 MST"),
-		AccessTime: web.AccessTime.Format("2006-01-02T15:04:05 MST"),
-	})
+	return json.Marshal(web.ToAPI())
 }
 
 func (web UserWebsite) Equal(compare UserWebsite) bool {
 	return web.UserUUID == compare.UserUUID &&
 		web.WebsiteUUID == compare.WebsiteUUID &&
 		web.GroupName == compare.GroupName &&
+		web.LastReadItem == compare.LastReadItem &&
+		web.SnoozeUntil.Unix()/1000 == compare.SnoozeUntil.Unix()/1000 &&
+		web.Muted == compare.Muted &&
+		web.ShareUUID == compare.ShareUUID &&
 		web.AccessTime.Unix()/1000 == compare.AccessTime.Unix()/1000 &&
 		web.Website.UUID == compare.Website.UUID &&
 		web.Website.URL == compare.Website.URL &&
@@ -0,0 +1,285 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WebsiteHealthUnknown filters websites which are not checked yet
+const WebsiteHealthUnknown = "unknown"
+
+const (
+	SortDefault    = ""
+	SortUpdateTime = "update_time"
+	SortAccessTime = "access_time"
+	SortTitle      = "title"
+)
+
+const MaxPageSize = 200
+
+var (
+	ErrInvalidFilter = errors.New("invalid filter")
+	ErrInvalidSort   = errors.New("invalid sort")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// UserWebsiteFilter narrows down websites of user, empty fields are not filtered
+type UserWebsiteFilter struct {
+	Search      string
+	Host        string
+	UpdatedOnly bool
+	Health      string
+	// Tags keeps websites having all of the tags if MatchAllTags is true,
+	// otherwise keeps websites having any of the tags
+	Tags         []string
+	MatchAllTags bool
+	// Groups keeps websites of any of the groups
+	Groups []string
+}
+
+func NewUserWebsiteFilter(search, host string, updatedOnly bool, health string) (UserWebsiteFilter, error) {
+	switch health {
+	case "", WebsiteHealthy, WebsiteUnhealthy, WebsitePending, WebsiteHealthUnknown:
+	default:
+		return UserWebsiteFilter{}, ErrInvalidFilter
+	}
+
+	return UserWebsiteFilter{
+		Search:      strings.TrimSpace(search),
+		Host:        strings.ToLower(strings.TrimSpace(host)),
+		UpdatedOnly: updatedOnly,
+		Health:      health,
+	}, nil
+}
+
+func (web UserWebsite) health() string {
+	if web.Website.Health == "" {
+		return WebsiteHealthUnknown
+	}
+
+	return web.Website.Health
+}
+
+// Match reports if website fulfills all conditions of filter, search is
+// matched case insensitively against title and url
+func (filter UserWebsiteFilter) Match(web UserWebsite) bool {
+	if filter.Search != "" {
+		search := strings.ToLower(filter.Search)
+		if !strings.Contains(strings.ToLower(web.Website.Title), search) &&
+			!strings.Contains(strings.ToLower(web.Website.URL), search) {
+			return false
+		}
+	}
+
+	if filter.Host != "" && !strings.EqualFold(web.Website.Hostname(), filter.Host) {
+		return false
+	}
+
+	if filter.UpdatedOnly && !web.Website.UpdateTime.After(web.AccessTime) {
+		return false
+	}
+
+	if filter.Health != "" && web.health() != filter.Health {
+		return false
+	}
+
+	if len(filter.Groups) > 0 && !slices.Contains(filter.Groups, web.GroupName) {
+		return false
+	}
+
+	return web.hasTags(filter.Tags, filter.MatchAllTags)
+}
+
+// UserWebsiteSort orders websites by key, the default order lists
+// notifiable updated websites first, then the most recently updated ones
+type UserWebsiteSort struct {
+	Key  string
+	Desc bool
+}
+
+// ParseUserWebsiteSort parses sort key, key prefixed by "-" is sorted descendingly
+func ParseUserWebsiteSort(s string) (UserWebsiteSort, error) {
+	order := UserWebsiteSort{Key: strings.TrimPrefix(s, "-"), Desc: strings.HasPrefix(s, "-")}
+	switch order.Key {
+	case SortUpdateTime, SortAccessTime, SortTitle:
+		return order, nil
+	case SortDefault:
+		if !order.Desc {
+			return order, nil
+		}
+	}
+
+	return UserWebsiteSort{}, ErrInvalidSort
+}
+
+// UserWebsiteCursor keeps the values of website used by sorting, it points
+// at the last website of a page
+type UserWebsiteCursor struct {
+	Unread     bool      `json:"unread,omitempty"`
+	UpdateTime time.Time `json:"update_time"`
+	AccessTime time.Time `json:"access_time"`
+	Title      string    `json:"title,omitempty"`
+	UUID       string    `json:"uuid"`
+}
+
+func NewUserWebsiteCursor(web UserWebsite, now time.Time) UserWebsiteCursor {
+	return UserWebsiteCursor{
+		Unread:     web.Website.UpdateTime.After(web.AccessTime) && web.Notifiable(now),
+		UpdateTime: web.Website.UpdateTime,
+		AccessTime: web.AccessTime,
+		Title:      web.Website.Title,
+		UUID:       web.WebsiteUUID,
+	}
+}
+
+// ParseUserWebsiteCursor decodes cursor returned by String, empty cursor
+// points at the start of websites and is returned as nil
+func ParseUserWebsiteCursor(s string) (*UserWebsiteCursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor UserWebsiteCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.UUID == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+func (cursor UserWebsiteCursor) String() string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// compare returns negative number if a is listed before b, website uuid
+// breaks the tie to keep the order stable across pages
+func (order UserWebsiteSort) compare(a, b UserWebsiteCursor) int {
+	result := 0
+	switch order.Key {
+	case SortUpdateTime:
+		result = a.UpdateTime.Compare(b.UpdateTime)
+	case SortAccessTime:
+		result = a.AccessTime.Compare(b.AccessTime)
+	case SortTitle:
+		result = strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+	default:
+		if a.Unread != b.Unread {
+			result = 1
+			if a.Unread {
+				result = -1
+			}
+		} else if result = -a.UpdateTime.Compare(b.UpdateTime); result == 0 {
+			result = -a.AccessTime.Compare(b.AccessTime)
+		}
+	}
+
+	if order.Desc {
+		result = -result
+	}
+	if result == 0 {
+		result = strings.Compare(a.UUID, b.UUID)
+	}
+
+	return result
+}
+
+func (webs UserWebsites) Sort(order UserWebsiteSort, now time.Time) {
+	sort.SliceStable(webs, func(i, j int) bool {
+		return order.compare(NewUserWebsiteCursor(webs[i], now), NewUserWebsiteCursor(webs[j], now)) < 0
+	})
+}
+
+// UserWebsitePage selects websites listed after cursor in sort order, Limit
+// <= 0 selects all remaining websites
+type UserWebsitePage struct {
+	Sort   UserWebsiteSort
+	Cursor *UserWebsiteCursor
+	Limit  int
+}
+
+// Page returns websites of page in sorted websites
+func (webs UserWebsites) Page(page UserWebsitePage, now time.Time) UserWebsites {
+	start := 0
+	if page.Cursor != nil {
+		start = sort.Search(len(webs), func(i int) bool {
+			return page.Sort.compare(*page.Cursor, NewUserWebsiteCursor(webs[i], now)) < 0
+		})
+	}
+
+	end := len(webs)
+	if page.Limit > 0 && start+page.Limit < end {
+		end = start + page.Limit
+	}
+
+	return webs[start:end]
+}
+
+// WebsiteGroupCursor points at the last group of a page, if the group is no
+// longer listed, next page starts at its position so that no group is skipped
+type WebsiteGroupCursor struct {
+	Group    string `json:"group"`
+	Position int    `json:"position"`
+}
+
+// ParseWebsiteGroupCursor decodes cursor returned by String, empty cursor
+// points at the start of groups and is returned as nil
+func ParseWebsiteGroupCursor(s string) (*WebsiteGroupCursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor WebsiteGroupCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Group == "" || cursor.Position < 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+func (cursor WebsiteGroupCursor) String() string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// WebsiteGroupPage selects groups listed after cursor, Limit <= 0 selects
+// all remaining groups
+type WebsiteGroupPage struct {
+	Cursor *WebsiteGroupCursor
+	Limit  int
+}
+
+// Page returns names of groups in page of ordered group names, and cursor of
+// next page if there are remaining groups
+func (page WebsiteGroupPage) Page(names []string) ([]string, *WebsiteGroupCursor) {
+	start := 0
+	if page.Cursor != nil {
+		start = slices.Index(names, page.Cursor.Group) + 1
+		if start == 0 {
+			start = min(page.Cursor.Position, len(names))
+		}
+	}
+
+	end := len(names)
+	if page.Limit <= 0 || start+page.Limit >= end {
+		return names[start:end], nil
+	}
+
+	end = start + page.Limit
+	return names[start:end], &WebsiteGroupCursor{Group: names[end-1], Position: end - 1}
+}
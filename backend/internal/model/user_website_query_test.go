@@ -0,0 +1,376 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUserWebsiteFilter_Match(t *testing.T) {
+	web := UserWebsite{
+		WebsiteUUID: "1",
+		AccessTime:  time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Website: Website{
+			URL:        "https://www.example.com/novel/1",
+			Title:      "My Novel",
+			UpdateTime: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+			Health:     WebsiteHealthy,
+		},
+	}
+
+	tests := []struct {
+		name   string
+		filter UserWebsiteFilter
+		web    UserWebsite
+		expect bool
+	}{
+		{
+			name:   "match all with empty filter",
+			filter: UserWebsiteFilter{},
+			web:    web,
+			expect: true,
+		},
+		{
+			name:   "match search in title case insensitively",
+			filter: UserWebsiteFilter{Search: "novel"},
+			web:    web,
+			expect: true,
+		},
+		{
+			name:   "match search in url",
+			filter: UserWebsiteFilter{Search: "example"},
+			web:    web,
+			expect: true,
+		},
+		{
+			name:   "not match search",
+			filter: UserWebsiteFilter{Search: "comic"},
+			web:    web,
+			expect: false,
+		},
+		{
+			name:   "match host",
+			filter: UserWebsiteFilter{Host: "www.example.com"},
+			web:    web,
+			expect: true,
+		},
+		{
+			name:   "not match other host",
+			filter: UserWebsiteFilter{Host: "example.com"},
+			web:    web,
+			expect: false,
+		},
+		{
+			name:   "not match updated only if website is read",
+			filter: UserWebsiteFilter{UpdatedOnly: true},
+			web:    UserWebsite{AccessTime: web.Website.UpdateTime, Website: web.Website},
+			expect: false,
+		},
+		{
+			name:   "match health",
+			filter: UserWebsiteFilter{Health: WebsiteHealthy},
+			web:    web,
+			expect: true,
+		},
+		{
+			name:   "match unknown health of unchecked website",
+			filter: UserWebsiteFilter{Health: WebsiteHealthUnknown},
+			web:    UserWebsite{},
+			expect: true,
+		},
+		{
+			name:   "match all tags",
+			filter: UserWebsiteFilter{Tags: []string{"a", "b"}, MatchAllTags: true},
+			web:    UserWebsite{Tags: []string{"a", "b", "c"}},
+			expect: true,
+		},
+		{
+			name:   "not match all tags",
+			filter: UserWebsiteFilter{Tags: []string{"a", "b"}, MatchAllTags: true},
+			web:    UserWebsite{Tags: []string{"a"}},
+			expect: false,
+		},
+		{
+			name:   "match any tag",
+			filter: UserWebsiteFilter{Tags: []string{"a", "b"}},
+			web:    UserWebsite{Tags: []string{"b"}},
+			expect: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			result := test.filter.Match(test.web)
+			if result != test.expect {
+				t.Errorf("got: %v; want: %v", result, test.expect)
+			}
+		})
+	}
+}
+
+func TestParseUserWebsiteSort(t *testing.T) {
+	tests := []struct {
+		name      string
+		sort      string
+		expect    UserWebsiteSort
+		expectErr error
+	}{
+		{
+			name:   "default sort",
+			sort:   "",
+			expect: UserWebsiteSort{},
+		},
+		{
+			name:   "descending sort",
+			sort:   "-update_time",
+			expect: UserWebsiteSort{Key: SortUpdateTime, Desc: true},
+		},
+		{
+			name:      "unknown sort key",
+			sort:      "url",
+			expectErr: ErrInvalidSort,
+		},
+		{
+			name:      "descending default sort",
+			sort:      "-",
+			expectErr: ErrInvalidSort,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			result, err := ParseUserWebsiteSort(test.sort)
+			if err != test.expectErr {
+				t.Errorf("got error: %v; want: %v", err, test.expectErr)
+			}
+			if result != test.expect {
+				t.Errorf("got: %v; want: %v", result, test.expect)
+			}
+		})
+	}
+}
+
+func TestUserWebsites_Sort(t *testing.T) {
+	now := time.Date(2020, 1, 10, 0, 0, 0, 0, time.UTC)
+	webs := UserWebsites{
+		{WebsiteUUID: "read", AccessTime: time.Date(2020, 1, 5, 0, 0, 0, 0, time.UTC), Website: Website{Title: "b", UpdateTime: time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC)}},
+		{WebsiteUUID: "muted", Muted: true, Website: Website{Title: "c", UpdateTime: time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)}},
+		{WebsiteUUID: "unread", Website: Website{Title: "A", UpdateTime: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)}},
+	}
+
+	tests := []struct {
+		name   string
+		order  UserWebsiteSort
+		expect []string
+	}{
+		{
+			name:   "default sort lists notifiable updated websites first",
+			order:  UserWebsiteSort{},
+			expect: []string{"unread", "read", "muted"},
+		},
+		{
+			name:   "sort by title",
+			order:  UserWebsiteSort{Key: SortTitle},
+			expect: []string{"unread", "read", "muted"},
+		},
+		{
+			name:   "sort by update time descendingly",
+			order:  UserWebsiteSort{Key: SortUpdateTime, Desc: true},
+			expect: []string{"read", "muted", "unread"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			sorted := append(UserWebsites{}, webs...)
+			sorted.Sort(test.order, now)
+			result := make([]string, len(sorted))
+			for i, web := range sorted {
+				result[i] = web.WebsiteUUID
+			}
+			if !cmp.Equal(result, test.expect) {
+				t.Errorf("got: %v; want: %v", result, test.expect)
+			}
+		})
+	}
+}
+
+func TestUserWebsites_Page(t *testing.T) {
+	now := time.Date(2020, 1, 10, 0, 0, 0, 0, time.UTC)
+	order := UserWebsiteSort{Key: SortTitle}
+	webs := UserWebsites{
+		{WebsiteUUID: "1", Website: Website{Title: "a"}},
+		{WebsiteUUID: "2", Website: Website{Title: "b"}},
+		{WebsiteUUID: "3", Website: Website{Title: "c"}},
+	}
+
+	page := webs.Page(UserWebsitePage{Sort: order, Limit: 2}, now)
+	if len(page) != 2 || page[1].WebsiteUUID != "2" {
+		t.Fatalf("got first page: %v", page)
+	}
+
+	// website of cursor is removed between requests
+	cursor := NewUserWebsiteCursor(page[1], now)
+	remaining := UserWebsites{webs[0], webs[2]}
+	page = remaining.Page(UserWebsitePage{Sort: order, Cursor: &cursor, Limit: 2}, now)
+	if len(page) != 1 || page[0].WebsiteUUID != "3" {
+		t.Fatalf("got second page: %v", page)
+	}
+}
+
+func TestParseUserWebsiteCursor(t *testing.T) {
+	cursor := UserWebsiteCursor{
+		Unread:     true,
+		UpdateTime: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+		AccessTime: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Title:      "title",
+		UUID:       "1",
+	}
+
+	tests := []struct {
+		name      string
+		cursor    string
+		expect    *UserWebsiteCursor
+		expectErr error
+	}{
+		{
+			name:   "empty cursor",
+			cursor: "",
+			expect: nil,
+		},
+		{
+			name:   "encoded cursor",
+			cursor: cursor.String(),
+			expect: &cursor,
+		},
+		{
+			name:      "invalid cursor",
+			cursor:    "invalid",
+			expectErr: ErrInvalidCursor,
+		},
+		{
+			name:      "cursor without uuid",
+			cursor:    UserWebsiteCursor{Title: "title"}.String(),
+			expectErr: ErrInvalidCursor,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			result, err := ParseUserWebsiteCursor(test.cursor)
+			if err != test.expectErr {
+				t.Errorf("got error: %v; want: %v", err, test.expectErr)
+			}
+			if !cmp.Equal(result, test.expect) {
+				t.Errorf("got: %v; want: %v", result, test.expect)
+			}
+		})
+	}
+}
+
+func TestWebsiteGroupPage_Page(t *testing.T) {
+	names := []string{"a", "b", "c"}
+
+	tests := []struct {
+		name         string
+		names        []string
+		page         WebsiteGroupPage
+		expect       []string
+		expectCursor *WebsiteGroupCursor
+	}{
+		{
+			name:         "first page",
+			names:        names,
+			page:         WebsiteGroupPage{Limit: 2},
+			expect:       []string{"a", "b"},
+			expectCursor: &WebsiteGroupCursor{Group: "b", Position: 1},
+		},
+		{
+			name:   "last page",
+			names:  names,
+			page:   WebsiteGroupPage{Cursor: &WebsiteGroupCursor{Group: "b", Position: 1}, Limit: 2},
+			expect: []string{"c"},
+		},
+		{
+			name:   "all groups without limit",
+			names:  names,
+			page:   WebsiteGroupPage{},
+			expect: names,
+		},
+		{
+			name:   "group of cursor is removed between requests",
+			names:  []string{"a", "c"},
+			page:   WebsiteGroupPage{Cursor: &WebsiteGroupCursor{Group: "b", Position: 1}, Limit: 2},
+			expect: []string{"c"},
+		},
+		{
+			name:   "position of removed group is out of range",
+			names:  []string{"a"},
+			page:   WebsiteGroupPage{Cursor: &WebsiteGroupCursor{Group: "b", Position: 1}, Limit: 2},
+			expect: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			result, cursor := test.page.Page(test.names)
+			if !cmp.Equal(result, test.expect) {
+				t.Errorf("got: %v; want: %v", result, test.expect)
+			}
+			if !cmp.Equal(cursor, test.expectCursor) {
+				t.Errorf("got cursor: %v; want: %v", cursor, test.expectCursor)
+			}
+		})
+	}
+}
+
+func TestParseWebsiteGroupCursor(t *testing.T) {
+	cursor := WebsiteGroupCursor{Group: "group", Position: 3}
+
+	tests := []struct {
+		name      string
+		cursor    string
+		expect    *WebsiteGroupCursor
+		expectErr error
+	}{
+		{
+			name:   "empty cursor",
+			cursor: "",
+			expect: nil,
+		},
+		{
+			name:   "encoded cursor",
+			cursor: cursor.String(),
+			expect: &cursor,
+		},
+		{
+			name:      "invalid cursor",
+			cursor:    "invalid",
+			expectErr: ErrInvalidCursor,
+		},
+		{
+			name:      "cursor without group",
+			cursor:    WebsiteGroupCursor{Position: 1}.String(),
+			expectErr: ErrInvalidCursor,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			result, err := ParseWebsiteGroupCursor(test.cursor)
+			if err != test.expectErr {
+				t.Errorf("got error: %v; want: %v", err, test.expectErr)
+			}
+			if !cmp.Equal(result, test.expect) {
+				t.Errorf("got: %v; want: %v", result, test.expect)
+			}
+		})
+	}
+}
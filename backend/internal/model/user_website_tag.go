@@ -0,0 +1,27 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+const MaxTagLength = 64
+
+var ErrInvalidTag = errors.New("invalid tag")
+
+type UserWebsiteTag struct {
+	UserUUID    string
+	WebsiteUUID string
+	Tag         string
+}
+
+// NormalizeTag trims tag and checks if it is a valid tag name
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" || utf8.RuneCountInString(tag) > MaxTagLength {
+		return "", ErrInvalidTag
+	}
+
+	return tag, nil
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		name      string
+		tag       string
+		expect    string
+		expectErr error
+	}{
+		{
+			name:   "trim spaces of tag",
+			tag:    "  novel ",
+			expect: "novel",
+		},
+		{
+			name:      "return error if tag is empty",
+			tag:       "   ",
+			expectErr: ErrInvalidTag,
+		},
+		{
+			name:      "return error if tag is too long",
+			tag:       strings.Repeat("a", MaxTagLength+1),
+			expectErr: ErrInvalidTag,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			result, err := NormalizeTag(test.tag)
+			if err != test.expectErr {
+				t.Errorf("got error: %v; want: %v", err, test.expectErr)
+			}
+			if result != test.expect {
+				t.Errorf("got: %v; want: %v", result, test.expect)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/htchan/WebHistory/internal/config"
 )
 
 func Test_NewUserWebsite(t *testing.T) {
@@ -24,6 +25,20 @@ func Test_NewUserWebsite(t *testing.T) {
 			expectedGroupName:  "",
 			expectedAccessTime: time.Now().UTC().Truncate(time.Second),
 		},
+		{
+			name:               "group by title",
+			web:                Website{UUID: "uuid", URL: "https://example.com", Title: "title"},
+			userUUID:           "user uuid",
+			expectedGroupName:  "title",
+			expectedAccessTime: time.Now().UTC().Truncate(time.Second),
+		},
+		{
+			name:               "group by placeholder if title is not fetched",
+			web:                Website{UUID: "uuid", URL: "https://example.com"},
+			userUUID:           "user uuid",
+			expectedGroupName:  "https://example.com",
+			expectedAccessTime: time.Now().UTC().Truncate(time.Second),
+		},
 	}
 
 	for _, test := range tests {
@@ -66,7 +81,27 @@ func TestUserWebsite_MarshalJSON(t *testing.T) {
 				GroupName:  "group",
 				AccessTime: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
 			},
-			expect: `{"uuid":"","user_uuid":"user uuid","url":"http://example.com","title":"title","group_name":"group","update_time":"2020-01-02T00:00:00 UTC","access_time":"2020-01-02T00:00:00 UTC"}`,
+			expect: `{"uuid":"","user_uuid":"user uuid","url":"http://example.com","title":"title","group_name":"group","update_time":"2020-01-02T00:00:00 UTC","access_time":"2020-01-02T00:00:00 UTC","unread_count":0}`,
+		},
+		{
+			name: "include latest items",
+			web: UserWebsite{
+				Website: Website{
+					UUID:       "uuid",
+					URL:        "http://example.com",
+					Title:      "title",
+					UpdateTime: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+					Items: []WebsiteItem{
+						{Name: "chapter 1", Link: "http://example.com/1", FirstSeen: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+						{Name: "chapter 2", Link: "http://example.com/2", FirstSeen: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+					},
+					Conf: &config.WebsiteConfig{LatestItemCount: 1},
+				},
+				UserUUID:   "user uuid",
+				GroupName:  "group",
+				AccessTime: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+			},
+			expect: `{"uuid":"","user_uuid":"user uuid","url":"http://example.com","title":"title","group_name":"group","update_time":"2020-01-02T00:00:00 UTC","access_time":"2020-01-02T00:00:00 UTC","unread_count":0,"items":[{"name":"chapter 2","link":"http://example.com/2","first_seen":"2020-01-02T00:00:00Z"}]}`,
 		},
 	}
 
@@ -124,3 +159,160 @@ func TestUserWebsites_WebsiteGroups(t *testing.T) {
 		})
 	}
 }
+
+func TestUserWebsite_UnreadCount(t *testing.T) {
+	items := []WebsiteItem{
+		{Name: "chapter 3", Link: "/3", FirstSeen: time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{Name: "chapter 2", Link: "/2", FirstSeen: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{Name: "chapter 1", Link: "/1", FirstSeen: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	tests := []struct {
+		name   string
+		web    UserWebsite
+		expect int
+	}{
+		{
+			name: "count items newer than last read item",
+			web: UserWebsite{
+				LastReadItem: "/1",
+				AccessTime:   time.Date(2020, 1, 5, 0, 0, 0, 0, time.UTC),
+				Website:      Website{Items: items},
+			},
+			expect: 2,
+		},
+		{
+			name: "zero if last read item is the latest",
+			web: UserWebsite{
+				LastReadItem: "/3",
+				Website:      Website{Items: items},
+			},
+			expect: 0,
+		},
+		{
+			name: "count items seen after access time without last read item",
+			web: UserWebsite{
+				AccessTime: time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC),
+				Website:    Website{Items: items},
+			},
+			expect: 2,
+		},
+		{
+			name: "count items seen after access time if last read item no longer listed",
+			web: UserWebsite{
+				LastReadItem: "/0",
+				AccessTime:   time.Date(2020, 1, 2, 12, 0, 0, 0, time.UTC),
+				Website:      Website{Items: items},
+			},
+			expect: 1,
+		},
+		{
+			name: "count items seen in the same fetch by their ordinal",
+			web: UserWebsite{
+				LastReadItem: "/4",
+				Website: Website{Items: []WebsiteItem{
+					{Name: "chapter 4", Link: "/4", FirstSeen: time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC), Ordinal: 1},
+					{Name: "chapter 3", Link: "/3", FirstSeen: time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC), Ordinal: 2},
+					{Name: "chapter 6", Link: "/6", FirstSeen: time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC)},
+					{Name: "chapter 5", Link: "/5", FirstSeen: time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC), Ordinal: 0},
+				}},
+			},
+			expect: 2,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			result := test.web.UnreadCount()
+			if result != test.expect {
+				t.Errorf("got: %v; want: %v", result, test.expect)
+			}
+		})
+	}
+}
+
+func TestUserWebsite_Notifiable(t *testing.T) {
+	now := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		web    UserWebsite
+		expect bool
+	}{
+		{
+			name:   "notifiable by default",
+			web:    UserWebsite{},
+			expect: true,
+		},
+		{
+			name:   "not notifiable if muted",
+			web:    UserWebsite{Muted: true},
+			expect: false,
+		},
+		{
+			name:   "not notifiable if snoozed",
+			web:    UserWebsite{SnoozeUntil: now.Add(time.Hour)},
+			expect: false,
+		},
+		{
+			name:   "notifiable if snooze expired",
+			web:    UserWebsite{SnoozeUntil: now.Add(-time.Hour)},
+			expect: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			result := test.web.Notifiable(now)
+			if result != test.expect {
+				t.Errorf("got: %v; want: %v", result, test.expect)
+			}
+		})
+	}
+}
+
+func TestUserWebsites_FilterByTags(t *testing.T) {
+	webs := UserWebsites{
+		{WebsiteUUID: "1", Tags: []string{"novel", "daily"}},
+		{WebsiteUUID: "2", Tags: []string{"novel"}},
+		{WebsiteUUID: "3"},
+	}
+
+	tests := []struct {
+		name     string
+		tags     []string
+		matchAll bool
+		expect   UserWebsites
+	}{
+		{
+			name:     "return all websites if no tag given",
+			tags:     nil,
+			matchAll: true,
+			expect:   webs,
+		},
+		{
+			name:     "keep websites having all tags",
+			tags:     []string{"novel", "daily"},
+			matchAll: true,
+			expect:   UserWebsites{webs[0]},
+		},
+		{
+			name:     "keep websites having any tags",
+			tags:     []string{"novel", "daily"},
+			matchAll: false,
+			expect:   UserWebsites{webs[0], webs[1]},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			result := webs.FilterByTags(test.tags, test.matchAll)
+			if !cmp.Equal(result, test.expect) {
+				t.Errorf("got: %v; want: %v", result, test.expect)
+			}
+		})
+	}
+}
@@ -3,21 +3,82 @@ package model
 import (
 	"encoding/json"
 	"net/url"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/htchan/WebHistory/internal/config"
+	"github.com/htchan/WebHistory/pkg/api"
 	"go.opentelemetry.io/otel/attribute"
 )
 
 type Website struct {
-	UUID       string    `json:"uuid"`
-	URL        string    `json:"url"`
-	Title      string    `json:"title"`
-	RawContent string    `json:"raw_content"`
-	UpdateTime time.Time `json:"update_time"`
-	Conf       *config.WebsiteConfig
+	UUID               string        `json:"uuid"`
+	URL                string        `json:"url"`
+	Title              string        `json:"title"`
+	RawContent         string        `json:"raw_content"`
+	PreviousRawContent string        `json:"previous_raw_content"`
+	Items              []WebsiteItem `json:"items"`
+	UpdateTime         time.Time     `json:"update_time"`
+	Health             string        `json:"health"`
+	Conf               *config.WebsiteConfig
+}
+
+// health of website, empty health means website is not checked yet,
+// pending means website is waiting for the first fetch after creation
+const (
+	WebsiteHealthy   = "healthy"
+	WebsiteUnhealthy = "unhealthy"
+	WebsitePending   = "pending"
+)
+
+// WebsiteItem is an entry (e.g. chapter / episode) listed in website
+type WebsiteItem struct {
+	Name      string    `json:"name,omitempty"`
+	Link      string    `json:"link,omitempty"`
+	Date      string    `json:"date,omitempty"`
+	FirstSeen time.Time `json:"first_seen"`
+	// Ordinal is position of item in the fetched list where it is first
+	// seen, it keeps the order of items first seen in the same fetch
+	Ordinal int `json:"ordinal,omitempty"`
+}
+
+// Key identifies item among items of website, link is preferred over name
+func (item WebsiteItem) Key() string {
+	if item.Link != "" {
+		return item.Link
+	}
+
+	return item.Name
+}
+
+func itemsToAPI(items []WebsiteItem) []api.WebsiteItem {
+	if len(items) == 0 {
+		return nil
+	}
+
+	result := make([]api.WebsiteItem, len(items))
+	for i, item := range items {
+		result[i] = api.WebsiteItem{
+			Name:      item.Name,
+			Link:      item.Link,
+			Date:      item.Date,
+			FirstSeen: item.FirstSeen,
+		}
+	}
+
+	return result
+}
+
+// ContentDiff lists the content entries added and removed by the latest update
+type ContentDiff struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+func (diff ContentDiff) ToAPI() api.ContentDiff {
+	return api.ContentDiff(diff)
 }
 
 func NewWebsite(url string, conf *config.WebsiteConfig) Website {
@@ -39,18 +100,19 @@ func (web Website) Map() map[string]interface{} {
 	}
 }
 
-func (web Website) MarshalJSON() ([]byte, error) {
-	return json.Marshal(&struct {
-		UUID       string `json:"uuid"`
-		URL        string `json:"url"`
-		Title      string `json:"title"`
-		UpdateTime string `json:"update_time"`
-	}{
+// ToAPI converts website to the representation in API response
+func (web Website) ToAPI() api.Website {
+	return api.Website{
 		UUID:       web.UUID,
 		URL:        web.URL,
 		Title:      web.Title,
 		UpdateTime: web.UpdateTime.Format("2006-01-02T15:04:05 MST"),
-	})
+		Items:      itemsToAPI(web.LatestItems()),
+	}
+}
+
+func (web Website) MarshalJSON() ([]byte, error) {
+	return json.Marshal(web.ToAPI())
 }
 
 func (web Website) Host() string {
@@ -62,15 +124,154 @@ func (web Website) Host() string {
 	return strings.Join(splitedHost[len(splitedHost)-2:], ".")
 }
 
+// PlaceholderGroupName names group of website until its title is fetched
+func (web Website) PlaceholderGroupName() string {
+	return web.URL
+}
+
+// Hostname returns the full host name in url of website
+func (web Website) Hostname() string {
+	u, err := url.Parse(web.URL)
+	if err != nil {
+		return ""
+	}
+
+	return u.Hostname()
+}
+
 func (web Website) Content() []string {
 	return strings.Split(web.RawContent, web.Conf.Separator)
 }
 
+func (web Website) PreviousContent() []string {
+	if web.PreviousRawContent == "" {
+		return nil
+	}
+
+	return strings.Split(web.PreviousRawContent, web.Conf.Separator)
+}
+
+// subtract returns entries of from which do not exist in entries, duplicated
+// entries are matched one by one
+func subtract(from, entries []string) []string {
+	count := make(map[string]int)
+	for _, entry := range entries {
+		count[entry]++
+	}
+
+	result := []string{}
+	for _, entry := range from {
+		if count[entry] > 0 {
+			count[entry]--
+			continue
+		}
+		result = append(result, entry)
+	}
+
+	return result
+}
+
+// Diff compares content with the content before the latest update
+func (web Website) Diff() ContentDiff {
+	var content []string
+	if web.RawContent != "" {
+		content = web.Content()
+	}
+	previous := web.PreviousContent()
+
+	return ContentDiff{
+		Added:   subtract(content, previous),
+		Removed: subtract(previous, content),
+	}
+}
+
+// MergeItems replaces items of website by parsed items, items already listed
+// keep their first seen time and ordinal, returns true if items changed
+func (web *Website) MergeItems(items []WebsiteItem, now time.Time) bool {
+	seen := make(map[string]WebsiteItem)
+	for _, item := range web.Items {
+		seen[item.Key()] = item
+	}
+
+	merged := make([]WebsiteItem, len(items))
+	for i, item := range items {
+		item.FirstSeen, item.Ordinal = now, i
+		if seenItem, ok := seen[item.Key()]; ok {
+			item.FirstSeen, item.Ordinal = seenItem.FirstSeen, seenItem.Ordinal
+		}
+		merged[i] = item
+	}
+
+	if itemsEqual(web.Items, merged) {
+		return false
+	}
+
+	web.Items = merged
+	return true
+}
+
+// sortedItems returns items ordered from the most recently seen, items seen
+// in the same fetch are kept in the order they were listed
+func (web Website) sortedItems() []WebsiteItem {
+	items := make([]WebsiteItem, len(web.Items))
+	copy(items, web.Items)
+	sort.SliceStable(items, func(i, j int) bool {
+		if !items[i].FirstSeen.Equal(items[j].FirstSeen) {
+			return items[i].FirstSeen.After(items[j].FirstSeen)
+		}
+		return items[i].Ordinal < items[j].Ordinal
+	})
+
+	return items
+}
+
+func (web Website) FindItem(key string) (WebsiteItem, bool) {
+	for _, item := range web.Items {
+		if item.Key() == key {
+			return item, true
+		}
+	}
+
+	return WebsiteItem{}, false
+}
+
+// LatestItems returns the most recently seen items, limited by LatestItemCount of config
+func (web Website) LatestItems() []WebsiteItem {
+	items := web.sortedItems()
+
+	if web.Conf != nil && web.Conf.LatestItemCount > 0 && len(items) > web.Conf.LatestItemCount {
+		items = items[:web.Conf.LatestItemCount]
+	}
+
+	return items
+}
+
+func itemsEqual(items, compare []WebsiteItem) bool {
+	if len(items) != len(compare) {
+		return false
+	}
+
+	for i := range items {
+		if items[i].Name != compare[i].Name ||
+			items[i].Link != compare[i].Link ||
+			items[i].Date != compare[i].Date ||
+			items[i].Ordinal != compare[i].Ordinal ||
+			!items[i].FirstSeen.Equal(compare[i].FirstSeen) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (web Website) Equal(compare Website) bool {
 	return web.UUID == compare.UUID &&
 		web.URL == compare.URL &&
 		web.Title == compare.Title &&
 		web.RawContent == compare.RawContent &&
+		web.PreviousRawContent == compare.PreviousRawContent &&
+		itemsEqual(web.Items, compare.Items) &&
+		web.Health == compare.Health &&
 		web.UpdateTime.Unix()/1000 == compare.UpdateTime.Unix()/1000
 }
 
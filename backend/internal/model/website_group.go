@@ -1,5 +1,88 @@
 package model
 
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const MaxGroupNameLength = 255
+
+var ErrInvalidGroupName = errors.New("invalid group name")
+
 type WebsiteGroup []UserWebsite
 
 type WebsiteGroups []WebsiteGroup
+
+func (group WebsiteGroup) UnreadCount() int {
+	count := 0
+	for _, web := range group {
+		count += web.UnreadCount()
+	}
+
+	return count
+}
+
+func (group WebsiteGroup) Name() string {
+	if len(group) == 0 {
+		return ""
+	}
+
+	return group[0].GroupName
+}
+
+// Sort moves groups listed in order to the front by their position in order,
+// groups not listed keep their original relative order
+func (groups WebsiteGroups) Sort(order []string) {
+	position := groupPositions(order)
+	sort.SliceStable(groups, func(i, j int) bool {
+		return position(groups[i].Name()) < position(groups[j].Name())
+	})
+}
+
+// SortGroupNames orders group names the same way as WebsiteGroups.Sort
+func SortGroupNames(names []string, order []string) {
+	position := groupPositions(order)
+	sort.SliceStable(names, func(i, j int) bool {
+		return position(names[i]) < position(names[j])
+	})
+}
+
+func groupPositions(order []string) func(string) int {
+	positions := make(map[string]int)
+	for i, name := range order {
+		positions[name] = i
+	}
+
+	return func(name string) int {
+		if pos, ok := positions[name]; ok {
+			return pos
+		}
+		return len(order)
+	}
+}
+
+// NormalizeGroupName trims group name and checks if it is not empty, not
+// too long and contains printable characters only
+func NormalizeGroupName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", ErrInvalidGroupName
+	}
+	if !utf8.ValidString(name) {
+		return "", fmt.Errorf("%w: invalid utf-8", ErrInvalidGroupName)
+	}
+	if utf8.RuneCountInString(name) > MaxGroupNameLength {
+		return "", fmt.Errorf("%w: longer than %d characters", ErrInvalidGroupName, MaxGroupNameLength)
+	}
+	for _, char := range name {
+		if !unicode.IsGraphic(char) {
+			return "", fmt.Errorf("%w: contains unprintable character", ErrInvalidGroupName)
+		}
+	}
+
+	return name, nil
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWebsiteGroups_Sort(t *testing.T) {
+	tests := []struct {
+		name   string
+		groups WebsiteGroups
+		order  []string
+		expect []string
+	}{
+		{
+			name: "keep original order without group order",
+			groups: WebsiteGroups{
+				{{GroupName: "b"}}, {{GroupName: "a"}}, {{GroupName: "c"}},
+			},
+			order:  nil,
+			expect: []string{"b", "a", "c"},
+		},
+		{
+			name: "move ordered groups to front",
+			groups: WebsiteGroups{
+				{{GroupName: "b"}}, {{GroupName: "a"}}, {{GroupName: "c"}}, {{GroupName: "d"}},
+			},
+			order:  []string{"c", "unknown", "a"},
+			expect: []string{"c", "a", "b", "d"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			test.groups.Sort(test.order)
+			names := make([]string, len(test.groups))
+			for i, group := range test.groups {
+				names[i] = group.Name()
+			}
+			if !cmp.Equal(names, test.expect) {
+				t.Errorf("got: %v; want: %v", names, test.expect)
+			}
+		})
+	}
+}
+
+func TestNormalizeGroupName(t *testing.T) {
+	tests := []struct {
+		name      string
+		groupName string
+		expect    string
+		expectErr error
+	}{
+		{
+			name:      "trim spaces of group name",
+			groupName: " my group  ",
+			expect:    "my group",
+		},
+		{
+			name:      "return error if group name is empty",
+			groupName: "  ",
+			expectErr: ErrInvalidGroupName,
+		},
+		{
+			name:      "accept unicode and punctuation",
+			groupName: "漫畫 / comics",
+			expect:    "漫畫 / comics",
+		},
+		{
+			name:      "return error if group name is too long",
+			groupName: strings.Repeat("a", MaxGroupNameLength+1),
+			expectErr: ErrInvalidGroupName,
+		},
+		{
+			name:      "return error if group name contains control character",
+			groupName: "my\x00group",
+			expectErr: ErrInvalidGroupName,
+		},
+		{
+			name:      "return error if group name is not utf-8",
+			groupName: "my\xffgroup",
+			expectErr: ErrInvalidGroupName,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			result, err := NormalizeGroupName(test.groupName)
+			if !errors.Is(err, test.expectErr) {
+				t.Errorf("got error: %v; want: %v", err, test.expectErr)
+			}
+			if result != test.expect {
+				t.Errorf("got: %v; want: %v", result, test.expect)
+			}
+		})
+	}
+}
@@ -2,17 +2,90 @@ package model
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/htchan/WebHistory/pkg/api"
 )
 
 type WebsiteSetting struct {
-	Domain               string
-	TitleGoquerySelector string
-	DatesGoquerySelector string
-	FocusIndexFrom       int
-	FocusIndexTo         int
+	Domain                  string    `json:"domain"`
+	TitleGoquerySelector    string    `json:"title_goquery_selector"`
+	DatesGoquerySelector    string    `json:"dates_goquery_selector"`
+	FocusIndexFrom          int       `json:"focus_index_from"`
+	FocusIndexTo            int       `json:"focus_index_to"`
+	ItemNameGoquerySelector string    `json:"item_name_goquery_selector,omitempty"`
+	ItemLinkGoquerySelector string    `json:"item_link_goquery_selector,omitempty"`
+	ConsecutiveFailures     int       `json:"consecutive_failures,omitempty"`
+	BrokenAt                time.Time `json:"broken_at"`
+}
+
+func (setting WebsiteSetting) ToAPI() api.WebsiteSetting {
+	var brokenAt *time.Time
+	if setting.IsBroken() {
+		brokenAt = &setting.BrokenAt
+	}
+
+	return api.WebsiteSetting{
+		Domain:                  setting.Domain,
+		TitleGoquerySelector:    setting.TitleGoquerySelector,
+		DatesGoquerySelector:    setting.DatesGoquerySelector,
+		FocusIndexFrom:          setting.FocusIndexFrom,
+		FocusIndexTo:            setting.FocusIndexTo,
+		ItemNameGoquerySelector: setting.ItemNameGoquerySelector,
+		ItemLinkGoquerySelector: setting.ItemLinkGoquerySelector,
+		ConsecutiveFailures:     setting.ConsecutiveFailures,
+		BrokenAt:                brokenAt,
+	}
+}
+
+func (setting *WebsiteSetting) IsBroken() bool {
+	return !setting.BrokenAt.IsZero()
+}
+
+// RecordParse counts consecutive parse failures of websites under the setting,
+// the setting is flagged broken once the count reaches threshold, and
+// recovers on the first successful parse
+func (setting *WebsiteSetting) RecordParse(success bool, threshold int, now time.Time) {
+	if success {
+		setting.ConsecutiveFailures = 0
+		setting.BrokenAt = time.Time{}
+		return
+	}
+
+	setting.ConsecutiveFailures++
+	if threshold > 0 && setting.ConsecutiveFailures >= threshold && !setting.IsBroken() {
+		setting.BrokenAt = now
+	}
+}
+
+// focus keeps entries between FocusIndexFrom and FocusIndexTo of setting
+func (setting *WebsiteSetting) focus(entries []string) []string {
+	fromN, toN := setting.FocusIndexFrom, setting.FocusIndexTo
+	if fromN < 0 {
+		fromN = len(entries) + fromN
+		if fromN < 0 {
+			fromN = 0
+		}
+	} else if fromN > len(entries) {
+		fromN = len(entries) - 1
+	}
+
+	if toN <= 0 {
+		toN = len(entries) + toN
+		if toN < 0 {
+			toN = len(entries)
+		}
+	} else if toN > len(entries) {
+		toN = len(entries)
+	}
+	if fromN <= toN {
+		entries = entries[fromN:toN]
+	}
+
+	return entries
 }
 
 func (setting *WebsiteSetting) Parse(response string) (string, []string) {
@@ -30,27 +103,69 @@ func (setting *WebsiteSetting) Parse(response string) (string, []string) {
 		dates = append(dates, strings.TrimSpace(s.Text()))
 	})
 
-	fromN, toN := setting.FocusIndexFrom, setting.FocusIndexTo
-	if fromN < 0 {
-		fromN = len(dates) + fromN
-		if fromN < 0 {
-			fromN = 0
-		}
-	} else if fromN > len(dates) {
-		fromN = len(dates) - 1
+	return title, setting.focus(dates)
+}
+
+// ParseItems extracts items by the optional item selectors of setting, item
+// links are resolved against baseURL and item dates are paired by index
+func (setting *WebsiteSetting) ParseItems(response string, baseURL string) []WebsiteItem {
+	if setting.ItemNameGoquerySelector == "" && setting.ItemLinkGoquerySelector == "" {
+		return nil
 	}
 
-	if toN <= 0 {
-		toN = len(dates) + toN
-		if toN < 0 {
-			toN = len(dates)
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(response))
+	if err != nil {
+		return nil
+	}
+
+	base, _ := url.Parse(baseURL)
+
+	var names, links, dates []string
+	if setting.ItemNameGoquerySelector != "" {
+		doc.Find(setting.ItemNameGoquerySelector).Each(func(i int, s *goquery.Selection) {
+			names = append(names, strings.TrimSpace(s.Text()))
+		})
+	}
+	if setting.ItemLinkGoquerySelector != "" {
+		doc.Find(setting.ItemLinkGoquerySelector).Each(func(i int, s *goquery.Selection) {
+			link, ok := s.Attr("href")
+			if !ok {
+				link = s.Text()
+			}
+			links = append(links, resolveLink(base, strings.TrimSpace(link)))
+		})
+	}
+	doc.Find(setting.DatesGoquerySelector).Each(func(i int, s *goquery.Selection) {
+		dates = append(dates, strings.TrimSpace(s.Text()))
+	})
+	names, links, dates = setting.focus(names), setting.focus(links), setting.focus(dates)
+
+	count := len(names)
+	if setting.ItemNameGoquerySelector == "" {
+		count = len(links)
+	}
+
+	items := make([]WebsiteItem, count)
+	for i := range items {
+		if i < len(names) {
+			items[i].Name = names[i]
+		}
+		if i < len(links) {
+			items[i].Link = links[i]
+		}
+		if i < len(dates) {
+			items[i].Date = dates[i]
 		}
-	} else if toN > len(dates) {
-		toN = len(dates)
 	}
-	if fromN <= toN {
-		dates = dates[fromN:toN]
+
+	return items
+}
+
+func resolveLink(base *url.URL, link string) string {
+	ref, err := url.Parse(link)
+	if err != nil || base == nil {
+		return link
 	}
 
-	return title, dates
+	return base.ResolveReference(ref).String()
 }
@@ -1,7 +1,9 @@
 package model
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -59,3 +61,162 @@ func TestWebsiteSetting_Parse(t *testing.T) {
 		})
 	}
 }
+
+func TestWebsiteSetting_RecordParse(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	before := now.Add(-time.Hour)
+
+	tests := []struct {
+		name          string
+		setting       *WebsiteSetting
+		success       bool
+		threshold     int
+		expectSetting *WebsiteSetting
+	}{
+		{
+			name:          "success reset failures count",
+			setting:       &WebsiteSetting{ConsecutiveFailures: 2},
+			success:       true,
+			threshold:     3,
+			expectSetting: &WebsiteSetting{},
+		},
+		{
+			name:          "success recover broken setting",
+			setting:       &WebsiteSetting{ConsecutiveFailures: 5, BrokenAt: before},
+			success:       true,
+			threshold:     3,
+			expectSetting: &WebsiteSetting{},
+		},
+		{
+			name:          "failure below threshold",
+			setting:       &WebsiteSetting{ConsecutiveFailures: 1},
+			success:       false,
+			threshold:     3,
+			expectSetting: &WebsiteSetting{ConsecutiveFailures: 2},
+		},
+		{
+			name:          "failure reach threshold",
+			setting:       &WebsiteSetting{ConsecutiveFailures: 2},
+			success:       false,
+			threshold:     3,
+			expectSetting: &WebsiteSetting{ConsecutiveFailures: 3, BrokenAt: now},
+		},
+		{
+			name:          "failure keep broken time of broken setting",
+			setting:       &WebsiteSetting{ConsecutiveFailures: 3, BrokenAt: before},
+			success:       false,
+			threshold:     3,
+			expectSetting: &WebsiteSetting{ConsecutiveFailures: 4, BrokenAt: before},
+		},
+		{
+			name:          "zero threshold never flag broken",
+			setting:       &WebsiteSetting{ConsecutiveFailures: 10},
+			success:       false,
+			threshold:     0,
+			expectSetting: &WebsiteSetting{ConsecutiveFailures: 11},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			test.setting.RecordParse(test.success, test.threshold, now)
+			assert.Equal(t, test.expectSetting, test.setting)
+		})
+	}
+}
+
+func TestWebsiteSetting_ParseItems(t *testing.T) {
+	t.Parallel()
+
+	resp := `<html><body>
+	<ul>
+		<li><a href="/book/3">chapter 3</a><span>03-01</span></li>
+		<li><a href="2">chapter 2</a><span>02-01</span></li>
+		<li><a href="https://other.com/1">chapter 1</a><span>01-01</span></li>
+	</ul>
+	</body></html>`
+
+	tests := []struct {
+		name    string
+		setting *WebsiteSetting
+		expect  []WebsiteItem
+	}{
+		{
+			name: "parse name, link and date of items",
+			setting: &WebsiteSetting{
+				DatesGoquerySelector:    "li>span",
+				ItemNameGoquerySelector: "li>a",
+				ItemLinkGoquerySelector: "li>a",
+			},
+			expect: []WebsiteItem{
+				{Name: "chapter 3", Link: "http://example.com/book/3", Date: "03-01"},
+				{Name: "chapter 2", Link: "http://example.com/book/2", Date: "02-01"},
+				{Name: "chapter 1", Link: "https://other.com/1", Date: "01-01"},
+			},
+		},
+		{
+			name: "parse link only",
+			setting: &WebsiteSetting{
+				ItemLinkGoquerySelector: "li>a",
+				FocusIndexTo:            1,
+			},
+			expect: []WebsiteItem{
+				{Link: "http://example.com/book/3"},
+			},
+		},
+		{
+			name:    "return nil without item selector",
+			setting: &WebsiteSetting{DatesGoquerySelector: "li>span"},
+			expect:  nil,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			items := test.setting.ParseItems(resp, "http://example.com/book/index")
+			assert.Equal(t, test.expect, items)
+		})
+	}
+}
+
+func TestWebsiteSetting_ToAPI(t *testing.T) {
+	t.Parallel()
+
+	brokenAt := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		setting WebsiteSetting
+		expect  string
+	}{
+		{
+			name:    "omit broken time of working setting",
+			setting: WebsiteSetting{Domain: "example.com"},
+			expect:  `{"domain":"example.com","title_goquery_selector":"","dates_goquery_selector":"","focus_index_from":0,"focus_index_to":0}`,
+		},
+		{
+			name:    "include broken time of broken setting",
+			setting: WebsiteSetting{Domain: "example.com", ConsecutiveFailures: 3, BrokenAt: brokenAt},
+			expect:  `{"domain":"example.com","title_goquery_selector":"","dates_goquery_selector":"","focus_index_from":0,"focus_index_to":0,"consecutive_failures":3,"broken_at":"2020-01-02T00:00:00Z"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := json.Marshal(test.setting.ToAPI())
+			assert.NoError(t, err)
+			assert.Equal(t, test.expect, string(result))
+		})
+	}
+}
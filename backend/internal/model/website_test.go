@@ -183,3 +183,178 @@ func TestWebsite_Content(t *testing.T) {
 		})
 	}
 }
+
+func TestWebsite_Diff(t *testing.T) {
+	conf := &config.WebsiteConfig{Separator: "\n"}
+	tests := []struct {
+		name   string
+		web    Website
+		expect ContentDiff
+	}{
+		{
+			name: "new entries added on top",
+			web: Website{
+				RawContent:         "3\n2\n1",
+				PreviousRawContent: "2\n1",
+				Conf:               conf,
+			},
+			expect: ContentDiff{Added: []string{"3"}, Removed: []string{}},
+		},
+		{
+			name: "entries added and removed",
+			web: Website{
+				RawContent:         "4\n3",
+				PreviousRawContent: "2\n1",
+				Conf:               conf,
+			},
+			expect: ContentDiff{Added: []string{"4", "3"}, Removed: []string{"2", "1"}},
+		},
+		{
+			name: "duplicated entries are compared one by one",
+			web: Website{
+				RawContent:         "1\n1\n2",
+				PreviousRawContent: "1\n2",
+				Conf:               conf,
+			},
+			expect: ContentDiff{Added: []string{"1"}, Removed: []string{}},
+		},
+		{
+			name: "every entry is added without previous content",
+			web: Website{
+				RawContent: "2\n1",
+				Conf:       conf,
+			},
+			expect: ContentDiff{Added: []string{"2", "1"}, Removed: []string{}},
+		},
+		{
+			name:   "empty diff without content",
+			web:    Website{Conf: conf},
+			expect: ContentDiff{Added: []string{}, Removed: []string{}},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			result := test.web.Diff()
+			if !cmp.Equal(result, test.expect) {
+				t.Errorf("got unexpected diff")
+				t.Error(result)
+				t.Error(test.expect)
+			}
+		})
+	}
+}
+
+func TestWebsite_MergeItems(t *testing.T) {
+	before := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		web         Website
+		items       []WebsiteItem
+		expect      bool
+		expectItems []WebsiteItem
+	}{
+		{
+			name: "new items are first seen now",
+			web: Website{Items: []WebsiteItem{
+				{Name: "chapter 1", Link: "http://example.com/1", FirstSeen: before},
+			}},
+			items: []WebsiteItem{
+				{Name: "chapter 2", Link: "http://example.com/2"},
+				{Name: "chapter 1", Link: "http://example.com/1"},
+			},
+			expect: true,
+			expectItems: []WebsiteItem{
+				{Name: "chapter 2", Link: "http://example.com/2", FirstSeen: now},
+				{Name: "chapter 1", Link: "http://example.com/1", FirstSeen: before},
+			},
+		},
+		{
+			name: "new items keep their position and listed items keep their ordinal",
+			web: Website{Items: []WebsiteItem{
+				{Name: "chapter 1", Link: "http://example.com/1", FirstSeen: before, Ordinal: 1},
+				{Name: "chapter 2", Link: "http://example.com/2", FirstSeen: before},
+			}},
+			items: []WebsiteItem{
+				{Name: "chapter 4", Link: "http://example.com/4"},
+				{Name: "chapter 3", Link: "http://example.com/3"},
+				{Name: "chapter 2", Link: "http://example.com/2"},
+				{Name: "chapter 1", Link: "http://example.com/1"},
+			},
+			expect: true,
+			expectItems: []WebsiteItem{
+				{Name: "chapter 4", Link: "http://example.com/4", FirstSeen: now},
+				{Name: "chapter 3", Link: "http://example.com/3", FirstSeen: now, Ordinal: 1},
+				{Name: "chapter 2", Link: "http://example.com/2", FirstSeen: before},
+				{Name: "chapter 1", Link: "http://example.com/1", FirstSeen: before, Ordinal: 1},
+			},
+		},
+		{
+			name: "items without link are matched by name",
+			web: Website{Items: []WebsiteItem{
+				{Name: "chapter 1", FirstSeen: before},
+			}},
+			items:  []WebsiteItem{{Name: "chapter 1"}},
+			expect: false,
+			expectItems: []WebsiteItem{
+				{Name: "chapter 1", FirstSeen: before},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			result := test.web.MergeItems(test.items, now)
+			if result != test.expect {
+				t.Errorf("got: %v; want: %v", result, test.expect)
+			}
+			if !cmp.Equal(test.web.Items, test.expectItems) {
+				t.Errorf("items diff: %v", cmp.Diff(test.web.Items, test.expectItems))
+			}
+		})
+	}
+}
+
+func TestWebsite_LatestItems(t *testing.T) {
+	items := []WebsiteItem{
+		{Name: "chapter 1", FirstSeen: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "chapter 3", FirstSeen: time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{Name: "chapter 2", FirstSeen: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	tests := []struct {
+		name   string
+		web    Website
+		expect []string
+	}{
+		{
+			name:   "sort by first seen time",
+			web:    Website{Items: items},
+			expect: []string{"chapter 3", "chapter 2", "chapter 1"},
+		},
+		{
+			name:   "limit by latest item count",
+			web:    Website{Items: items, Conf: &config.WebsiteConfig{LatestItemCount: 2}},
+			expect: []string{"chapter 3", "chapter 2"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var result []string
+			for _, item := range test.web.LatestItems() {
+				result = append(result, item.Name)
+			}
+			if !cmp.Equal(result, test.expect) {
+				t.Errorf("got unexpected items")
+				t.Error(result)
+				t.Error(test.expect)
+			}
+		})
+	}
+}
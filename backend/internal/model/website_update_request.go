@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebsiteUpdateRequest asks worker to fetch website out of its schedule,
+// TraceContext carries the trace of the request across processes
+type WebsiteUpdateRequest struct {
+	UUID         string
+	WebsiteUUID  string
+	TraceContext map[string]string
+	CreateTime   time.Time
+}
+
+func NewWebsiteUpdateRequest(websiteUUID string, traceContext map[string]string) WebsiteUpdateRequest {
+	return WebsiteUpdateRequest{
+		UUID:         uuid.New().String(),
+		WebsiteUUID:  websiteUUID,
+		TraceContext: traceContext,
+		CreateTime:   time.Now().UTC().Truncate(time.Second),
+	}
+}
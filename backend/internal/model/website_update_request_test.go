@@ -0,0 +1,26 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewWebsiteUpdateRequest(t *testing.T) {
+	traceContext := map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}
+	req := NewWebsiteUpdateRequest("uuid", traceContext)
+
+	if req.UUID == "" {
+		t.Errorf("request uuid is empty")
+	}
+	if req.WebsiteUUID != "uuid" {
+		t.Errorf("got website uuid: %v; want: %v", req.WebsiteUUID, "uuid")
+	}
+	if !cmp.Equal(req.TraceContext, traceContext) {
+		t.Errorf("got trace context: %v; want: %v", req.TraceContext, traceContext)
+	}
+	if time.Since(req.CreateTime) > time.Minute {
+		t.Errorf("got create time: %v", req.CreateTime)
+	}
+}
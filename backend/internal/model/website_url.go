@@ -0,0 +1,54 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const MaxURLLength = 2048
+
+var ErrInvalidURL = errors.New("invalid url")
+
+// DefaultURLSchemes are the schemes allowed if no scheme is configured
+var DefaultURLSchemes = []string{"http", "https"}
+
+// NormalizeURL trims url and checks if it is an absolute url of one of
+// schemes with host
+func NormalizeURL(rawURL string, schemes []string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return "", ErrInvalidURL
+	}
+	if len(rawURL) > MaxURLLength {
+		return "", fmt.Errorf("%w: longer than %d characters", ErrInvalidURL, MaxURLLength)
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: malformed url", ErrInvalidURL)
+	}
+
+	if len(schemes) == 0 {
+		schemes = DefaultURLSchemes
+	}
+	if !containsFold(schemes, u.Scheme) {
+		return "", fmt.Errorf("%w: scheme must be one of %s", ErrInvalidURL, strings.Join(schemes, ", "))
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("%w: host is required", ErrInvalidURL)
+	}
+
+	return rawURL, nil
+}
+
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+
+	return false
+}
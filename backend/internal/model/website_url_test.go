@@ -0,0 +1,84 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		url       string
+		schemes   []string
+		expect    string
+		expectErr error
+	}{
+		{
+			name:   "trim spaces of url",
+			url:    " https://example.com/path?q=1  ",
+			expect: "https://example.com/path?q=1",
+		},
+		{
+			name:   "allow http by default",
+			url:    "http://example.com",
+			expect: "http://example.com",
+		},
+		{
+			name:   "match scheme case insensitively",
+			url:    "HTTPS://example.com",
+			expect: "HTTPS://example.com",
+		},
+		{
+			name:      "return error if url is empty",
+			url:       "  ",
+			expectErr: ErrInvalidURL,
+		},
+		{
+			name:      "return error if url is malformed",
+			url:       "http://exa mple.com/%zz",
+			expectErr: ErrInvalidURL,
+		},
+		{
+			name:      "return error if url is relative",
+			url:       "/path",
+			expectErr: ErrInvalidURL,
+		},
+		{
+			name:      "return error if scheme is not allowed by default",
+			url:       "ftp://example.com",
+			expectErr: ErrInvalidURL,
+		},
+		{
+			name:      "return error if scheme is not in configured schemes",
+			url:       "http://example.com",
+			schemes:   []string{"https"},
+			expectErr: ErrInvalidURL,
+		},
+		{
+			name:      "return error if host is missing",
+			url:       "https:///path",
+			expectErr: ErrInvalidURL,
+		},
+		{
+			name:      "return error if url is too long",
+			url:       "https://example.com/" + strings.Repeat("a", MaxURLLength),
+			expectErr: ErrInvalidURL,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := NormalizeURL(test.url, test.schemes)
+			if !errors.Is(err, test.expectErr) {
+				t.Errorf("got error: %v; want: %v", err, test.expectErr)
+			}
+			if result != test.expect {
+				t.Errorf("got: %v; want: %v", result, test.expect)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+
+	"github.com/htchan/WebHistory/pkg/api"
+)
+
+// WorkerStatusTimeout is the time after the last heartbeat that worker is
+// considered gone
+const WorkerStatusTimeout = 2 * time.Minute
+
+// WorkerStatus is the heartbeat of worker process reporting its executor
+// usage
+type WorkerStatus struct {
+	WorkerID      string    `json:"worker_id"`
+	ExecutorCount int       `json:"executor_count"`
+	BusyExecutors int       `json:"busy_executors"`
+	QueueDepth    int       `json:"queue_depth"`
+	StartTime     time.Time `json:"start_time"`
+	HeartbeatTime time.Time `json:"heartbeat_time"`
+}
+
+func (status WorkerStatus) Alive(now time.Time) bool {
+	return now.Sub(status.HeartbeatTime) < WorkerStatusTimeout
+}
+
+func (status WorkerStatus) ToAPI(now time.Time) api.WorkerStatus {
+	return api.WorkerStatus{
+		Alive:         status.Alive(now),
+		BusyExecutors: status.BusyExecutors,
+		ExecutorCount: status.ExecutorCount,
+		HeartbeatTime: status.HeartbeatTime,
+		QueueDepth:    status.QueueDepth,
+		StartTime:     status.StartTime,
+		WorkerID:      status.WorkerID,
+	}
+}
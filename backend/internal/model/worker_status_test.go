@@ -0,0 +1,31 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWorkerStatus_Alive(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		status WorkerStatus
+		want   bool
+	}{
+		{name: "recent heartbeat", status: WorkerStatus{HeartbeatTime: now.Add(-time.Minute)}, want: true},
+		{name: "outdated heartbeat", status: WorkerStatus{HeartbeatTime: now.Add(-WorkerStatusTimeout)}, want: false},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := test.status.Alive(now); got != test.want {
+				t.Errorf("got alive: %v; want: %v", got, test.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package repository
 import (
 	"database/sql"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/htchan/WebHistory/internal/model"
@@ -12,6 +14,16 @@ type InMemRepo struct {
 	webs        []model.Website
 	userWebs    []model.UserWebsite
 	webSettings []model.WebsiteSetting
+	tags        []model.UserWebsiteTag
+	groupOrders map[string][]string
+	requests    []model.WebsiteUpdateRequest
+	users       []model.User
+	apiTokens   []model.APIToken
+	workers     []model.WorkerStatus
+	shares      []model.GroupShare
+	shareSubs   []model.GroupShareSubscription
+	publicLinks []model.GroupPublicLink
+	importJobs  []model.ImportJob
 	err         error
 }
 
@@ -32,7 +44,7 @@ func (r *InMemRepo) CreateWebsite(web *model.Website) error {
 	}
 	for _, w := range r.webs {
 		if w.URL == web.URL {
-			web = &w
+			*web = w
 			return r.err
 		}
 	}
@@ -76,7 +88,7 @@ func (r *InMemRepo) FindWebsite(uuid string) (*model.Website, error) {
 			return &web, r.err
 		}
 	}
-	return nil, fmt.Errorf("website not found")
+	return nil, fmt.Errorf("website: %w", ErrNotFound)
 }
 
 func (r *InMemRepo) CreateUserWebsite(web *model.UserWebsite) error {
@@ -85,7 +97,7 @@ func (r *InMemRepo) CreateUserWebsite(web *model.UserWebsite) error {
 	}
 	for _, w := range r.userWebs {
 		if w.UserUUID == web.UserUUID && w.WebsiteUUID == web.WebsiteUUID {
-			web = &w
+			*web = w
 			return r.err
 		}
 	}
@@ -120,14 +132,148 @@ func (r *InMemRepo) DeleteUserWebsite(web *model.UserWebsite) error {
 	return r.err
 }
 
+func (r *InMemRepo) MoveUserWebsites(userUUID, group string, websiteUUIDs []string) error {
+	if r.err != nil {
+		return r.err
+	}
+	for i, w := range r.userWebs {
+		if w.UserUUID != userUUID {
+			continue
+		}
+		for _, uuid := range websiteUUIDs {
+			if w.WebsiteUUID == uuid {
+				r.userWebs[i].GroupName = group
+			}
+		}
+	}
+	return r.err
+}
+
+func (r *InMemRepo) RenameWebsitePlaceholderGroups(websiteUUID, placeholder, group string) error {
+	if r.err != nil {
+		return r.err
+	}
+	for i, w := range r.userWebs {
+		if w.WebsiteUUID == websiteUUID && w.GroupName == placeholder {
+			r.userWebs[i].GroupName = group
+		}
+	}
+	return r.err
+}
+
+func (r *InMemRepo) RenameUserWebsiteGroup(userUUID, group, newGroup string) error {
+	if r.err != nil {
+		return r.err
+	}
+	for i, w := range r.userWebs {
+		if w.UserUUID == userUUID && w.GroupName == group {
+			r.userWebs[i].GroupName = newGroup
+		}
+	}
+
+	var order []string
+	for _, name := range r.groupOrders[userUUID] {
+		if name == group {
+			name = newGroup
+		}
+		if !contains(order, name) {
+			order = append(order, name)
+		}
+	}
+	if order != nil {
+		r.groupOrders[userUUID] = order
+	}
+
+	if _, err := r.FindGroupShareByGroup(userUUID, newGroup); err == nil {
+		if share, err := r.FindGroupShareByGroup(userUUID, group); err == nil {
+			r.DeleteGroupShare(share.UUID)
+		}
+	}
+	for i, share := range r.shares {
+		if share.OwnerUUID == userUUID && share.GroupName == group {
+			r.shares[i].GroupName = newGroup
+		}
+	}
+
+	if _, err := r.FindGroupPublicLinkByGroup(userUUID, newGroup); err == nil {
+		r.DeleteGroupPublicLink(userUUID, group)
+	}
+	for i, link := range r.publicLinks {
+		if link.OwnerUUID == userUUID && link.GroupName == group {
+			r.publicLinks[i].GroupName = newGroup
+		}
+	}
+	return r.err
+}
+
+func contains(list []string, target string) bool {
+	for _, s := range list {
+		if s == target {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *InMemRepo) FindUserGroupOrder(userUUID string) ([]string, error) {
+	return r.groupOrders[userUUID], r.err
+}
+
+func (r *InMemRepo) UpdateUserGroupOrder(userUUID string, groups []string) error {
+	if r.err != nil {
+		return r.err
+	}
+	if r.groupOrders == nil {
+		r.groupOrders = make(map[string][]string)
+	}
+	r.groupOrders[userUUID] = groups
+	return r.err
+}
+
+func (r *InMemRepo) withTags(web model.UserWebsite) model.UserWebsite {
+	web.Tags = nil
+	for _, tag := range r.tags {
+		if tag.UserUUID == web.UserUUID && tag.WebsiteUUID == web.WebsiteUUID {
+			web.Tags = append(web.Tags, tag.Tag)
+		}
+	}
+	return web
+}
+
 func (r *InMemRepo) FindUserWebsites(userUUID string) (model.UserWebsites, error) {
-	return r.userWebs, r.err
+	var webs model.UserWebsites
+	for _, web := range r.userWebs {
+		if web.UserUUID == userUUID {
+			webs = append(webs, r.withTags(web))
+		}
+	}
+	return webs, r.err
+}
+func (r *InMemRepo) SearchUserWebsites(userUUID string, filter model.UserWebsiteFilter, page model.UserWebsitePage) (model.UserWebsites, error) {
+	var webs model.UserWebsites
+	for _, web := range r.userWebs {
+		web = r.withTags(web)
+		if web.UserUUID == userUUID && filter.Match(web) {
+			webs = append(webs, web)
+		}
+	}
+	now := time.Now().UTC()
+	webs.Sort(page.Sort, now)
+	return webs.Page(page, now), r.err
+}
+func (r *InMemRepo) SearchUserWebsiteGroups(userUUID string, filter model.UserWebsiteFilter, order model.UserWebsiteSort) ([]string, error) {
+	webs, err := r.SearchUserWebsites(userUUID, filter, model.UserWebsitePage{Sort: order})
+	var groups []string
+	for _, group := range webs.WebsiteGroups() {
+		groups = append(groups, group.Name())
+	}
+	return groups, err
 }
 func (r *InMemRepo) FindUserWebsitesByGroup(userUUID, group string) (model.WebsiteGroup, error) {
 	var webs model.WebsiteGroup
 	for _, web := range r.userWebs {
 		if web.UserUUID == userUUID && web.GroupName == group {
-			webs = append(webs, web)
+			webs = append(webs, r.withTags(web))
 		}
 	}
 	return webs, r.err
@@ -135,14 +281,83 @@ func (r *InMemRepo) FindUserWebsitesByGroup(userUUID, group string) (model.Websi
 func (r *InMemRepo) FindUserWebsite(userUUID, websiteUUID string) (*model.UserWebsite, error) {
 	for _, web := range r.userWebs {
 		if web.UserUUID == userUUID && web.WebsiteUUID == websiteUUID {
+			web = r.withTags(web)
 			return &web, r.err
 		}
 	}
-	return nil, fmt.Errorf("user website not found")
+	return nil, fmt.Errorf("user website: %w", ErrNotFound)
+}
+
+func (r *InMemRepo) CreateUserWebsiteTag(tag *model.UserWebsiteTag) error {
+	if r.err != nil {
+		return r.err
+	}
+	for _, t := range r.tags {
+		if t == *tag {
+			return r.err
+		}
+	}
+	r.tags = append(r.tags, *tag)
+	return r.err
+}
+
+func (r *InMemRepo) DeleteUserWebsiteTag(tag *model.UserWebsiteTag) error {
+	if r.err != nil {
+		return r.err
+	}
+	var result []model.UserWebsiteTag
+	for _, t := range r.tags {
+		if t == *tag {
+			continue
+		}
+		result = append(result, t)
+	}
+	r.tags = result
+	return r.err
+}
+
+func (r *InMemRepo) FindUserTags(userUUID string) ([]string, error) {
+	tagSet := make(map[string]bool)
+	var tags []string
+	for _, t := range r.tags {
+		if t.UserUUID == userUUID && !tagSet[t.Tag] {
+			tagSet[t.Tag] = true
+			tags = append(tags, t.Tag)
+		}
+	}
+	sort.Strings(tags)
+	return tags, r.err
+}
+
+func (r *InMemRepo) RenameUserTag(userUUID, tag, newTag string) error {
+	if r.err != nil {
+		return r.err
+	}
+	for _, t := range r.tags {
+		if t.UserUUID == userUUID && t.Tag == tag {
+			r.CreateUserWebsiteTag(&model.UserWebsiteTag{UserUUID: userUUID, WebsiteUUID: t.WebsiteUUID, Tag: newTag})
+		}
+	}
+	return r.DeleteUserTag(userUUID, tag)
+}
+
+func (r *InMemRepo) DeleteUserTag(userUUID, tag string) error {
+	if r.err != nil {
+		return r.err
+	}
+	var result []model.UserWebsiteTag
+	for _, t := range r.tags {
+		if t.UserUUID == userUUID && t.Tag == tag {
+			continue
+		}
+		result = append(result, t)
+	}
+	r.tags = result
+	return r.err
 }
 
 func (r *InMemRepo) FindWebsiteSettings() ([]model.WebsiteSetting, error) {
-	return r.webSettings, nil
+	return r.webSettings, r.err
 }
 
 func (r *InMemRepo) FindWebsiteSetting(domain string) (*model.WebsiteSetting, error) {
@@ -151,7 +366,399 @@ func (r *InMemRepo) FindWebsiteSetting(domain string) (*model.WebsiteSetting, er
 			return &setting, nil
 		}
 	}
-	return nil, fmt.Errorf("setting not found")
+	return nil, fmt.Errorf("setting: %w", ErrNotFound)
+}
+
+func (r *InMemRepo) RecordWebsiteSettingParse(domain string, success bool, threshold int, now time.Time) (*model.WebsiteSetting, bool, error) {
+	if r.err != nil {
+		return nil, false, r.err
+	}
+	for i, s := range r.webSettings {
+		if s.Domain == domain {
+			wasBroken := s.IsBroken()
+			r.webSettings[i].RecordParse(success, threshold, now)
+			setting := r.webSettings[i]
+			return &setting, wasBroken, nil
+		}
+	}
+	return nil, false, fmt.Errorf("setting: %w", ErrNotFound)
+}
+
+func (r *InMemRepo) CreateWebsiteUpdateRequest(req *model.WebsiteUpdateRequest) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	r.requests = append(r.requests, *req)
+	return nil
+}
+
+func (r *InMemRepo) ClaimWebsiteUpdateRequests(limit int) ([]model.WebsiteUpdateRequest, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	if limit > len(r.requests) {
+		limit = len(r.requests)
+	}
+	claimed := append([]model.WebsiteUpdateRequest(nil), r.requests[:limit]...)
+	r.requests = r.requests[limit:]
+
+	return claimed, nil
+}
+
+func (r *InMemRepo) CreateUser(user *model.User) error {
+	if r.err != nil {
+		return r.err
+	}
+	for _, u := range r.users {
+		if u.Username == user.Username {
+			return fmt.Errorf("user: %w", ErrDuplicate)
+		}
+	}
+
+	r.users = append(r.users, *user)
+	return nil
+}
+
+func (r *InMemRepo) FindUserByUsername(username string) (*model.User, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	for _, user := range r.users {
+		if user.Username == username {
+			return &user, nil
+		}
+	}
+	return nil, fmt.Errorf("user: %w", ErrNotFound)
+}
+
+func (r *InMemRepo) CreateAPIToken(token *model.APIToken) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	r.apiTokens = append(r.apiTokens, *token)
+	return nil
+}
+
+func (r *InMemRepo) FindAPIToken(tokenHash string) (*model.APIToken, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	for _, token := range r.apiTokens {
+		if token.TokenHash == tokenHash {
+			return &token, nil
+		}
+	}
+	return nil, fmt.Errorf("api token: %w", ErrNotFound)
+}
+
+func (r *InMemRepo) FindAPITokens(userUUID string) ([]model.APIToken, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	var tokens []model.APIToken
+	for _, token := range r.apiTokens {
+		if token.UserUUID == userUUID {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+func (r *InMemRepo) DeleteAPIToken(userUUID, tokenUUID string) error {
+	if r.err != nil {
+		return r.err
+	}
+	for i, token := range r.apiTokens {
+		if token.UserUUID == userUUID && token.UUID == tokenUUID {
+			r.apiTokens = append(r.apiTokens[:i], r.apiTokens[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *InMemRepo) CreateGroupShare(share *model.GroupShare) error {
+	if r.err != nil {
+		return r.err
+	}
+	for i, s := range r.shares {
+		if s.OwnerUUID == share.OwnerUUID && s.GroupName == share.GroupName {
+			r.shares[i].Mode = share.Mode
+			*share = r.shares[i]
+			return nil
+		}
+	}
+
+	r.shares = append(r.shares, *share)
+	return nil
+}
+
+func (r *InMemRepo) FindGroupShare(uuid string) (*model.GroupShare, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	for _, share := range r.shares {
+		if share.UUID == uuid {
+			return &share, nil
+		}
+	}
+	return nil, fmt.Errorf("group share: %w", ErrNotFound)
+}
+
+func (r *InMemRepo) FindGroupShareByGroup(ownerUUID, group string) (*model.GroupShare, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	for _, share := range r.shares {
+		if share.OwnerUUID == ownerUUID && share.GroupName == group {
+			return &share, nil
+		}
+	}
+	return nil, fmt.Errorf("group share: %w", ErrNotFound)
+}
+
+func (r *InMemRepo) FindGroupShares(ownerUUID string) ([]model.GroupShare, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	var shares []model.GroupShare
+	for _, share := range r.shares {
+		if share.OwnerUUID == ownerUUID {
+			shares = append(shares, share)
+		}
+	}
+	return shares, nil
+}
+
+func (r *InMemRepo) DeleteGroupShare(uuid string) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	var shares []model.GroupShare
+	for _, share := range r.shares {
+		if share.UUID != uuid {
+			shares = append(shares, share)
+		}
+	}
+	r.shares = shares
+
+	var subs []model.GroupShareSubscription
+	for _, sub := range r.shareSubs {
+		if sub.ShareUUID != uuid {
+			subs = append(subs, sub)
+		}
+	}
+	r.shareSubs = subs
+	return nil
+}
+
+func (r *InMemRepo) CreateGroupShareSubscription(sub *model.GroupShareSubscription) error {
+	if r.err != nil {
+		return r.err
+	}
+	for i, s := range r.shareSubs {
+		if s.ShareUUID == sub.ShareUUID && s.UserUUID == sub.UserUUID {
+			r.shareSubs[i].GroupName = sub.GroupName
+			return nil
+		}
+	}
+
+	r.shareSubs = append(r.shareSubs, *sub)
+	return nil
+}
+
+func (r *InMemRepo) DeleteGroupShareSubscription(shareUUID, userUUID string) error {
+	if r.err != nil {
+		return r.err
+	}
+	for i, sub := range r.shareSubs {
+		if sub.ShareUUID == shareUUID && sub.UserUUID == userUUID {
+			r.shareSubs = append(r.shareSubs[:i], r.shareSubs[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *InMemRepo) FindGroupShareSubscriptions(shareUUID string) ([]model.GroupShareSubscription, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	var subs []model.GroupShareSubscription
+	for _, sub := range r.shareSubs {
+		if sub.ShareUUID == shareUUID {
+			subs = append(subs, sub)
+		}
+	}
+	return subs, nil
+}
+
+func (r *InMemRepo) FindUserGroupShareSubscriptions(userUUID string) ([]model.GroupShareSubscription, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	var subs []model.GroupShareSubscription
+	for _, sub := range r.shareSubs {
+		if sub.UserUUID == userUUID {
+			subs = append(subs, sub)
+		}
+	}
+	return subs, nil
+}
+
+func (r *InMemRepo) CreateGroupPublicLink(link *model.GroupPublicLink) error {
+	if r.err != nil {
+		return r.err
+	}
+	for i, l := range r.publicLinks {
+		if l.OwnerUUID == link.OwnerUUID && l.GroupName == link.GroupName {
+			r.publicLinks[i] = *link
+			return nil
+		}
+	}
+
+	r.publicLinks = append(r.publicLinks, *link)
+	return nil
+}
+
+func (r *InMemRepo) FindGroupPublicLink(token string) (*model.GroupPublicLink, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	for _, link := range r.publicLinks {
+		if link.Token == token {
+			return &link, nil
+		}
+	}
+	return nil, fmt.Errorf("group public link: %w", ErrNotFound)
+}
+
+func (r *InMemRepo) FindGroupPublicLinkByGroup(ownerUUID, group string) (*model.GroupPublicLink, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	for _, link := range r.publicLinks {
+		if link.OwnerUUID == ownerUUID && link.GroupName == group {
+			return &link, nil
+		}
+	}
+	return nil, fmt.Errorf("group public link: %w", ErrNotFound)
+}
+
+func (r *InMemRepo) DeleteGroupPublicLink(ownerUUID, group string) error {
+	if r.err != nil {
+		return r.err
+	}
+	for i, link := range r.publicLinks {
+		if link.OwnerUUID == ownerUUID && link.GroupName == group {
+			r.publicLinks = append(r.publicLinks[:i], r.publicLinks[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *InMemRepo) CreateImportJob(job *model.ImportJob) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.importJobs = append(r.importJobs, *job)
+	return nil
+}
+
+func (r *InMemRepo) UpdateImportJob(job *model.ImportJob) error {
+	if r.err != nil {
+		return r.err
+	}
+	for i, j := range r.importJobs {
+		if j.ID == job.ID {
+			r.importJobs[i] = *job
+			return nil
+		}
+	}
+	return fmt.Errorf("import job: %w", ErrNotFound)
+}
+
+func (r *InMemRepo) FindImportJob(userUUID, uuid string) (*model.ImportJob, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	for _, job := range r.importJobs {
+		if job.UserUUID == userUUID && job.ID == uuid {
+			return &job, nil
+		}
+	}
+	return nil, fmt.Errorf("import job: %w", ErrNotFound)
+}
+
+func (r *InMemRepo) DeleteImportJobs(before time.Time) error {
+	if r.err != nil {
+		return r.err
+	}
+	var jobs []model.ImportJob
+	for _, job := range r.importJobs {
+		if !job.CreateTime.Before(before) {
+			jobs = append(jobs, job)
+		}
+	}
+	r.importJobs = jobs
+	return nil
+}
+
+func (r *InMemRepo) FindWebsiteSubscriberCounts() (map[string]int, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	counts := make(map[string]int)
+	for _, web := range r.userWebs {
+		counts[web.WebsiteUUID]++
+	}
+	return counts, nil
+}
+
+func (r *InMemRepo) CountWebsiteUpdateRequests() (int, error) {
+	return len(r.requests), r.err
+}
+
+func (r *InMemRepo) UpsertWorkerStatus(status *model.WorkerStatus) error {
+	if r.err != nil {
+		return r.err
+	}
+	for i, s := range r.workers {
+		if s.WorkerID == status.WorkerID {
+			r.workers[i] = *status
+			return nil
+		}
+	}
+
+	r.workers = append(r.workers, *status)
+	return nil
+}
+
+func (r *InMemRepo) FindWorkerStatuses() ([]model.WorkerStatus, error) {
+	return r.workers, r.err
+}
+
+func (r *InMemRepo) DeleteWorkerStatus(workerID string) error {
+	if r.err != nil {
+		return r.err
+	}
+	for i, s := range r.workers {
+		if s.WorkerID == workerID {
+			r.workers = append(r.workers[:i], r.workers[i+1:]...)
+			return nil
+		}
+	}
+	return nil
 }
 
 func (r InMemRepo) Equal(compare InMemRepo) bool {
@@ -159,6 +766,14 @@ func (r InMemRepo) Equal(compare InMemRepo) bool {
 		cmp.Equal(r.userWebs, compare.userWebs)
 }
 
+// WithTx runs fn with the repo itself, changes are not rolled back
+func (r *InMemRepo) WithTx(fn func(Repostory) error) error {
+	if r.err != nil {
+		return r.err
+	}
+	return fn(r)
+}
+
 func (r InMemRepo) Stats() sql.DBStats {
 	return sql.DBStats{}
 }
@@ -7,9 +7,11 @@ package mockrepo
 import (
 	sql "database/sql"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/htchan/WebHistory/internal/model"
+	repository "github.com/htchan/WebHistory/internal/repository"
 )
 
 // MockRepostory is a mock of Repostory interface.
@@ -35,6 +37,120 @@ func (m *MockRepostory) EXPECT() *MockRepostoryMockRecorder {
 	return m.recorder
 }
 
+// ClaimWebsiteUpdateRequests mocks base method.
+func (m *MockRepostory) ClaimWebsiteUpdateRequests(arg0 int) ([]model.WebsiteUpdateRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebsiteUpdateRequests", arg0)
+	ret0, _ := ret[0].([]model.WebsiteUpdateRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWebsiteUpdateRequests indicates an expected call of ClaimWebsiteUpdateRequests.
+func (mr *MockRepostoryMockRecorder) ClaimWebsiteUpdateRequests(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebsiteUpdateRequests", reflect.TypeOf((*MockRepostory)(nil).ClaimWebsiteUpdateRequests), arg0)
+}
+
+// CountWebsiteUpdateRequests mocks base method.
+func (m *MockRepostory) CountWebsiteUpdateRequests() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWebsiteUpdateRequests")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWebsiteUpdateRequests indicates an expected call of CountWebsiteUpdateRequests.
+func (mr *MockRepostoryMockRecorder) CountWebsiteUpdateRequests() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWebsiteUpdateRequests", reflect.TypeOf((*MockRepostory)(nil).CountWebsiteUpdateRequests))
+}
+
+// CreateAPIToken mocks base method.
+func (m *MockRepostory) CreateAPIToken(arg0 *model.APIToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIToken", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAPIToken indicates an expected call of CreateAPIToken.
+func (mr *MockRepostoryMockRecorder) CreateAPIToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIToken", reflect.TypeOf((*MockRepostory)(nil).CreateAPIToken), arg0)
+}
+
+// CreateGroupPublicLink mocks base method.
+func (m *MockRepostory) CreateGroupPublicLink(arg0 *model.GroupPublicLink) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGroupPublicLink", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateGroupPublicLink indicates an expected call of CreateGroupPublicLink.
+func (mr *MockRepostoryMockRecorder) CreateGroupPublicLink(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGroupPublicLink", reflect.TypeOf((*MockRepostory)(nil).CreateGroupPublicLink), arg0)
+}
+
+// CreateGroupShare mocks base method.
+func (m *MockRepostory) CreateGroupShare(arg0 *model.GroupShare) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGroupShare", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateGroupShare indicates an expected call of CreateGroupShare.
+func (mr *MockRepostoryMockRecorder) CreateGroupShare(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGroupShare", reflect.TypeOf((*MockRepostory)(nil).CreateGroupShare), arg0)
+}
+
+// CreateGroupShareSubscription mocks base method.
+func (m *MockRepostory) CreateGroupShareSubscription(arg0 *model.GroupShareSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGroupShareSubscription", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateGroupShareSubscription indicates an expected call of CreateGroupShareSubscription.
+func (mr *MockRepostoryMockRecorder) CreateGroupShareSubscription(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGroupShareSubscription", reflect.TypeOf((*MockRepostory)(nil).CreateGroupShareSubscription), arg0)
+}
+
+// CreateImportJob mocks base method.
+func (m *MockRepostory) CreateImportJob(arg0 *model.ImportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateImportJob", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateImportJob indicates an expected call of CreateImportJob.
+func (mr *MockRepostoryMockRecorder) CreateImportJob(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImportJob", reflect.TypeOf((*MockRepostory)(nil).CreateImportJob), arg0)
+}
+
+// CreateUser mocks base method.
+func (m *MockRepostory) CreateUser(arg0 *model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockRepostoryMockRecorder) CreateUser(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockRepostory)(nil).CreateUser), arg0)
+}
+
 // CreateUserWebsite mocks base method.
 func (m *MockRepostory) CreateUserWebsite(arg0 *model.UserWebsite) error {
 	m.ctrl.T.Helper()
@@ -49,6 +165,20 @@ func (mr *MockRepostoryMockRecorder) CreateUserWebsite(arg0 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserWebsite", reflect.TypeOf((*MockRepostory)(nil).CreateUserWebsite), arg0)
 }
 
+// CreateUserWebsiteTag mocks base method.
+func (m *MockRepostory) CreateUserWebsiteTag(arg0 *model.UserWebsiteTag) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserWebsiteTag", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUserWebsiteTag indicates an expected call of CreateUserWebsiteTag.
+func (mr *MockRepostoryMockRecorder) CreateUserWebsiteTag(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserWebsiteTag", reflect.TypeOf((*MockRepostory)(nil).CreateUserWebsiteTag), arg0)
+}
+
 // CreateWebsite mocks base method.
 func (m *MockRepostory) CreateWebsite(arg0 *model.Website) error {
 	m.ctrl.T.Helper()
@@ -63,6 +193,104 @@ func (mr *MockRepostoryMockRecorder) CreateWebsite(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebsite", reflect.TypeOf((*MockRepostory)(nil).CreateWebsite), arg0)
 }
 
+// CreateWebsiteUpdateRequest mocks base method.
+func (m *MockRepostory) CreateWebsiteUpdateRequest(arg0 *model.WebsiteUpdateRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebsiteUpdateRequest", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebsiteUpdateRequest indicates an expected call of CreateWebsiteUpdateRequest.
+func (mr *MockRepostoryMockRecorder) CreateWebsiteUpdateRequest(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebsiteUpdateRequest", reflect.TypeOf((*MockRepostory)(nil).CreateWebsiteUpdateRequest), arg0)
+}
+
+// DeleteAPIToken mocks base method.
+func (m *MockRepostory) DeleteAPIToken(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAPIToken", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAPIToken indicates an expected call of DeleteAPIToken.
+func (mr *MockRepostoryMockRecorder) DeleteAPIToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAPIToken", reflect.TypeOf((*MockRepostory)(nil).DeleteAPIToken), arg0, arg1)
+}
+
+// DeleteGroupPublicLink mocks base method.
+func (m *MockRepostory) DeleteGroupPublicLink(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGroupPublicLink", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGroupPublicLink indicates an expected call of DeleteGroupPublicLink.
+func (mr *MockRepostoryMockRecorder) DeleteGroupPublicLink(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroupPublicLink", reflect.TypeOf((*MockRepostory)(nil).DeleteGroupPublicLink), arg0, arg1)
+}
+
+// DeleteGroupShare mocks base method.
+func (m *MockRepostory) DeleteGroupShare(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGroupShare", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGroupShare indicates an expected call of DeleteGroupShare.
+func (mr *MockRepostoryMockRecorder) DeleteGroupShare(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroupShare", reflect.TypeOf((*MockRepostory)(nil).DeleteGroupShare), arg0)
+}
+
+// DeleteGroupShareSubscription mocks base method.
+func (m *MockRepostory) DeleteGroupShareSubscription(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGroupShareSubscription", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGroupShareSubscription indicates an expected call of DeleteGroupShareSubscription.
+func (mr *MockRepostoryMockRecorder) DeleteGroupShareSubscription(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroupShareSubscription", reflect.TypeOf((*MockRepostory)(nil).DeleteGroupShareSubscription), arg0, arg1)
+}
+
+// DeleteImportJobs mocks base method.
+func (m *MockRepostory) DeleteImportJobs(arg0 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteImportJobs", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteImportJobs indicates an expected call of DeleteImportJobs.
+func (mr *MockRepostoryMockRecorder) DeleteImportJobs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteImportJobs", reflect.TypeOf((*MockRepostory)(nil).DeleteImportJobs), arg0)
+}
+
+// DeleteUserTag mocks base method.
+func (m *MockRepostory) DeleteUserTag(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserTag", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserTag indicates an expected call of DeleteUserTag.
+func (mr *MockRepostoryMockRecorder) DeleteUserTag(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserTag", reflect.TypeOf((*MockRepostory)(nil).DeleteUserTag), arg0, arg1)
+}
+
 // DeleteUserWebsite mocks base method.
 func (m *MockRepostory) DeleteUserWebsite(arg0 *model.UserWebsite) error {
 	m.ctrl.T.Helper()
@@ -77,6 +305,20 @@ func (mr *MockRepostoryMockRecorder) DeleteUserWebsite(arg0 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserWebsite", reflect.TypeOf((*MockRepostory)(nil).DeleteUserWebsite), arg0)
 }
 
+// DeleteUserWebsiteTag mocks base method.
+func (m *MockRepostory) DeleteUserWebsiteTag(arg0 *model.UserWebsiteTag) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserWebsiteTag", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserWebsiteTag indicates an expected call of DeleteUserWebsiteTag.
+func (mr *MockRepostoryMockRecorder) DeleteUserWebsiteTag(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserWebsiteTag", reflect.TypeOf((*MockRepostory)(nil).DeleteUserWebsiteTag), arg0)
+}
+
 // DeleteWebsite mocks base method.
 func (m *MockRepostory) DeleteWebsite(arg0 *model.Website) error {
 	m.ctrl.T.Helper()
@@ -91,6 +333,215 @@ func (mr *MockRepostoryMockRecorder) DeleteWebsite(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebsite", reflect.TypeOf((*MockRepostory)(nil).DeleteWebsite), arg0)
 }
 
+// DeleteWorkerStatus mocks base method.
+func (m *MockRepostory) DeleteWorkerStatus(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkerStatus", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkerStatus indicates an expected call of DeleteWorkerStatus.
+func (mr *MockRepostoryMockRecorder) DeleteWorkerStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkerStatus", reflect.TypeOf((*MockRepostory)(nil).DeleteWorkerStatus), arg0)
+}
+
+// FindAPIToken mocks base method.
+func (m *MockRepostory) FindAPIToken(arg0 string) (*model.APIToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAPIToken", arg0)
+	ret0, _ := ret[0].(*model.APIToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAPIToken indicates an expected call of FindAPIToken.
+func (mr *MockRepostoryMockRecorder) FindAPIToken(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAPIToken", reflect.TypeOf((*MockRepostory)(nil).FindAPIToken), arg0)
+}
+
+// FindAPITokens mocks base method.
+func (m *MockRepostory) FindAPITokens(arg0 string) ([]model.APIToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAPITokens", arg0)
+	ret0, _ := ret[0].([]model.APIToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAPITokens indicates an expected call of FindAPITokens.
+func (mr *MockRepostoryMockRecorder) FindAPITokens(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAPITokens", reflect.TypeOf((*MockRepostory)(nil).FindAPITokens), arg0)
+}
+
+// FindGroupPublicLink mocks base method.
+func (m *MockRepostory) FindGroupPublicLink(arg0 string) (*model.GroupPublicLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindGroupPublicLink", arg0)
+	ret0, _ := ret[0].(*model.GroupPublicLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindGroupPublicLink indicates an expected call of FindGroupPublicLink.
+func (mr *MockRepostoryMockRecorder) FindGroupPublicLink(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindGroupPublicLink", reflect.TypeOf((*MockRepostory)(nil).FindGroupPublicLink), arg0)
+}
+
+// FindGroupPublicLinkByGroup mocks base method.
+func (m *MockRepostory) FindGroupPublicLinkByGroup(arg0, arg1 string) (*model.GroupPublicLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindGroupPublicLinkByGroup", arg0, arg1)
+	ret0, _ := ret[0].(*model.GroupPublicLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindGroupPublicLinkByGroup indicates an expected call of FindGroupPublicLinkByGroup.
+func (mr *MockRepostoryMockRecorder) FindGroupPublicLinkByGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindGroupPublicLinkByGroup", reflect.TypeOf((*MockRepostory)(nil).FindGroupPublicLinkByGroup), arg0, arg1)
+}
+
+// FindGroupShare mocks base method.
+func (m *MockRepostory) FindGroupShare(arg0 string) (*model.GroupShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindGroupShare", arg0)
+	ret0, _ := ret[0].(*model.GroupShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindGroupShare indicates an expected call of FindGroupShare.
+func (mr *MockRepostoryMockRecorder) FindGroupShare(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindGroupShare", reflect.TypeOf((*MockRepostory)(nil).FindGroupShare), arg0)
+}
+
+// FindGroupShareByGroup mocks base method.
+func (m *MockRepostory) FindGroupShareByGroup(arg0, arg1 string) (*model.GroupShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindGroupShareByGroup", arg0, arg1)
+	ret0, _ := ret[0].(*model.GroupShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindGroupShareByGroup indicates an expected call of FindGroupShareByGroup.
+func (mr *MockRepostoryMockRecorder) FindGroupShareByGroup(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindGroupShareByGroup", reflect.TypeOf((*MockRepostory)(nil).FindGroupShareByGroup), arg0, arg1)
+}
+
+// FindGroupShareSubscriptions mocks base method.
+func (m *MockRepostory) FindGroupShareSubscriptions(arg0 string) ([]model.GroupShareSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindGroupShareSubscriptions", arg0)
+	ret0, _ := ret[0].([]model.GroupShareSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindGroupShareSubscriptions indicates an expected call of FindGroupShareSubscriptions.
+func (mr *MockRepostoryMockRecorder) FindGroupShareSubscriptions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindGroupShareSubscriptions", reflect.TypeOf((*MockRepostory)(nil).FindGroupShareSubscriptions), arg0)
+}
+
+// FindGroupShares mocks base method.
+func (m *MockRepostory) FindGroupShares(arg0 string) ([]model.GroupShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindGroupShares", arg0)
+	ret0, _ := ret[0].([]model.GroupShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindGroupShares indicates an expected call of FindGroupShares.
+func (mr *MockRepostoryMockRecorder) FindGroupShares(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindGroupShares", reflect.TypeOf((*MockRepostory)(nil).FindGroupShares), arg0)
+}
+
+// FindImportJob mocks base method.
+func (m *MockRepostory) FindImportJob(arg0, arg1 string) (*model.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindImportJob", arg0, arg1)
+	ret0, _ := ret[0].(*model.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindImportJob indicates an expected call of FindImportJob.
+func (mr *MockRepostoryMockRecorder) FindImportJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindImportJob", reflect.TypeOf((*MockRepostory)(nil).FindImportJob), arg0, arg1)
+}
+
+// FindUserByUsername mocks base method.
+func (m *MockRepostory) FindUserByUsername(arg0 string) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUserByUsername", arg0)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUserByUsername indicates an expected call of FindUserByUsername.
+func (mr *MockRepostoryMockRecorder) FindUserByUsername(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserByUsername", reflect.TypeOf((*MockRepostory)(nil).FindUserByUsername), arg0)
+}
+
+// FindUserGroupOrder mocks base method.
+func (m *MockRepostory) FindUserGroupOrder(arg0 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUserGroupOrder", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUserGroupOrder indicates an expected call of FindUserGroupOrder.
+func (mr *MockRepostoryMockRecorder) FindUserGroupOrder(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserGroupOrder", reflect.TypeOf((*MockRepostory)(nil).FindUserGroupOrder), arg0)
+}
+
+// FindUserGroupShareSubscriptions mocks base method.
+func (m *MockRepostory) FindUserGroupShareSubscriptions(arg0 string) ([]model.GroupShareSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUserGroupShareSubscriptions", arg0)
+	ret0, _ := ret[0].([]model.GroupShareSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUserGroupShareSubscriptions indicates an expected call of FindUserGroupShareSubscriptions.
+func (mr *MockRepostoryMockRecorder) FindUserGroupShareSubscriptions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserGroupShareSubscriptions", reflect.TypeOf((*MockRepostory)(nil).FindUserGroupShareSubscriptions), arg0)
+}
+
+// FindUserTags mocks base method.
+func (m *MockRepostory) FindUserTags(arg0 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUserTags", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUserTags indicates an expected call of FindUserTags.
+func (mr *MockRepostoryMockRecorder) FindUserTags(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserTags", reflect.TypeOf((*MockRepostory)(nil).FindUserTags), arg0)
+}
+
 // FindUserWebsite mocks base method.
 func (m *MockRepostory) FindUserWebsite(arg0, arg1 string) (*model.UserWebsite, error) {
 	m.ctrl.T.Helper()
@@ -181,6 +632,21 @@ func (mr *MockRepostoryMockRecorder) FindWebsiteSettings() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindWebsiteSettings", reflect.TypeOf((*MockRepostory)(nil).FindWebsiteSettings))
 }
 
+// FindWebsiteSubscriberCounts mocks base method.
+func (m *MockRepostory) FindWebsiteSubscriberCounts() (map[string]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindWebsiteSubscriberCounts")
+	ret0, _ := ret[0].(map[string]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindWebsiteSubscriberCounts indicates an expected call of FindWebsiteSubscriberCounts.
+func (mr *MockRepostoryMockRecorder) FindWebsiteSubscriberCounts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindWebsiteSubscriberCounts", reflect.TypeOf((*MockRepostory)(nil).FindWebsiteSubscriberCounts))
+}
+
 // FindWebsites mocks base method.
 func (m *MockRepostory) FindWebsites() ([]model.Website, error) {
 	m.ctrl.T.Helper()
@@ -196,6 +662,123 @@ func (mr *MockRepostoryMockRecorder) FindWebsites() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindWebsites", reflect.TypeOf((*MockRepostory)(nil).FindWebsites))
 }
 
+// FindWorkerStatuses mocks base method.
+func (m *MockRepostory) FindWorkerStatuses() ([]model.WorkerStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindWorkerStatuses")
+	ret0, _ := ret[0].([]model.WorkerStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindWorkerStatuses indicates an expected call of FindWorkerStatuses.
+func (mr *MockRepostoryMockRecorder) FindWorkerStatuses() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindWorkerStatuses", reflect.TypeOf((*MockRepostory)(nil).FindWorkerStatuses))
+}
+
+// MoveUserWebsites mocks base method.
+func (m *MockRepostory) MoveUserWebsites(arg0, arg1 string, arg2 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveUserWebsites", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveUserWebsites indicates an expected call of MoveUserWebsites.
+func (mr *MockRepostoryMockRecorder) MoveUserWebsites(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveUserWebsites", reflect.TypeOf((*MockRepostory)(nil).MoveUserWebsites), arg0, arg1, arg2)
+}
+
+// RecordWebsiteSettingParse mocks base method.
+func (m *MockRepostory) RecordWebsiteSettingParse(arg0 string, arg1 bool, arg2 int, arg3 time.Time) (*model.WebsiteSetting, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordWebsiteSettingParse", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*model.WebsiteSetting)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RecordWebsiteSettingParse indicates an expected call of RecordWebsiteSettingParse.
+func (mr *MockRepostoryMockRecorder) RecordWebsiteSettingParse(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWebsiteSettingParse", reflect.TypeOf((*MockRepostory)(nil).RecordWebsiteSettingParse), arg0, arg1, arg2, arg3)
+}
+
+// RenameUserTag mocks base method.
+func (m *MockRepostory) RenameUserTag(arg0, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameUserTag", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameUserTag indicates an expected call of RenameUserTag.
+func (mr *MockRepostoryMockRecorder) RenameUserTag(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameUserTag", reflect.TypeOf((*MockRepostory)(nil).RenameUserTag), arg0, arg1, arg2)
+}
+
+// RenameUserWebsiteGroup mocks base method.
+func (m *MockRepostory) RenameUserWebsiteGroup(arg0, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameUserWebsiteGroup", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameUserWebsiteGroup indicates an expected call of RenameUserWebsiteGroup.
+func (mr *MockRepostoryMockRecorder) RenameUserWebsiteGroup(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameUserWebsiteGroup", reflect.TypeOf((*MockRepostory)(nil).RenameUserWebsiteGroup), arg0, arg1, arg2)
+}
+
+// RenameWebsitePlaceholderGroups mocks base method.
+func (m *MockRepostory) RenameWebsitePlaceholderGroups(arg0, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameWebsitePlaceholderGroups", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameWebsitePlaceholderGroups indicates an expected call of RenameWebsitePlaceholderGroups.
+func (mr *MockRepostoryMockRecorder) RenameWebsitePlaceholderGroups(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameWebsitePlaceholderGroups", reflect.TypeOf((*MockRepostory)(nil).RenameWebsitePlaceholderGroups), arg0, arg1, arg2)
+}
+
+// SearchUserWebsiteGroups mocks base method.
+func (m *MockRepostory) SearchUserWebsiteGroups(arg0 string, arg1 model.UserWebsiteFilter, arg2 model.UserWebsiteSort) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchUserWebsiteGroups", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchUserWebsiteGroups indicates an expected call of SearchUserWebsiteGroups.
+func (mr *MockRepostoryMockRecorder) SearchUserWebsiteGroups(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchUserWebsiteGroups", reflect.TypeOf((*MockRepostory)(nil).SearchUserWebsiteGroups), arg0, arg1, arg2)
+}
+
+// SearchUserWebsites mocks base method.
+func (m *MockRepostory) SearchUserWebsites(arg0 string, arg1 model.UserWebsiteFilter, arg2 model.UserWebsitePage) (model.UserWebsites, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchUserWebsites", arg0, arg1, arg2)
+	ret0, _ := ret[0].(model.UserWebsites)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchUserWebsites indicates an expected call of SearchUserWebsites.
+func (mr *MockRepostoryMockRecorder) SearchUserWebsites(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchUserWebsites", reflect.TypeOf((*MockRepostory)(nil).SearchUserWebsites), arg0, arg1, arg2)
+}
+
 // Stats mocks base method.
 func (m *MockRepostory) Stats() sql.DBStats {
 	m.ctrl.T.Helper()
@@ -210,6 +793,34 @@ func (mr *MockRepostoryMockRecorder) Stats() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockRepostory)(nil).Stats))
 }
 
+// UpdateImportJob mocks base method.
+func (m *MockRepostory) UpdateImportJob(arg0 *model.ImportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateImportJob", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateImportJob indicates an expected call of UpdateImportJob.
+func (mr *MockRepostoryMockRecorder) UpdateImportJob(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateImportJob", reflect.TypeOf((*MockRepostory)(nil).UpdateImportJob), arg0)
+}
+
+// UpdateUserGroupOrder mocks base method.
+func (m *MockRepostory) UpdateUserGroupOrder(arg0 string, arg1 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserGroupOrder", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserGroupOrder indicates an expected call of UpdateUserGroupOrder.
+func (mr *MockRepostoryMockRecorder) UpdateUserGroupOrder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserGroupOrder", reflect.TypeOf((*MockRepostory)(nil).UpdateUserGroupOrder), arg0, arg1)
+}
+
 // UpdateUserWebsite mocks base method.
 func (m *MockRepostory) UpdateUserWebsite(arg0 *model.UserWebsite) error {
 	m.ctrl.T.Helper()
@@ -237,3 +848,31 @@ func (mr *MockRepostoryMockRecorder) UpdateWebsite(arg0 interface{}) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebsite", reflect.TypeOf((*MockRepostory)(nil).UpdateWebsite), arg0)
 }
+
+// UpsertWorkerStatus mocks base method.
+func (m *MockRepostory) UpsertWorkerStatus(arg0 *model.WorkerStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkerStatus", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkerStatus indicates an expected call of UpsertWorkerStatus.
+func (mr *MockRepostoryMockRecorder) UpsertWorkerStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkerStatus", reflect.TypeOf((*MockRepostory)(nil).UpsertWorkerStatus), arg0)
+}
+
+// WithTx mocks base method.
+func (m *MockRepostory) WithTx(arg0 func(repository.Repostory) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockRepostoryMockRecorder) WithTx(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockRepostory)(nil).WithTx), arg0)
+}
@@ -2,10 +2,19 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
+	"time"
 
 	"github.com/htchan/WebHistory/internal/model"
 )
 
+var (
+	// ErrNotFound is returned when the requested record does not exist
+	ErrNotFound = errors.New("record not found")
+	// ErrDuplicate is returned when the record conflicts with an existing one
+	ErrDuplicate = errors.New("record already exists")
+)
+
 //go:generate mockgen -destination=mockrepo/mockrepo.go -package=mockrepo . Repostory
 type Repostory interface {
 	CreateWebsite(*model.Website) error
@@ -20,11 +29,73 @@ type Repostory interface {
 	DeleteUserWebsite(*model.UserWebsite) error
 
 	FindUserWebsites(userUUID string) (model.UserWebsites, error)
+	SearchUserWebsites(userUUID string, filter model.UserWebsiteFilter, page model.UserWebsitePage) (model.UserWebsites, error)
+	// SearchUserWebsiteGroups returns names of groups having websites
+	// matching filter, ordered by their first website in sort order
+	SearchUserWebsiteGroups(userUUID string, filter model.UserWebsiteFilter, order model.UserWebsiteSort) ([]string, error)
 	FindUserWebsitesByGroup(userUUID, group string) (model.WebsiteGroup, error)
 	FindUserWebsite(userUUID, websiteUUID string) (*model.UserWebsite, error)
 
+	MoveUserWebsites(userUUID, group string, websiteUUIDs []string) error
+	RenameUserWebsiteGroup(userUUID, group, newGroup string) error
+	// RenameWebsitePlaceholderGroups renames group of user websites of
+	// website which are still named by placeholder
+	RenameWebsitePlaceholderGroups(websiteUUID, placeholder, group string) error
+	FindUserGroupOrder(userUUID string) ([]string, error)
+	UpdateUserGroupOrder(userUUID string, groups []string) error
+
+	CreateUserWebsiteTag(*model.UserWebsiteTag) error
+	DeleteUserWebsiteTag(*model.UserWebsiteTag) error
+	FindUserTags(userUUID string) ([]string, error)
+	RenameUserTag(userUUID, tag, newTag string) error
+	DeleteUserTag(userUUID, tag string) error
+
 	FindWebsiteSettings() ([]model.WebsiteSetting, error)
 	FindWebsiteSetting(host string) (*model.WebsiteSetting, error)
+	// RecordWebsiteSettingParse counts parse result against setting of domain
+	// atomically, it returns the updated setting and if it was broken before
+	RecordWebsiteSettingParse(domain string, success bool, threshold int, now time.Time) (*model.WebsiteSetting, bool, error)
+
+	CreateWebsiteUpdateRequest(*model.WebsiteUpdateRequest) error
+	ClaimWebsiteUpdateRequests(limit int) ([]model.WebsiteUpdateRequest, error)
+
+	CreateUser(*model.User) error
+	FindUserByUsername(username string) (*model.User, error)
+	CreateAPIToken(*model.APIToken) error
+	FindAPIToken(tokenHash string) (*model.APIToken, error)
+	FindAPITokens(userUUID string) ([]model.APIToken, error)
+	DeleteAPIToken(userUUID, tokenUUID string) error
+
+	CreateGroupShare(*model.GroupShare) error
+	FindGroupShare(uuid string) (*model.GroupShare, error)
+	FindGroupShareByGroup(ownerUUID, group string) (*model.GroupShare, error)
+	FindGroupShares(ownerUUID string) ([]model.GroupShare, error)
+	DeleteGroupShare(uuid string) error
+	CreateGroupShareSubscription(*model.GroupShareSubscription) error
+	DeleteGroupShareSubscription(shareUUID, userUUID string) error
+	FindGroupShareSubscriptions(shareUUID string) ([]model.GroupShareSubscription, error)
+	FindUserGroupShareSubscriptions(userUUID string) ([]model.GroupShareSubscription, error)
+
+	CreateGroupPublicLink(*model.GroupPublicLink) error
+	FindGroupPublicLink(token string) (*model.GroupPublicLink, error)
+	FindGroupPublicLinkByGroup(ownerUUID, group string) (*model.GroupPublicLink, error)
+	DeleteGroupPublicLink(ownerUUID, group string) error
+
+	CreateImportJob(*model.ImportJob) error
+	UpdateImportJob(*model.ImportJob) error
+	FindImportJob(userUUID, uuid string) (*model.ImportJob, error)
+	// DeleteImportJobs deletes import jobs created before t
+	DeleteImportJobs(before time.Time) error
+
+	FindWebsiteSubscriberCounts() (map[string]int, error)
+	CountWebsiteUpdateRequests() (int, error)
+	UpsertWorkerStatus(*model.WorkerStatus) error
+	FindWorkerStatuses() ([]model.WorkerStatus, error)
+	DeleteWorkerStatus(workerID string) error
+
+	// WithTx runs fn with a repository of a transaction, changes made by fn
+	// are rolled back if it returns error
+	WithTx(fn func(Repostory) error) error
 
 	Stats() sql.DBStats
 }
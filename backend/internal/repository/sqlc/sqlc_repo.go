@@ -3,17 +3,23 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/htchan/WebHistory/internal/config"
 	"github.com/htchan/WebHistory/internal/model"
 	"github.com/htchan/WebHistory/internal/repository"
 	"github.com/htchan/WebHistory/internal/sqlc"
+	"github.com/lib/pq"
 )
 
 type SqlcRepo struct {
 	ctx   context.Context
+	sqlDB *sql.DB
+	tx    *sql.Tx
 	db    *sqlc.Queries
 	stats func() sql.DBStats
 	conf  *config.WebsiteConfig
@@ -24,85 +30,267 @@ var _ repository.Repostory = &SqlcRepo{}
 func NewRepo(db *sql.DB, conf *config.WebsiteConfig) *SqlcRepo {
 	return &SqlcRepo{
 		ctx:   context.Background(),
+		sqlDB: db,
 		db:    sqlc.New(db),
 		stats: db.Stats,
 		conf:  conf,
 	}
 }
 
+// WithTx runs fn with a repo bound to a transaction, the transaction is
+// rolled back if fn fails. Nested calls join the outer transaction
+func (r *SqlcRepo) WithTx(fn func(repository.Repostory) error) error {
+	return r.withTx(func(txRepo *SqlcRepo) error { return fn(txRepo) })
+}
+
+func (r *SqlcRepo) withTx(fn func(*SqlcRepo) error) error {
+	if r.tx != nil {
+		return fn(r)
+	}
+
+	tx, err := r.sqlDB.BeginTx(r.ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction fail: %w", err)
+	}
+
+	txRepo := *r
+	txRepo.tx = tx
+	txRepo.db = r.db.WithTx(tx)
+	if err := fn(&txRepo); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return errors.Join(err, fmt.Errorf("rollback transaction fail: %w", rollbackErr))
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction fail: %w", err)
+	}
+
+	return nil
+}
+
+// fromSqlError marks errors of missing or duplicated record, so callers can
+// tell them from other database errors
+func fromSqlError(err error) error {
+	var pqErr *pq.Error
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return fmt.Errorf("%w: %w", repository.ErrNotFound, err)
+	case errors.As(err, &pqErr) && pqErr.Code == "23505":
+		return fmt.Errorf("%w: %w", repository.ErrDuplicate, err)
+	default:
+		return err
+	}
+}
+
 func toSqlString(s string) sql.NullString {
 	return sql.NullString{String: s, Valid: true}
 }
 
+func toOptionalSqlString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
 func toSqlTime(t time.Time) sql.NullTime {
 	return sql.NullTime{Time: t, Valid: true}
 }
 
+func fromSqlTime(t sql.NullTime) time.Time {
+	if !t.Valid {
+		return time.Time{}
+	}
+
+	return t.Time.UTC().Truncate(time.Second)
+}
+
+func fromSqlItems(s sql.NullString) []model.WebsiteItem {
+	var items []model.WebsiteItem
+	if !s.Valid || s.String == "" {
+		return items
+	}
+
+	json.Unmarshal([]byte(s.String), &items)
+
+	return items
+}
+
+func toSqlItems(items []model.WebsiteItem) sql.NullString {
+	if len(items) == 0 {
+		return sql.NullString{}
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		return sql.NullString{}
+	}
+
+	return toSqlString(string(data))
+}
+
 func fromSqlcWebsite(webModel sqlc.Website) model.Website {
 	return model.Website{
-		UUID:       webModel.Uuid.String,
-		URL:        webModel.Url.String,
-		Title:      webModel.Title.String,
-		RawContent: webModel.Content.String,
-		UpdateTime: webModel.UpdateTime.Time.UTC().Truncate(time.Second),
+		UUID:               webModel.Uuid.String,
+		URL:                webModel.Url.String,
+		Title:              webModel.Title.String,
+		RawContent:         webModel.Content.String,
+		PreviousRawContent: webModel.PreviousContent.String,
+		Items:              fromSqlItems(webModel.Items),
+		UpdateTime:         webModel.UpdateTime.Time.UTC().Truncate(time.Second),
+		Health:             webModel.Health.String,
 	}
 }
 
 func fromSqlcWebsiteSetting(webModel sqlc.WebsiteSetting) model.WebsiteSetting {
 	return model.WebsiteSetting{
-		Domain:               webModel.Domain.String,
-		TitleGoquerySelector: webModel.TitleGoquerySelector.String,
-		DatesGoquerySelector: webModel.DateGoquerySelector.String,
-		FocusIndexFrom:       int(webModel.FocusIndexFrom.Int32),
-		FocusIndexTo:         int(webModel.FocusIndexTo.Int32),
+		Domain:                  webModel.Domain.String,
+		TitleGoquerySelector:    webModel.TitleGoquerySelector.String,
+		DatesGoquerySelector:    webModel.DateGoquerySelector.String,
+		FocusIndexFrom:          int(webModel.FocusIndexFrom.Int32),
+		FocusIndexTo:            int(webModel.FocusIndexTo.Int32),
+		ConsecutiveFailures:     int(webModel.ConsecutiveFailures.Int32),
+		BrokenAt:                fromSqlTime(webModel.BrokenAt),
+		ItemNameGoquerySelector: webModel.ItemNameGoquerySelector.String,
+		ItemLinkGoquerySelector: webModel.ItemLinkGoquerySelector.String,
 	}
 }
 
 func fromSqlcListUserWebsitesRow(userWebModel sqlc.ListUserWebsitesRow) model.UserWebsite {
 	return model.UserWebsite{
-		WebsiteUUID: userWebModel.WebsiteUuid.String,
-		UserUUID:    userWebModel.UserUuid.String,
-		GroupName:   userWebModel.GroupName.String,
-		AccessTime:  userWebModel.AccessTime.Time.UTC().Truncate(time.Second),
+		WebsiteUUID:  userWebModel.WebsiteUuid.String,
+		UserUUID:     userWebModel.UserUuid.String,
+		GroupName:    userWebModel.GroupName.String,
+		AccessTime:   userWebModel.AccessTime.Time.UTC().Truncate(time.Second),
+		LastReadItem: userWebModel.LastReadItem.String,
+		SnoozeUntil:  fromSqlTime(userWebModel.SnoozeUntil),
+		Muted:        userWebModel.Muted.Bool,
+		ShareUUID:    userWebModel.ShareUuid.String,
 		Website: model.Website{
 			UUID:       userWebModel.WebsiteUuid.String,
 			URL:        userWebModel.Url.String,
 			Title:      userWebModel.Title.String,
 			UpdateTime: userWebModel.UpdateTime.Time.UTC().Truncate(time.Second),
+			Items:      fromSqlItems(userWebModel.Items),
+			Health:     userWebModel.Health.String,
 		},
 	}
 }
 
 func fromSqlcListUserWebsitesByGroupRow(userWebModel sqlc.ListUserWebsitesByGroupRow) model.UserWebsite {
 	return model.UserWebsite{
-		WebsiteUUID: userWebModel.WebsiteUuid.String,
-		UserUUID:    userWebModel.UserUuid.String,
-		GroupName:   userWebModel.GroupName.String,
-		AccessTime:  userWebModel.AccessTime.Time.UTC().Truncate(time.Second),
+		WebsiteUUID:  userWebModel.WebsiteUuid.String,
+		UserUUID:     userWebModel.UserUuid.String,
+		GroupName:    userWebModel.GroupName.String,
+		AccessTime:   userWebModel.AccessTime.Time.UTC().Truncate(time.Second),
+		LastReadItem: userWebModel.LastReadItem.String,
+		SnoozeUntil:  fromSqlTime(userWebModel.SnoozeUntil),
+		Muted:        userWebModel.Muted.Bool,
+		ShareUUID:    userWebModel.ShareUuid.String,
 		Website: model.Website{
 			UUID:       userWebModel.WebsiteUuid.String,
 			URL:        userWebModel.Url.String,
 			Title:      userWebModel.Title.String,
 			UpdateTime: userWebModel.UpdateTime.Time.UTC().Truncate(time.Second),
+			Items:      fromSqlItems(userWebModel.Items),
+			Health:     userWebModel.Health.String,
 		},
 	}
 }
 
 func fromSqlcGetUserWebsiteRow(userWebModel sqlc.GetUserWebsiteRow) model.UserWebsite {
 	return model.UserWebsite{
-		WebsiteUUID: userWebModel.WebsiteUuid.String,
-		UserUUID:    userWebModel.UserUuid.String,
-		GroupName:   userWebModel.GroupName.String,
-		AccessTime:  userWebModel.AccessTime.Time.UTC().Truncate(time.Second),
+		WebsiteUUID:  userWebModel.WebsiteUuid.String,
+		UserUUID:     userWebModel.UserUuid.String,
+		GroupName:    userWebModel.GroupName.String,
+		AccessTime:   userWebModel.AccessTime.Time.UTC().Truncate(time.Second),
+		LastReadItem: userWebModel.LastReadItem.String,
+		SnoozeUntil:  fromSqlTime(userWebModel.SnoozeUntil),
+		Muted:        userWebModel.Muted.Bool,
+		ShareUUID:    userWebModel.ShareUuid.String,
+		Website: model.Website{
+			UUID:       userWebModel.WebsiteUuid.String,
+			URL:        userWebModel.Url.String,
+			Title:      userWebModel.Title.String,
+			UpdateTime: userWebModel.UpdateTime.Time.UTC().Truncate(time.Second),
+			Items:      fromSqlItems(userWebModel.Items),
+			Health:     userWebModel.Health.String,
+		},
+	}
+}
+
+func fromSqlcSearchUserWebsitesRow(userWebModel sqlc.SearchUserWebsitesRow) model.UserWebsite {
+	return model.UserWebsite{
+		WebsiteUUID:  userWebModel.WebsiteUuid.String,
+		UserUUID:     userWebModel.UserUuid.String,
+		GroupName:    userWebModel.GroupName.String,
+		AccessTime:   userWebModel.AccessTime.Time.UTC().Truncate(time.Second),
+		LastReadItem: userWebModel.LastReadItem.String,
+		SnoozeUntil:  fromSqlTime(userWebModel.SnoozeUntil),
+		Muted:        userWebModel.Muted.Bool,
+		ShareUUID:    userWebModel.ShareUuid.String,
 		Website: model.Website{
 			UUID:       userWebModel.WebsiteUuid.String,
 			URL:        userWebModel.Url.String,
 			Title:      userWebModel.Title.String,
 			UpdateTime: userWebModel.UpdateTime.Time.UTC().Truncate(time.Second),
+			Items:      fromSqlItems(userWebModel.Items),
+			Health:     userWebModel.Health.String,
 		},
 	}
 }
 
+// escapeLike escapes wildcard characters of pattern used in LIKE
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func toSqlcSearchUserWebsitesParams(userUUID string, filter model.UserWebsiteFilter, page model.UserWebsitePage) sqlc.SearchUserWebsitesParams {
+	params := sqlc.SearchUserWebsitesParams{
+		UserUuid:     toSqlString(userUUID),
+		Host:         toOptionalSqlString(filter.Host),
+		UpdatedOnly:  filter.UpdatedOnly,
+		Health:       toOptionalSqlString(filter.Health),
+		Tags:         append([]string{}, filter.Tags...),
+		MatchAllTags: filter.MatchAllTags,
+		GroupNames:   append([]string{}, filter.Groups...),
+		SortKey:      page.Sort.Key,
+		SortDesc:     page.Sort.Desc,
+		PageLimit:    sql.NullInt32{Int32: int32(page.Limit), Valid: page.Limit > 0},
+	}
+	if filter.Search != "" {
+		params.Search = toSqlString("%" + escapeLike(filter.Search) + "%")
+	}
+	if page.Cursor != nil {
+		params.CursorUuid = toSqlString(page.Cursor.UUID)
+		params.CursorUnread = page.Cursor.Unread
+		params.CursorUpdateTime = page.Cursor.UpdateTime
+		params.CursorAccessTime = page.Cursor.AccessTime
+		params.CursorTitle = page.Cursor.Title
+	}
+
+	return params
+}
+
+func toSqlcSearchUserWebsiteGroupsParams(userUUID string, filter model.UserWebsiteFilter, order model.UserWebsiteSort) sqlc.SearchUserWebsiteGroupsParams {
+	params := sqlc.SearchUserWebsiteGroupsParams{
+		SortKey:      order.Key,
+		SortDesc:     order.Desc,
+		UserUuid:     toSqlString(userUUID),
+		Host:         toOptionalSqlString(filter.Host),
+		UpdatedOnly:  filter.UpdatedOnly,
+		Health:       toOptionalSqlString(filter.Health),
+		Tags:         append([]string{}, filter.Tags...),
+		MatchAllTags: filter.MatchAllTags,
+		GroupNames:   append([]string{}, filter.Groups...),
+	}
+	if filter.Search != "" {
+		params.Search = toSqlString("%" + escapeLike(filter.Search) + "%")
+	}
+
+	return params
+}
+
 func toSqlcListUserWebsitesByGroupParams(userUUID, groupName string) sqlc.ListUserWebsitesByGroupParams {
 	return sqlc.ListUserWebsitesByGroupParams{
 		UserUuid:  toSqlString(userUUID),
@@ -124,6 +312,8 @@ func toSqlcCreateWebsiteParams(web *model.Website) sqlc.CreateWebsiteParams {
 		Title:      toSqlString(web.Title),
 		Content:    toSqlString(web.RawContent),
 		UpdateTime: toSqlTime(web.UpdateTime),
+		Items:      toSqlItems(web.Items),
+		Health:     toOptionalSqlString(web.Health),
 	}
 }
 
@@ -133,25 +323,32 @@ func toSqlcCreateUserWebsiteParams(userWeb *model.UserWebsite) sqlc.CreateUserWe
 		WebsiteUuid: toSqlString(userWeb.WebsiteUUID),
 		AccessTime:  toSqlTime(userWeb.AccessTime),
 		GroupName:   toSqlString(userWeb.GroupName),
+		ShareUuid:   toOptionalSqlString(userWeb.ShareUUID),
 	}
 }
 
 func toSqlcUpdateWebsiteParams(web *model.Website) sqlc.UpdateWebsiteParams {
 	return sqlc.UpdateWebsiteParams{
-		Url:        toSqlString(web.URL),
-		Title:      toSqlString(web.Title),
-		Content:    toSqlString(web.RawContent),
-		UpdateTime: toSqlTime(web.UpdateTime),
-		Uuid:       toSqlString(web.UUID),
+		Url:             toSqlString(web.URL),
+		Title:           toSqlString(web.Title),
+		Content:         toSqlString(web.RawContent),
+		PreviousContent: toSqlString(web.PreviousRawContent),
+		Items:           toSqlItems(web.Items),
+		UpdateTime:      toSqlTime(web.UpdateTime),
+		Health:          toOptionalSqlString(web.Health),
+		Uuid:            toSqlString(web.UUID),
 	}
 }
 
 func toSqlcUpdateUserWebsiteParams(userWeb *model.UserWebsite) sqlc.UpdateUserWebsiteParams {
 	return sqlc.UpdateUserWebsiteParams{
-		UserUuid:    toSqlString(userWeb.UserUUID),
-		WebsiteUuid: toSqlString(userWeb.WebsiteUUID),
-		AccessTime:  toSqlTime(userWeb.AccessTime),
-		GroupName:   toSqlString(userWeb.GroupName),
+		UserUuid:     toSqlString(userWeb.UserUUID),
+		WebsiteUuid:  toSqlString(userWeb.WebsiteUUID),
+		AccessTime:   toSqlTime(userWeb.AccessTime),
+		GroupName:    toSqlString(userWeb.GroupName),
+		LastReadItem: toSqlString(userWeb.LastReadItem),
+		SnoozeUntil:  sql.NullTime{Time: userWeb.SnoozeUntil, Valid: !userWeb.SnoozeUntil.IsZero()},
+		Muted:        sql.NullBool{Bool: userWeb.Muted, Valid: true},
 	}
 }
 
@@ -169,12 +366,14 @@ func (r *SqlcRepo) CreateWebsite(web *model.Website) error {
 		toSqlcCreateWebsiteParams(web),
 	)
 	if err != nil {
-		return err
+		return fromSqlError(err)
 	}
 
 	web.UUID = webModel.Uuid.String
 	web.Title, web.RawContent = webModel.Title.String, webModel.Content.String
+	web.Items = fromSqlItems(webModel.Items)
 	web.UpdateTime = webModel.UpdateTime.Time
+	web.Health = webModel.Health.String
 	web.Conf = r.conf
 
 	return nil
@@ -186,7 +385,7 @@ func (r *SqlcRepo) UpdateWebsite(web *model.Website) error {
 		toSqlcUpdateWebsiteParams(web),
 	)
 	if err != nil {
-		return fmt.Errorf("update website fail: %w", err)
+		return fmt.Errorf("update website fail: %w", fromSqlError(err))
 	}
 
 	return nil
@@ -198,7 +397,7 @@ func (r *SqlcRepo) DeleteWebsite(web *model.Website) error {
 		toSqlString(web.UUID),
 	)
 	if err != nil {
-		return fmt.Errorf("fail to delete website: %w", err)
+		return fmt.Errorf("fail to delete website: %w", fromSqlError(err))
 	}
 
 	return nil
@@ -207,7 +406,7 @@ func (r *SqlcRepo) DeleteWebsite(web *model.Website) error {
 func (r *SqlcRepo) FindWebsites() ([]model.Website, error) {
 	webModels, err := r.db.ListWebsites(r.ctx)
 	if err != nil {
-		return nil, fmt.Errorf("list websites fail: %w", err)
+		return nil, fmt.Errorf("list websites fail: %w", fromSqlError(err))
 	}
 
 	webs := make([]model.Website, len(webModels))
@@ -222,7 +421,7 @@ func (r *SqlcRepo) FindWebsites() ([]model.Website, error) {
 func (r *SqlcRepo) FindWebsite(uuid string) (*model.Website, error) {
 	webModel, err := r.db.GetWebsite(r.ctx, sql.NullString{String: uuid, Valid: true})
 	if err != nil {
-		return nil, fmt.Errorf("get website fail: %w", err)
+		return nil, fmt.Errorf("get website fail: %w", fromSqlError(err))
 	}
 
 	web := fromSqlcWebsite(webModel)
@@ -233,14 +432,18 @@ func (r *SqlcRepo) FindWebsite(uuid string) (*model.Website, error) {
 func (r *SqlcRepo) CreateUserWebsite(web *model.UserWebsite) error {
 	userWebModel, err := r.db.CreateUserWebsite(r.ctx, toSqlcCreateUserWebsiteParams(web))
 	if err != nil {
-		return fmt.Errorf("create user website fail: %w", err)
+		return fmt.Errorf("create user website fail: %w", fromSqlError(err))
 	}
 
 	web.GroupName = userWebModel.GroupName.String
 	web.AccessTime = userWebModel.AccessTime.Time
+	web.LastReadItem = userWebModel.LastReadItem.String
+	web.SnoozeUntil = fromSqlTime(userWebModel.SnoozeUntil)
+	web.Muted = userWebModel.Muted.Bool
+	web.ShareUUID = userWebModel.ShareUuid.String
 	tempWeb, err := r.FindWebsite(web.WebsiteUUID)
 	if err != nil {
-		return fmt.Errorf("assign website fail: %w", err)
+		return fmt.Errorf("assign website fail: %w", fromSqlError(err))
 	}
 
 	web.Website = *tempWeb
@@ -251,7 +454,7 @@ func (r *SqlcRepo) CreateUserWebsite(web *model.UserWebsite) error {
 func (r *SqlcRepo) UpdateUserWebsite(web *model.UserWebsite) error {
 	_, err := r.db.UpdateUserWebsite(r.ctx, toSqlcUpdateUserWebsiteParams(web))
 	if err != nil {
-		return fmt.Errorf("fail to update user website: %w", err)
+		return fmt.Errorf("fail to update user website: %w", fromSqlError(err))
 	}
 
 	return nil
@@ -260,7 +463,15 @@ func (r *SqlcRepo) UpdateUserWebsite(web *model.UserWebsite) error {
 func (r *SqlcRepo) DeleteUserWebsite(web *model.UserWebsite) error {
 	err := r.db.DeleteUserWebsite(r.ctx, toSqlcDeleteUserWebsiteParams(web))
 	if err != nil {
-		return fmt.Errorf("delete user website fail: %w", err)
+		return fmt.Errorf("delete user website fail: %w", fromSqlError(err))
+	}
+
+	err = r.db.DeleteUserWebsiteTags(r.ctx, sqlc.DeleteUserWebsiteTagsParams{
+		UserUuid:    toSqlString(web.UserUUID),
+		WebsiteUuid: toSqlString(web.WebsiteUUID),
+	})
+	if err != nil {
+		return fmt.Errorf("delete user website tags fail: %w", fromSqlError(err))
 	}
 
 	return nil
@@ -269,13 +480,19 @@ func (r *SqlcRepo) DeleteUserWebsite(web *model.UserWebsite) error {
 func (r *SqlcRepo) FindUserWebsites(userUUID string) (model.UserWebsites, error) {
 	userWebModels, err := r.db.ListUserWebsites(r.ctx, toSqlString(userUUID))
 	if err != nil {
-		return nil, fmt.Errorf("list user websites fail: %w", err)
+		return nil, fmt.Errorf("list user websites fail: %w", fromSqlError(err))
+	}
+
+	tags, err := r.userWebsiteTags(userUUID)
+	if err != nil {
+		return nil, err
 	}
 
 	webs := make(model.UserWebsites, len(userWebModels))
 	for i, userWebModel := range userWebModels {
 		webs[i] = fromSqlcListUserWebsitesRow(userWebModel)
 		webs[i].Website.Conf = r.conf
+		webs[i].Tags = tags[webs[i].WebsiteUUID]
 	}
 
 	return webs, nil
@@ -284,13 +501,19 @@ func (r *SqlcRepo) FindUserWebsites(userUUID string) (model.UserWebsites, error)
 func (r *SqlcRepo) FindUserWebsitesByGroup(userUUID, groupName string) (model.WebsiteGroup, error) {
 	userWebModels, err := r.db.ListUserWebsitesByGroup(r.ctx, toSqlcListUserWebsitesByGroupParams(userUUID, groupName))
 	if err != nil {
-		return nil, fmt.Errorf("find user websites by group fail: %w", err)
+		return nil, fmt.Errorf("find user websites by group fail: %w", fromSqlError(err))
+	}
+
+	tags, err := r.userWebsiteTags(userUUID)
+	if err != nil {
+		return nil, err
 	}
 
 	group := make(model.WebsiteGroup, len(userWebModels))
 	for i, userWebModel := range userWebModels {
 		group[i] = fromSqlcListUserWebsitesByGroupRow(userWebModel)
 		group[i].Website.Conf = r.conf
+		group[i].Tags = tags[group[i].WebsiteUUID]
 	}
 
 	return group, nil
@@ -299,19 +522,264 @@ func (r *SqlcRepo) FindUserWebsitesByGroup(userUUID, groupName string) (model.We
 func (r *SqlcRepo) FindUserWebsite(userUUID, websiteUUID string) (*model.UserWebsite, error) {
 	userWebModel, err := r.db.GetUserWebsite(r.ctx, toSqlcGetUserWebsitesParams(userUUID, websiteUUID))
 	if err != nil {
-		return nil, fmt.Errorf("get user website fail: %w", err)
+		return nil, fmt.Errorf("get user website fail: %w", fromSqlError(err))
+	}
+
+	tags, err := r.userWebsiteTags(userUUID)
+	if err != nil {
+		return nil, err
 	}
 
 	web := fromSqlcGetUserWebsiteRow(userWebModel)
 	web.Website.Conf = r.conf
+	web.Tags = tags[web.WebsiteUUID]
 
 	return &web, nil
 }
 
+func (r *SqlcRepo) MoveUserWebsites(userUUID, group string, websiteUUIDs []string) error {
+	err := r.db.MoveUserWebsites(r.ctx, sqlc.MoveUserWebsitesParams{
+		UserUuid:     toSqlString(userUUID),
+		GroupName:    toSqlString(group),
+		WebsiteUuids: websiteUUIDs,
+	})
+	if err != nil {
+		return fmt.Errorf("move user websites fail: %w", fromSqlError(err))
+	}
+
+	return nil
+}
+
+func (r *SqlcRepo) RenameWebsitePlaceholderGroups(websiteUUID, placeholder, group string) error {
+	err := r.db.RenameWebsitePlaceholderGroups(r.ctx, sqlc.RenameWebsitePlaceholderGroupsParams{
+		GroupName:   toSqlString(group),
+		WebsiteUuid: toSqlString(websiteUUID),
+		Placeholder: toSqlString(placeholder),
+	})
+	if err != nil {
+		return fmt.Errorf("rename website placeholder groups fail: %w", fromSqlError(err))
+	}
+
+	return nil
+}
+
+func (r *SqlcRepo) RenameUserWebsiteGroup(userUUID, group, newGroup string) error {
+	return r.withTx(func(tx *SqlcRepo) error {
+		err := tx.db.UpdateUserWebsitesGroupName(tx.ctx, sqlc.UpdateUserWebsitesGroupNameParams{
+			UserUuid:    toSqlString(userUUID),
+			GroupName:   toSqlString(group),
+			GroupName_2: toSqlString(newGroup),
+		})
+		if err != nil {
+			return fmt.Errorf("update user websites group name fail: %w", fromSqlError(err))
+		}
+
+		err = tx.db.RenameUserWebsiteGroup(tx.ctx, sqlc.RenameUserWebsiteGroupParams{
+			UserUuid:    toSqlString(userUUID),
+			GroupName:   toSqlString(group),
+			GroupName_2: toSqlString(newGroup),
+		})
+		if err != nil {
+			return fmt.Errorf("rename user website group fail: %w", fromSqlError(err))
+		}
+
+		// position of old group is left if new group already has a position
+		err = tx.db.DeleteUserWebsiteGroup(tx.ctx, sqlc.DeleteUserWebsiteGroupParams{
+			UserUuid:  toSqlString(userUUID),
+			GroupName: toSqlString(group),
+		})
+		if err != nil {
+			return fmt.Errorf("delete user website group fail: %w", fromSqlError(err))
+		}
+
+		err = tx.db.RenameGroupShare(tx.ctx, sqlc.RenameGroupShareParams{
+			OwnerUuid:   toSqlString(userUUID),
+			GroupName:   toSqlString(group),
+			GroupName_2: toSqlString(newGroup),
+		})
+		if err != nil {
+			return fmt.Errorf("rename group share fail: %w", fromSqlError(err))
+		}
+
+		// share of old group is left if new group is shared already
+		share, err := tx.FindGroupShareByGroup(userUUID, group)
+		if err == nil {
+			err = tx.DeleteGroupShare(share.UUID)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = tx.db.RenameGroupPublicLink(tx.ctx, sqlc.RenameGroupPublicLinkParams{
+			OwnerUuid:   toSqlString(userUUID),
+			GroupName:   toSqlString(group),
+			GroupName_2: toSqlString(newGroup),
+		})
+		if err != nil {
+			return fmt.Errorf("rename group public link fail: %w", fromSqlError(err))
+		}
+
+		// public link of old group is left if new group has a link already
+		return tx.DeleteGroupPublicLink(userUUID, group)
+	})
+}
+
+func (r *SqlcRepo) FindUserGroupOrder(userUUID string) ([]string, error) {
+	groupModels, err := r.db.ListUserWebsiteGroups(r.ctx, toSqlString(userUUID))
+	if err != nil {
+		return nil, fmt.Errorf("list user website groups fail: %w", fromSqlError(err))
+	}
+
+	groups := make([]string, len(groupModels))
+	for i, groupModel := range groupModels {
+		groups[i] = groupModel.String
+	}
+
+	return groups, nil
+}
+
+func (r *SqlcRepo) UpdateUserGroupOrder(userUUID string, groups []string) error {
+	return r.withTx(func(tx *SqlcRepo) error {
+		err := tx.db.DeleteUserWebsiteGroups(tx.ctx, toSqlString(userUUID))
+		if err != nil {
+			return fmt.Errorf("delete user website groups fail: %w", fromSqlError(err))
+		}
+
+		for i, group := range groups {
+			err = tx.db.CreateUserWebsiteGroup(tx.ctx, sqlc.CreateUserWebsiteGroupParams{
+				UserUuid:  toSqlString(userUUID),
+				GroupName: toSqlString(group),
+				Position:  sql.NullInt32{Int32: int32(i), Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("create user website group fail: %w", fromSqlError(err))
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *SqlcRepo) SearchUserWebsites(userUUID string, filter model.UserWebsiteFilter, page model.UserWebsitePage) (model.UserWebsites, error) {
+	userWebModels, err := r.db.SearchUserWebsites(r.ctx, toSqlcSearchUserWebsitesParams(userUUID, filter, page))
+	if err != nil {
+		return nil, fmt.Errorf("search user websites fail: %w", fromSqlError(err))
+	}
+
+	tags, err := r.userWebsiteTags(userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	webs := make(model.UserWebsites, len(userWebModels))
+	for i, userWebModel := range userWebModels {
+		webs[i] = fromSqlcSearchUserWebsitesRow(userWebModel)
+		webs[i].Website.Conf = r.conf
+		webs[i].Tags = tags[webs[i].WebsiteUUID]
+	}
+
+	return webs, nil
+}
+
+func (r *SqlcRepo) SearchUserWebsiteGroups(userUUID string, filter model.UserWebsiteFilter, order model.UserWebsiteSort) ([]string, error) {
+	groupModels, err := r.db.SearchUserWebsiteGroups(r.ctx, toSqlcSearchUserWebsiteGroupsParams(userUUID, filter, order))
+	if err != nil {
+		return nil, fmt.Errorf("search user website groups fail: %w", fromSqlError(err))
+	}
+
+	groups := make([]string, len(groupModels))
+	for i, groupModel := range groupModels {
+		groups[i] = groupModel.String
+	}
+
+	return groups, nil
+}
+
+// userWebsiteTags returns tags of user grouped by website uuid
+func (r *SqlcRepo) userWebsiteTags(userUUID string) (map[string][]string, error) {
+	tagModels, err := r.db.ListUserWebsiteTags(r.ctx, toSqlString(userUUID))
+	if err != nil {
+		return nil, fmt.Errorf("list user website tags fail: %w", fromSqlError(err))
+	}
+
+	tags := make(map[string][]string)
+	for _, tagModel := range tagModels {
+		tags[tagModel.WebsiteUuid.String] = append(tags[tagModel.WebsiteUuid.String], tagModel.Tag.String)
+	}
+
+	return tags, nil
+}
+
+func (r *SqlcRepo) CreateUserWebsiteTag(tag *model.UserWebsiteTag) error {
+	err := r.db.CreateUserWebsiteTag(r.ctx, sqlc.CreateUserWebsiteTagParams{
+		UserUuid:    toSqlString(tag.UserUUID),
+		WebsiteUuid: toSqlString(tag.WebsiteUUID),
+		Tag:         toSqlString(tag.Tag),
+	})
+	if err != nil {
+		return fmt.Errorf("create user website tag fail: %w", fromSqlError(err))
+	}
+
+	return nil
+}
+
+func (r *SqlcRepo) DeleteUserWebsiteTag(tag *model.UserWebsiteTag) error {
+	err := r.db.DeleteUserWebsiteTag(r.ctx, sqlc.DeleteUserWebsiteTagParams{
+		UserUuid:    toSqlString(tag.UserUUID),
+		WebsiteUuid: toSqlString(tag.WebsiteUUID),
+		Tag:         toSqlString(tag.Tag),
+	})
+	if err != nil {
+		return fmt.Errorf("delete user website tag fail: %w", fromSqlError(err))
+	}
+
+	return nil
+}
+
+func (r *SqlcRepo) FindUserTags(userUUID string) ([]string, error) {
+	tagModels, err := r.db.ListUserTags(r.ctx, toSqlString(userUUID))
+	if err != nil {
+		return nil, fmt.Errorf("list user tags fail: %w", fromSqlError(err))
+	}
+
+	tags := make([]string, len(tagModels))
+	for i, tagModel := range tagModels {
+		tags[i] = tagModel.String
+	}
+
+	return tags, nil
+}
+
+func (r *SqlcRepo) RenameUserTag(userUUID, tag, newTag string) error {
+	err := r.db.RenameUserTag(r.ctx, sqlc.RenameUserTagParams{
+		UserUuid: toSqlString(userUUID),
+		Tag:      toSqlString(tag),
+		Tag_2:    toSqlString(newTag),
+	})
+	if err != nil {
+		return fmt.Errorf("rename user tag fail: %w", fromSqlError(err))
+	}
+
+	// websites already tagged by new tag keep the old tag after rename
+	return r.DeleteUserTag(userUUID, tag)
+}
+
+func (r *SqlcRepo) DeleteUserTag(userUUID, tag string) error {
+	err := r.db.DeleteUserTag(r.ctx, sqlc.DeleteUserTagParams{
+		UserUuid: toSqlString(userUUID),
+		Tag:      toSqlString(tag),
+	})
+	if err != nil {
+		return fmt.Errorf("delete user tag fail: %w", fromSqlError(err))
+	}
+
+	return nil
+}
+
 func (r *SqlcRepo) FindWebsiteSettings() ([]model.WebsiteSetting, error) {
 	settingModels, err := r.db.ListWebsiteSettings(r.ctx)
 	if err != nil {
-		return nil, fmt.Errorf("list user websites fail: %w", err)
+		return nil, fmt.Errorf("list user websites fail: %w", fromSqlError(err))
 	}
 
 	settings := make([]model.WebsiteSetting, len(settingModels))
@@ -325,7 +793,7 @@ func (r *SqlcRepo) FindWebsiteSettings() ([]model.WebsiteSetting, error) {
 func (r *SqlcRepo) FindWebsiteSetting(domain string) (*model.WebsiteSetting, error) {
 	settingModel, err := r.db.GetWebsiteSetting(r.ctx, toSqlString(domain))
 	if err != nil {
-		return nil, fmt.Errorf("get website settings fail: %w", err)
+		return nil, fmt.Errorf("get website settings fail: %w", fromSqlError(err))
 	}
 
 	setting := fromSqlcWebsiteSetting(settingModel)
@@ -333,6 +801,497 @@ func (r *SqlcRepo) FindWebsiteSetting(domain string) (*model.WebsiteSetting, err
 	return &setting, nil
 }
 
+func (r *SqlcRepo) RecordWebsiteSettingParse(domain string, success bool, threshold int, now time.Time) (*model.WebsiteSetting, bool, error) {
+	row, err := r.db.RecordWebsiteSettingParse(r.ctx, sqlc.RecordWebsiteSettingParseParams{
+		Success:   success,
+		Threshold: int32(threshold),
+		Now:       now,
+		Domain:    toSqlString(domain),
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("record website setting parse fail: %w", fromSqlError(err))
+	}
+
+	setting := fromSqlcWebsiteSetting(sqlc.WebsiteSetting{
+		Domain:                  row.Domain,
+		FocusIndexFrom:          row.FocusIndexFrom,
+		FocusIndexTo:            row.FocusIndexTo,
+		TitleGoquerySelector:    row.TitleGoquerySelector,
+		DateGoquerySelector:     row.DateGoquerySelector,
+		ConsecutiveFailures:     row.ConsecutiveFailures,
+		BrokenAt:                row.BrokenAt,
+		ItemNameGoquerySelector: row.ItemNameGoquerySelector,
+		ItemLinkGoquerySelector: row.ItemLinkGoquerySelector,
+	})
+
+	return &setting, row.PreviousBrokenAt.Valid, nil
+}
+
+func (r *SqlcRepo) CreateWebsiteUpdateRequest(req *model.WebsiteUpdateRequest) error {
+	traceContext, err := json.Marshal(req.TraceContext)
+	if err != nil {
+		return fmt.Errorf("marshal trace context fail: %w", fromSqlError(err))
+	}
+
+	err = r.db.CreateWebsiteUpdateRequest(r.ctx, sqlc.CreateWebsiteUpdateRequestParams{
+		Uuid:         toSqlString(req.UUID),
+		WebsiteUuid:  toSqlString(req.WebsiteUUID),
+		TraceContext: toSqlString(string(traceContext)),
+		CreateTime:   toSqlTime(req.CreateTime),
+	})
+	if err != nil {
+		return fmt.Errorf("create website update request fail: %w", fromSqlError(err))
+	}
+
+	return nil
+}
+
+// ClaimWebsiteUpdateRequests removes and returns the oldest requests,
+// requests claimed by other worker concurrently are skipped
+func (r *SqlcRepo) ClaimWebsiteUpdateRequests(limit int) ([]model.WebsiteUpdateRequest, error) {
+	reqModels, err := r.db.ClaimWebsiteUpdateRequests(r.ctx, int32(limit))
+	if err != nil {
+		return nil, fmt.Errorf("claim website update requests fail: %w", fromSqlError(err))
+	}
+
+	reqs := make([]model.WebsiteUpdateRequest, len(reqModels))
+	for i, reqModel := range reqModels {
+		reqs[i] = model.WebsiteUpdateRequest{
+			UUID:        reqModel.Uuid.String,
+			WebsiteUUID: reqModel.WebsiteUuid.String,
+			CreateTime:  reqModel.CreateTime.Time,
+		}
+		if reqModel.TraceContext.Valid {
+			json.Unmarshal([]byte(reqModel.TraceContext.String), &reqs[i].TraceContext)
+		}
+	}
+
+	return reqs, nil
+}
+
+func (r *SqlcRepo) CreateUser(user *model.User) error {
+	err := r.db.CreateUser(r.ctx, sqlc.CreateUserParams{
+		Uuid:         toSqlString(user.UUID),
+		Username:     toSqlString(user.Username),
+		PasswordHash: toSqlString(user.PasswordHash),
+		CreateTime:   toSqlTime(user.CreateTime),
+	})
+	if err != nil {
+		return fmt.Errorf("create user fail: %w", fromSqlError(err))
+	}
+
+	return nil
+}
+
+func (r *SqlcRepo) FindUserByUsername(username string) (*model.User, error) {
+	userModel, err := r.db.GetUserByUsername(r.ctx, toSqlString(username))
+	if err != nil {
+		return nil, fmt.Errorf("find user fail: %w", fromSqlError(err))
+	}
+
+	return &model.User{
+		UUID:         userModel.Uuid.String,
+		Username:     userModel.Username.String,
+		PasswordHash: userModel.PasswordHash.String,
+		CreateTime:   fromSqlTime(userModel.CreateTime),
+	}, nil
+}
+
+func fromSqlcAPIToken(tokenModel sqlc.ApiToken) model.APIToken {
+	return model.APIToken{
+		UUID:       tokenModel.Uuid.String,
+		UserUUID:   tokenModel.UserUuid.String,
+		Name:       tokenModel.Name.String,
+		TokenHash:  tokenModel.TokenHash.String,
+		CreateTime: fromSqlTime(tokenModel.CreateTime),
+	}
+}
+
+func (r *SqlcRepo) CreateAPIToken(token *model.APIToken) error {
+	err := r.db.CreateAPIToken(r.ctx, sqlc.CreateAPITokenParams{
+		Uuid:       toSqlString(token.UUID),
+		UserUuid:   toSqlString(token.UserUUID),
+		Name:       toSqlString(token.Name),
+		TokenHash:  toSqlString(token.TokenHash),
+		CreateTime: toSqlTime(token.CreateTime),
+	})
+	if err != nil {
+		return fmt.Errorf("create api token fail: %w", fromSqlError(err))
+	}
+
+	return nil
+}
+
+func (r *SqlcRepo) FindAPIToken(tokenHash string) (*model.APIToken, error) {
+	tokenModel, err := r.db.GetAPITokenByHash(r.ctx, toSqlString(tokenHash))
+	if err != nil {
+		return nil, fmt.Errorf("find api token fail: %w", fromSqlError(err))
+	}
+
+	token := fromSqlcAPIToken(tokenModel)
+	return &token, nil
+}
+
+func (r *SqlcRepo) FindAPITokens(userUUID string) ([]model.APIToken, error) {
+	tokenModels, err := r.db.ListAPITokens(r.ctx, toSqlString(userUUID))
+	if err != nil {
+		return nil, fmt.Errorf("list api tokens fail: %w", fromSqlError(err))
+	}
+
+	tokens := make([]model.APIToken, len(tokenModels))
+	for i, tokenModel := range tokenModels {
+		tokens[i] = fromSqlcAPIToken(tokenModel)
+	}
+
+	return tokens, nil
+}
+
+func (r *SqlcRepo) DeleteAPIToken(userUUID, tokenUUID string) error {
+	err := r.db.DeleteAPIToken(r.ctx, sqlc.DeleteAPITokenParams{
+		UserUuid: toSqlString(userUUID),
+		Uuid:     toSqlString(tokenUUID),
+	})
+	if err != nil {
+		return fmt.Errorf("delete api token fail: %w", fromSqlError(err))
+	}
+
+	return nil
+}
+
+func fromSqlcGroupShare(shareModel sqlc.GroupShare) model.GroupShare {
+	return model.GroupShare{
+		UUID:       shareModel.Uuid.String,
+		OwnerUUID:  shareModel.OwnerUuid.String,
+		GroupName:  shareModel.GroupName.String,
+		Mode:       model.GroupShareMode(shareModel.Mode.String),
+		CreateTime: shareModel.CreateTime.Time,
+	}
+}
+
+func fromSqlcGroupShareSubscription(subModel sqlc.GroupShareSubscription) model.GroupShareSubscription {
+	return model.GroupShareSubscription{
+		ShareUUID:  subModel.ShareUuid.String,
+		UserUUID:   subModel.UserUuid.String,
+		GroupName:  subModel.GroupName.String,
+		CreateTime: subModel.CreateTime.Time,
+	}
+}
+
+// CreateGroupShare shares group of owner, mode is updated if the group is
+// shared already
+func (r *SqlcRepo) CreateGroupShare(share *model.GroupShare) error {
+	shareModel, err := r.db.CreateGroupShare(r.ctx, sqlc.CreateGroupShareParams{
+		Uuid:       toSqlString(share.UUID),
+		OwnerUuid:  toSqlString(share.OwnerUUID),
+		GroupName:  toSqlString(share.GroupName),
+		Mode:       toSqlString(string(share.Mode)),
+		CreateTime: toSqlTime(share.CreateTime),
+	})
+	if err != nil {
+		return fmt.Errorf("create group share fail: %w", fromSqlError(err))
+	}
+
+	*share = fromSqlcGroupShare(shareModel)
+	return nil
+}
+
+func (r *SqlcRepo) FindGroupShare(uuid string) (*model.GroupShare, error) {
+	shareModel, err := r.db.GetGroupShare(r.ctx, toSqlString(uuid))
+	if err != nil {
+		return nil, fmt.Errorf("find group share fail: %w", fromSqlError(err))
+	}
+
+	share := fromSqlcGroupShare(shareModel)
+	return &share, nil
+}
+
+func (r *SqlcRepo) FindGroupShareByGroup(ownerUUID, group string) (*model.GroupShare, error) {
+	shareModel, err := r.db.GetGroupShareByGroup(r.ctx, sqlc.GetGroupShareByGroupParams{
+		OwnerUuid: toSqlString(ownerUUID),
+		GroupName: toSqlString(group),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find group share fail: %w", fromSqlError(err))
+	}
+
+	share := fromSqlcGroupShare(shareModel)
+	return &share, nil
+}
+
+func (r *SqlcRepo) FindGroupShares(ownerUUID string) ([]model.GroupShare, error) {
+	shareModels, err := r.db.ListGroupShares(r.ctx, toSqlString(ownerUUID))
+	if err != nil {
+		return nil, fmt.Errorf("list group shares fail: %w", fromSqlError(err))
+	}
+
+	shares := make([]model.GroupShare, len(shareModels))
+	for i, shareModel := range shareModels {
+		shares[i] = fromSqlcGroupShare(shareModel)
+	}
+
+	return shares, nil
+}
+
+// DeleteGroupShare removes share with its subscriptions, websites synced
+// to subscribers are kept
+func (r *SqlcRepo) DeleteGroupShare(uuid string) error {
+	err := r.db.DeleteGroupShareSubscriptions(r.ctx, toSqlString(uuid))
+	if err != nil {
+		return fmt.Errorf("delete group share subscriptions fail: %w", fromSqlError(err))
+	}
+
+	err = r.db.DeleteGroupShare(r.ctx, toSqlString(uuid))
+	if err != nil {
+		return fmt.Errorf("delete group share fail: %w", fromSqlError(err))
+	}
+
+	return nil
+}
+
+func (r *SqlcRepo) CreateGroupShareSubscription(sub *model.GroupShareSubscription) error {
+	err := r.db.CreateGroupShareSubscription(r.ctx, sqlc.CreateGroupShareSubscriptionParams{
+		ShareUuid:  toSqlString(sub.ShareUUID),
+		UserUuid:   toSqlString(sub.UserUUID),
+		GroupName:  toSqlString(sub.GroupName),
+		CreateTime: toSqlTime(sub.CreateTime),
+	})
+	if err != nil {
+		return fmt.Errorf("create group share subscription fail: %w", fromSqlError(err))
+	}
+
+	return nil
+}
+
+func (r *SqlcRepo) DeleteGroupShareSubscription(shareUUID, userUUID string) error {
+	err := r.db.DeleteGroupShareSubscription(r.ctx, sqlc.DeleteGroupShareSubscriptionParams{
+		ShareUuid: toSqlString(shareUUID),
+		UserUuid:  toSqlString(userUUID),
+	})
+	if err != nil {
+		return fmt.Errorf("delete group share subscription fail: %w", fromSqlError(err))
+	}
+
+	return nil
+}
+
+func (r *SqlcRepo) FindGroupShareSubscriptions(shareUUID string) ([]model.GroupShareSubscription, error) {
+	subModels, err := r.db.ListGroupShareSubscriptions(r.ctx, toSqlString(shareUUID))
+	if err != nil {
+		return nil, fmt.Errorf("list group share subscriptions fail: %w", fromSqlError(err))
+	}
+
+	subs := make([]model.GroupShareSubscription, len(subModels))
+	for i, subModel := range subModels {
+		subs[i] = fromSqlcGroupShareSubscription(subModel)
+	}
+
+	return subs, nil
+}
+
+func (r *SqlcRepo) FindUserGroupShareSubscriptions(userUUID string) ([]model.GroupShareSubscription, error) {
+	subModels, err := r.db.ListUserGroupShareSubscriptions(r.ctx, toSqlString(userUUID))
+	if err != nil {
+		return nil, fmt.Errorf("list user group share subscriptions fail: %w", fromSqlError(err))
+	}
+
+	subs := make([]model.GroupShareSubscription, len(subModels))
+	for i, subModel := range subModels {
+		subs[i] = fromSqlcGroupShareSubscription(subModel)
+	}
+
+	return subs, nil
+}
+
+func fromSqlcGroupPublicLink(linkModel sqlc.GroupPublicLink) model.GroupPublicLink {
+	return model.GroupPublicLink{
+		Token:      linkModel.Token.String,
+		OwnerUUID:  linkModel.OwnerUuid.String,
+		GroupName:  linkModel.GroupName.String,
+		CreateTime: linkModel.CreateTime.Time,
+	}
+}
+
+// CreateGroupPublicLink creates public link of group, token of existing link
+// is replaced so that the old token stops working
+func (r *SqlcRepo) CreateGroupPublicLink(link *model.GroupPublicLink) error {
+	linkModel, err := r.db.CreateGroupPublicLink(r.ctx, sqlc.CreateGroupPublicLinkParams{
+		Token:      toSqlString(link.Token),
+		OwnerUuid:  toSqlString(link.OwnerUUID),
+		GroupName:  toSqlString(link.GroupName),
+		CreateTime: toSqlTime(link.CreateTime),
+	})
+	if err != nil {
+		return fmt.Errorf("create group public link fail: %w", fromSqlError(err))
+	}
+
+	*link = fromSqlcGroupPublicLink(linkModel)
+	return nil
+}
+
+func (r *SqlcRepo) FindGroupPublicLink(token string) (*model.GroupPublicLink, error) {
+	linkModel, err := r.db.GetGroupPublicLink(r.ctx, toSqlString(token))
+	if err != nil {
+		return nil, fmt.Errorf("find group public link fail: %w", fromSqlError(err))
+	}
+
+	link := fromSqlcGroupPublicLink(linkModel)
+	return &link, nil
+}
+
+func (r *SqlcRepo) FindGroupPublicLinkByGroup(ownerUUID, group string) (*model.GroupPublicLink, error) {
+	linkModel, err := r.db.GetGroupPublicLinkByGroup(r.ctx, sqlc.GetGroupPublicLinkByGroupParams{
+		OwnerUuid: toSqlString(ownerUUID),
+		GroupName: toSqlString(group),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find group public link fail: %w", fromSqlError(err))
+	}
+
+	link := fromSqlcGroupPublicLink(linkModel)
+	return &link, nil
+}
+
+func (r *SqlcRepo) DeleteGroupPublicLink(ownerUUID, group string) error {
+	err := r.db.DeleteGroupPublicLink(r.ctx, sqlc.DeleteGroupPublicLinkParams{
+		OwnerUuid: toSqlString(ownerUUID),
+		GroupName: toSqlString(group),
+	})
+	if err != nil {
+		return fmt.Errorf("delete group public link fail: %w", fromSqlError(err))
+	}
+
+	return nil
+}
+
+// FindWebsiteSubscriberCounts returns number of users subscribing each
+// website, websites without subscriber are not included
+func (r *SqlcRepo) FindWebsiteSubscriberCounts() (map[string]int, error) {
+	rows, err := r.db.CountWebsiteSubscribers(r.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("count website subscribers fail: %w", fromSqlError(err))
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.WebsiteUuid.String] = int(row.SubscriberCount)
+	}
+
+	return counts, nil
+}
+
+func (r *SqlcRepo) CountWebsiteUpdateRequests() (int, error) {
+	count, err := r.db.CountWebsiteUpdateRequests(r.ctx)
+	if err != nil {
+		return 0, fmt.Errorf("count website update requests fail: %w", fromSqlError(err))
+	}
+
+	return int(count), nil
+}
+
+func (r *SqlcRepo) UpsertWorkerStatus(status *model.WorkerStatus) error {
+	err := r.db.UpsertWorkerStatus(r.ctx, sqlc.UpsertWorkerStatusParams{
+		WorkerID:      toSqlString(status.WorkerID),
+		ExecutorCount: sql.NullInt32{Int32: int32(status.ExecutorCount), Valid: true},
+		BusyExecutors: sql.NullInt32{Int32: int32(status.BusyExecutors), Valid: true},
+		QueueDepth:    sql.NullInt32{Int32: int32(status.QueueDepth), Valid: true},
+		StartTime:     toSqlTime(status.StartTime),
+		HeartbeatTime: toSqlTime(status.HeartbeatTime),
+	})
+	if err != nil {
+		return fmt.Errorf("upsert worker status fail: %w", fromSqlError(err))
+	}
+
+	return nil
+}
+
+func (r *SqlcRepo) FindWorkerStatuses() ([]model.WorkerStatus, error) {
+	statusModels, err := r.db.ListWorkerStatuses(r.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list worker statuses fail: %w", fromSqlError(err))
+	}
+
+	statuses := make([]model.WorkerStatus, len(statusModels))
+	for i, statusModel := range statusModels {
+		statuses[i] = model.WorkerStatus{
+			WorkerID:      statusModel.WorkerID.String,
+			ExecutorCount: int(statusModel.ExecutorCount.Int32),
+			BusyExecutors: int(statusModel.BusyExecutors.Int32),
+			QueueDepth:    int(statusModel.QueueDepth.Int32),
+			StartTime:     statusModel.StartTime.Time,
+			HeartbeatTime: statusModel.HeartbeatTime.Time,
+		}
+	}
+
+	return statuses, nil
+}
+
+func (r *SqlcRepo) DeleteWorkerStatus(workerID string) error {
+	if err := r.db.DeleteWorkerStatus(r.ctx, toSqlString(workerID)); err != nil {
+		return fmt.Errorf("delete worker status fail: %w", fromSqlError(err))
+	}
+
+	return nil
+}
+
+func (r *SqlcRepo) CreateImportJob(job *model.ImportJob) error {
+	err := r.db.CreateImportJob(r.ctx, sqlc.CreateImportJobParams{
+		Uuid:       toSqlString(job.ID),
+		UserUuid:   toSqlString(job.UserUUID),
+		Status:     toSqlString(job.Status),
+		Total:      sql.NullInt32{Int32: int32(job.Total), Valid: true},
+		CreateTime: toSqlTime(job.CreateTime),
+	})
+	if err != nil {
+		return fmt.Errorf("create import job fail: %w", fromSqlError(err))
+	}
+
+	return nil
+}
+
+func (r *SqlcRepo) UpdateImportJob(job *model.ImportJob) error {
+	err := r.db.UpdateImportJob(r.ctx, sqlc.UpdateImportJobParams{
+		Uuid:       toSqlString(job.ID),
+		Status:     toSqlString(job.Status),
+		Results:    toOptionalSqlString(job.Results),
+		Error:      toOptionalSqlString(job.Error),
+		FinishTime: sql.NullTime{Time: job.FinishTime, Valid: !job.FinishTime.IsZero()},
+	})
+	if err != nil {
+		return fmt.Errorf("update import job fail: %w", fromSqlError(err))
+	}
+
+	return nil
+}
+
+func (r *SqlcRepo) FindImportJob(userUUID, uuid string) (*model.ImportJob, error) {
+	jobModel, err := r.db.GetImportJob(r.ctx, sqlc.GetImportJobParams{
+		Uuid:     toSqlString(uuid),
+		UserUuid: toSqlString(userUUID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find import job fail: %w", fromSqlError(err))
+	}
+
+	return &model.ImportJob{
+		ID:         jobModel.Uuid.String,
+		UserUUID:   jobModel.UserUuid.String,
+		Status:     jobModel.Status.String,
+		Total:      int(jobModel.Total.Int32),
+		Results:    jobModel.Results.String,
+		Error:      jobModel.Error.String,
+		CreateTime: fromSqlTime(jobModel.CreateTime),
+		FinishTime: fromSqlTime(jobModel.FinishTime),
+	}, nil
+}
+
+func (r *SqlcRepo) DeleteImportJobs(before time.Time) error {
+	if err := r.db.DeleteImportJobs(r.ctx, toSqlTime(before)); err != nil {
+		return fmt.Errorf("delete import jobs fail: %w", fromSqlError(err))
+	}
+
+	return nil
+}
+
 func (r *SqlcRepo) Stats() sql.DBStats {
 	return r.stats()
 }
@@ -2,6 +2,7 @@ package sqlc
 
 import (
 	"database/sql"
+	"errors"
 	"testing"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/htchan/WebHistory/internal/config"
 	"github.com/htchan/WebHistory/internal/model"
+	"github.com/htchan/WebHistory/internal/repository"
 	_ "github.com/lib/pq"
 	"gotest.tools/assert"
 )
@@ -844,3 +846,70 @@ func TestSqlcRepo_FindUserWebsite(t *testing.T) {
 		})
 	}
 }
+
+func TestSqlcRepo_WithTx(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("postgres", connString)
+	if err != nil {
+		t.Fatalf("open database fail: %v", err)
+	}
+
+	r := NewRepo(db, &config.WebsiteConfig{})
+
+	userUUID := "with-tx-user-uuid"
+	t.Cleanup(func() {
+		db.Exec("delete from user_website_groups where user_uuid=$1", userUUID)
+		db.Close()
+	})
+
+	if err := r.UpdateUserGroupOrder(userUUID, []string{"a", "b"}); err != nil {
+		t.Fatalf("update user group order fail: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		fnErr     error
+		expect    []string
+		expectErr bool
+	}{
+		{
+			name:      "roll back changes if fn fails",
+			fnErr:     errors.New("some error"),
+			expect:    []string{"a", "b"},
+			expectErr: true,
+		},
+		{
+			name:      "commit changes if fn succeeds",
+			fnErr:     nil,
+			expect:    []string{"b", "c"},
+			expectErr: false,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			err := r.WithTx(func(txRepo repository.Repostory) error {
+				if err := txRepo.UpdateUserGroupOrder(userUUID, []string{"b", "c"}); err != nil {
+					return err
+				}
+
+				return test.fnErr
+			})
+			if (err != nil) != test.expectErr {
+				t.Errorf("got error: %v; want error: %v", err, test.expectErr)
+			}
+
+			result, err := r.FindUserGroupOrder(userUUID)
+			if err != nil {
+				t.Errorf("find user group order fail: %v", err)
+			}
+			if !cmp.Equal(result, test.expect) {
+				t.Errorf("result different from expected")
+				t.Error(result)
+				t.Error(test.expect)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package website
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// maxBodySize limits the size of form or json body of request
+const maxBodySize = 1 << 20
+
+var (
+	errRequired         = errors.New("value is required")
+	errDuplicatedValue  = errors.New("value is duplicated")
+	errUnsupportedValue = errors.New("value must be string, number, boolean or array of them")
+)
+
+// parseBody parses the form or json object body of request into req.Form
+// and req.PostForm, so that params middlewares read both encodings in the
+// same way. Body larger than maxBodySize is rejected
+func parseBody(res http.ResponseWriter, req *http.Request) error {
+	if req.PostForm != nil {
+		return nil
+	}
+
+	if req.Body != nil {
+		req.Body = http.MaxBytesReader(res, req.Body, maxBodySize)
+	}
+
+	var err error
+	if isJSONBody(req) {
+		err = parseJSONBody(req)
+	} else {
+		err = req.ParseForm()
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return BodyTooLargeError
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	if err != nil {
+		return BadRequestError
+	}
+
+	return nil
+}
+
+func isJSONBody(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/json"
+}
+
+// parseJSONBody decodes the json object body into form values, scalar is
+// converted to single value and array of scalar to multiple values
+func parseJSONBody(req *http.Request) error {
+	query, err := url.ParseQuery(req.URL.RawQuery)
+	if err != nil {
+		return err
+	}
+
+	postForm := make(url.Values)
+	if req.Body != nil {
+		var body map[string]interface{}
+		decoder := json.NewDecoder(req.Body)
+		decoder.UseNumber()
+		err := decoder.Decode(&body)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if decoder.More() {
+			return errors.New("unexpected data after json object")
+		}
+
+		for key, value := range body {
+			values, err := formValues(value)
+			if err != nil {
+				return invalidField(key, err)
+			}
+			if len(values) > 0 {
+				postForm[key] = values
+			}
+		}
+	}
+
+	req.PostForm = postForm
+	req.Form = make(url.Values, len(postForm)+len(query))
+	for key, values := range postForm {
+		req.Form[key] = append(req.Form[key], values...)
+	}
+	for key, values := range query {
+		req.Form[key] = append(req.Form[key], values...)
+	}
+
+	return nil
+}
+
+func formValues(value interface{}) ([]string, error) {
+	if array, ok := value.([]interface{}); ok {
+		values := make([]string, 0, len(array))
+		for _, item := range array {
+			if _, ok := item.([]interface{}); ok {
+				return nil, errUnsupportedValue
+			}
+			itemValues, err := formValues(item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, itemValues...)
+		}
+
+		return values, nil
+	}
+
+	switch value := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{value}, nil
+	case bool:
+		return []string{strconv.FormatBool(value)}, nil
+	case json.Number:
+		return []string{value.String()}, nil
+	default:
+		return nil, errUnsupportedValue
+	}
+}
@@ -0,0 +1,163 @@
+package website
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_parseBody(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		contentType    string
+		body           string
+		expectForm     url.Values
+		expectPostForm url.Values
+		expectErr      *APIError
+	}{
+		{
+			name:           "parse form body",
+			method:         "POST",
+			target:         "/",
+			contentType:    "application/x-www-form-urlencoded",
+			body:           "url=https://example.com&group=a&group=b",
+			expectForm:     url.Values{"url": {"https://example.com"}, "group": {"a", "b"}},
+			expectPostForm: url.Values{"url": {"https://example.com"}, "group": {"a", "b"}},
+		},
+		{
+			name:           "parse json body",
+			method:         "POST",
+			target:         "/",
+			contentType:    "application/json; charset=utf-8",
+			body:           `{"url":"https://example.com","group":["a","b"],"muted":false,"limit":10,"item":null}`,
+			expectForm:     url.Values{"url": {"https://example.com"}, "group": {"a", "b"}, "muted": {"false"}, "limit": {"10"}},
+			expectPostForm: url.Values{"url": {"https://example.com"}, "group": {"a", "b"}, "muted": {"false"}, "limit": {"10"}},
+		},
+		{
+			name:           "merge query into form of json body",
+			method:         "PUT",
+			target:         "/?tag=query",
+			contentType:    "application/json",
+			body:           `{"tag":"body"}`,
+			expectForm:     url.Values{"tag": {"body", "query"}},
+			expectPostForm: url.Values{"tag": {"body"}},
+		},
+		{
+			name:           "accept empty json body",
+			method:         "POST",
+			target:         "/",
+			contentType:    "application/json",
+			expectForm:     url.Values{},
+			expectPostForm: url.Values{},
+		},
+		{
+			name:           "ignore json body of get request",
+			method:         "GET",
+			target:         "/?tag=query",
+			contentType:    "application/json",
+			body:           `{"tag":"body"}`,
+			expectForm:     url.Values{"tag": {"query"}},
+			expectPostForm: url.Values{},
+		},
+		{
+			name:        "return bad request if json is malformed",
+			method:      "POST",
+			target:      "/",
+			contentType: "application/json",
+			body:        `{"url":`,
+			expectErr:   BadRequestError,
+		},
+		{
+			name:        "return bad request if json is not object",
+			method:      "POST",
+			target:      "/",
+			contentType: "application/json",
+			body:        `["https://example.com"]`,
+			expectErr:   BadRequestError,
+		},
+		{
+			name:        "return bad request if data follows json object",
+			method:      "POST",
+			target:      "/",
+			contentType: "application/json",
+			body:        `{"url":"https://example.com"}{}`,
+			expectErr:   BadRequestError,
+		},
+		{
+			name:        "return field error if value is object",
+			method:      "POST",
+			target:      "/",
+			contentType: "application/json",
+			body:        `{"url":{"href":"https://example.com"}}`,
+			expectErr: &APIError{
+				Status:  http.StatusUnprocessableEntity,
+				Code:    "invalid_params",
+				Message: "url: " + errUnsupportedValue.Error(),
+				Field:   "url",
+			},
+		},
+		{
+			name:        "return field error if value is nested array",
+			method:      "POST",
+			target:      "/",
+			contentType: "application/json",
+			body:        `{"group":[["a"]]}`,
+			expectErr: &APIError{
+				Status:  http.StatusUnprocessableEntity,
+				Code:    "invalid_params",
+				Message: "group: " + errUnsupportedValue.Error(),
+				Field:   "group",
+			},
+		},
+		{
+			name:        "return body too large if form body exceeds limit",
+			method:      "POST",
+			target:      "/",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "url=" + strings.Repeat("a", maxBodySize),
+			expectErr:   BodyTooLargeError,
+		},
+		{
+			name:        "return body too large if json body exceeds limit",
+			method:      "POST",
+			target:      "/",
+			contentType: "application/json",
+			body:        `{"url":"` + strings.Repeat("a", maxBodySize) + `"}`,
+			expectErr:   BodyTooLargeError,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(test.method, test.target, strings.NewReader(test.body))
+			req.Header.Set("Content-Type", test.contentType)
+
+			err := parseBody(httptest.NewRecorder(), req)
+			if test.expectErr != nil {
+				if !cmp.Equal(toAPIError(err), test.expectErr) {
+					t.Errorf("got error: %v; want: %v", err, test.expectErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("got error: %v; want: nil", err)
+			}
+			if !cmp.Equal(req.Form, test.expectForm) {
+				t.Errorf("form diff: %v", cmp.Diff(test.expectForm, req.Form))
+			}
+			if !cmp.Equal(req.PostForm, test.expectPostForm) {
+				t.Errorf("post form diff: %v", cmp.Diff(test.expectPostForm, req.PostForm))
+			}
+		})
+	}
+}
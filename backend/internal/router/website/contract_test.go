@@ -0,0 +1,386 @@
+package website
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/htchan/WebHistory/internal/auth"
+	"github.com/htchan/WebHistory/internal/config"
+	"github.com/htchan/WebHistory/internal/events"
+	"github.com/htchan/WebHistory/internal/model"
+	"github.com/htchan/WebHistory/internal/repository"
+	"github.com/htchan/WebHistory/pkg/api"
+	"github.com/htchan/WebHistory/pkg/client"
+)
+
+const contractPrefix = "/api/web-watcher"
+
+type contractCall struct {
+	method, pattern string
+	status          int
+	contentType     string
+	body            []byte
+}
+
+// contractRecorder records route pattern and response of every request
+// served by the router
+type contractRecorder struct {
+	router http.Handler
+	calls  []contractCall
+}
+
+func (recorder *contractRecorder) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	rctx := chi.NewRouteContext()
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	rr := httptest.NewRecorder()
+	recorder.router.ServeHTTP(rr, req)
+
+	recorder.calls = append(recorder.calls, contractCall{
+		method: req.Method,
+		// root of sub router is joined as "//" in route pattern
+		pattern:     strings.ReplaceAll(strings.TrimPrefix(rctx.RoutePattern(), contractPrefix), "//", "/"),
+		status:      rr.Code,
+		contentType: rr.Header().Get("Content-Type"),
+		body:        rr.Body.Bytes(),
+	})
+
+	for key, values := range rr.Header() {
+		res.Header()[key] = values
+	}
+	res.WriteHeader(rr.Code)
+	res.Write(rr.Body.Bytes())
+}
+
+// validateSchema reports where value does not match the openapi schema
+func validateSchema(schema map[string]interface{}, components map[string]interface{}, value interface{}, path string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		return validateSchema(components[name].(map[string]interface{}), components, value, path)
+	}
+	if value == nil {
+		if nullable, _ := schema["nullable"].(bool); nullable {
+			return nil
+		}
+		return []string{fmt.Sprintf("%s: got null", path)}
+	}
+
+	var errs []string
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: got %T; want object", path, value)}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		required, _ := schema["required"].([]interface{})
+		for _, name := range required {
+			if _, ok := object[name.(string)]; !ok {
+				errs = append(errs, fmt.Sprintf("%s: missing required %v", path, name))
+			}
+		}
+		for key, item := range object {
+			if properties != nil {
+				property, ok := properties[key].(map[string]interface{})
+				if !ok {
+					errs = append(errs, fmt.Sprintf("%s: undocumented field %v", path, key))
+					continue
+				}
+				errs = append(errs, validateSchema(property, components, item, path+"."+key)...)
+			} else if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+				errs = append(errs, validateSchema(additional, components, item, path+"."+key)...)
+			}
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: got %T; want array", path, value)}
+		}
+		for i, item := range array {
+			errs = append(errs, validateSchema(schema["items"].(map[string]interface{}), components, item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			errs = append(errs, fmt.Sprintf("%s: got %T; want string", path, value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			errs = append(errs, fmt.Sprintf("%s: got %T; want boolean", path, value))
+		}
+	case "integer", "number":
+		if _, ok := value.(float64); !ok {
+			errs = append(errs, fmt.Sprintf("%s: got %T; want %v", path, value, schema["type"]))
+		}
+	}
+
+	return errs
+}
+
+func Test_routesMatchOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	r := repository.NewInMemRepo(nil, nil, nil, nil)
+	conf := &config.APIConfig{
+		BinConfig:         config.APIBinConfig{APIRoutePrefix: contractPrefix},
+		UserServiceConfig: config.UserServiceConfig{JWTSecret: "secret", JWTTTL: time.Hour, AllowRegistration: true},
+	}
+	authenticator, err := auth.NewLocalAuthenticator(&conf.UserServiceConfig, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := chi.NewRouter()
+	AddRoutes(router, r, events.NewInMemPubSub(), authenticator, conf)
+
+	served := make(map[string]bool)
+	err = chi.Walk(router, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		served[method+" "+strings.TrimPrefix(route, contractPrefix)] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	documented := make(map[string]bool)
+	for _, op := range api.Operations {
+		documented[op.Method+" "+op.Path] = true
+	}
+
+	for route := range served {
+		if !documented[route] {
+			t.Errorf("route %v is not documented", route)
+		}
+	}
+	for route := range documented {
+		if !served[route] {
+			t.Errorf("documented route %v is not served", route)
+		}
+	}
+}
+
+// Test_handlersMatchOpenAPI drives every operation through the go client
+// and checks the status and body of each response against the spec
+func Test_handlersMatchOpenAPI(t *testing.T) {
+	ctx := context.Background()
+	r := repository.NewInMemRepo(nil, nil, nil, nil)
+	conf := &config.APIConfig{
+		BinConfig:         config.APIBinConfig{APIRoutePrefix: contractPrefix},
+		UserServiceConfig: config.UserServiceConfig{JWTSecret: "secret", JWTTTL: time.Hour, AllowRegistration: true},
+		WebsiteConfig:     config.WebsiteConfig{Separator: "\n", MaxDateLength: 2, URLAllowList: []string{"example.com"}},
+	}
+	authenticator, err := auth.NewLocalAuthenticator(&conf.UserServiceConfig, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin, err := authenticator.Register("admin", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf.UserServiceConfig.AdminUsers = []string{admin.UUID}
+
+	router := chi.NewRouter()
+	AddRoutes(router, r, events.NewInMemPubSub(), authenticator, conf)
+	recorder := &contractRecorder{router: router}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	anonymous := client.New(server.URL + contractPrefix)
+	user, err := anonymous.Register(ctx, "user", "password")
+	must(err)
+	token, err := anonymous.Login(ctx, "user", "password")
+	must(err)
+	c := client.New(server.URL+contractPrefix, client.WithToken(token))
+	adminToken, err := anonymous.Login(ctx, "admin", "password")
+	must(err)
+	adminClient := client.New(server.URL+contractPrefix, client.WithToken(adminToken))
+
+	apiToken, err := c.CreateAPIToken(ctx, "cli")
+	must(err)
+	_, err = c.ListAPITokens(ctx)
+	must(err)
+	must(c.DeleteAPIToken(ctx, apiToken.APIToken.UUID))
+
+	first, err := c.CreateWebsite(ctx, "https://example.com/first")
+	must(err)
+	second, err := c.CreateWebsite(ctx, "https://example.com/second")
+	must(err)
+	web, err := r.FindWebsite(first.WebsiteUUID)
+	must(err)
+	web.MergeItems([]model.WebsiteItem{{Name: "chapter 1", Link: "https://example.com/first/1"}}, time.Now())
+	must(r.UpdateWebsite(web))
+	// in memory repo keeps its own copy of website in user website
+	userWeb, err := r.FindUserWebsite(user.UserUUID, first.WebsiteUUID)
+	must(err)
+	userWeb.Website = *web
+	must(r.UpdateUserWebsite(userWeb))
+
+	_, err = c.ListWebsiteGroups(ctx, nil)
+	must(err)
+	_, err = c.GetWebsite(ctx, first.WebsiteUUID)
+	must(err)
+	_, err = c.GetWebsiteDiff(ctx, first.WebsiteUUID)
+	must(err)
+	_, err = c.RefreshWebsite(ctx, first.WebsiteUUID)
+	must(err)
+	_, err = c.ReadWebsite(ctx, first.WebsiteUUID, "")
+	must(err)
+	_, err = c.SnoozeWebsite(ctx, first.WebsiteUUID, time.Now().Add(time.Hour))
+	must(err)
+	_, err = c.MuteWebsite(ctx, first.WebsiteUUID, true)
+	must(err)
+
+	_, err = c.AddWebsiteTag(ctx, first.WebsiteUUID, "news")
+	must(err)
+	_, err = c.ListTags(ctx)
+	must(err)
+	must(c.RenameTag(ctx, "news", "daily"))
+	_, err = c.RemoveWebsiteTag(ctx, first.WebsiteUUID, "daily")
+	must(err)
+	_, err = c.AddWebsiteTag(ctx, first.WebsiteUUID, "daily")
+	must(err)
+	must(c.DeleteTag(ctx, "daily"))
+
+	_, err = c.ChangeWebsiteGroup(ctx, first.WebsiteUUID, "alpha")
+	must(err)
+	_, err = c.ChangeWebsiteGroup(ctx, second.WebsiteUUID, "beta")
+	must(err)
+	_, err = c.GetWebsiteGroup(ctx, "alpha")
+	must(err)
+	_, err = c.RenameWebsiteGroup(ctx, "alpha", "gamma")
+	must(err)
+	_, err = c.MergeWebsiteGroup(ctx, "beta", "gamma")
+	must(err)
+	_, err = c.SplitWebsiteGroup(ctx, "gamma", api.SplitGroupRequest{GroupName: "delta", WebsiteUUIDs: []string{second.WebsiteUUID}})
+	must(err)
+	_, err = c.MoveWebsites(ctx, "gamma", []string{second.WebsiteUUID})
+	must(err)
+	_, err = c.GetGroupOrder(ctx)
+	must(err)
+	_, err = c.UpdateGroupOrder(ctx, []string{"gamma"})
+	must(err)
+
+	share, err := c.ShareWebsiteGroup(ctx, "gamma", "collaborative")
+	must(err)
+	_, err = adminClient.SubscribeGroupShare(ctx, share.UUID, "mirror")
+	must(err)
+	_, err = adminClient.ListGroupShares(ctx)
+	must(err)
+	shared, err := adminClient.AddSharedWebsite(ctx, share.UUID, "https://example.com/shared")
+	must(err)
+	must(adminClient.RemoveSharedWebsite(ctx, share.UUID, shared.WebsiteUUID))
+	must(adminClient.UnsubscribeGroupShare(ctx, share.UUID))
+	must(c.UnshareWebsiteGroup(ctx, "gamma"))
+
+	link, err := c.CreateGroupPublicLink(ctx, "gamma")
+	must(err)
+	_, err = c.GetGroupPublicLink(ctx, "gamma")
+	must(err)
+	_, err = anonymous.GetPublicGroup(ctx, link.Token)
+	must(err)
+	must(c.DeleteGroupPublicLink(ctx, "gamma"))
+
+	_, err = c.ImportWebsites(ctx, "json", strings.NewReader(`[{"url":"https://example.com/imported"}]`))
+	must(err)
+	jobID, err := c.ImportBookmarks(ctx, strings.NewReader(`<DL><DT><A HREF="https://example.com/bookmark">bookmark</A></DL>`))
+	must(err)
+	for i := 0; i < 100; i++ {
+		job, err := c.GetImportJob(ctx, jobID)
+		must(err)
+		if job.Status != "running" {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	export, err := c.ExportWebsites(ctx, "json")
+	must(err)
+	io.Copy(io.Discard, export)
+	export.Close()
+
+	_, err = adminClient.AdminListWebsites(ctx)
+	must(err)
+	must(adminClient.AdminCheckWebsite(ctx, first.WebsiteUUID))
+	_, err = adminClient.AdminWorkerStatus(ctx)
+	must(err)
+	_, err = adminClient.AdminBrokenWebsiteSettings(ctx)
+	must(err)
+	_, err = adminClient.AdminDBStats(ctx)
+	must(err)
+
+	_, err = c.GetWebsite(ctx, "unknown")
+	if err == nil {
+		t.Errorf("get unknown website succeed")
+	}
+	must(c.DeleteWebsite(ctx, first.WebsiteUUID))
+
+	spec, err := anonymous.OpenAPI(ctx)
+	must(err)
+
+	specJSON, err := json.Marshal(spec)
+	must(err)
+	var doc map[string]interface{}
+	must(json.Unmarshal(specJSON, &doc))
+	components := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	errorSchema := map[string]interface{}{"$ref": "#/components/schemas/ErrorResponse"}
+
+	exercised := make(map[string]bool)
+	for _, call := range recorder.calls {
+		op, ok := api.FindOperation(call.method, call.pattern)
+		if !ok {
+			t.Errorf("%v %v: undocumented operation", call.method, call.pattern)
+			continue
+		}
+		exercised[op.ID] = true
+
+		schema := errorSchema
+		if call.status == op.Status {
+			if op.Response == nil {
+				continue
+			}
+			paths := doc["paths"].(map[string]interface{})
+			response := paths[op.Path].(map[string]interface{})[strings.ToLower(op.Method)].(map[string]interface{})["responses"].(map[string]interface{})[fmt.Sprint(op.Status)].(map[string]interface{})
+			schema = response["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
+		} else if call.status < 400 {
+			t.Errorf("%v: got status %v; want %v", op.ID, call.status, op.Status)
+			continue
+		}
+
+		if !strings.HasPrefix(call.contentType, "application/json") {
+			t.Errorf("%v: got content type %v", op.ID, call.contentType)
+		}
+		var body interface{}
+		if err := json.NewDecoder(bytes.NewReader(call.body)).Decode(&body); err != nil {
+			t.Errorf("%v: invalid json %q: %v", op.ID, call.body, err)
+			continue
+		}
+		for _, err := range validateSchema(schema, components, body, op.ID) {
+			t.Error(err)
+		}
+	}
+
+	var missing []string
+	for _, op := range api.Operations {
+		// event stream does not end, it is covered by its handler test
+		if !exercised[op.ID] && op.ID != "websiteEvents" {
+			missing = append(missing, op.ID)
+		}
+	}
+	sort.Strings(missing)
+	if len(missing) > 0 {
+		t.Errorf("operations not exercised: %v", missing)
+	}
+}
@@ -0,0 +1,144 @@
+package website
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/htchan/WebHistory/internal/auth"
+	"github.com/htchan/WebHistory/internal/model"
+	"github.com/htchan/WebHistory/internal/repository"
+	"github.com/htchan/WebHistory/internal/service"
+	"github.com/htchan/WebHistory/internal/watchlist"
+	"github.com/htchan/WebHistory/pkg/api"
+)
+
+// APIError is an error responded to client, Code is stable for client to
+// match on and Message is safe to expose. Field names the invalid param of
+// validation error
+type APIError struct {
+	Status  int
+	Code    string
+	Message string
+	Field   string
+}
+
+func (err *APIError) Error() string {
+	return err.Message
+}
+
+var (
+	BadRequestError     = &APIError{Status: http.StatusBadRequest, Code: "bad_request", Message: "malformed request"}
+	UnauthorizedError   = &APIError{Status: http.StatusUnauthorized, Code: "unauthorized", Message: "unauthorized"}
+	ForbiddenError      = &APIError{Status: http.StatusForbidden, Code: "forbidden", Message: "forbidden"}
+	RecordNotFoundError = &APIError{Status: http.StatusNotFound, Code: "not_found", Message: "record not found"}
+	DuplicateError      = &APIError{Status: http.StatusConflict, Code: "duplicate", Message: "record already exists"}
+	GroupExistError     = &APIError{Status: http.StatusConflict, Code: "group_exists", Message: "group already exists"}
+	SyncedGroupError    = &APIError{Status: http.StatusForbidden, Code: "synced_group", Message: "group is synced from a share"}
+	BodyTooLargeError   = &APIError{Status: http.StatusRequestEntityTooLarge, Code: "body_too_large", Message: "request body too large"}
+	InvalidParamsError  = &APIError{Status: http.StatusUnprocessableEntity, Code: "invalid_params", Message: "invalid params"}
+	InternalError       = &APIError{Status: http.StatusInternalServerError, Code: "internal_error", Message: "internal server error"}
+)
+
+// knownErrors maps errors of other packages to the responded error, the
+// message of the known error is responded instead of the wrapped detail
+var knownErrors = []struct {
+	err    error
+	status int
+	code   string
+	field  string
+}{
+	{err: repository.ErrNotFound, status: http.StatusNotFound, code: RecordNotFoundError.Code},
+	{err: repository.ErrDuplicate, status: http.StatusConflict, code: DuplicateError.Code},
+	{err: auth.ErrUnauthorized, status: http.StatusUnauthorized, code: UnauthorizedError.Code},
+	{err: auth.ErrInvalidCredentials, status: http.StatusUnauthorized, code: "invalid_credentials"},
+	{err: auth.ErrUserExists, status: http.StatusConflict, code: "user_exists"},
+	{err: auth.ErrUnavailable, status: http.StatusServiceUnavailable, code: "service_unavailable"},
+	{err: model.ErrInvalidUsername, status: http.StatusUnprocessableEntity, code: "invalid_username", field: "username"},
+	{err: model.ErrInvalidPassword, status: http.StatusUnprocessableEntity, code: "invalid_password", field: "password"},
+	{err: model.ErrInvalidGroupName, status: http.StatusUnprocessableEntity, code: "invalid_group_name", field: "group_name"},
+	{err: model.ErrInvalidGroupShareMode, status: http.StatusUnprocessableEntity, code: "invalid_share_mode", field: "mode"},
+	{err: model.ErrInvalidTag, status: http.StatusUnprocessableEntity, code: "invalid_tag", field: "tag"},
+	{err: model.ErrInvalidFilter, status: http.StatusUnprocessableEntity, code: "invalid_filter"},
+	{err: model.ErrInvalidSort, status: http.StatusUnprocessableEntity, code: "invalid_sort", field: "sort"},
+	{err: model.ErrInvalidCursor, status: http.StatusUnprocessableEntity, code: "invalid_cursor", field: "cursor"},
+	{err: model.ErrInvalidURL, status: http.StatusUnprocessableEntity, code: "invalid_url", field: "url"},
+	{err: service.ErrBlockedURL, status: http.StatusUnprocessableEntity, code: "blocked_url", field: "url"},
+	{err: watchlist.ErrUnsupportedFormat, status: http.StatusUnprocessableEntity, code: "unsupported_format"},
+	{err: watchlist.ErrJobNotFound, status: http.StatusNotFound, code: "import_job_not_found"},
+}
+
+// toAPIError finds the responded error of err, unknown errors are hidden
+// behind InternalError so that database errors are not leaked
+func toAPIError(err error) *APIError {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	for _, known := range knownErrors {
+		if errors.Is(err, known.err) {
+			return &APIError{Status: known.status, Code: known.code, Message: known.err.Error(), Field: known.field}
+		}
+	}
+
+	return InternalError
+}
+
+// invalidField is the validation error of field, the code of known
+// validation error is kept and the detail of err is responded
+func invalidField(field string, err error) *APIError {
+	code := InvalidParamsError.Code
+	if apiErr := toAPIError(err); apiErr.Status == http.StatusUnprocessableEntity {
+		code = apiErr.Code
+	}
+
+	return &APIError{
+		Status:  http.StatusUnprocessableEntity,
+		Code:    code,
+		Message: fmt.Sprintf("%s: %v", field, err),
+		Field:   field,
+	}
+}
+
+// wantProblem reports if client accepts RFC 7807 problem details
+func wantProblem(req *http.Request) bool {
+	return strings.Contains(req.Header.Get("Accept"), api.ProblemContentType)
+}
+
+func writeError(res http.ResponseWriter, req *http.Request, err error) {
+	apiErr := toAPIError(err)
+
+	var requestID string
+	if id, ok := req.Context().Value(ContextKeyReqID).(uuid.UUID); ok {
+		requestID = id.String()
+	}
+
+	if wantProblem(req) {
+		res.Header().Set("Content-Type", api.ProblemContentType)
+		res.WriteHeader(apiErr.Status)
+		json.NewEncoder(res).Encode(api.Problem{
+			Type:      "about:blank",
+			Title:     http.StatusText(apiErr.Status),
+			Status:    apiErr.Status,
+			Detail:    apiErr.Message,
+			Instance:  req.URL.Path,
+			Code:      apiErr.Code,
+			Field:     apiErr.Field,
+			RequestID: requestID,
+		})
+		return
+	}
+
+	res.Header().Set("Content-Type", "application/json; charset=utf-8")
+	res.WriteHeader(apiErr.Status)
+	json.NewEncoder(res).Encode(api.ErrorResponse{
+		Code:      apiErr.Code,
+		Error:     apiErr.Message,
+		Field:     apiErr.Field,
+		RequestID: requestID,
+	})
+}
@@ -6,31 +6,138 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/rs/zerolog"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/htchan/WebHistory/internal/auth"
 	"github.com/htchan/WebHistory/internal/config"
+	"github.com/htchan/WebHistory/internal/events"
 	"github.com/htchan/WebHistory/internal/model"
 	"github.com/htchan/WebHistory/internal/repository"
 	"github.com/htchan/WebHistory/internal/service"
+	"github.com/htchan/WebHistory/internal/sharing"
+	"github.com/htchan/WebHistory/internal/telemetry"
+	"github.com/htchan/WebHistory/internal/watchlist"
+	"github.com/htchan/WebHistory/pkg/api"
 )
 
+// websiteListParams parses the filter, sort and group pagination of website
+// listing
+func websiteListParams(req *http.Request) (model.UserWebsiteFilter, model.UserWebsiteSort, model.WebsiteGroupPage, error) {
+	query := req.URL.Query()
+
+	var updatedOnly bool
+	if updatedOnlyStr := query.Get("updated_only"); updatedOnlyStr != "" {
+		var err error
+		updatedOnly, err = strconv.ParseBool(updatedOnlyStr)
+		if err != nil {
+			return model.UserWebsiteFilter{}, model.UserWebsiteSort{}, model.WebsiteGroupPage{}, err
+		}
+	}
+
+	filter, err := model.NewUserWebsiteFilter(query.Get("q"), query.Get("host"), updatedOnly, query.Get("health"))
+	if err != nil {
+		return model.UserWebsiteFilter{}, model.UserWebsiteSort{}, model.WebsiteGroupPage{}, err
+	}
+
+	for _, tag := range query["tag"] {
+		tag, err = model.NormalizeTag(tag)
+		if err != nil {
+			return model.UserWebsiteFilter{}, model.UserWebsiteSort{}, model.WebsiteGroupPage{}, err
+		}
+		filter.Tags = append(filter.Tags, tag)
+	}
+
+	switch query.Get("tag_mode") {
+	case "", "and":
+		filter.MatchAllTags = true
+	case "or":
+		filter.MatchAllTags = false
+	default:
+		return model.UserWebsiteFilter{}, model.UserWebsiteSort{}, model.WebsiteGroupPage{}, InvalidParamsError
+	}
+
+	order, err := model.ParseUserWebsiteSort(query.Get("sort"))
+	if err != nil {
+		return model.UserWebsiteFilter{}, model.UserWebsiteSort{}, model.WebsiteGroupPage{}, err
+	}
+
+	var page model.WebsiteGroupPage
+	page.Cursor, err = model.ParseWebsiteGroupCursor(query.Get("cursor"))
+	if err != nil {
+		return model.UserWebsiteFilter{}, model.UserWebsiteSort{}, model.WebsiteGroupPage{}, err
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		page.Limit, err = strconv.Atoi(limitStr)
+		if err != nil || page.Limit <= 0 || page.Limit > model.MaxPageSize {
+			return model.UserWebsiteFilter{}, model.UserWebsiteSort{}, model.WebsiteGroupPage{}, InvalidParamsError
+		}
+	}
+
+	return filter, order, page, nil
+}
+
+// getAllWebsiteGroupsHandler lists website groups of user, pages are split
+// by group so that each group is returned as a whole
 func getAllWebsiteGroupsHandler(r repository.Repostory) http.HandlerFunc {
 	return func(res http.ResponseWriter, req *http.Request) {
 		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
-		webs, err := r.FindUserWebsites(userUUID)
+
+		filter, order, page, err := websiteListParams(req)
 		if err != nil {
-			zerolog.Ctx(req.Context()).Error().Err(err).Msg("find user websites failed")
-			writeError(res, http.StatusBadRequest, RecordNotFoundError)
+			writeError(res, req, InvalidParamsError)
 			return
 		}
 
-		json.NewEncoder(res).Encode(map[string]interface{}{
-			"website_groups": webs.WebsiteGroups(),
-		})
+		groupOrder, err := r.FindUserGroupOrder(userUUID)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("find user group order failed")
+			writeError(res, req, err)
+			return
+		}
+
+		groupNames, err := r.SearchUserWebsiteGroups(userUUID, filter, order)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("search user website groups failed")
+			writeError(res, req, err)
+			return
+		}
+		model.SortGroupNames(groupNames, groupOrder)
+
+		groupNames, nextCursor := page.Page(groupNames)
+		result := api.WebsiteGroupsResponse{
+			UnreadCounts:  make(map[string]int),
+			WebsiteGroups: make([][]api.UserWebsite, 0, len(groupNames)),
+		}
+		if nextCursor != nil {
+			result.NextCursor = nextCursor.String()
+		}
+		if len(groupNames) == 0 {
+			json.NewEncoder(res).Encode(result)
+			return
+		}
+
+		filter.Groups = groupNames
+		webs, err := r.SearchUserWebsites(userUUID, filter, model.UserWebsitePage{Sort: order})
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("search user websites failed")
+			writeError(res, req, err)
+			return
+		}
+
+		groups := webs.WebsiteGroups()
+		groups.Sort(groupOrder)
+		for _, group := range groups {
+			result.UnreadCounts[group.Name()] = group.UnreadCount()
+			result.WebsiteGroups = append(result.WebsiteGroups, model.UserWebsites(group).ToAPI())
+		}
+
+		json.NewEncoder(res).Encode(result)
 	}
 }
 
@@ -41,29 +148,41 @@ func getWebsiteGroupHandler(r repository.Repostory) http.HandlerFunc {
 		webs, err := r.FindUserWebsitesByGroup(userUUID, groupName)
 		if err != nil || len(webs) == 0 {
 			zerolog.Ctx(req.Context()).Error().Err(err).Msg("find user websites by group failed")
-			writeError(res, http.StatusBadRequest, RecordNotFoundError)
+			writeError(res, req, RecordNotFoundError)
 			return
 		}
 
-		json.NewEncoder(res).Encode(
-			map[string]interface{}{"website_group": webs},
-		)
+		json.NewEncoder(res).Encode(websiteGroupResponse(webs))
+	}
+}
+
+func websiteGroupResponse(webs model.WebsiteGroup) api.WebsiteGroupResponse {
+	return api.WebsiteGroupResponse{
+		UnreadCount:  webs.UnreadCount(),
+		WebsiteGroup: model.UserWebsites(webs).ToAPI(),
+	}
+}
+
+// publishEvent publishes event of website, failure does not fail the request
+// as event only notifies clients to reload
+func publishEvent(ctx context.Context, publisher events.Publisher, event events.Event) {
+	if err := publisher.Publish(ctx, event); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Str("event", string(event.Type)).Msg("publish website event failed")
 	}
 }
 
-func createWebsiteHandler(r repository.Repostory, conf *config.WebsiteConfig) http.HandlerFunc {
+func createWebsiteHandler(r repository.Repostory, conf *config.WebsiteConfig, publisher events.Publisher) http.HandlerFunc {
 	return func(res http.ResponseWriter, req *http.Request) {
 		// userUUID, err := UserUUID(req)
 		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
 		url := req.Context().Value(ContextKeyWebURL).(string)
 
-		web := model.NewWebsite(url, conf)
-		service.Update(context.Background(), r, &web)
-
-		err := r.CreateWebsite(&web)
+		// website is fetched by worker, so that slow website does not
+		// block the request
+		web, err := service.CreatePendingWebsite(req.Context(), r, conf, url)
 		if err != nil {
 			zerolog.Ctx(req.Context()).Error().Err(err).Msg("create website failed")
-			writeError(res, http.StatusBadRequest, err)
+			writeError(res, req, err)
 			return
 		}
 
@@ -71,13 +190,142 @@ func createWebsiteHandler(r repository.Repostory, conf *config.WebsiteConfig) ht
 		err = r.CreateUserWebsite(&userWeb)
 		if err != nil {
 			zerolog.Ctx(req.Context()).Error().Err(err).Msg("create user website failed")
-			writeError(res, http.StatusBadRequest, err)
+			writeError(res, req, err)
+			return
+		}
+		syncChangedGroups(req.Context(), r, userUUID, userWeb.GroupName)
+
+		event := events.NewEvent(events.WebsiteCreated, web.UUID)
+		event.UserUUID = userUUID
+		publishEvent(req.Context(), publisher, event)
+
+		res.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(res).Encode(api.CreateWebsiteResponse{
+			Message:     fmt.Sprintf("website <%v> accepted", web.URL),
+			WebsiteUUID: web.UUID,
+		})
+	}
+}
+
+// maxImportSize limits the size of watchlist uploaded for import
+const maxImportSize = 5 << 20
+
+func importWebsitesHandler(r repository.Repostory, conf *config.WebsiteConfig) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+
+		formatStr := req.URL.Query().Get("format")
+		if formatStr == "" {
+			formatStr = req.Header.Get("Content-Type")
+		}
+		format, err := watchlist.ParseFormat(formatStr)
+		if err != nil {
+			writeError(res, req, err)
+			return
+		}
+
+		entries, err := watchlist.Decode(http.MaxBytesReader(res, req.Body, maxImportSize), format)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("decode watchlist failed")
+			writeError(res, req, InvalidParamsError)
+			return
+		}
+
+		results, err := watchlist.Import(req.Context(), r, conf, userUUID, entries)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("import watchlist failed")
+			writeError(res, req, err)
+			return
+		}
+
+		// imported websites may join any group of user
+		if err := sharing.SyncOwner(r, userUUID); err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("sync shared groups failed")
+		}
+
+		json.NewEncoder(res).Encode(api.ImportResponse{
+			Results: watchlist.ResultsToAPI(results),
+			Summary: watchlist.SummaryToAPI(watchlist.Summary(results)),
+		})
+	}
+}
+
+// importBookmarksHandler imports bookmarks html exported by browser in
+// background, the result can be queried by returned job id
+func importBookmarksHandler(r repository.Repostory, conf *config.WebsiteConfig, jobs *watchlist.JobStore) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+
+		entries, err := watchlist.DecodeBookmarks(http.MaxBytesReader(res, req.Body, maxImportSize))
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("decode bookmarks failed")
+			writeError(res, req, InvalidParamsError)
 			return
 		}
 
-		json.NewEncoder(res).Encode(map[string]interface{}{
-			"message": fmt.Sprintf("website <%v> inserted", web.Title),
+		jobID, err := jobs.Start(req.Context(), userUUID, len(entries), func(ctx context.Context) ([]watchlist.Result, error) {
+			results, err := watchlist.ImportSupportedHosts(ctx, r, conf, userUUID, entries)
+			if err == nil {
+				if err := sharing.SyncOwner(r, userUUID); err != nil {
+					zerolog.Ctx(ctx).Error().Err(err).Msg("sync shared groups failed")
+				}
+			}
+
+			return results, err
 		})
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("start import job failed")
+			writeError(res, req, err)
+			return
+		}
+
+		res.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(res).Encode(api.ImportJobCreatedResponse{JobID: jobID})
+	}
+}
+
+func getImportJobHandler(jobs *watchlist.JobStore) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+
+		job, err := jobs.Get(userUUID, chi.URLParam(req, "jobID"))
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("get import job failed")
+			writeError(res, req, err)
+			return
+		}
+
+		json.NewEncoder(res).Encode(api.ImportJobResponse{Job: job.ToAPI()})
+	}
+}
+
+func exportWebsitesHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+
+		format := watchlist.FormatJSON
+		if formatStr := req.URL.Query().Get("format"); formatStr != "" {
+			var err error
+			format, err = watchlist.ParseFormat(formatStr)
+			if err != nil {
+				writeError(res, req, err)
+				return
+			}
+		}
+
+		webs, err := r.FindUserWebsites(userUUID)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("find user websites failed")
+			writeError(res, req, err)
+			return
+		}
+
+		res.Header().Set("Content-Type", format.ContentType())
+		res.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="watchlist.%s"`, format))
+		err = watchlist.Encode(res, format, watchlist.FromUserWebsites(webs))
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("encode watchlist failed")
+		}
 	}
 }
 
@@ -85,8 +333,24 @@ func getWebsiteHandler(r repository.Repostory) http.HandlerFunc {
 	return func(res http.ResponseWriter, req *http.Request) {
 		web := req.Context().Value(ContextKeyWebsite).(model.UserWebsite)
 
-		json.NewEncoder(res).Encode(map[string]interface{}{
-			"website": web,
+		json.NewEncoder(res).Encode(api.WebsiteResponse{Website: web.ToAPI()})
+	}
+}
+
+func getWebsiteDiffHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userWeb := req.Context().Value(ContextKeyWebsite).(model.UserWebsite)
+
+		web, err := r.FindWebsite(userWeb.WebsiteUUID)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("find website failed")
+			writeError(res, req, err)
+			return
+		}
+
+		json.NewEncoder(res).Encode(api.WebsiteDiffResponse{
+			Diff:    web.Diff().ToAPI(),
+			Website: userWeb.ToAPI(),
 		})
 	}
 }
@@ -99,64 +363,1304 @@ func refreshWebsiteHandler(r repository.Repostory) http.HandlerFunc {
 		err := r.UpdateUserWebsite(&web)
 		if err != nil {
 			zerolog.Ctx(req.Context()).Error().Err(err).Msg("update user website failed")
-			writeError(res, http.StatusInternalServerError, err)
+			writeError(res, req, err)
 			return
 		}
 
-		json.NewEncoder(res).Encode(map[string]interface{}{
-			"website": web,
-		})
+		json.NewEncoder(res).Encode(api.WebsiteResponse{Website: web.ToAPI()})
+	}
+}
+
+// readWebsiteHandler marks item of website as the last read item of user,
+// the most recently seen item is marked if item is not specified
+func readWebsiteHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		web := req.Context().Value(ContextKeyWebsite).(model.UserWebsite)
+		itemKey := req.Context().Value(ContextKeyItem).(string)
+
+		if itemKey == "" {
+			items := web.Website.LatestItems()
+			if len(items) == 0 {
+				writeError(res, req, InvalidParamsError)
+				return
+			}
+			itemKey = items[0].Key()
+		} else if _, ok := web.Website.FindItem(itemKey); !ok {
+			writeError(res, req, InvalidParamsError)
+			return
+		}
+
+		web.LastReadItem = itemKey
+		web.AccessTime = time.Now().UTC().Truncate(time.Second)
+
+		err := r.UpdateUserWebsite(&web)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("update user website failed")
+			writeError(res, req, err)
+			return
+		}
+
+		json.NewEncoder(res).Encode(api.WebsiteResponse{Website: web.ToAPI()})
+	}
+}
+
+func snoozeWebsiteHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		web := req.Context().Value(ContextKeyWebsite).(model.UserWebsite)
+		web.SnoozeUntil = req.Context().Value(ContextKeySnooze).(time.Time)
+
+		err := r.UpdateUserWebsite(&web)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("update user website failed")
+			writeError(res, req, err)
+			return
+		}
+
+		json.NewEncoder(res).Encode(api.WebsiteResponse{Website: web.ToAPI()})
+	}
+}
+
+func muteWebsiteHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		web := req.Context().Value(ContextKeyWebsite).(model.UserWebsite)
+		web.Muted = req.Context().Value(ContextKeyMuted).(bool)
+
+		err := r.UpdateUserWebsite(&web)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("update user website failed")
+			writeError(res, req, err)
+			return
+		}
+
+		json.NewEncoder(res).Encode(api.WebsiteResponse{Website: web.ToAPI()})
 	}
 }
 
-func deleteWebsiteHandler(r repository.Repostory) http.HandlerFunc {
+func deleteWebsiteHandler(r repository.Repostory, publisher events.Publisher) http.HandlerFunc {
 	return func(res http.ResponseWriter, req *http.Request) {
 		web := req.Context().Value(ContextKeyWebsite).(model.UserWebsite)
 
 		err := r.DeleteUserWebsite(&web)
 		if err != nil {
 			zerolog.Ctx(req.Context()).Error().Err(err).Msg("delete user website failed")
-			writeError(res, http.StatusInternalServerError, err)
+			writeError(res, req, err)
 			return
 		}
+		syncChangedGroups(req.Context(), r, web.UserUUID, web.GroupName)
 
-		json.NewEncoder(res).Encode(map[string]interface{}{
-			"message": fmt.Sprintf("website <%v> deleted", web.Website.Title),
-		})
+		event := events.NewEvent(events.WebsiteDeleted, web.WebsiteUUID)
+		event.UserUUID = web.UserUUID
+		publishEvent(req.Context(), publisher, event)
+
+		json.NewEncoder(res).Encode(api.MessageResponse{Message: fmt.Sprintf("website <%v> deleted", web.Website.Title)})
 	}
 }
 
-func validGroupName(web model.UserWebsite, groupName string) bool {
-	for _, char := range strings.Split(groupName, "") {
-		if strings.Contains(web.Website.Title, char) {
-			return true
+// eventKeepAliveInterval is the interval of comment sent to keep idle
+// event stream open through proxies
+const eventKeepAliveInterval = 30 * time.Second
+
+// watchedWebsites caches websites of user for an event stream, so that
+// events are filtered without querying repository for every event. Cache is
+// reloaded once user creates or deletes website, and at every keep-alive to
+// pick up muted, snoozed and synced websites
+type watchedWebsites struct {
+	r        repository.Repostory
+	userUUID string
+	webs     map[string]model.UserWebsite
+}
+
+func newWatchedWebsites(r repository.Repostory, userUUID string) (*watchedWebsites, error) {
+	watched := &watchedWebsites{r: r, userUUID: userUUID}
+	return watched, watched.reload()
+}
+
+func (watched *watchedWebsites) reload() error {
+	webs, err := watched.r.FindUserWebsites(watched.userUUID)
+	if err != nil {
+		return err
+	}
+
+	watched.webs = make(map[string]model.UserWebsite, len(webs))
+	for _, web := range webs {
+		watched.webs[web.WebsiteUUID] = web
+	}
+
+	return nil
+}
+
+// visible reports if event should be sent to user, update of website is
+// sent to users watching it unless they muted or snoozed the website
+func (watched *watchedWebsites) visible(ctx context.Context, event events.Event, now time.Time) bool {
+	if event.UserUUID != "" {
+		if event.UserUUID != watched.userUUID {
+			return false
+		}
+
+		if event.Type == events.WebsiteCreated || event.Type == events.WebsiteDeleted {
+			if err := watched.reload(); err != nil {
+				zerolog.Ctx(ctx).Error().Err(err).Msg("reload watched websites failed")
+			}
 		}
+
+		return true
 	}
-	return false
+
+	web, ok := watched.webs[event.WebsiteUUID]
+	return ok && web.Notifiable(now)
 }
 
-func changeWebsiteGroupHandler(r repository.Repostory) http.HandlerFunc {
+// websiteEventsHandler streams events of websites of user as server-sent events
+func websiteEventsHandler(r repository.Repostory, subscriber events.Subscriber) http.HandlerFunc {
 	return func(res http.ResponseWriter, req *http.Request) {
-		web := req.Context().Value(ContextKeyWebsite).(model.UserWebsite)
-		groupName := req.Context().Value(ContextKeyGroup).(string)
-		if !validGroupName(web, groupName) {
-			writeError(res, http.StatusBadRequest, errors.New("invalid group name"))
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+
+		eventChan, err := subscriber.Subscribe(ctx)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("subscribe website events failed")
+			writeError(res, req, err)
 			return
 		}
-		web.GroupName = groupName
-		err := r.UpdateUserWebsite(&web)
+
+		watched, err := newWatchedWebsites(r, userUUID)
 		if err != nil {
-			writeError(res, http.StatusBadRequest, err)
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("find user websites failed")
+			writeError(res, req, err)
 			return
 		}
-		json.NewEncoder(res).Encode(map[string]interface{}{
-			"website": web,
+
+		// stream is kept open beyond the write timeout of server
+		rc := http.NewResponseController(res)
+		if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+			zerolog.Ctx(req.Context()).Debug().Err(err).Msg("clear write deadline failed")
+		}
+
+		res.Header().Set("Content-Type", "text/event-stream")
+		res.Header().Set("Cache-Control", "no-cache")
+		res.Header().Set("Connection", "keep-alive")
+		res.WriteHeader(http.StatusOK)
+		rc.Flush()
+
+		ticker := time.NewTicker(eventKeepAliveInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := watched.reload(); err != nil {
+					zerolog.Ctx(req.Context()).Error().Err(err).Msg("reload watched websites failed")
+				}
+				fmt.Fprint(res, ": keep-alive\n\n")
+			case event, ok := <-eventChan:
+				if !ok {
+					return
+				}
+				if !watched.visible(req.Context(), event, time.Now()) {
+					continue
+				}
+
+				data, err := json.Marshal(event)
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event.Type, data)
+			}
+
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func addWebsiteTagHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		web := req.Context().Value(ContextKeyWebsite).(model.UserWebsite)
+		tag := req.Context().Value(ContextKeyTag).(string)
+
+		err := r.CreateUserWebsiteTag(&model.UserWebsiteTag{
+			UserUUID: web.UserUUID, WebsiteUUID: web.WebsiteUUID, Tag: tag,
 		})
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("create user website tag failed")
+			writeError(res, req, err)
+			return
+		}
+
+		if !web.HasTag(tag) {
+			web.Tags = append(web.Tags, tag)
+		}
+
+		json.NewEncoder(res).Encode(api.WebsiteResponse{Website: web.ToAPI()})
 	}
 }
 
-func dbStatsHandler(r repository.Repostory) http.HandlerFunc {
+func removeWebsiteTagHandler(r repository.Repostory) http.HandlerFunc {
 	return func(res http.ResponseWriter, req *http.Request) {
-		json.NewEncoder(res).Encode(r.Stats())
+		web := req.Context().Value(ContextKeyWebsite).(model.UserWebsite)
+		tag := chi.URLParam(req, "tag")
+
+		err := r.DeleteUserWebsiteTag(&model.UserWebsiteTag{
+			UserUUID: web.UserUUID, WebsiteUUID: web.WebsiteUUID, Tag: tag,
+		})
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("delete user website tag failed")
+			writeError(res, req, err)
+			return
+		}
+
+		var tags []string
+		for _, t := range web.Tags {
+			if t != tag {
+				tags = append(tags, t)
+			}
+		}
+		web.Tags = tags
+
+		json.NewEncoder(res).Encode(api.WebsiteResponse{Website: web.ToAPI()})
+	}
+}
+
+func getTagsHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+		tags, err := r.FindUserTags(userUUID)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("find user tags failed")
+			writeError(res, req, err)
+			return
+		}
+
+		if tags == nil {
+			tags = []string{}
+		}
+
+		json.NewEncoder(res).Encode(api.TagsResponse{Tags: tags})
+	}
+}
+
+func renameTagHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+		tag := chi.URLParam(req, "tag")
+		newTag := req.Context().Value(ContextKeyTag).(string)
+
+		err := r.RenameUserTag(userUUID, tag, newTag)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("rename user tag failed")
+			writeError(res, req, err)
+			return
+		}
+
+		json.NewEncoder(res).Encode(api.MessageResponse{Message: fmt.Sprintf("tag <%v> renamed to <%v>", tag, newTag)})
+	}
+}
+
+func deleteTagHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+		tag := chi.URLParam(req, "tag")
+
+		err := r.DeleteUserTag(userUUID, tag)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("delete user tag failed")
+			writeError(res, req, err)
+			return
+		}
+
+		json.NewEncoder(res).Encode(api.MessageResponse{Message: fmt.Sprintf("tag <%v> deleted", tag)})
+	}
+}
+
+func validGroupName(web model.UserWebsite, groupName string) bool {
+	for _, char := range strings.Split(groupName, "") {
+		if strings.Contains(web.Website.Title, char) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkGroupName normalizes group name of websites, websites have to share
+// characters of their title with the group name if strict is true
+func checkGroupName(webs []model.UserWebsite, groupName string, strict bool) (string, error) {
+	groupName, err := model.NormalizeGroupName(groupName)
+	if err != nil {
+		return "", err
+	}
+
+	if strict {
+		for _, web := range webs {
+			if !validGroupName(web, groupName) {
+				return "", model.ErrInvalidGroupName
+			}
+		}
+	}
+
+	return groupName, nil
+}
+
+func changeWebsiteGroupHandler(r repository.Repostory, strict bool) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		web := req.Context().Value(ContextKeyWebsite).(model.UserWebsite)
+		groupName, err := checkGroupName(
+			[]model.UserWebsite{web}, req.Context().Value(ContextKeyGroup).(string), strict,
+		)
+		if err != nil {
+			writeError(res, req, err)
+			return
+		}
+		oldGroupName := web.GroupName
+		web.GroupName = groupName
+		err = r.UpdateUserWebsite(&web)
+		if err != nil {
+			writeError(res, req, err)
+			return
+		}
+		syncChangedGroups(req.Context(), r, web.UserUUID, oldGroupName, groupName)
+		json.NewEncoder(res).Encode(api.WebsiteResponse{Website: web.ToAPI()})
+	}
+}
+
+// renameWebsiteGroupHandler renames group to a name not used by other group
+func renameWebsiteGroupHandler(r repository.Repostory, strict bool) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+		groupName := chi.URLParam(req, "groupName")
+
+		webs, err := r.FindUserWebsitesByGroup(userUUID, groupName)
+		if err != nil || len(webs) == 0 {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("find user websites by group failed")
+			writeError(res, req, RecordNotFoundError)
+			return
+		}
+
+		newGroupName, err := checkGroupName(webs, req.Context().Value(ContextKeyGroup).(string), strict)
+		if err != nil {
+			writeError(res, req, err)
+			return
+		}
+
+		existing, err := r.FindUserWebsitesByGroup(userUUID, newGroupName)
+		if err == nil && len(existing) > 0 {
+			writeError(res, req, GroupExistError)
+			return
+		}
+
+		renameWebsiteGroup(res, req, r, userUUID, groupName, newGroupName, false)
+	}
+}
+
+// mergeWebsiteGroupHandler moves all websites of group to an existing group
+func mergeWebsiteGroupHandler(r repository.Repostory, strict bool) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+		groupName := chi.URLParam(req, "groupName")
+		targetName := req.Context().Value(ContextKeyGroup).(string)
+
+		webs, err := r.FindUserWebsitesByGroup(userUUID, groupName)
+		if err != nil || len(webs) == 0 {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("find user websites by group failed")
+			writeError(res, req, RecordNotFoundError)
+			return
+		}
+
+		targets, err := r.FindUserWebsitesByGroup(userUUID, targetName)
+		if err != nil || len(targets) == 0 || targetName == groupName {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("find user websites by group failed")
+			writeError(res, req, RecordNotFoundError)
+			return
+		}
+
+		if _, err := checkGroupName(webs, targetName, strict); err != nil {
+			writeError(res, req, err)
+			return
+		}
+
+		renameWebsiteGroup(res, req, r, userUUID, groupName, targetName, true)
+	}
+}
+
+// renameWebsiteGroup moves websites of group to new group, share of the
+// group follows the websites unless new group is shared already. Public link
+// of merged group is revoked instead of publishing the websites of target
+// group to its holders
+func renameWebsiteGroup(
+	res http.ResponseWriter, req *http.Request, r repository.Repostory,
+	userUUID, groupName, newGroupName string, merge bool,
+) {
+	err := r.WithTx(func(r repository.Repostory) error {
+		if merge {
+			if err := r.DeleteGroupPublicLink(userUUID, groupName); err != nil {
+				return err
+			}
+		}
+
+		if err := r.RenameUserWebsiteGroup(userUUID, groupName, newGroupName); err != nil {
+			return err
+		}
+
+		return syncGroups(r, userUUID, groupName, newGroupName)
+	})
+	if err != nil {
+		zerolog.Ctx(req.Context()).Error().Err(err).Msg("rename user website group failed")
+		writeError(res, req, err)
+		return
+	}
+
+	webs, err := r.FindUserWebsitesByGroup(userUUID, newGroupName)
+	if err != nil {
+		zerolog.Ctx(req.Context()).Error().Err(err).Msg("find user websites by group failed")
+		writeError(res, req, err)
+		return
+	}
+
+	json.NewEncoder(res).Encode(websiteGroupResponse(webs))
+}
+
+// splitWebsiteGroupHandler moves websites of group to another group
+func splitWebsiteGroupHandler(r repository.Repostory, strict bool) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+		groupName := chi.URLParam(req, "groupName")
+
+		webs, err := r.FindUserWebsitesByGroup(userUUID, groupName)
+		if err != nil || len(webs) == 0 {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("find user websites by group failed")
+			writeError(res, req, RecordNotFoundError)
+			return
+		}
+
+		moveWebsites(res, req, r, strict, model.UserWebsites(webs), req.Context().Value(ContextKeyGroup).(string))
+	}
+}
+
+// moveWebsitesHandler moves websites of user to group
+func moveWebsitesHandler(r repository.Repostory, strict bool) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+
+		webs, err := r.FindUserWebsites(userUUID)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("find user websites failed")
+			writeError(res, req, err)
+			return
+		}
+
+		moveWebsites(res, req, r, strict, webs, chi.URLParam(req, "groupName"))
+	}
+}
+
+// moveWebsites moves websites listed in request to group, all listed websites
+// have to be found in candidates
+func moveWebsites(
+	res http.ResponseWriter, req *http.Request, r repository.Repostory,
+	strict bool, candidates model.UserWebsites, groupName string,
+) {
+	userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+	webUUIDs := req.Context().Value(ContextKeyWebsiteUUIDs).([]string)
+
+	webs := make([]model.UserWebsite, 0, len(webUUIDs))
+	var sourceGroups []string
+	for _, webUUID := range webUUIDs {
+		found := false
+		for _, web := range candidates {
+			if web.WebsiteUUID == webUUID {
+				webs = append(webs, web)
+				sourceGroups = append(sourceGroups, web.GroupName)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			writeError(res, req, RecordNotFoundError)
+			return
+		}
+
+		synced, err := syncedWebsite(r, webs[len(webs)-1])
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("find group share subscriptions failed")
+			writeError(res, req, err)
+			return
+		} else if synced {
+			writeError(res, req, SyncedGroupError)
+			return
+		}
+	}
+
+	groupName, err := checkGroupName(webs, groupName, strict)
+	if err != nil {
+		writeError(res, req, err)
+		return
+	}
+
+	err = r.WithTx(func(r repository.Repostory) error {
+		if err := r.MoveUserWebsites(userUUID, groupName, webUUIDs); err != nil {
+			return err
+		}
+
+		return syncGroups(r, userUUID, append(sourceGroups, groupName)...)
+	})
+	if err != nil {
+		zerolog.Ctx(req.Context()).Error().Err(err).Msg("move user websites failed")
+		writeError(res, req, err)
+		return
+	}
+
+	group, err := r.FindUserWebsitesByGroup(userUUID, groupName)
+	if err != nil {
+		zerolog.Ctx(req.Context()).Error().Err(err).Msg("find user websites by group failed")
+		writeError(res, req, err)
+		return
+	}
+
+	json.NewEncoder(res).Encode(websiteGroupResponse(group))
+}
+
+// syncChangedGroups updates subscribers and public link of groups of user
+// changed by request, failed sync is retried by next change of the group
+func syncChangedGroups(ctx context.Context, r repository.Repostory, userUUID string, groupNames ...string) {
+	err := syncGroups(r, userUUID, groupNames...)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("sync changed groups failed")
+	}
+}
+
+// syncGroups updates subscribers and public link of groups of user
+func syncGroups(r repository.Repostory, userUUID string, groupNames ...string) error {
+	var errs []error
+	synced := make(map[string]bool)
+	for _, groupName := range groupNames {
+		if synced[groupName] {
+			continue
+		}
+		synced[groupName] = true
+
+		err := sharing.SyncGroup(r, userUUID, groupName)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("sync shared group %s fail: %w", groupName, err))
+		}
+
+		err = revokeEmptyGroupLink(r, userUUID, groupName)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("revoke group public link %s fail: %w", groupName, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// revokeEmptyGroupLink revokes public link of group which no longer has any
+// website, so that a new group reusing the name is not published by the link
+func revokeEmptyGroupLink(r repository.Repostory, userUUID, groupName string) error {
+	webs, err := r.FindUserWebsitesByGroup(userUUID, groupName)
+	if err != nil || len(webs) > 0 {
+		return nil
+	}
+
+	return r.DeleteGroupPublicLink(userUUID, groupName)
+}
+
+// subscribedGroup reports if group of user is synced from a subscribed share
+func subscribedGroup(r repository.Repostory, userUUID, groupName string) (bool, error) {
+	subs, err := r.FindUserGroupShareSubscriptions(userUUID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, sub := range subs {
+		if sub.GroupName == groupName {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// syncedWebsite reports if website is synced from a share which user still
+// subscribes, websites are left to user once the share is dropped
+func syncedWebsite(r repository.Repostory, web model.UserWebsite) (bool, error) {
+	if web.ShareUUID == "" {
+		return false, nil
+	}
+
+	subs, err := r.FindUserGroupShareSubscriptions(web.UserUUID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, sub := range subs {
+		if sub.ShareUUID == web.ShareUUID && sub.GroupName == web.GroupName {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func getGroupOrderHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+		order, err := r.FindUserGroupOrder(userUUID)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("find user group order failed")
+			writeError(res, req, err)
+			return
+		}
+
+		if order == nil {
+			order = []string{}
+		}
+
+		json.NewEncoder(res).Encode(api.GroupOrderResponse{GroupOrder: order})
+	}
+}
+
+func updateGroupOrderHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+		order := req.Context().Value(ContextKeyGroupOrder).([]string)
+
+		err := r.UpdateUserGroupOrder(userUUID, order)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("update user group order failed")
+			writeError(res, req, err)
+			return
+		}
+
+		json.NewEncoder(res).Encode(api.GroupOrderResponse{GroupOrder: order})
+	}
+}
+
+func getGroupSharesHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+
+		shares, err := r.FindGroupShares(userUUID)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("find group shares failed")
+			writeError(res, req, err)
+			return
+		}
+
+		subs, err := r.FindUserGroupShareSubscriptions(userUUID)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("find group share subscriptions failed")
+			writeError(res, req, err)
+			return
+		}
+
+		result := api.GroupSharesResponse{
+			Shares:        make([]api.GroupShare, len(shares)),
+			Subscriptions: make([]api.GroupShareSubscription, len(subs)),
+		}
+		for i, share := range shares {
+			result.Shares[i] = share.ToAPI()
+		}
+		for i, sub := range subs {
+			result.Subscriptions[i] = sub.ToAPI()
+		}
+
+		json.NewEncoder(res).Encode(result)
+	}
+}
+
+// shareWebsiteGroupHandler shares group of user, mode of share is updated
+// if the group is shared already
+func shareWebsiteGroupHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+		groupName := chi.URLParam(req, "groupName")
+		mode := req.Context().Value(ContextKeyShareMode).(model.GroupShareMode)
+
+		webs, err := r.FindUserWebsitesByGroup(userUUID, groupName)
+		if err != nil || len(webs) == 0 {
+			writeError(res, req, RecordNotFoundError)
+			return
+		}
+
+		share := model.NewGroupShare(userUUID, groupName, mode)
+		err = r.CreateGroupShare(&share)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("create group share failed")
+			writeError(res, req, err)
+			return
+		}
+
+		json.NewEncoder(res).Encode(api.GroupShareResponse{Share: share.ToAPI()})
+	}
+}
+
+// unshareWebsiteGroupHandler stops sharing group, websites already synced
+// to subscribers are kept in their groups
+func unshareWebsiteGroupHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+		groupName := chi.URLParam(req, "groupName")
+
+		share, err := r.FindGroupShareByGroup(userUUID, groupName)
+		if err != nil {
+			writeError(res, req, RecordNotFoundError)
+			return
+		}
+
+		err = r.DeleteGroupShare(share.UUID)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("delete group share failed")
+			writeError(res, req, err)
+			return
+		}
+
+		json.NewEncoder(res).Encode(api.MessageResponse{Message: fmt.Sprintf("group <%v> unshared", groupName)})
+	}
+}
+
+// subscribeGroupShareHandler subscribes share into a new group of user,
+// the group is named after the shared group unless group name is given
+func subscribeGroupShareHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+
+		share, err := r.FindGroupShare(chi.URLParam(req, "shareUUID"))
+		if err != nil {
+			writeError(res, req, RecordNotFoundError)
+			return
+		}
+
+		if share.OwnerUUID == userUUID {
+			writeError(res, req, InvalidParamsError)
+			return
+		}
+
+		groupName := strings.TrimSpace(req.Context().Value(ContextKeyGroup).(string))
+		if groupName == "" {
+			groupName = share.GroupName
+		}
+
+		existing, err := r.FindUserWebsitesByGroup(userUUID, groupName)
+		if err == nil && len(existing) > 0 {
+			writeError(res, req, GroupExistError)
+			return
+		}
+
+		sub := model.NewGroupShareSubscription(share.UUID, userUUID, groupName)
+		err = r.CreateGroupShareSubscription(&sub)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("create group share subscription failed")
+			writeError(res, req, err)
+			return
+		}
+
+		err = sharing.SyncSubscription(r, *share, sub)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("sync group share subscription failed")
+			writeError(res, req, err)
+			return
+		}
+
+		json.NewEncoder(res).Encode(api.GroupShareSubscriptionResponse{Subscription: sub.ToAPI()})
+	}
+}
+
+// unsubscribeGroupShareHandler stops syncing share, websites already synced
+// are kept in group of user
+func unsubscribeGroupShareHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+		shareUUID := chi.URLParam(req, "shareUUID")
+
+		err := r.DeleteGroupShareSubscription(shareUUID, userUUID)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("delete group share subscription failed")
+			writeError(res, req, err)
+			return
+		}
+
+		json.NewEncoder(res).Encode(api.MessageResponse{Message: fmt.Sprintf("share <%v> unsubscribed", shareUUID)})
+	}
+}
+
+// findEditableShare finds share which user is allowed to change websites,
+// which is any share of owner or collaborative share subscribed by user
+func findEditableShare(r repository.Repostory, shareUUID, userUUID string) (*model.GroupShare, error) {
+	share, err := r.FindGroupShare(shareUUID)
+	if err != nil {
+		return nil, RecordNotFoundError
+	}
+
+	if share.OwnerUUID == userUUID {
+		return share, nil
+	}
+
+	if share.Mode != model.GroupShareCollaborative {
+		return nil, ForbiddenError
+	}
+
+	subs, err := r.FindUserGroupShareSubscriptions(userUUID)
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range subs {
+		if sub.ShareUUID == share.UUID {
+			return share, nil
+		}
+	}
+
+	return nil, ForbiddenError
+}
+
+// addSharedWebsiteHandler adds website to shared group on behalf of owner
+// and syncs it to all subscribers
+func addSharedWebsiteHandler(r repository.Repostory, conf *config.WebsiteConfig) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+		url := req.Context().Value(ContextKeyWebURL).(string)
+
+		share, err := findEditableShare(r, chi.URLParam(req, "shareUUID"), userUUID)
+		if err != nil {
+			writeError(res, req, err)
+			return
+		}
+
+		web, err := service.CreatePendingWebsite(req.Context(), r, conf, url)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("create website failed")
+			writeError(res, req, err)
+			return
+		}
+
+		var sourceGroups []string
+		if ownerWeb, err := r.FindUserWebsite(share.OwnerUUID, web.UUID); err == nil {
+			if ownerWeb.GroupName != share.GroupName {
+				sourceGroups = append(sourceGroups, ownerWeb.GroupName)
+				err = r.MoveUserWebsites(share.OwnerUUID, share.GroupName, []string{web.UUID})
+			}
+		} else {
+			ownerWeb := model.NewUserWebsite(web, share.OwnerUUID)
+			ownerWeb.GroupName = share.GroupName
+			err = r.CreateUserWebsite(&ownerWeb)
+		}
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("add website to shared group failed")
+			writeError(res, req, err)
+			return
+		}
+
+		err = sharing.Sync(r, *share)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("sync group share failed")
+			writeError(res, req, err)
+			return
+		}
+		syncChangedGroups(req.Context(), r, share.OwnerUUID, sourceGroups...)
+
+		res.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(res).Encode(api.CreateWebsiteResponse{
+			Message:     fmt.Sprintf("website <%v> accepted", web.URL),
+			WebsiteUUID: web.UUID,
+		})
+	}
+}
+
+// removeSharedWebsiteHandler removes website from shared group on behalf of
+// owner and from groups of all subscribers
+func removeSharedWebsiteHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+
+		share, err := findEditableShare(r, chi.URLParam(req, "shareUUID"), userUUID)
+		if err != nil {
+			writeError(res, req, err)
+			return
+		}
+
+		ownerWeb, err := r.FindUserWebsite(share.OwnerUUID, chi.URLParam(req, "webUUID"))
+		if err != nil || ownerWeb.GroupName != share.GroupName {
+			writeError(res, req, RecordNotFoundError)
+			return
+		}
+
+		err = r.DeleteUserWebsite(ownerWeb)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("delete user website failed")
+			writeError(res, req, err)
+			return
+		}
+
+		err = sharing.RemoveWebsite(r, *share, ownerWeb.WebsiteUUID)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("remove shared website failed")
+			writeError(res, req, err)
+			return
+		}
+
+		err = sharing.Sync(r, *share)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("sync group share failed")
+			writeError(res, req, err)
+			return
+		}
+		err = revokeEmptyGroupLink(r, share.OwnerUUID, share.GroupName)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Str("group", share.GroupName).Msg("revoke group public link failed")
+		}
+
+		json.NewEncoder(res).Encode(api.MessageResponse{Message: fmt.Sprintf("website <%v> removed from share", ownerWeb.Website.Title)})
+	}
+}
+
+// getGroupPublicLinkHandler returns public link of group if the group is
+// published
+func getGroupPublicLinkHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+
+		link, err := r.FindGroupPublicLinkByGroup(userUUID, chi.URLParam(req, "groupName"))
+		if err != nil {
+			writeError(res, req, RecordNotFoundError)
+			return
+		}
+
+		json.NewEncoder(res).Encode(api.GroupPublicLinkResponse{PublicLink: link.ToAPI()})
+	}
+}
+
+// createGroupPublicLinkHandler publishes group with a new token, calling it
+// on a published group rotates the token and revokes the old one
+func createGroupPublicLinkHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+		groupName := chi.URLParam(req, "groupName")
+
+		webs, err := r.FindUserWebsitesByGroup(userUUID, groupName)
+		if err != nil || len(webs) == 0 {
+			writeError(res, req, RecordNotFoundError)
+			return
+		}
+
+		link, err := model.NewGroupPublicLink(userUUID, groupName)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("generate group public link failed")
+			writeError(res, req, err)
+			return
+		}
+
+		err = r.CreateGroupPublicLink(&link)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("create group public link failed")
+			writeError(res, req, err)
+			return
+		}
+
+		json.NewEncoder(res).Encode(api.GroupPublicLinkResponse{PublicLink: link.ToAPI()})
+	}
+}
+
+func deleteGroupPublicLinkHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+		groupName := chi.URLParam(req, "groupName")
+
+		if _, err := r.FindGroupPublicLinkByGroup(userUUID, groupName); err != nil {
+			writeError(res, req, RecordNotFoundError)
+			return
+		}
+
+		err := r.DeleteGroupPublicLink(userUUID, groupName)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("delete group public link failed")
+			writeError(res, req, err)
+			return
+		}
+
+		json.NewEncoder(res).Encode(api.MessageResponse{Message: fmt.Sprintf("public link of group <%v> revoked", groupName)})
+	}
+}
+
+// wantAtom reports if client asks for atom feed by format query or accept
+// header
+func wantAtom(req *http.Request) bool {
+	if format := req.URL.Query().Get("format"); format != "" {
+		return format == "atom"
+	}
+
+	return strings.Contains(req.Header.Get("Accept"), "application/atom+xml")
+}
+
+// publicGroupHandler lists websites of group published by public link
+// without exposing anything specific to the owner
+func publicGroupHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		link, err := r.FindGroupPublicLink(chi.URLParam(req, "token"))
+		if err != nil {
+			writeError(res, req, RecordNotFoundError)
+			return
+		}
+
+		webs, err := r.FindUserWebsitesByGroup(link.OwnerUUID, link.GroupName)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("find user websites by group failed")
+			writeError(res, req, err)
+			return
+		}
+
+		if wantAtom(req) {
+			scheme := "http"
+			if req.TLS != nil {
+				scheme = "https"
+			}
+
+			res.Header().Set("Content-Type", sharing.AtomContentType)
+			err = sharing.EncodeAtom(res, fmt.Sprintf("%s://%s%s", scheme, req.Host, req.URL.Path), link.GroupName, webs)
+			if err != nil {
+				zerolog.Ctx(req.Context()).Error().Err(err).Msg("encode atom feed failed")
+			}
+			return
+		}
+
+		result := api.PublicGroupResponse{
+			GroupName: link.GroupName,
+			Websites:  make([]api.PublicWebsite, len(webs)),
+		}
+		for i, web := range webs {
+			result.Websites[i] = api.PublicWebsite{
+				Title:      web.Website.Title,
+				UpdateTime: web.Website.UpdateTime.Format("2006-01-02T15:04:05 MST"),
+				URL:        web.Website.URL,
+			}
+		}
+
+		json.NewEncoder(res).Encode(result)
+	}
+}
+
+func brokenWebsiteSettingsHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		settings, err := r.FindWebsiteSettings()
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("find website settings failed")
+			writeError(res, req, err)
+			return
+		}
+
+		broken := []api.WebsiteSetting{}
+		for _, setting := range settings {
+			if setting.IsBroken() {
+				broken = append(broken, setting.ToAPI())
+			}
+		}
+
+		json.NewEncoder(res).Encode(api.WebsiteSettingsResponse{WebsiteSettings: broken})
+	}
+}
+
+// adminWebsitesHandler lists all websites with number of users subscribing
+// them, website nobody subscribes is listed with zero subscriber
+func adminWebsitesHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		webs, err := r.FindWebsites()
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("find websites failed")
+			writeError(res, req, err)
+			return
+		}
+
+		counts, err := r.FindWebsiteSubscriberCounts()
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("count website subscribers failed")
+			writeError(res, req, err)
+			return
+		}
+
+		result := api.AdminWebsitesResponse{Websites: make([]api.AdminWebsite, len(webs))}
+		for i, web := range webs {
+			result.Websites[i] = api.AdminWebsite{
+				Health:          web.Health,
+				SubscriberCount: counts[web.UUID],
+				Website:         web.ToAPI(),
+			}
+		}
+
+		json.NewEncoder(res).Encode(result)
+	}
+}
+
+// forceCheckWebsiteHandler queues website to be fetched by worker
+// regardless of its schedule
+func forceCheckWebsiteHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		webUUID := chi.URLParam(req, "webUUID")
+		web, err := r.FindWebsite(webUUID)
+		if err != nil {
+			writeError(res, req, RecordNotFoundError)
+			return
+		}
+
+		updateReq := model.NewWebsiteUpdateRequest(web.UUID, telemetry.InjectTraceContext(req.Context()))
+		err = r.CreateWebsiteUpdateRequest(&updateReq)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("create website update request failed")
+			writeError(res, req, err)
+			return
+		}
+
+		res.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(res).Encode(api.MessageResponse{Message: fmt.Sprintf("website <%v> queued for check", web.URL)})
+	}
+}
+
+func workerStatusHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		statuses, err := r.FindWorkerStatuses()
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("find worker statuses failed")
+			writeError(res, req, err)
+			return
+		}
+
+		pendingCount, err := r.CountWebsiteUpdateRequests()
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("count website update requests failed")
+			writeError(res, req, err)
+			return
+		}
+
+		now := time.Now().UTC()
+		result := api.WorkerStatusResponse{
+			PendingUpdateRequests: pendingCount,
+			Workers:               make([]api.WorkerStatus, len(statuses)),
+		}
+		for i, status := range statuses {
+			result.Workers[i] = status.ToAPI(now)
+		}
+
+		json.NewEncoder(res).Encode(result)
+	}
+}
+
+func dbStatsHandler(r repository.Repostory) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		json.NewEncoder(res).Encode(r.Stats())
+	}
+}
+
+func openAPIHandler(serverURL string) http.HandlerFunc {
+	spec := api.OpenAPI(serverURL)
+
+	return func(res http.ResponseWriter, req *http.Request) {
+		json.NewEncoder(res).Encode(spec)
+	}
+}
+
+func loginHandler(authenticator *auth.LocalAuthenticator) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		if err := parseBody(res, req); err != nil {
+			writeError(res, req, err)
+			return
+		}
+
+		token, err := authenticator.Login(req.Form.Get("username"), req.Form.Get("password"))
+		if errors.Is(err, auth.ErrInvalidCredentials) {
+			writeError(res, req, err)
+			return
+		} else if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("login failed")
+			writeError(res, req, err)
+			return
+		}
+
+		json.NewEncoder(res).Encode(api.LoginResponse{Token: token})
+	}
+}
+
+func registerHandler(authenticator *auth.LocalAuthenticator) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		if err := parseBody(res, req); err != nil {
+			writeError(res, req, err)
+			return
+		}
+
+		user, err := authenticator.Register(req.Form.Get("username"), req.Form.Get("password"))
+		if errors.Is(err, model.ErrInvalidUsername) || errors.Is(err, model.ErrInvalidPassword) ||
+			errors.Is(err, auth.ErrUserExists) {
+			writeError(res, req, err)
+			return
+		} else if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("register failed")
+			writeError(res, req, err)
+			return
+		}
+
+		res.WriteHeader(http.StatusCreated)
+		json.NewEncoder(res).Encode(api.RegisterResponse{
+			UserUUID: user.UUID,
+			Username: user.Username,
+		})
+	}
+}
+
+func getAPITokensHandler(authenticator *auth.LocalAuthenticator) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+
+		tokens, err := authenticator.APITokens(userUUID)
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("find api tokens failed")
+			writeError(res, req, err)
+			return
+		}
+
+		result := api.APITokensResponse{Tokens: make([]api.APIToken, len(tokens))}
+		for i, token := range tokens {
+			result.Tokens[i] = token.ToAPI()
+		}
+
+		json.NewEncoder(res).Encode(result)
+	}
+}
+
+// createAPITokenHandler responds the plain token, which is the only chance
+// for user to get it
+func createAPITokenHandler(authenticator *auth.LocalAuthenticator) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+		if err := parseBody(res, req); err != nil {
+			writeError(res, req, err)
+			return
+		}
+
+		apiToken, token, err := authenticator.CreateAPIToken(userUUID, req.Form.Get("name"))
+		if err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("create api token failed")
+			writeError(res, req, err)
+			return
+		}
+
+		res.WriteHeader(http.StatusCreated)
+		json.NewEncoder(res).Encode(api.CreateAPITokenResponse{
+			APIToken: apiToken.ToAPI(),
+			Token:    token,
+		})
+	}
+}
+
+func deleteAPITokenHandler(authenticator *auth.LocalAuthenticator) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+		tokenUUID := chi.URLParam(req, "tokenUUID")
+
+		if err := authenticator.DeleteAPIToken(userUUID, tokenUUID); err != nil {
+			zerolog.Ctx(req.Context()).Error().Err(err).Msg("delete api token failed")
+			writeError(res, req, err)
+			return
+		}
+
+		json.NewEncoder(res).Encode(api.MessageResponse{Message: "api token deleted"})
 	}
 }
@@ -2,15 +2,19 @@ package website
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/htchan/WebHistory/internal/auth"
 	"github.com/htchan/WebHistory/internal/config"
+	"github.com/htchan/WebHistory/internal/model"
 	"github.com/htchan/WebHistory/internal/repository"
-	"github.com/htchan/WebHistory/internal/utils"
+	"github.com/htchan/WebHistory/internal/service"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
@@ -18,11 +22,20 @@ import (
 type ContextKey string
 
 const (
-	ContextKeyReqID    ContextKey = "req_id"
-	ContextKeyUserUUID ContextKey = "user_uuid"
-	ContextKeyWebURL   ContextKey = "web_url"
-	ContextKeyWebsite  ContextKey = "website"
-	ContextKeyGroup    ContextKey = "group"
+	ContextKeyReqID     ContextKey = "req_id"
+	ContextKeyUserUUID  ContextKey = "user_uuid"
+	ContextKeyRole      ContextKey = "role"
+	ContextKeyWebURL    ContextKey = "web_url"
+	ContextKeyWebsite   ContextKey = "website"
+	ContextKeyGroup     ContextKey = "group"
+	ContextKeyItem      ContextKey = "item"
+	ContextKeySnooze    ContextKey = "snooze_until"
+	ContextKeyMuted     ContextKey = "muted"
+	ContextKeyTag       ContextKey = "tag"
+	ContextKeyShareMode ContextKey = "share_mode"
+
+	ContextKeyWebsiteUUIDs ContextKey = "website_uuids"
+	ContextKeyGroupOrder   ContextKey = "group_order"
 )
 
 func logRequest() func(next http.Handler) http.Handler {
@@ -48,7 +61,7 @@ func logRequest() func(next http.Handler) http.Handler {
 	}
 }
 
-func AuthenticateMiddleware(conf *config.UserServiceConfig) func(next http.Handler) http.Handler {
+func AuthenticateMiddleware(authenticator auth.Authenticator, roles auth.Roles) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(
 			func(res http.ResponseWriter, req *http.Request) {
@@ -57,26 +70,51 @@ func AuthenticateMiddleware(conf *config.UserServiceConfig) func(next http.Handl
 					return
 				}
 				token := req.Header.Get("Authorization")
-				userUUID := ""
-
-				if token != "" {
-					userUUID = utils.FindUserByToken(token, conf)
-				}
-
-				if userUUID == "" {
-					writeError(res, http.StatusUnauthorized, UnauthorizedError)
+				userUUID, err := authenticator.Authenticate(req.Context(), token)
+				if errors.Is(err, auth.ErrUnavailable) {
+					zerolog.Ctx(req.Context()).Error().Err(err).Msg("authenticate failed")
+					writeError(res, req, auth.ErrUnavailable)
+					return
+				} else if err != nil || userUUID == "" {
+					zerolog.Ctx(req.Context()).Debug().Err(err).Msg("authenticate failed")
+					writeError(res, req, UnauthorizedError)
 					return
 				}
 
+				role := roles.Of(userUUID)
 				zerolog.Ctx(req.Context()).Debug().
 					Str("user_uuid", userUUID).
+					Str("role", string(role)).
 					Msg("set params")
 				ctx := context.WithValue(req.Context(), ContextKeyUserUUID, userUUID)
+				ctx = context.WithValue(ctx, ContextKeyRole, role)
 				next.ServeHTTP(res, req.WithContext(ctx))
 			},
 		)
 	}
 }
+
+// RequireRole rejects authenticated user without the given role
+func RequireRole(role auth.Role) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(res http.ResponseWriter, req *http.Request) {
+				if req.Method == http.MethodOptions {
+					next.ServeHTTP(res, req)
+					return
+				}
+
+				userRole, _ := req.Context().Value(ContextKeyRole).(auth.Role)
+				if userRole != role {
+					writeError(res, req, ForbiddenError)
+					return
+				}
+
+				next.ServeHTTP(res, req)
+			},
+		)
+	}
+}
 func SetContentType(next http.Handler) http.Handler {
 	return http.HandlerFunc(
 		func(res http.ResponseWriter, req *http.Request) {
@@ -86,27 +124,39 @@ func SetContentType(next http.Handler) http.Handler {
 	)
 }
 
-func WebsiteParams(next http.Handler) http.Handler {
-	return http.HandlerFunc(
-		func(res http.ResponseWriter, req *http.Request) {
-			err := req.ParseForm()
-			if err != nil {
-				writeError(res, http.StatusBadRequest, InvalidParamsError)
-				return
-			}
-			url := req.Form.Get("url")
-			if url == "" || !strings.HasPrefix(url, "http") {
-				writeError(res, http.StatusBadRequest, InvalidParamsError)
-				return
-			}
+// WebsiteParams parses the url of website, url must be of allowed scheme
+// and must not point to blocked host
+func WebsiteParams(conf *config.WebsiteConfig) func(http.Handler) http.Handler {
+	policy := service.NewURLPolicy(conf)
 
-			zerolog.Ctx(req.Context()).Debug().
-				Str("web url", url).
-				Msg("set params")
-			ctx := context.WithValue(req.Context(), ContextKeyWebURL, url)
-			next.ServeHTTP(res, req.WithContext(ctx))
-		},
-	)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(res http.ResponseWriter, req *http.Request) {
+				err := parseBody(res, req)
+				if err != nil {
+					writeError(res, req, err)
+					return
+				}
+
+				url, err := model.NormalizeURL(req.Form.Get("url"), conf.AllowedURLSchemes)
+				if err != nil {
+					writeError(res, req, invalidField("url", err))
+					return
+				}
+				if err := policy.Check(req.Context(), url); err != nil {
+					zerolog.Ctx(req.Context()).Warn().Err(err).Str("web url", url).Msg("url blocked")
+					writeError(res, req, invalidField("url", err))
+					return
+				}
+
+				zerolog.Ctx(req.Context()).Debug().
+					Str("web url", url).
+					Msg("set params")
+				ctx := context.WithValue(req.Context(), ContextKeyWebURL, url)
+				next.ServeHTTP(res, req.WithContext(ctx))
+			},
+		)
+	}
 }
 
 func QueryWebsite(r repository.Repostory) func(http.Handler) http.Handler {
@@ -117,7 +167,7 @@ func QueryWebsite(r repository.Repostory) func(http.Handler) http.Handler {
 				webUUID := chi.URLParam(req, "webUUID")
 				web, err := r.FindUserWebsite(userUUID, webUUID)
 				if err != nil {
-					writeError(res, http.StatusBadRequest, err)
+					writeError(res, req, err)
 					return
 				}
 
@@ -135,13 +185,21 @@ func QueryWebsite(r repository.Repostory) func(http.Handler) http.Handler {
 func GroupNameParams(next http.Handler) http.Handler {
 	return http.HandlerFunc(
 		func(res http.ResponseWriter, req *http.Request) {
-			err := req.ParseForm()
+			err := parseBody(res, req)
 			if err != nil {
-				writeError(res, http.StatusBadRequest, InvalidParamsError)
+				writeError(res, req, err)
 				return
 			}
 
 			groupName := req.Form.Get("group_name")
+			if strings.TrimSpace(groupName) != "" {
+				groupName, err = model.NormalizeGroupName(groupName)
+				if err != nil {
+					writeError(res, req, invalidField("group_name", err))
+					return
+				}
+			}
+
 			zerolog.Ctx(req.Context()).Debug().
 				Str("group name", groupName).
 				Msg("set params")
@@ -150,3 +208,237 @@ func GroupNameParams(next http.Handler) http.Handler {
 		},
 	)
 }
+
+// WebsiteUUIDsParams parses the non empty list of website uuid
+func WebsiteUUIDsParams(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(res http.ResponseWriter, req *http.Request) {
+			err := parseBody(res, req)
+			if err != nil {
+				writeError(res, req, err)
+				return
+			}
+
+			webUUIDs := req.Form["website_uuid"]
+			if len(webUUIDs) == 0 {
+				writeError(res, req, invalidField("website_uuid", errRequired))
+				return
+			}
+
+			zerolog.Ctx(req.Context()).Debug().
+				Strs("website uuids", webUUIDs).
+				Msg("set params")
+			ctx := context.WithValue(req.Context(), ContextKeyWebsiteUUIDs, webUUIDs)
+			next.ServeHTTP(res, req.WithContext(ctx))
+		},
+	)
+}
+
+// GroupOrderParams parses the ordered list of group name without duplication
+func GroupOrderParams(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(res http.ResponseWriter, req *http.Request) {
+			err := parseBody(res, req)
+			if err != nil {
+				writeError(res, req, err)
+				return
+			}
+
+			order := make([]string, 0, len(req.Form["group"]))
+			seen := make(map[string]bool)
+			for _, groupName := range req.Form["group"] {
+				groupName, err = model.NormalizeGroupName(groupName)
+				if err != nil {
+					writeError(res, req, invalidField("group", err))
+					return
+				}
+				if seen[groupName] {
+					writeError(res, req, invalidField("group", errDuplicatedValue))
+					return
+				}
+				seen[groupName] = true
+				order = append(order, groupName)
+			}
+
+			zerolog.Ctx(req.Context()).Debug().
+				Strs("group order", order).
+				Msg("set params")
+			ctx := context.WithValue(req.Context(), ContextKeyGroupOrder, order)
+			next.ServeHTTP(res, req.WithContext(ctx))
+		},
+	)
+}
+
+func ItemParams(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(res http.ResponseWriter, req *http.Request) {
+			err := parseBody(res, req)
+			if err != nil {
+				writeError(res, req, err)
+				return
+			}
+
+			item := req.Form.Get("item")
+			zerolog.Ctx(req.Context()).Debug().
+				Str("item", item).
+				Msg("set params")
+			ctx := context.WithValue(req.Context(), ContextKeyItem, item)
+			next.ServeHTTP(res, req.WithContext(ctx))
+		},
+	)
+}
+
+// SnoozeParams parses the RFC3339 until time of snooze, an empty until
+// time cancels the snooze
+func SnoozeParams(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(res http.ResponseWriter, req *http.Request) {
+			err := parseBody(res, req)
+			if err != nil {
+				writeError(res, req, err)
+				return
+			}
+
+			var until time.Time
+			if untilStr := req.Form.Get("until"); untilStr != "" {
+				until, err = time.Parse(time.RFC3339, untilStr)
+				if err != nil {
+					writeError(res, req, invalidField("until", err))
+					return
+				}
+			}
+
+			zerolog.Ctx(req.Context()).Debug().
+				Time("snooze until", until).
+				Msg("set params")
+			ctx := context.WithValue(req.Context(), ContextKeySnooze, until.UTC().Truncate(time.Second))
+			next.ServeHTTP(res, req.WithContext(ctx))
+		},
+	)
+}
+
+// MuteParams parses whether website is muted, website is muted if not specified
+func MuteParams(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(res http.ResponseWriter, req *http.Request) {
+			err := parseBody(res, req)
+			if err != nil {
+				writeError(res, req, err)
+				return
+			}
+
+			muted := true
+			if mutedStr := req.Form.Get("muted"); mutedStr != "" {
+				muted, err = strconv.ParseBool(mutedStr)
+				if err != nil {
+					writeError(res, req, invalidField("muted", err))
+					return
+				}
+			}
+
+			zerolog.Ctx(req.Context()).Debug().
+				Bool("muted", muted).
+				Msg("set params")
+			ctx := context.WithValue(req.Context(), ContextKeyMuted, muted)
+			next.ServeHTTP(res, req.WithContext(ctx))
+		},
+	)
+}
+
+// TagParams parses the tag to add to website or the new name of tag
+func TagParams(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(res http.ResponseWriter, req *http.Request) {
+			err := parseBody(res, req)
+			if err != nil {
+				writeError(res, req, err)
+				return
+			}
+
+			tag, err := model.NormalizeTag(req.Form.Get("tag"))
+			if err != nil {
+				writeError(res, req, invalidField("tag", err))
+				return
+			}
+
+			zerolog.Ctx(req.Context()).Debug().
+				Str("tag", tag).
+				Msg("set params")
+			ctx := context.WithValue(req.Context(), ContextKeyTag, tag)
+			next.ServeHTTP(res, req.WithContext(ctx))
+		},
+	)
+}
+
+func ShareModeParams(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(res http.ResponseWriter, req *http.Request) {
+			err := parseBody(res, req)
+			if err != nil {
+				writeError(res, req, err)
+				return
+			}
+
+			mode, err := model.ParseGroupShareMode(req.Form.Get("mode"))
+			if err != nil {
+				writeError(res, req, invalidField("mode", err))
+				return
+			}
+
+			zerolog.Ctx(req.Context()).Debug().
+				Str("share mode", string(mode)).
+				Msg("set params")
+			ctx := context.WithValue(req.Context(), ContextKeyShareMode, mode)
+			next.ServeHTTP(res, req.WithContext(ctx))
+		},
+	)
+}
+
+// RejectSyncedWebsite rejects change of website synced from a subscribed
+// share, websites of subscribed group are managed by the share owner and
+// collaborators through the share
+func RejectSyncedWebsite(r repository.Repostory) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(res http.ResponseWriter, req *http.Request) {
+				web := req.Context().Value(ContextKeyWebsite).(model.UserWebsite)
+
+				synced, err := syncedWebsite(r, web)
+				if err != nil {
+					zerolog.Ctx(req.Context()).Error().Err(err).Msg("find group share subscriptions failed")
+					writeError(res, req, err)
+					return
+				} else if synced {
+					writeError(res, req, SyncedGroupError)
+					return
+				}
+
+				next.ServeHTTP(res, req)
+			},
+		)
+	}
+}
+
+// RejectSubscribedGroup rejects renaming, merging or splitting group which
+// user subscribed from a share
+func RejectSubscribedGroup(r repository.Repostory) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(res http.ResponseWriter, req *http.Request) {
+				userUUID := req.Context().Value(ContextKeyUserUUID).(string)
+
+				subscribed, err := subscribedGroup(r, userUUID, chi.URLParam(req, "groupName"))
+				if err != nil {
+					zerolog.Ctx(req.Context()).Error().Err(err).Msg("find group share subscriptions failed")
+					writeError(res, req, err)
+					return
+				} else if subscribed {
+					writeError(res, req, SyncedGroupError)
+					return
+				}
+
+				next.ServeHTTP(res, req)
+			},
+		)
+	}
+}
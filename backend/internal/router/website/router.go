@@ -1,36 +1,63 @@
 package website
 
 import (
-	"errors"
 	"fmt"
 	"net/http"
 	"os"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/cors"
+	"github.com/htchan/WebHistory/internal/auth"
 	"github.com/htchan/WebHistory/internal/config"
+	"github.com/htchan/WebHistory/internal/events"
 	"github.com/htchan/WebHistory/internal/repository"
+	"github.com/htchan/WebHistory/internal/watchlist"
 )
 
-var UnauthorizedError = errors.New("unauthorized")
-var InvalidParamsError = errors.New("invalid params")
-var RecordNotFoundError = errors.New("record not found")
-
-func writeError(res http.ResponseWriter, statusCode int, err error) {
-	res.WriteHeader(statusCode)
-	fmt.Fprintln(res, fmt.Sprintf(`{ "error": "%v" }`, err))
-}
-
 func redirectLogin(res http.ResponseWriter, req *http.Request) {
 	loginURL := os.Getenv("LOGIN_URL")
 	serviceUUID := os.Getenv("SERVICE_UUID")
 	http.Redirect(res, req, fmt.Sprintf("%v?service=%v", loginURL, serviceUUID), 302)
 }
 
-func AddRoutes(router chi.Router, r repository.Repostory, conf *config.APIConfig) {
+func AddRoutes(router chi.Router, r repository.Repostory, pubsub events.PubSub, authenticator auth.Authenticator, conf *config.APIConfig) {
 	router.Use(logRequest())
 
+	importJobs := watchlist.NewJobStore(r)
+	roles := auth.NewRoles(conf.UserServiceConfig.AdminUsers)
+
 	router.Route(conf.BinConfig.APIRoutePrefix, func(router chi.Router) {
+		router.With(SetContentType).Get("/openapi.json", openAPIHandler(conf.BinConfig.APIRoutePrefix))
+
+		if localAuth, ok := authenticator.(*auth.LocalAuthenticator); ok {
+			router.Route("/auth", func(router chi.Router) {
+				router.Use(
+					cors.Handler(
+						cors.Options{
+							AllowedOrigins: []string{"*"},
+							AllowedMethods: []string{"GET", "POST", "DELETE", "OPTIONS"},
+							AllowedHeaders: []string{"*"},
+							MaxAge:         300,
+						},
+					),
+				)
+				router.Use(SetContentType)
+
+				router.Post("/login", loginHandler(localAuth))
+				if localAuth.AllowRegistration() {
+					router.Post("/register", registerHandler(localAuth))
+				}
+
+				router.Group(func(router chi.Router) {
+					router.Use(AuthenticateMiddleware(authenticator, roles))
+
+					router.Get("/tokens", getAPITokensHandler(localAuth))
+					router.Post("/tokens", createAPITokenHandler(localAuth))
+					router.Delete("/tokens/{tokenUUID}", deleteAPITokenHandler(localAuth))
+				})
+			})
+		}
+
 		router.Route("/websites", func(router chi.Router) {
 			router.Use(
 				cors.Handler(
@@ -42,23 +69,95 @@ func AddRoutes(router chi.Router, r repository.Repostory, conf *config.APIConfig
 					},
 				),
 			)
-			router.Use(AuthenticateMiddleware(&conf.UserServiceConfig))
+			router.Use(AuthenticateMiddleware(authenticator, roles))
 			router.Use(SetContentType)
 
 			router.Route("/groups", func(router chi.Router) {
 				router.Get("/", getAllWebsiteGroupsHandler(r))
 				router.Get("/{groupName}", getWebsiteGroupHandler(r))
+				router.With(RejectSubscribedGroup(r), GroupNameParams).Put("/{groupName}", renameWebsiteGroupHandler(r, conf.BinConfig.StrictGroupName))
+				router.With(RejectSubscribedGroup(r), GroupNameParams).Post("/{groupName}/merge", mergeWebsiteGroupHandler(r, conf.BinConfig.StrictGroupName))
+				router.With(RejectSubscribedGroup(r), GroupNameParams, WebsiteUUIDsParams).Post("/{groupName}/split", splitWebsiteGroupHandler(r, conf.BinConfig.StrictGroupName))
+				router.With(WebsiteUUIDsParams).Put("/{groupName}/websites", moveWebsitesHandler(r, conf.BinConfig.StrictGroupName))
+				router.With(ShareModeParams).Post("/{groupName}/share", shareWebsiteGroupHandler(r))
+				router.Delete("/{groupName}/share", unshareWebsiteGroupHandler(r))
+				router.Get("/{groupName}/public-link", getGroupPublicLinkHandler(r))
+				router.Post("/{groupName}/public-link", createGroupPublicLinkHandler(r))
+				router.Delete("/{groupName}/public-link", deleteGroupPublicLinkHandler(r))
 			})
 
-			router.With(WebsiteParams).Post("/", createWebsiteHandler(r, &conf.WebsiteConfig))
+			router.Route("/shares", func(router chi.Router) {
+				router.Get("/", getGroupSharesHandler(r))
+				router.With(GroupNameParams).Post("/{shareUUID}/subscription", subscribeGroupShareHandler(r))
+				router.Delete("/{shareUUID}/subscription", unsubscribeGroupShareHandler(r))
+				router.With(WebsiteParams(&conf.WebsiteConfig)).Post("/{shareUUID}/websites", addSharedWebsiteHandler(r, &conf.WebsiteConfig))
+				router.Delete("/{shareUUID}/websites/{webUUID}", removeSharedWebsiteHandler(r))
+			})
+
+			router.Get("/group-order", getGroupOrderHandler(r))
+			router.With(GroupOrderParams).Put("/group-order", updateGroupOrderHandler(r))
+
+			router.Route("/tags", func(router chi.Router) {
+				router.Get("/", getTagsHandler(r))
+				router.With(TagParams).Put("/{tag}", renameTagHandler(r))
+				router.Delete("/{tag}", deleteTagHandler(r))
+			})
+
+			router.With(WebsiteParams(&conf.WebsiteConfig)).Post("/", createWebsiteHandler(r, &conf.WebsiteConfig, pubsub))
+			router.Get("/events", websiteEventsHandler(r, pubsub))
+			router.Post("/import", importWebsitesHandler(r, &conf.WebsiteConfig))
+			router.Post("/import/bookmarks", importBookmarksHandler(r, &conf.WebsiteConfig, importJobs))
+			router.Get("/import/jobs/{jobID}", getImportJobHandler(importJobs))
+			router.Get("/export", exportWebsitesHandler(r))
 
 			router.With(QueryWebsite(r)).Route("/{webUUID}", func(router chi.Router) {
 				router.Get("/", getWebsiteHandler(r))
-				router.Delete("/", deleteWebsiteHandler(r))
+				router.Get("/diff", getWebsiteDiffHandler(r))
+				router.With(RejectSyncedWebsite(r)).Delete("/", deleteWebsiteHandler(r, pubsub))
 				router.Put("/refresh", refreshWebsiteHandler(r))
-				router.With(GroupNameParams).Put("/change-group", changeWebsiteGroupHandler(r))
+				router.With(ItemParams).Put("/read", readWebsiteHandler(r))
+				router.With(SnoozeParams).Put("/snooze", snoozeWebsiteHandler(r))
+				router.With(MuteParams).Put("/mute", muteWebsiteHandler(r))
+				router.With(RejectSyncedWebsite(r), GroupNameParams).Put("/change-group", changeWebsiteGroupHandler(r, conf.BinConfig.StrictGroupName))
+				router.With(TagParams).Post("/tags", addWebsiteTagHandler(r))
+				router.Delete("/tags/{tag}", removeWebsiteTagHandler(r))
 			})
 		})
-		router.Get("/db-stats", dbStatsHandler(r))
+		router.Route("/public", func(router chi.Router) {
+			router.Use(
+				cors.Handler(
+					cors.Options{
+						AllowedOrigins: []string{"*"},
+						AllowedMethods: []string{"GET", "OPTIONS"},
+						AllowedHeaders: []string{"*"},
+						MaxAge:         300,
+					},
+				),
+			)
+			router.Use(SetContentType)
+
+			router.Get("/groups/{token}", publicGroupHandler(r))
+		})
+		router.Route("/admin", func(router chi.Router) {
+			router.Use(
+				cors.Handler(
+					cors.Options{
+						AllowedOrigins: []string{"*"},
+						AllowedMethods: []string{"GET", "POST", "OPTIONS"},
+						AllowedHeaders: []string{"*"},
+						MaxAge:         300,
+					},
+				),
+			)
+			router.Use(AuthenticateMiddleware(authenticator, roles))
+			router.Use(RequireRole(auth.RoleAdmin))
+			router.Use(SetContentType)
+
+			router.Get("/websites", adminWebsitesHandler(r))
+			router.Post("/websites/{webUUID}/check", forceCheckWebsiteHandler(r))
+			router.Get("/workers", workerStatusHandler(r))
+			router.Get("/website-settings/broken", brokenWebsiteSettingsHandler(r))
+			router.Get("/db-stats", dbStatsHandler(r))
+		})
 	})
 }
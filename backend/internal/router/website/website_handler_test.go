@@ -1,10 +1,12 @@
 package website
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -15,16 +17,54 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
+	"github.com/htchan/WebHistory/internal/auth"
 	"github.com/htchan/WebHistory/internal/config"
+	"github.com/htchan/WebHistory/internal/events"
 	"github.com/htchan/WebHistory/internal/model"
 	"github.com/htchan/WebHistory/internal/repository"
+	"github.com/htchan/WebHistory/internal/watchlist"
 )
 
+func newTaggedRepo(webs []model.UserWebsite, tags []model.UserWebsiteTag) repository.Repostory {
+	r := repository.NewInMemRepo(nil, webs, nil, nil)
+	for i := range tags {
+		r.CreateUserWebsiteTag(&tags[i])
+	}
+
+	return r
+}
+
 func Test_getAllWebsiteGroupsHandler(t *testing.T) {
+	taggedWebs := []model.UserWebsite{
+		{
+			UserUUID:    "abc",
+			WebsiteUUID: "1",
+			GroupName:   "group 1",
+			AccessTime:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+			Website: model.Website{
+				UUID:       "1",
+				Title:      "title 1",
+				UpdateTime: time.Date(2000, 1, 1, 1, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			UserUUID:    "abc",
+			WebsiteUUID: "2",
+			GroupName:   "group 2",
+			AccessTime:  time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC),
+			Website: model.Website{
+				UUID:       "2",
+				Title:      "title 2",
+				UpdateTime: time.Date(2000, 1, 2, 1, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
 	tests := []struct {
 		name         string
 		r            repository.Repostory
 		userUUID     string
+		query        string
 		expectStatus int
 		expectRes    string
 	}{
@@ -67,14 +107,113 @@ func Test_getAllWebsiteGroupsHandler(t *testing.T) {
 			}, nil, nil),
 			userUUID:     "abc",
 			expectStatus: 200,
-			expectRes:    `{"website_groups":[[{"uuid":"1","user_uuid":"abc","url":"","title":"title 1","group_name":"group 1","update_time":"2000-01-01T01:00:00 UTC","access_time":"2000-01-01T00:00:00 UTC"},{"uuid":"2","user_uuid":"abc","url":"","title":"title 2","group_name":"group 1","update_time":"2000-01-02T01:00:00 UTC","access_time":"2000-01-02T00:00:00 UTC"}],[{"uuid":"3","user_uuid":"abc","url":"","title":"title 3","group_name":"group 3","update_time":"2000-01-03T01:00:00 UTC","access_time":"2000-01-03T00:00:00 UTC"}]]}`,
+			expectRes:    `{"unread_counts":{"group 1":0,"group 3":0},"website_groups":[[{"uuid":"3","user_uuid":"abc","url":"","title":"title 3","group_name":"group 3","update_time":"2000-01-03T01:00:00 UTC","access_time":"2000-01-03T00:00:00 UTC","unread_count":0}],[{"uuid":"2","user_uuid":"abc","url":"","title":"title 2","group_name":"group 1","update_time":"2000-01-02T01:00:00 UTC","access_time":"2000-01-02T00:00:00 UTC","unread_count":0},{"uuid":"1","user_uuid":"abc","url":"","title":"title 1","group_name":"group 1","update_time":"2000-01-01T01:00:00 UTC","access_time":"2000-01-01T00:00:00 UTC","unread_count":0}]]}`,
+		},
+		{
+			name: "filter user websites having all tags",
+			r: newTaggedRepo(taggedWebs, []model.UserWebsiteTag{
+				{UserUUID: "abc", WebsiteUUID: "1", Tag: "novel"},
+				{UserUUID: "abc", WebsiteUUID: "1", Tag: "daily"},
+				{UserUUID: "abc", WebsiteUUID: "2", Tag: "novel"},
+			}),
+			userUUID:     "abc",
+			query:        "?tag=novel&tag=daily",
+			expectStatus: 200,
+			expectRes:    `{"unread_counts":{"group 1":0},"website_groups":[[{"uuid":"1","user_uuid":"abc","url":"","title":"title 1","group_name":"group 1","update_time":"2000-01-01T01:00:00 UTC","access_time":"2000-01-01T00:00:00 UTC","tags":["novel","daily"],"unread_count":0}]]}`,
+		},
+		{
+			name: "filter user websites having any tags",
+			r: newTaggedRepo(taggedWebs, []model.UserWebsiteTag{
+				{UserUUID: "abc", WebsiteUUID: "1", Tag: "novel"},
+				{UserUUID: "abc", WebsiteUUID: "2", Tag: "daily"},
+			}),
+			userUUID:     "abc",
+			query:        "?tag=novel&tag=daily&tag_mode=or",
+			expectStatus: 200,
+			expectRes:    `{"unread_counts":{"group 1":0,"group 2":0},"website_groups":[[{"uuid":"2","user_uuid":"abc","url":"","title":"title 2","group_name":"group 2","update_time":"2000-01-02T01:00:00 UTC","access_time":"2000-01-02T00:00:00 UTC","tags":["daily"],"unread_count":0}],[{"uuid":"1","user_uuid":"abc","url":"","title":"title 1","group_name":"group 1","update_time":"2000-01-01T01:00:00 UTC","access_time":"2000-01-01T00:00:00 UTC","tags":["novel"],"unread_count":0}]]}`,
+		},
+		{
+			name:         "search user websites by title and sort by title",
+			r:            newTaggedRepo(taggedWebs, nil),
+			userUUID:     "abc",
+			query:        "?q=TITLE&sort=-title",
+			expectStatus: 200,
+			expectRes:    `{"unread_counts":{"group 1":0,"group 2":0},"website_groups":[[{"uuid":"2","user_uuid":"abc","url":"","title":"title 2","group_name":"group 2","update_time":"2000-01-02T01:00:00 UTC","access_time":"2000-01-02T00:00:00 UTC","unread_count":0}],[{"uuid":"1","user_uuid":"abc","url":"","title":"title 1","group_name":"group 1","update_time":"2000-01-01T01:00:00 UTC","access_time":"2000-01-01T00:00:00 UTC","unread_count":0}]]}`,
+		},
+		{
+			name:         "return first page with next cursor",
+			r:            newTaggedRepo(taggedWebs, nil),
+			userUUID:     "abc",
+			query:        "?sort=update_time&limit=1",
+			expectStatus: 200,
+			expectRes:    `{"next_cursor":"eyJncm91cCI6Imdyb3VwIDEiLCJwb3NpdGlvbiI6MH0","unread_counts":{"group 1":0},"website_groups":[[{"uuid":"1","user_uuid":"abc","url":"","title":"title 1","group_name":"group 1","update_time":"2000-01-01T01:00:00 UTC","access_time":"2000-01-01T00:00:00 UTC","unread_count":0}]]}`,
+		},
+		{
+			name:         "return page after cursor",
+			r:            newTaggedRepo(taggedWebs, nil),
+			userUUID:     "abc",
+			query:        "?sort=update_time&limit=1&cursor=eyJncm91cCI6Imdyb3VwIDEiLCJwb3NpdGlvbiI6MH0",
+			expectStatus: 200,
+			expectRes:    `{"unread_counts":{"group 2":0},"website_groups":[[{"uuid":"2","user_uuid":"abc","url":"","title":"title 2","group_name":"group 2","update_time":"2000-01-02T01:00:00 UTC","access_time":"2000-01-02T00:00:00 UTC","unread_count":0}]]}`,
+		},
+		{
+			name: "return whole group spanning page boundary",
+			r: newTaggedRepo(append([]model.UserWebsite{
+				{
+					UserUUID:    "abc",
+					WebsiteUUID: "3",
+					GroupName:   "group 1",
+					AccessTime:  time.Date(2000, 1, 3, 0, 0, 0, 0, time.UTC),
+					Website: model.Website{
+						UUID:       "3",
+						Title:      "title 3",
+						UpdateTime: time.Date(2000, 1, 3, 1, 0, 0, 0, time.UTC),
+					},
+				},
+			}, taggedWebs...), nil),
+			userUUID:     "abc",
+			query:        "?sort=update_time&limit=1",
+			expectStatus: 200,
+			expectRes:    `{"next_cursor":"eyJncm91cCI6Imdyb3VwIDEiLCJwb3NpdGlvbiI6MH0","unread_counts":{"group 1":0},"website_groups":[[{"uuid":"1","user_uuid":"abc","url":"","title":"title 1","group_name":"group 1","update_time":"2000-01-01T01:00:00 UTC","access_time":"2000-01-01T00:00:00 UTC","unread_count":0},{"uuid":"3","user_uuid":"abc","url":"","title":"title 3","group_name":"group 1","update_time":"2000-01-03T01:00:00 UTC","access_time":"2000-01-03T00:00:00 UTC","unread_count":0}]]}`,
+		},
+		{
+			name:         "return page after cursor of group missing from listing",
+			r:            newTaggedRepo(taggedWebs[1:], nil),
+			userUUID:     "abc",
+			query:        "?sort=update_time&limit=1&cursor=eyJncm91cCI6Imdyb3VwIDEiLCJwb3NpdGlvbiI6MH0",
+			expectStatus: 200,
+			expectRes:    `{"unread_counts":{"group 2":0},"website_groups":[[{"uuid":"2","user_uuid":"abc","url":"","title":"title 2","group_name":"group 2","update_time":"2000-01-02T01:00:00 UTC","access_time":"2000-01-02T00:00:00 UTC","unread_count":0}]]}`,
+		},
+		{
+			name:         "return error if sort key is invalid",
+			r:            newTaggedRepo(taggedWebs, nil),
+			userUUID:     "abc",
+			query:        "?sort=unknown",
+			expectStatus: 422,
+			expectRes:    `{"code":"invalid_params","error":"invalid params"}`,
+		},
+		{
+			name:         "return error if cursor is invalid",
+			r:            newTaggedRepo(taggedWebs, nil),
+			userUUID:     "abc",
+			query:        "?cursor=invalid",
+			expectStatus: 422,
+			expectRes:    `{"code":"invalid_params","error":"invalid params"}`,
+		},
+		{
+			name:         "return error if tag mode is invalid",
+			r:            newTaggedRepo(taggedWebs, nil),
+			userUUID:     "abc",
+			query:        "?tag=novel&tag_mode=xor",
+			expectStatus: 422,
+			expectRes:    `{"code":"invalid_params","error":"invalid params"}`,
 		},
 		{
 			name:         "return error if findUserWebsites return error",
 			r:            repository.NewInMemRepo(nil, nil, nil, errors.New("some error")),
 			userUUID:     "unknown",
-			expectStatus: 400,
-			expectRes:    `{ "error": "record not found" }`,
+			expectStatus: 500,
+			expectRes:    `{"code":"internal_error","error":"internal server error"}`,
 		},
 	}
 
@@ -82,7 +221,7 @@ func Test_getAllWebsiteGroupsHandler(t *testing.T) {
 		test := test
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
-			req, err := http.NewRequest("GET", "/websites/groups/", nil)
+			req, err := http.NewRequest("GET", "/websites/groups/"+test.query, nil)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -146,23 +285,23 @@ func Test_getWebsiteGroupHandler(t *testing.T) {
 			userUUID:     "abc",
 			group:        "group 1",
 			expectStatus: 200,
-			expectRes:    `{"website_group":[{"uuid":"1","user_uuid":"abc","url":"","title":"title 1","group_name":"group 1","update_time":"2000-01-01T01:00:00 UTC","access_time":"2000-01-01T00:00:00 UTC"},{"uuid":"2","user_uuid":"abc","url":"","title":"title 2","group_name":"group 1","update_time":"2000-01-02T01:00:00 UTC","access_time":"2000-01-02T00:00:00 UTC"}]}`,
+			expectRes:    `{"unread_count":0,"website_group":[{"uuid":"1","user_uuid":"abc","url":"","title":"title 1","group_name":"group 1","update_time":"2000-01-01T01:00:00 UTC","access_time":"2000-01-01T00:00:00 UTC","unread_count":0},{"uuid":"2","user_uuid":"abc","url":"","title":"title 2","group_name":"group 1","update_time":"2000-01-02T01:00:00 UTC","access_time":"2000-01-02T00:00:00 UTC","unread_count":0}]}`,
 		},
 		{
 			name:         "return error if user not exist",
 			r:            repository.NewInMemRepo(nil, nil, nil, errors.New("some error")),
 			userUUID:     "unknown",
 			group:        "group 1",
-			expectStatus: 400,
-			expectRes:    `{ "error": "record not found" }`,
+			expectStatus: 404,
+			expectRes:    `{"code":"not_found","error":"record not found"}`,
 		},
 		{
 			name:         "return error if group not exist",
 			r:            repository.NewInMemRepo(nil, nil, nil, errors.New("some error")),
 			userUUID:     "abc",
 			group:        "group not exist",
-			expectStatus: 400,
-			expectRes:    `{ "error": "record not found" }`,
+			expectStatus: 404,
+			expectRes:    `{"code":"not_found","error":"record not found"}`,
 		},
 	}
 
@@ -203,45 +342,78 @@ func Test_createWebsiteHandler(t *testing.T) {
 	uuid.SetRand(io.NopCloser(bytes.NewReader([]byte(
 		"000000000000000000000000000000000000000000000000000000000000000000000000000000",
 	))))
+	t.Cleanup(func() { uuid.SetRand(nil) })
 	tests := []struct {
-		name         string
-		r            repository.Repostory
-		conf         *config.WebsiteConfig
-		userUUID     string
-		url          string
-		expectStatus int
-		expectRes    string
-		expectRepo   repository.Repostory
+		name           string
+		r              repository.Repostory
+		conf           *config.WebsiteConfig
+		userUUID       string
+		url            string
+		expectStatus   int
+		expectRes      string
+		expectRepo     repository.Repostory
+		expectRequests []string
+		expectEvents   []events.Type
 	}{
 		{
-			name:         "get user websites of existing user and group",
+			name:         "create pending website and request update",
 			r:            repository.NewInMemRepo(nil, nil, nil, nil),
 			conf:         &config.WebsiteConfig{},
 			userUUID:     "abc",
 			url:          "https://example.com/",
-			expectStatus: 200,
-			expectRes:    `{"message":"website \u003c\u003e inserted"}`,
+			expectStatus: 202,
+			expectRes:    `{"message":"website \u003chttps://example.com/\u003e accepted","website_uuid":"30303030-3030-4030-b030-303030303030"}`,
 			expectRepo: repository.NewInMemRepo(
 				[]model.Website{
 					{
 						UUID: "30303030-3030-4030-b030-303030303030", URL: "https://example.com/",
-						UpdateTime: time.Now().UTC().Truncate(time.Second),
+						Health: model.WebsitePending,
 					},
 				},
 				[]model.UserWebsite{
 					{
 						WebsiteUUID: "30303030-3030-4030-b030-303030303030",
 						UserUUID:    "abc",
+						GroupName:   "https://example.com/",
 						AccessTime:  time.Now().UTC().Truncate(time.Second),
 						Website: model.Website{
-							UUID:       "30303030-3030-4030-b030-303030303030",
-							URL:        "https://example.com/",
-							UpdateTime: time.Now().UTC().Truncate(time.Second),
+							UUID:   "30303030-3030-4030-b030-303030303030",
+							URL:    "https://example.com/",
+							Health: model.WebsitePending,
 						},
 					},
 				},
 				nil, nil,
 			),
+			expectRequests: []string{"30303030-3030-4030-b030-303030303030"},
+			expectEvents:   []events.Type{events.WebsiteCreated},
+		},
+		{
+			name: "not request update for existing website",
+			r: repository.NewInMemRepo(
+				[]model.Website{{UUID: "existing", URL: "https://example.com/", Title: "title", Health: model.WebsiteHealthy}},
+				nil, nil, nil,
+			),
+			conf:         &config.WebsiteConfig{},
+			userUUID:     "abc",
+			url:          "https://example.com/",
+			expectStatus: 202,
+			expectRes:    `{"message":"website \u003chttps://example.com/\u003e accepted","website_uuid":"existing"}`,
+			expectRepo: repository.NewInMemRepo(
+				[]model.Website{{UUID: "existing", URL: "https://example.com/", Title: "title", Health: model.WebsiteHealthy}},
+				[]model.UserWebsite{
+					{
+						WebsiteUUID: "existing",
+						UserUUID:    "abc",
+						GroupName:   "title",
+						AccessTime:  time.Now().UTC().Truncate(time.Second),
+						Website:     model.Website{UUID: "existing", URL: "https://example.com/", Title: "title", Health: model.WebsiteHealthy},
+					},
+				},
+				nil, nil,
+			),
+			expectRequests: nil,
+			expectEvents:   []events.Type{events.WebsiteCreated},
 		},
 		{
 			name:         "return error if repo return error",
@@ -249,8 +421,8 @@ func Test_createWebsiteHandler(t *testing.T) {
 			conf:         &config.WebsiteConfig{},
 			userUUID:     "unknown",
 			url:          "https://example.com/",
-			expectStatus: 400,
-			expectRes:    `{ "error": "some error" }`,
+			expectStatus: 500,
+			expectRes:    `{"code":"internal_error","error":"internal server error"}`,
 			expectRepo:   repository.NewInMemRepo(nil, nil, nil, errors.New("some error")),
 		},
 	}
@@ -267,7 +439,11 @@ func Test_createWebsiteHandler(t *testing.T) {
 			ctx = context.WithValue(ctx, ContextKeyWebURL, test.url)
 			req = req.WithContext(ctx)
 			rr := httptest.NewRecorder()
-			createWebsiteHandler(test.r, test.conf).ServeHTTP(rr, req)
+			pubsub := events.NewInMemPubSub()
+			subCtx, cancel := context.WithCancel(context.Background())
+			eventChan, _ := pubsub.Subscribe(subCtx)
+			createWebsiteHandler(test.r, test.conf, pubsub).ServeHTTP(rr, req)
+			cancel()
 
 			if rr.Code != test.expectStatus {
 				t.Error("got different code as expect")
@@ -286,6 +462,26 @@ func Test_createWebsiteHandler(t *testing.T) {
 				t.Error(test.r)
 				t.Error(test.expectRepo)
 			}
+
+			reqs, _ := test.r.ClaimWebsiteUpdateRequests(10)
+			var requested []string
+			for _, req := range reqs {
+				requested = append(requested, req.WebsiteUUID)
+			}
+			if !cmp.Equal(requested, test.expectRequests) {
+				t.Errorf("got requests: %v; want: %v", requested, test.expectRequests)
+			}
+
+			var published []events.Type
+			for event := range eventChan {
+				if event.UserUUID != test.userUUID {
+					t.Errorf("got event of user: %v", event.UserUUID)
+				}
+				published = append(published, event.Type)
+			}
+			if !cmp.Equal(published, test.expectEvents) {
+				t.Errorf("got events: %v; want: %v", published, test.expectEvents)
+			}
 		})
 	}
 }
@@ -315,7 +511,7 @@ func Test_getWebsiteHandler(t *testing.T) {
 				},
 			},
 			expectStatus: 200,
-			expectRes:    `{"website":{"uuid":"web_uuid","user_uuid":"user_uuid","url":"http://example.com/","title":"title","group_name":"name","update_time":"2000-01-01T00:00:00 UTC","access_time":"2000-01-01T00:00:00 UTC"}}`,
+			expectRes:    `{"website":{"uuid":"web_uuid","user_uuid":"user_uuid","url":"http://example.com/","title":"title","group_name":"name","update_time":"2000-01-01T00:00:00 UTC","access_time":"2000-01-01T00:00:00 UTC","unread_count":0}}`,
 		},
 	}
 
@@ -349,6 +545,81 @@ func Test_getWebsiteHandler(t *testing.T) {
 	}
 }
 
+func Test_getWebsiteDiffHandler(t *testing.T) {
+	t.Parallel()
+	conf := &config.WebsiteConfig{Separator: "\n"}
+	userWeb := model.UserWebsite{
+		WebsiteUUID: "web_uuid",
+		UserUUID:    "user_uuid",
+		GroupName:   "name",
+		AccessTime:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		Website: model.Website{
+			UUID:       "web_uuid",
+			Title:      "title",
+			URL:        "http://example.com/",
+			UpdateTime: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	tests := []struct {
+		name         string
+		r            repository.Repostory
+		web          model.UserWebsite
+		expectStatus int
+		expectRes    string
+	}{
+		{
+			name: "return added and removed content",
+			r: repository.NewInMemRepo([]model.Website{
+				{
+					UUID:               "web_uuid",
+					RawContent:         "chapter 3\nchapter 2",
+					PreviousRawContent: "chapter 2\nchapter 1",
+					Conf:               conf,
+				},
+			}, nil, nil, nil),
+			web:          userWeb,
+			expectStatus: 200,
+			expectRes:    `{"diff":{"added":["chapter 3"],"removed":["chapter 1"]},"website":{"uuid":"web_uuid","user_uuid":"user_uuid","url":"http://example.com/","title":"title","group_name":"name","update_time":"2000-01-01T00:00:00 UTC","access_time":"2000-01-01T00:00:00 UTC","unread_count":0}}`,
+		},
+		{
+			name:         "return error if website not found",
+			r:            repository.NewInMemRepo(nil, nil, nil, nil),
+			web:          userWeb,
+			expectStatus: 404,
+			expectRes:    `{"code":"not_found","error":"record not found"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequest("GET", "/websites/{webUUID}/diff", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := req.Context()
+			ctx = context.WithValue(ctx, ContextKeyWebsite, test.web)
+			req = req.WithContext(ctx)
+			rr := httptest.NewRecorder()
+			getWebsiteDiffHandler(test.r).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Error("got different code as expect")
+				t.Error(rr.Code)
+				t.Error(test.expectStatus)
+			}
+
+			if strings.Trim(rr.Body.String(), "\n") != test.expectRes {
+				t.Error("got different response as expect")
+				t.Error(rr.Body.String())
+				t.Error(test.expectRes)
+			}
+		})
+	}
+}
+
 func Test_refreshWebsiteHandler(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -454,55 +725,150 @@ func Test_refreshWebsiteHandler(t *testing.T) {
 	}
 }
 
-func Test_deleteWebsiteHandler(t *testing.T) {
+func Test_readWebsiteHandler(t *testing.T) {
+	t.Parallel()
+	web := model.UserWebsite{
+		WebsiteUUID: "web_uuid",
+		UserUUID:    "user_uuid",
+		GroupName:   "name",
+		AccessTime:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		Website: model.Website{
+			UUID:       "web_uuid",
+			Title:      "title",
+			URL:        "http://example.com/",
+			UpdateTime: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+			Items: []model.WebsiteItem{
+				{Name: "chapter 2", Link: "http://example.com/2", FirstSeen: time.Date(2000, 1, 3, 0, 0, 0, 0, time.UTC)},
+				{Name: "chapter 1", Link: "http://example.com/1", FirstSeen: time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+
+	tests := []struct {
+		name               string
+		r                  repository.Repostory
+		web                model.UserWebsite
+		item               string
+		expectStatus       int
+		expectLastReadItem string
+		expectUnreadCount  int
+	}{
+		{
+			name:               "mark specific item read",
+			r:                  repository.NewInMemRepo(nil, []model.UserWebsite{web}, nil, nil),
+			web:                web,
+			item:               "http://example.com/1",
+			expectStatus:       200,
+			expectLastReadItem: "http://example.com/1",
+			expectUnreadCount:  1,
+		},
+		{
+			name:               "mark latest item read if item not specified",
+			r:                  repository.NewInMemRepo(nil, []model.UserWebsite{web}, nil, nil),
+			web:                web,
+			item:               "",
+			expectStatus:       200,
+			expectLastReadItem: "http://example.com/2",
+			expectUnreadCount:  0,
+		},
+		{
+			name:               "return error if item not exist",
+			r:                  repository.NewInMemRepo(nil, []model.UserWebsite{web}, nil, nil),
+			web:                web,
+			item:               "http://example.com/unknown",
+			expectStatus:       422,
+			expectLastReadItem: "",
+			expectUnreadCount:  2,
+		},
+		{
+			name:               "return error if update user website fail",
+			r:                  repository.NewInMemRepo(nil, []model.UserWebsite{web}, nil, errors.New("some error")),
+			web:                web,
+			item:               "http://example.com/1",
+			expectStatus:       500,
+			expectLastReadItem: "",
+			expectUnreadCount:  2,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequest("PUT", "/websites/{webUUID}/read", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := req.Context()
+			ctx = context.WithValue(ctx, ContextKeyWebsite, test.web)
+			ctx = context.WithValue(ctx, ContextKeyItem, test.item)
+			req = req.WithContext(ctx)
+			rr := httptest.NewRecorder()
+			readWebsiteHandler(test.r).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Error("got different code as expect")
+				t.Error(rr.Code)
+				t.Error(test.expectStatus)
+			}
+
+			if rr.Code != http.StatusOK {
+				return
+			}
+
+			userWeb, err := test.r.FindUserWebsite(test.web.UserUUID, test.web.WebsiteUUID)
+			if err != nil {
+				t.Fatalf("find user website fail: %v", err)
+			}
+			if userWeb.LastReadItem != test.expectLastReadItem {
+				t.Errorf("got last read item: %v; want: %v", userWeb.LastReadItem, test.expectLastReadItem)
+			}
+			if userWeb.UnreadCount() != test.expectUnreadCount {
+				t.Errorf("got unread count: %v; want: %v", userWeb.UnreadCount(), test.expectUnreadCount)
+			}
+		})
+	}
+}
+
+func Test_snoozeWebsiteHandler(t *testing.T) {
 	t.Parallel()
+	web := model.UserWebsite{
+		WebsiteUUID: "web_uuid",
+		UserUUID:    "user_uuid",
+		GroupName:   "name",
+		AccessTime:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		Website: model.Website{
+			UUID:       "web_uuid",
+			Title:      "title",
+			URL:        "http://example.com/",
+			UpdateTime: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
 	tests := []struct {
 		name         string
 		r            repository.Repostory
 		web          model.UserWebsite
-		expectRepo   repository.Repostory
+		until        time.Time
 		expectStatus int
-		expectResp   string
+		expectRes    string
 	}{
 		{
-			name: "return website of deleted content",
-			r: repository.NewInMemRepo(
-				nil,
-				[]model.UserWebsite{
-					{
-						WebsiteUUID: "web_uuid",
-						UserUUID:    "user_uuid",
-						GroupName:   "name",
-						AccessTime:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
-						Website: model.Website{
-							UUID:       "web_uuid",
-							Title:      "title",
-							URL:        "http://example.com/",
-							UpdateTime: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
-						},
-					},
-				},
-				nil, nil,
-			),
-			web: model.UserWebsite{
-				WebsiteUUID: "web_uuid",
-				UserUUID:    "user_uuid",
-				GroupName:   "name",
-				AccessTime:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
-				Website: model.Website{
-					UUID:       "web_uuid",
-					Title:      "title",
-					URL:        "http://example.com/",
-					UpdateTime: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
-				},
-			},
-			expectRepo: repository.NewInMemRepo(
-				nil,
-				nil,
-				nil, nil,
-			),
+			name:         "snooze website until specific time",
+			r:            repository.NewInMemRepo(nil, []model.UserWebsite{web}, nil, nil),
+			web:          web,
+			until:        time.Date(2000, 1, 8, 0, 0, 0, 0, time.UTC),
 			expectStatus: 200,
-			expectResp:   `{"message":"website \u003ctitle\u003e deleted"}`,
+			expectRes:    `{"website":{"uuid":"web_uuid","user_uuid":"user_uuid","url":"http://example.com/","title":"title","group_name":"name","update_time":"2000-01-01T00:00:00 UTC","access_time":"2000-01-01T00:00:00 UTC","snooze_until":"2000-01-08T00:00:00 UTC","unread_count":0}}`,
+		},
+		{
+			name:         "return error if update user website fail",
+			r:            repository.NewInMemRepo(nil, []model.UserWebsite{web}, nil, errors.New("some error")),
+			web:          web,
+			until:        time.Date(2000, 1, 8, 0, 0, 0, 0, time.UTC),
+			expectStatus: 500,
+			expectRes:    `{"code":"internal_error","error":"internal server error"}`,
 		},
 	}
 
@@ -511,15 +877,16 @@ func Test_deleteWebsiteHandler(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
 
-			req, err := http.NewRequest("GET", "/websites/{webUUID}/refresh", nil)
+			req, err := http.NewRequest("PUT", "/websites/{webUUID}/snooze", nil)
 			if err != nil {
 				t.Fatal(err)
 			}
 			ctx := req.Context()
 			ctx = context.WithValue(ctx, ContextKeyWebsite, test.web)
+			ctx = context.WithValue(ctx, ContextKeySnooze, test.until)
 			req = req.WithContext(ctx)
 			rr := httptest.NewRecorder()
-			deleteWebsiteHandler(test.r).ServeHTTP(rr, req)
+			snoozeWebsiteHandler(test.r).ServeHTTP(rr, req)
 
 			if rr.Code != test.expectStatus {
 				t.Error("got different code as expect")
@@ -527,37 +894,199 @@ func Test_deleteWebsiteHandler(t *testing.T) {
 				t.Error(test.expectStatus)
 			}
 
-			if strings.Trim(rr.Body.String(), "\n") != test.expectResp {
+			if strings.Trim(rr.Body.String(), "\n") != test.expectRes {
 				t.Error("got different response as expect")
 				t.Error(rr.Body.String())
-				t.Error(test.expectResp)
-			}
-
-			if !cmp.Equal(test.r, test.expectRepo) {
-				t.Error("got different repo as expect")
-				t.Error(test.r)
-				t.Error(test.expectRepo)
+				t.Error(test.expectRes)
 			}
 		})
 	}
 }
 
-func Test_changeWebsiteGroupHandler(t *testing.T) {
+func Test_muteWebsiteHandler(t *testing.T) {
 	t.Parallel()
+	web := model.UserWebsite{
+		WebsiteUUID: "web_uuid",
+		UserUUID:    "user_uuid",
+		GroupName:   "name",
+		AccessTime:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		Website: model.Website{
+			UUID:       "web_uuid",
+			Title:      "title",
+			URL:        "http://example.com/",
+			UpdateTime: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
 	tests := []struct {
 		name         string
 		r            repository.Repostory
 		web          model.UserWebsite
-		group        string
-		expectRepo   repository.Repostory
+		muted        bool
 		expectStatus int
-		expectResp   string
+		expectRes    string
 	}{
 		{
-			name: "return website of deleted content",
-			r: repository.NewInMemRepo(
-				nil,
-				[]model.UserWebsite{
+			name:         "mute website",
+			r:            repository.NewInMemRepo(nil, []model.UserWebsite{web}, nil, nil),
+			web:          web,
+			muted:        true,
+			expectStatus: 200,
+			expectRes:    `{"website":{"uuid":"web_uuid","user_uuid":"user_uuid","url":"http://example.com/","title":"title","group_name":"name","update_time":"2000-01-01T00:00:00 UTC","access_time":"2000-01-01T00:00:00 UTC","muted":true,"unread_count":0}}`,
+		},
+		{
+			name:         "unmute website",
+			r:            repository.NewInMemRepo(nil, []model.UserWebsite{web}, nil, nil),
+			web:          web,
+			muted:        false,
+			expectStatus: 200,
+			expectRes:    `{"website":{"uuid":"web_uuid","user_uuid":"user_uuid","url":"http://example.com/","title":"title","group_name":"name","update_time":"2000-01-01T00:00:00 UTC","access_time":"2000-01-01T00:00:00 UTC","unread_count":0}}`,
+		},
+		{
+			name:         "return error if update user website fail",
+			r:            repository.NewInMemRepo(nil, []model.UserWebsite{web}, nil, errors.New("some error")),
+			web:          web,
+			muted:        true,
+			expectStatus: 500,
+			expectRes:    `{"code":"internal_error","error":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequest("PUT", "/websites/{webUUID}/mute", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := req.Context()
+			ctx = context.WithValue(ctx, ContextKeyWebsite, test.web)
+			ctx = context.WithValue(ctx, ContextKeyMuted, test.muted)
+			req = req.WithContext(ctx)
+			rr := httptest.NewRecorder()
+			muteWebsiteHandler(test.r).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Error("got different code as expect")
+				t.Error(rr.Code)
+				t.Error(test.expectStatus)
+			}
+
+			if strings.Trim(rr.Body.String(), "\n") != test.expectRes {
+				t.Error("got different response as expect")
+				t.Error(rr.Body.String())
+				t.Error(test.expectRes)
+			}
+		})
+	}
+}
+
+func Test_deleteWebsiteHandler(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		r            repository.Repostory
+		web          model.UserWebsite
+		expectRepo   repository.Repostory
+		expectStatus int
+		expectResp   string
+	}{
+		{
+			name: "return website of deleted content",
+			r: repository.NewInMemRepo(
+				nil,
+				[]model.UserWebsite{
+					{
+						WebsiteUUID: "web_uuid",
+						UserUUID:    "user_uuid",
+						GroupName:   "name",
+						AccessTime:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+						Website: model.Website{
+							UUID:       "web_uuid",
+							Title:      "title",
+							URL:        "http://example.com/",
+							UpdateTime: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+						},
+					},
+				},
+				nil, nil,
+			),
+			web: model.UserWebsite{
+				WebsiteUUID: "web_uuid",
+				UserUUID:    "user_uuid",
+				GroupName:   "name",
+				AccessTime:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+				Website: model.Website{
+					UUID:       "web_uuid",
+					Title:      "title",
+					URL:        "http://example.com/",
+					UpdateTime: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			expectRepo: repository.NewInMemRepo(
+				nil,
+				nil,
+				nil, nil,
+			),
+			expectStatus: 200,
+			expectResp:   `{"message":"website \u003ctitle\u003e deleted"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequest("GET", "/websites/{webUUID}/refresh", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := req.Context()
+			ctx = context.WithValue(ctx, ContextKeyWebsite, test.web)
+			req = req.WithContext(ctx)
+			rr := httptest.NewRecorder()
+			deleteWebsiteHandler(test.r, events.NewInMemPubSub()).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Error("got different code as expect")
+				t.Error(rr.Code)
+				t.Error(test.expectStatus)
+			}
+
+			if strings.Trim(rr.Body.String(), "\n") != test.expectResp {
+				t.Error("got different response as expect")
+				t.Error(rr.Body.String())
+				t.Error(test.expectResp)
+			}
+
+			if !cmp.Equal(test.r, test.expectRepo) {
+				t.Error("got different repo as expect")
+				t.Error(test.r)
+				t.Error(test.expectRepo)
+			}
+		})
+	}
+}
+
+func Test_changeWebsiteGroupHandler(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		r            repository.Repostory
+		web          model.UserWebsite
+		group        string
+		expectRepo   repository.Repostory
+		expectStatus int
+		expectResp   string
+	}{
+		{
+			name: "return website of deleted content",
+			r: repository.NewInMemRepo(
+				nil,
+				[]model.UserWebsite{
 					{
 						WebsiteUUID: "web_uuid",
 						UserUUID:    "user_uuid",
@@ -605,7 +1134,7 @@ func Test_changeWebsiteGroupHandler(t *testing.T) {
 				nil, nil,
 			),
 			expectStatus: 200,
-			expectResp:   `{"website":{"uuid":"web_uuid","user_uuid":"user_uuid","url":"http://example.com/","title":"title","group_name":"group_name","update_time":"2000-01-01T00:00:00 UTC","access_time":"2000-01-01T00:00:00 UTC"}}`,
+			expectResp:   `{"website":{"uuid":"web_uuid","user_uuid":"user_uuid","url":"http://example.com/","title":"title","group_name":"group_name","update_time":"2000-01-01T00:00:00 UTC","access_time":"2000-01-01T00:00:00 UTC","unread_count":0}}`,
 		},
 	}
 
@@ -623,7 +1152,7 @@ func Test_changeWebsiteGroupHandler(t *testing.T) {
 			ctx = context.WithValue(ctx, ContextKeyGroup, test.group)
 			req = req.WithContext(ctx)
 			rr := httptest.NewRecorder()
-			changeWebsiteGroupHandler(test.r).ServeHTTP(rr, req)
+			changeWebsiteGroupHandler(test.r, true).ServeHTTP(rr, req)
 
 			if rr.Code != test.expectStatus {
 				t.Error("got different code as expect")
@@ -645,3 +1174,2329 @@ func Test_changeWebsiteGroupHandler(t *testing.T) {
 		})
 	}
 }
+
+func Test_adminWebsitesHandler(t *testing.T) {
+	t.Parallel()
+	webs := []model.Website{
+		{UUID: "1", URL: "http://example.com/1", Title: "one", UpdateTime: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), Health: model.WebsiteHealthy},
+		{UUID: "2", URL: "http://example.com/2", Title: "two", UpdateTime: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	tests := []struct {
+		name         string
+		r            repository.Repostory
+		expectStatus int
+		expectRes    string
+	}{
+		{
+			name: "list websites with subscriber count",
+			r: repository.NewInMemRepo(webs, []model.UserWebsite{
+				{UserUUID: "abc", WebsiteUUID: "1"},
+				{UserUUID: "def", WebsiteUUID: "1"},
+			}, nil, nil),
+			expectStatus: 200,
+			expectRes: `{"websites":[` +
+				`{"health":"healthy","subscriber_count":2,"website":{"uuid":"1","url":"http://example.com/1","title":"one","update_time":"2000-01-01T00:00:00 UTC"}},` +
+				`{"health":"","subscriber_count":0,"website":{"uuid":"2","url":"http://example.com/2","title":"two","update_time":"2000-01-01T00:00:00 UTC"}}]}`,
+		},
+		{
+			name:         "return empty list if no website",
+			r:            repository.NewInMemRepo(nil, nil, nil, nil),
+			expectStatus: 200,
+			expectRes:    `{"websites":[]}`,
+		},
+		{
+			name:         "return error if find websites return error",
+			r:            repository.NewInMemRepo(nil, nil, nil, errors.New("some error")),
+			expectStatus: 500,
+			expectRes:    `{"code":"internal_error","error":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest("GET", "/admin/websites", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+			adminWebsitesHandler(test.r).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Errorf("got code: %v; want: %v", rr.Code, test.expectStatus)
+			}
+
+			if strings.Trim(rr.Body.String(), "\n") != test.expectRes {
+				t.Errorf("got res: %v; want: %v", rr.Body.String(), test.expectRes)
+			}
+		})
+	}
+}
+
+func Test_forceCheckWebsiteHandler(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		r             repository.Repostory
+		webUUID       string
+		expectStatus  int
+		expectRes     string
+		expectPending int
+	}{
+		{
+			name:          "queue website for check",
+			r:             repository.NewInMemRepo([]model.Website{{UUID: "1", URL: "http://example.com/"}}, nil, nil, nil),
+			webUUID:       "1",
+			expectStatus:  202,
+			expectRes:     `{"message":"website \u003chttp://example.com/\u003e queued for check"}`,
+			expectPending: 1,
+		},
+		{
+			name:         "return not found for unknown website",
+			r:            repository.NewInMemRepo(nil, nil, nil, nil),
+			webUUID:      "unknown",
+			expectStatus: 404,
+			expectRes:    `{"code":"not_found","error":"record not found"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest("POST", "/admin/websites/{webUUID}/check", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("webUUID", test.webUUID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rr := httptest.NewRecorder()
+			forceCheckWebsiteHandler(test.r).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Errorf("got code: %v; want: %v", rr.Code, test.expectStatus)
+			}
+
+			if strings.Trim(rr.Body.String(), "\n") != test.expectRes {
+				t.Errorf("got res: %v; want: %v", rr.Body.String(), test.expectRes)
+			}
+
+			if pending, _ := test.r.CountWebsiteUpdateRequests(); pending != test.expectPending {
+				t.Errorf("got pending requests: %v; want: %v", pending, test.expectPending)
+			}
+		})
+	}
+}
+
+func Test_workerStatusHandler(t *testing.T) {
+	t.Parallel()
+	now := time.Now().UTC().Truncate(time.Second)
+	startTime := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := repository.NewInMemRepo([]model.Website{{UUID: "1"}}, nil, nil, nil)
+	r.UpsertWorkerStatus(&model.WorkerStatus{
+		WorkerID: "alive", ExecutorCount: 3, BusyExecutors: 1, QueueDepth: 2,
+		StartTime: startTime, HeartbeatTime: now,
+	})
+	r.UpsertWorkerStatus(&model.WorkerStatus{
+		WorkerID: "gone", ExecutorCount: 3, StartTime: startTime, HeartbeatTime: startTime,
+	})
+	updateReq := model.NewWebsiteUpdateRequest("1", nil)
+	r.CreateWebsiteUpdateRequest(&updateReq)
+
+	tests := []struct {
+		name         string
+		r            repository.Repostory
+		expectStatus int
+		expectRes    string
+	}{
+		{
+			name:         "list worker statuses",
+			r:            r,
+			expectStatus: 200,
+			expectRes: fmt.Sprintf(`{"pending_update_requests":1,"workers":[`+
+				`{"alive":true,"busy_executors":1,"executor_count":3,"heartbeat_time":"%v","queue_depth":2,"start_time":"2000-01-01T00:00:00Z","worker_id":"alive"},`+
+				`{"alive":false,"busy_executors":0,"executor_count":3,"heartbeat_time":"2000-01-01T00:00:00Z","queue_depth":0,"start_time":"2000-01-01T00:00:00Z","worker_id":"gone"}]}`,
+				now.Format(time.RFC3339)),
+		},
+		{
+			name:         "return error if find worker statuses return error",
+			r:            repository.NewInMemRepo(nil, nil, nil, errors.New("some error")),
+			expectStatus: 500,
+			expectRes:    `{"code":"internal_error","error":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest("GET", "/admin/workers", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+			workerStatusHandler(test.r).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Errorf("got code: %v; want: %v", rr.Code, test.expectStatus)
+			}
+
+			if strings.Trim(rr.Body.String(), "\n") != test.expectRes {
+				t.Errorf("got res: %v; want: %v", rr.Body.String(), test.expectRes)
+			}
+		})
+	}
+}
+
+func Test_brokenWebsiteSettingsHandler(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		r            repository.Repostory
+		expectStatus int
+		expectRes    string
+	}{
+		{
+			name: "list broken website settings only",
+			r: repository.NewInMemRepo(nil, nil, []model.WebsiteSetting{
+				{Domain: "healthy.com", TitleGoquerySelector: "title"},
+				{
+					Domain:               "broken.com",
+					TitleGoquerySelector: "title",
+					ConsecutiveFailures:  3,
+					BrokenAt:             time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+			}, nil),
+			expectStatus: 200,
+			expectRes:    `{"website_settings":[{"domain":"broken.com","title_goquery_selector":"title","dates_goquery_selector":"","focus_index_from":0,"focus_index_to":0,"consecutive_failures":3,"broken_at":"2000-01-01T00:00:00Z"}]}`,
+		},
+		{
+			name:         "return empty list if no setting broken",
+			r:            repository.NewInMemRepo(nil, nil, []model.WebsiteSetting{{Domain: "healthy.com"}}, nil),
+			expectStatus: 200,
+			expectRes:    `{"website_settings":[]}`,
+		},
+		{
+			name:         "return error if find website settings return error",
+			r:            repository.NewInMemRepo(nil, nil, nil, errors.New("some error")),
+			expectStatus: 500,
+			expectRes:    `{"code":"internal_error","error":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest("GET", "/admin/website-settings/broken", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+			brokenWebsiteSettingsHandler(test.r).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Error("got different code as expect")
+				t.Error(rr.Code)
+				t.Error(test.expectStatus)
+			}
+
+			if strings.Trim(rr.Body.String(), "\n") != test.expectRes {
+				t.Error("got different response as expect")
+				t.Error(rr.Body.String())
+				t.Error(test.expectRes)
+			}
+		})
+	}
+}
+
+func Test_addWebsiteTagHandler(t *testing.T) {
+	t.Parallel()
+	web := model.UserWebsite{
+		WebsiteUUID: "web_uuid",
+		UserUUID:    "user_uuid",
+		GroupName:   "name",
+		AccessTime:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		Tags:        []string{"novel"},
+		Website: model.Website{
+			UUID:       "web_uuid",
+			Title:      "title",
+			URL:        "http://example.com/",
+			UpdateTime: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	tests := []struct {
+		name         string
+		r            repository.Repostory
+		web          model.UserWebsite
+		tag          string
+		expectStatus int
+		expectRes    string
+		expectTags   []string
+	}{
+		{
+			name:         "add tag to website",
+			r:            newTaggedRepo([]model.UserWebsite{web}, []model.UserWebsiteTag{{UserUUID: "user_uuid", WebsiteUUID: "web_uuid", Tag: "novel"}}),
+			web:          web,
+			tag:          "daily",
+			expectStatus: 200,
+			expectRes:    `{"website":{"uuid":"web_uuid","user_uuid":"user_uuid","url":"http://example.com/","title":"title","group_name":"name","update_time":"2000-01-01T00:00:00 UTC","access_time":"2000-01-01T00:00:00 UTC","tags":["novel","daily"],"unread_count":0}}`,
+			expectTags:   []string{"daily", "novel"},
+		},
+		{
+			name:         "add existing tag to website",
+			r:            newTaggedRepo([]model.UserWebsite{web}, []model.UserWebsiteTag{{UserUUID: "user_uuid", WebsiteUUID: "web_uuid", Tag: "novel"}}),
+			web:          web,
+			tag:          "novel",
+			expectStatus: 200,
+			expectRes:    `{"website":{"uuid":"web_uuid","user_uuid":"user_uuid","url":"http://example.com/","title":"title","group_name":"name","update_time":"2000-01-01T00:00:00 UTC","access_time":"2000-01-01T00:00:00 UTC","tags":["novel"],"unread_count":0}}`,
+			expectTags:   []string{"novel"},
+		},
+		{
+			name:         "return error if create tag fail",
+			r:            repository.NewInMemRepo(nil, []model.UserWebsite{web}, nil, errors.New("some error")),
+			web:          web,
+			tag:          "daily",
+			expectStatus: 500,
+			expectRes:    `{"code":"internal_error","error":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequest("POST", "/websites/{webUUID}/tags", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := req.Context()
+			ctx = context.WithValue(ctx, ContextKeyWebsite, test.web)
+			ctx = context.WithValue(ctx, ContextKeyTag, test.tag)
+			req = req.WithContext(ctx)
+			rr := httptest.NewRecorder()
+			addWebsiteTagHandler(test.r).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Error("got different code as expect")
+				t.Error(rr.Code)
+				t.Error(test.expectStatus)
+			}
+
+			if strings.Trim(rr.Body.String(), "\n") != test.expectRes {
+				t.Error("got different response as expect")
+				t.Error(rr.Body.String())
+				t.Error(test.expectRes)
+			}
+
+			if test.expectStatus == 200 {
+				tags, _ := test.r.FindUserTags(test.web.UserUUID)
+				if !cmp.Equal(tags, test.expectTags) {
+					t.Errorf("got tags: %v; want: %v", tags, test.expectTags)
+				}
+			}
+		})
+	}
+}
+
+func Test_removeWebsiteTagHandler(t *testing.T) {
+	t.Parallel()
+	web := model.UserWebsite{
+		WebsiteUUID: "web_uuid",
+		UserUUID:    "user_uuid",
+		GroupName:   "name",
+		AccessTime:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		Tags:        []string{"novel", "daily"},
+		Website: model.Website{
+			UUID:       "web_uuid",
+			Title:      "title",
+			URL:        "http://example.com/",
+			UpdateTime: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	tests := []struct {
+		name         string
+		r            repository.Repostory
+		web          model.UserWebsite
+		tag          string
+		expectStatus int
+		expectRes    string
+	}{
+		{
+			name: "remove tag from website",
+			r: newTaggedRepo([]model.UserWebsite{web}, []model.UserWebsiteTag{
+				{UserUUID: "user_uuid", WebsiteUUID: "web_uuid", Tag: "novel"},
+				{UserUUID: "user_uuid", WebsiteUUID: "web_uuid", Tag: "daily"},
+			}),
+			web:          web,
+			tag:          "novel",
+			expectStatus: 200,
+			expectRes:    `{"website":{"uuid":"web_uuid","user_uuid":"user_uuid","url":"http://example.com/","title":"title","group_name":"name","update_time":"2000-01-01T00:00:00 UTC","access_time":"2000-01-01T00:00:00 UTC","tags":["daily"],"unread_count":0}}`,
+		},
+		{
+			name:         "return error if delete tag fail",
+			r:            repository.NewInMemRepo(nil, []model.UserWebsite{web}, nil, errors.New("some error")),
+			web:          web,
+			tag:          "novel",
+			expectStatus: 500,
+			expectRes:    `{"code":"internal_error","error":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequest("DELETE", "/websites/{webUUID}/tags/{tag}", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("tag", test.tag)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, ContextKeyWebsite, test.web)
+			req = req.WithContext(ctx)
+			rr := httptest.NewRecorder()
+			removeWebsiteTagHandler(test.r).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Error("got different code as expect")
+				t.Error(rr.Code)
+				t.Error(test.expectStatus)
+			}
+
+			if strings.Trim(rr.Body.String(), "\n") != test.expectRes {
+				t.Error("got different response as expect")
+				t.Error(rr.Body.String())
+				t.Error(test.expectRes)
+			}
+		})
+	}
+}
+
+func Test_getTagsHandler(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		r            repository.Repostory
+		userUUID     string
+		expectStatus int
+		expectRes    string
+	}{
+		{
+			name: "list distinct tags of user",
+			r: newTaggedRepo(nil, []model.UserWebsiteTag{
+				{UserUUID: "abc", WebsiteUUID: "1", Tag: "novel"},
+				{UserUUID: "abc", WebsiteUUID: "2", Tag: "novel"},
+				{UserUUID: "abc", WebsiteUUID: "2", Tag: "daily"},
+				{UserUUID: "def", WebsiteUUID: "2", Tag: "other"},
+			}),
+			userUUID:     "abc",
+			expectStatus: 200,
+			expectRes:    `{"tags":["daily","novel"]}`,
+		},
+		{
+			name:         "return empty list if user has no tag",
+			r:            newTaggedRepo(nil, nil),
+			userUUID:     "abc",
+			expectStatus: 200,
+			expectRes:    `{"tags":[]}`,
+		},
+		{
+			name:         "return error if find tags fail",
+			r:            repository.NewInMemRepo(nil, nil, nil, errors.New("some error")),
+			userUUID:     "abc",
+			expectStatus: 500,
+			expectRes:    `{"code":"internal_error","error":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest("GET", "/websites/tags/", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.WithValue(req.Context(), ContextKeyUserUUID, test.userUUID)
+			req = req.WithContext(ctx)
+			rr := httptest.NewRecorder()
+			getTagsHandler(test.r).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Error("got different code as expect")
+				t.Error(rr.Code)
+				t.Error(test.expectStatus)
+			}
+
+			if strings.Trim(rr.Body.String(), "\n") != test.expectRes {
+				t.Error("got different response as expect")
+				t.Error(rr.Body.String())
+				t.Error(test.expectRes)
+			}
+		})
+	}
+}
+
+func Test_renameTagHandler(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		r            repository.Repostory
+		tag          string
+		newTag       string
+		expectStatus int
+		expectRes    string
+		expectTags   []string
+	}{
+		{
+			name: "rename tag of all websites",
+			r: newTaggedRepo(nil, []model.UserWebsiteTag{
+				{UserUUID: "abc", WebsiteUUID: "1", Tag: "novel"},
+				{UserUUID: "abc", WebsiteUUID: "2", Tag: "novel"},
+				{UserUUID: "abc", WebsiteUUID: "2", Tag: "book"},
+			}),
+			tag:          "novel",
+			newTag:       "book",
+			expectStatus: 200,
+			expectRes:    `{"message":"tag \u003cnovel\u003e renamed to \u003cbook\u003e"}`,
+			expectTags:   []string{"book"},
+		},
+		{
+			name:         "return error if rename tag fail",
+			r:            repository.NewInMemRepo(nil, nil, nil, errors.New("some error")),
+			tag:          "novel",
+			newTag:       "book",
+			expectStatus: 500,
+			expectRes:    `{"code":"internal_error","error":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest("PUT", "/websites/tags/{tag}", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("tag", test.tag)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, ContextKeyUserUUID, "abc")
+			ctx = context.WithValue(ctx, ContextKeyTag, test.newTag)
+			req = req.WithContext(ctx)
+			rr := httptest.NewRecorder()
+			renameTagHandler(test.r).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Error("got different code as expect")
+				t.Error(rr.Code)
+				t.Error(test.expectStatus)
+			}
+
+			if strings.Trim(rr.Body.String(), "\n") != test.expectRes {
+				t.Error("got different response as expect")
+				t.Error(rr.Body.String())
+				t.Error(test.expectRes)
+			}
+
+			if test.expectStatus == 200 {
+				tags, _ := test.r.FindUserTags("abc")
+				if !cmp.Equal(tags, test.expectTags) {
+					t.Errorf("got tags: %v; want: %v", tags, test.expectTags)
+				}
+			}
+		})
+	}
+}
+
+func Test_deleteTagHandler(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		r            repository.Repostory
+		tag          string
+		expectStatus int
+		expectRes    string
+		expectTags   []string
+	}{
+		{
+			name: "delete tag from all websites",
+			r: newTaggedRepo(nil, []model.UserWebsiteTag{
+				{UserUUID: "abc", WebsiteUUID: "1", Tag: "novel"},
+				{UserUUID: "abc", WebsiteUUID: "2", Tag: "novel"},
+				{UserUUID: "abc", WebsiteUUID: "2", Tag: "book"},
+			}),
+			tag:          "novel",
+			expectStatus: 200,
+			expectRes:    `{"message":"tag \u003cnovel\u003e deleted"}`,
+			expectTags:   []string{"book"},
+		},
+		{
+			name:         "return error if delete tag fail",
+			r:            repository.NewInMemRepo(nil, nil, nil, errors.New("some error")),
+			tag:          "novel",
+			expectStatus: 500,
+			expectRes:    `{"code":"internal_error","error":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest("DELETE", "/websites/tags/{tag}", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("tag", test.tag)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, ContextKeyUserUUID, "abc")
+			req = req.WithContext(ctx)
+			rr := httptest.NewRecorder()
+			deleteTagHandler(test.r).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Error("got different code as expect")
+				t.Error(rr.Code)
+				t.Error(test.expectStatus)
+			}
+
+			if strings.Trim(rr.Body.String(), "\n") != test.expectRes {
+				t.Error("got different response as expect")
+				t.Error(rr.Body.String())
+				t.Error(test.expectRes)
+			}
+
+			if test.expectStatus == 200 {
+				tags, _ := test.r.FindUserTags("abc")
+				if !cmp.Equal(tags, test.expectTags) {
+					t.Errorf("got tags: %v; want: %v", tags, test.expectTags)
+				}
+			}
+		})
+	}
+}
+
+func newGroupTestRepo() *repository.InMemRepo {
+	return repository.NewInMemRepo(nil, []model.UserWebsite{
+		{UserUUID: "abc", WebsiteUUID: "1", GroupName: "fruit", Website: model.Website{UUID: "1", Title: "apple"}},
+		{UserUUID: "abc", WebsiteUUID: "2", GroupName: "fruit", Website: model.Website{UUID: "2", Title: "banana"}},
+		{UserUUID: "abc", WebsiteUUID: "3", GroupName: "veg", Website: model.Website{UUID: "3", Title: "carrot"}},
+	}, nil, nil)
+}
+
+func userWebsiteGroups(t *testing.T, r repository.Repostory) map[string]string {
+	t.Helper()
+	webs, err := r.FindUserWebsites("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	groups := make(map[string]string)
+	for _, web := range webs {
+		groups[web.WebsiteUUID] = web.GroupName
+	}
+
+	return groups
+}
+
+func Test_groupManagementHandlers(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		r            repository.Repostory
+		handler      func(repository.Repostory, bool) http.HandlerFunc
+		strict       bool
+		groupName    string
+		newGroupName string
+		webUUIDs     []string
+		expectStatus int
+		expectRes    string
+		expectGroups map[string]string
+	}{
+		{
+			name:         "rename group",
+			r:            newGroupTestRepo(),
+			handler:      renameWebsiteGroupHandler,
+			groupName:    "veg",
+			newGroupName: " vegetable ",
+			expectStatus: 200,
+			expectRes:    `{"unread_count":0,"website_group":[{"uuid":"3","user_uuid":"abc","url":"","title":"carrot","group_name":"vegetable","update_time":"0001-01-01T00:00:00 UTC","access_time":"0001-01-01T00:00:00 UTC","unread_count":0}]}`,
+			expectGroups: map[string]string{"1": "fruit", "2": "fruit", "3": "vegetable"},
+		},
+		{
+			name:         "rename group fail if new name is used",
+			r:            newGroupTestRepo(),
+			handler:      renameWebsiteGroupHandler,
+			groupName:    "veg",
+			newGroupName: "fruit",
+			expectStatus: 409,
+			expectRes:    `{"code":"group_exists","error":"group already exists"}`,
+			expectGroups: map[string]string{"1": "fruit", "2": "fruit", "3": "veg"},
+		},
+		{
+			name:         "rename group fail if new name is invalid in strict mode",
+			r:            newGroupTestRepo(),
+			handler:      renameWebsiteGroupHandler,
+			strict:       true,
+			groupName:    "veg",
+			newGroupName: "xyz",
+			expectStatus: 422,
+			expectRes:    `{"code":"invalid_group_name","error":"invalid group name","field":"group_name"}`,
+			expectGroups: map[string]string{"1": "fruit", "2": "fruit", "3": "veg"},
+		},
+		{
+			name:         "rename group fail if group not exist",
+			r:            newGroupTestRepo(),
+			handler:      renameWebsiteGroupHandler,
+			groupName:    "unknown",
+			newGroupName: "new",
+			expectStatus: 404,
+			expectRes:    `{"code":"not_found","error":"record not found"}`,
+			expectGroups: map[string]string{"1": "fruit", "2": "fruit", "3": "veg"},
+		},
+		{
+			name:         "merge group into another group",
+			r:            newGroupTestRepo(),
+			handler:      mergeWebsiteGroupHandler,
+			groupName:    "veg",
+			newGroupName: "fruit",
+			expectStatus: 200,
+			expectGroups: map[string]string{"1": "fruit", "2": "fruit", "3": "fruit"},
+		},
+		{
+			name:         "merge group fail if target group not exist",
+			r:            newGroupTestRepo(),
+			handler:      mergeWebsiteGroupHandler,
+			groupName:    "veg",
+			newGroupName: "unknown",
+			expectStatus: 404,
+			expectRes:    `{"code":"not_found","error":"record not found"}`,
+			expectGroups: map[string]string{"1": "fruit", "2": "fruit", "3": "veg"},
+		},
+		{
+			name:         "split websites out of group",
+			r:            newGroupTestRepo(),
+			handler:      splitWebsiteGroupHandler,
+			groupName:    "fruit",
+			newGroupName: "yellow",
+			webUUIDs:     []string{"2"},
+			expectStatus: 200,
+			expectRes:    `{"unread_count":0,"website_group":[{"uuid":"2","user_uuid":"abc","url":"","title":"banana","group_name":"yellow","update_time":"0001-01-01T00:00:00 UTC","access_time":"0001-01-01T00:00:00 UTC","unread_count":0}]}`,
+			expectGroups: map[string]string{"1": "fruit", "2": "yellow", "3": "veg"},
+		},
+		{
+			name:         "split group fail if website not in group",
+			r:            newGroupTestRepo(),
+			handler:      splitWebsiteGroupHandler,
+			groupName:    "fruit",
+			newGroupName: "yellow",
+			webUUIDs:     []string{"3"},
+			expectStatus: 404,
+			expectRes:    `{"code":"not_found","error":"record not found"}`,
+			expectGroups: map[string]string{"1": "fruit", "2": "fruit", "3": "veg"},
+		},
+		{
+			name:         "move websites to group",
+			r:            newGroupTestRepo(),
+			handler:      moveWebsitesHandler,
+			groupName:    "food",
+			webUUIDs:     []string{"1", "3"},
+			expectStatus: 200,
+			expectGroups: map[string]string{"1": "food", "2": "fruit", "3": "food"},
+		},
+		{
+			name:         "move websites fail if group name invalid in strict mode",
+			r:            newGroupTestRepo(),
+			handler:      moveWebsitesHandler,
+			strict:       true,
+			groupName:    "p",
+			webUUIDs:     []string{"1", "3"},
+			expectStatus: 422,
+			expectRes:    `{"code":"invalid_group_name","error":"invalid group name","field":"group_name"}`,
+			expectGroups: map[string]string{"1": "fruit", "2": "fruit", "3": "veg"},
+		},
+		{
+			name:         "move websites fail if website not found",
+			r:            newGroupTestRepo(),
+			handler:      moveWebsitesHandler,
+			groupName:    "food",
+			webUUIDs:     []string{"1", "unknown"},
+			expectStatus: 404,
+			expectRes:    `{"code":"not_found","error":"record not found"}`,
+			expectGroups: map[string]string{"1": "fruit", "2": "fruit", "3": "veg"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest("PUT", "/websites/groups/{groupName}", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("groupName", test.groupName)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, ContextKeyUserUUID, "abc")
+			ctx = context.WithValue(ctx, ContextKeyGroup, test.newGroupName)
+			ctx = context.WithValue(ctx, ContextKeyWebsiteUUIDs, test.webUUIDs)
+			req = req.WithContext(ctx)
+			rr := httptest.NewRecorder()
+			test.handler(test.r, test.strict).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Error("got different code as expect")
+				t.Error(rr.Code)
+				t.Error(test.expectStatus)
+			}
+
+			if test.expectRes != "" && strings.Trim(rr.Body.String(), "\n") != test.expectRes {
+				t.Error("got different response as expect")
+				t.Error(rr.Body.String())
+				t.Error(test.expectRes)
+			}
+
+			if groups := userWebsiteGroups(t, test.r); !cmp.Equal(groups, test.expectGroups) {
+				t.Errorf("got groups: %v; want: %v", groups, test.expectGroups)
+			}
+		})
+	}
+}
+
+func Test_updateGroupOrderHandler(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		r            repository.Repostory
+		order        []string
+		expectStatus int
+		expectRes    string
+		expectOrder  []string
+	}{
+		{
+			name:         "update group order",
+			r:            newGroupTestRepo(),
+			order:        []string{"veg", "fruit"},
+			expectStatus: 200,
+			expectRes:    `{"group_order":["veg","fruit"]}`,
+			expectOrder:  []string{"veg", "fruit"},
+		},
+		{
+			name:         "return error if update group order fail",
+			r:            repository.NewInMemRepo(nil, nil, nil, errors.New("some error")),
+			order:        []string{"veg", "fruit"},
+			expectStatus: 500,
+			expectRes:    `{"code":"internal_error","error":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest("PUT", "/websites/group-order", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.WithValue(req.Context(), ContextKeyUserUUID, "abc")
+			ctx = context.WithValue(ctx, ContextKeyGroupOrder, test.order)
+			req = req.WithContext(ctx)
+			rr := httptest.NewRecorder()
+			updateGroupOrderHandler(test.r).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Error("got different code as expect")
+				t.Error(rr.Code)
+				t.Error(test.expectStatus)
+			}
+
+			if strings.Trim(rr.Body.String(), "\n") != test.expectRes {
+				t.Error("got different response as expect")
+				t.Error(rr.Body.String())
+				t.Error(test.expectRes)
+			}
+
+			if test.expectStatus == 200 {
+				order, _ := test.r.FindUserGroupOrder("abc")
+				if !cmp.Equal(order, test.expectOrder) {
+					t.Errorf("got order: %v; want: %v", order, test.expectOrder)
+				}
+			}
+		})
+	}
+}
+
+func Test_getGroupOrderHandler(t *testing.T) {
+	t.Parallel()
+	ordered := newGroupTestRepo()
+	ordered.UpdateUserGroupOrder("abc", []string{"veg", "fruit"})
+
+	tests := []struct {
+		name         string
+		r            repository.Repostory
+		expectStatus int
+		expectRes    string
+	}{
+		{
+			name:         "get group order",
+			r:            ordered,
+			expectStatus: 200,
+			expectRes:    `{"group_order":["veg","fruit"]}`,
+		},
+		{
+			name:         "return empty list if group order not set",
+			r:            newGroupTestRepo(),
+			expectStatus: 200,
+			expectRes:    `{"group_order":[]}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest("GET", "/websites/group-order", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.WithValue(req.Context(), ContextKeyUserUUID, "abc")
+			req = req.WithContext(ctx)
+			rr := httptest.NewRecorder()
+			getGroupOrderHandler(test.r).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Error("got different code as expect")
+				t.Error(rr.Code)
+				t.Error(test.expectStatus)
+			}
+
+			if strings.Trim(rr.Body.String(), "\n") != test.expectRes {
+				t.Error("got different response as expect")
+				t.Error(rr.Body.String())
+				t.Error(test.expectRes)
+			}
+		})
+	}
+}
+
+func Test_importWebsitesHandler(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		r            repository.Repostory
+		format       string
+		contentType  string
+		body         string
+		expectStatus int
+		expectRes    string
+	}{
+		{
+			name:         "import csv with per row results",
+			r:            repository.NewInMemRepo(nil, nil, nil, nil),
+			contentType:  "text/csv",
+			body:         "url,group_name\nhttps://example.com,group\nexample.com,\n",
+			expectStatus: 200,
+			expectRes:    `{"results":[{"row":1,"url":"https://example.com","status":"created","website_uuid":"<uuid>"},{"row":2,"url":"example.com","status":"invalid","error":"invalid url"}],"summary":{"blocked":0,"created":1,"duplicate":0,"error":0,"invalid":1,"unsupported_host":0}}`,
+		},
+		{
+			name:         "return error if format is unsupported",
+			r:            repository.NewInMemRepo(nil, nil, nil, nil),
+			format:       "yaml",
+			body:         "",
+			expectStatus: 422,
+			expectRes:    `{"code":"unsupported_format","error":"unsupported format"}`,
+		},
+		{
+			name:         "return error if watchlist is malformed",
+			r:            repository.NewInMemRepo(nil, nil, nil, nil),
+			format:       "json",
+			body:         "{",
+			expectStatus: 422,
+			expectRes:    `{"code":"invalid_params","error":"invalid params"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest("POST", "/websites/import?format="+test.format, strings.NewReader(test.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", test.contentType)
+			req = req.WithContext(context.WithValue(req.Context(), ContextKeyUserUUID, "abc"))
+			rr := httptest.NewRecorder()
+			importWebsitesHandler(test.r, &config.WebsiteConfig{URLAllowList: []string{"example.com"}}).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Error("got different code as expect")
+				t.Error(rr.Code)
+				t.Error(test.expectStatus)
+			}
+
+			body := strings.Trim(rr.Body.String(), "\n")
+			if webs, _ := test.r.FindUserWebsites("abc"); len(webs) > 0 {
+				body = strings.ReplaceAll(body, webs[0].WebsiteUUID, "<uuid>")
+			}
+			if body != test.expectRes {
+				t.Error("got different response as expect")
+				t.Error(body)
+				t.Error(test.expectRes)
+			}
+		})
+	}
+}
+
+func Test_exportWebsitesHandler(t *testing.T) {
+	t.Parallel()
+	r := repository.NewInMemRepo(nil, []model.UserWebsite{
+		{
+			UserUUID:    "abc",
+			WebsiteUUID: "1",
+			GroupName:   "group",
+			AccessTime:  time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+			Website: model.Website{
+				UUID:       "1",
+				URL:        "https://example.com",
+				Title:      "title",
+				UpdateTime: time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}, nil, nil)
+
+	tests := []struct {
+		name              string
+		r                 repository.Repostory
+		format            string
+		expectStatus      int
+		expectContentType string
+		expectRes         string
+	}{
+		{
+			name:              "export csv",
+			r:                 r,
+			format:            "csv",
+			expectStatus:      200,
+			expectContentType: "text/csv; charset=utf-8",
+			expectRes:         "url,title,group_name,access_time,update_time\nhttps://example.com,title,group,2000-01-01T00:00:00Z,2000-01-02T00:00:00Z",
+		},
+		{
+			name:              "export json by default",
+			r:                 r,
+			format:            "",
+			expectStatus:      200,
+			expectContentType: "application/json; charset=utf-8",
+			expectRes:         `[{"url":"https://example.com","title":"title","group_name":"group","access_time":"2000-01-01T00:00:00Z","update_time":"2000-01-02T00:00:00Z"}]`,
+		},
+		{
+			name:         "return error if format is unsupported",
+			r:            r,
+			format:       "yaml",
+			expectStatus: 422,
+			expectRes:    `{"code":"unsupported_format","error":"unsupported format"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest("GET", "/websites/export?format="+test.format, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req = req.WithContext(context.WithValue(req.Context(), ContextKeyUserUUID, "abc"))
+			rr := httptest.NewRecorder()
+			exportWebsitesHandler(test.r).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Error("got different code as expect")
+				t.Error(rr.Code)
+				t.Error(test.expectStatus)
+			}
+
+			if test.expectContentType != "" && rr.Header().Get("Content-Type") != test.expectContentType {
+				t.Errorf("got content type: %s; want: %s", rr.Header().Get("Content-Type"), test.expectContentType)
+			}
+
+			if strings.Trim(rr.Body.String(), "\n") != test.expectRes {
+				t.Error("got different response as expect")
+				t.Error(rr.Body.String())
+				t.Error(test.expectRes)
+			}
+		})
+	}
+}
+
+func Test_importBookmarksHandler(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		r             repository.Repostory
+		body          string
+		expectStatus  int
+		expectSummary map[watchlist.Status]int
+	}{
+		{
+			name: "import bookmarks in background",
+			r:    repository.NewInMemRepo(nil, nil, []model.WebsiteSetting{{Domain: "example.com"}}, nil),
+			body: `<DL><p><DT><H3>news</H3><DL><p>
+				<DT><A HREF="https://example.com/1">1</A>
+				<DT><A HREF="https://example.com/1">1</A>
+				<DT><A HREF="https://unknown.com/2">2</A>
+			</DL><p></DL><p>`,
+			expectStatus: 202,
+			expectSummary: map[watchlist.Status]int{
+				watchlist.StatusCreated: 1, watchlist.StatusDuplicate: 1, watchlist.StatusUnsupportedHost: 1,
+				watchlist.StatusInvalid: 0, watchlist.StatusError: 0, watchlist.StatusBlocked: 0,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			jobs := watchlist.NewJobStore(test.r)
+			req, err := http.NewRequest("POST", "/websites/import/bookmarks", strings.NewReader(test.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req = req.WithContext(context.WithValue(req.Context(), ContextKeyUserUUID, "abc"))
+			rr := httptest.NewRecorder()
+			importBookmarksHandler(test.r, &config.WebsiteConfig{URLAllowList: []string{"example.com"}}, jobs).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Error("got different code as expect")
+				t.Error(rr.Code)
+				t.Error(test.expectStatus)
+			}
+
+			var res struct {
+				JobID string `json:"job_id"`
+			}
+			if err := json.NewDecoder(rr.Body).Decode(&res); err != nil {
+				t.Fatalf("decode response fail: %v", err)
+			}
+			jobs.Wait(res.JobID)
+
+			job, err := jobs.Get("abc", res.JobID)
+			if err != nil {
+				t.Fatalf("get job %s fail: %v", res.JobID, err)
+			}
+			if !cmp.Equal(job.Summary, test.expectSummary) {
+				t.Errorf("summary diff: %v", cmp.Diff(job.Summary, test.expectSummary))
+			}
+
+			webs, _ := test.r.FindUserWebsites("abc")
+			if len(webs) != 1 || webs[0].GroupName != "news" {
+				t.Errorf("got unexpected user websites: %v", webs)
+			}
+		})
+	}
+}
+
+func Test_getImportJobHandler(t *testing.T) {
+	t.Parallel()
+	jobs := watchlist.NewJobStore(repository.NewInMemRepo(nil, nil, nil, nil))
+	jobID, err := jobs.Start(context.Background(), "abc", 1, func(ctx context.Context) ([]watchlist.Result, error) {
+		return []watchlist.Result{{Row: 1, URL: "https://example.com", Status: watchlist.StatusDuplicate}}, nil
+	})
+	if err != nil {
+		t.Fatalf("start job fail: %v", err)
+	}
+	jobs.Wait(jobID)
+	job, _ := jobs.Get("abc", jobID)
+
+	tests := []struct {
+		name         string
+		userUUID     string
+		jobID        string
+		expectStatus int
+		expectRes    string
+	}{
+		{
+			name:         "get job of user",
+			userUUID:     "abc",
+			jobID:        jobID,
+			expectStatus: 200,
+			expectRes: fmt.Sprintf(
+				`{"job":{"id":"%s","status":"completed","total":1,"summary":{"blocked":0,"created":0,"duplicate":1,"error":0,"invalid":0,"unsupported_host":0},"results":[{"row":1,"url":"https://example.com","status":"duplicate"}],"created_at":"%s","finished_at":"%s"}}`,
+				jobID, job.CreatedAt.Format(time.RFC3339), job.FinishedAt.Format(time.RFC3339),
+			),
+		},
+		{
+			name:         "return not found for job of other user",
+			userUUID:     "def",
+			jobID:        jobID,
+			expectStatus: 404,
+			expectRes:    `{"code":"import_job_not_found","error":"import job not found"}`,
+		},
+		{
+			name:         "return not found for unknown job",
+			userUUID:     "abc",
+			jobID:        "unknown",
+			expectStatus: 404,
+			expectRes:    `{"code":"import_job_not_found","error":"import job not found"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest("GET", "/websites/import/jobs/"+test.jobID, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("jobID", test.jobID)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, ContextKeyUserUUID, test.userUUID)
+			req = req.WithContext(ctx)
+			rr := httptest.NewRecorder()
+			getImportJobHandler(jobs).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Error("got different code as expect")
+				t.Error(rr.Code)
+				t.Error(test.expectStatus)
+			}
+
+			if strings.Trim(rr.Body.String(), "\n") != test.expectRes {
+				t.Error("got different response as expect")
+				t.Error(rr.Body.String())
+				t.Error(test.expectRes)
+			}
+		})
+	}
+}
+
+func Test_watchedWebsites_visible(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := repository.NewInMemRepo(nil, []model.UserWebsite{
+		{UserUUID: "abc", WebsiteUUID: "watched"},
+		{UserUUID: "abc", WebsiteUUID: "muted", Muted: true},
+		{UserUUID: "abc", WebsiteUUID: "snoozed", SnoozeUntil: now.Add(time.Hour)},
+		{UserUUID: "def", WebsiteUUID: "other"},
+	}, nil, nil)
+	watched, err := newWatchedWebsites(r, "abc")
+	if err != nil {
+		t.Fatalf("load watched websites fail: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		event  events.Event
+		expect bool
+	}{
+		{
+			name:   "update of watched website",
+			event:  events.Event{Type: events.WebsiteUpdated, WebsiteUUID: "watched"},
+			expect: true,
+		},
+		{
+			name:   "update of muted website",
+			event:  events.Event{Type: events.WebsiteUpdated, WebsiteUUID: "muted"},
+			expect: false,
+		},
+		{
+			name:   "update of snoozed website",
+			event:  events.Event{Type: events.WebsiteHealthChanged, WebsiteUUID: "snoozed"},
+			expect: false,
+		},
+		{
+			name:   "update of website watched by other user",
+			event:  events.Event{Type: events.WebsiteUpdated, WebsiteUUID: "other"},
+			expect: false,
+		},
+		{
+			name:   "event of user",
+			event:  events.Event{Type: events.WebsiteDeleted, WebsiteUUID: "muted", UserUUID: "abc"},
+			expect: true,
+		},
+		{
+			name:   "event of other user",
+			event:  events.Event{Type: events.WebsiteCreated, WebsiteUUID: "watched", UserUUID: "def"},
+			expect: false,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			if result := watched.visible(context.Background(), test.event, now); result != test.expect {
+				t.Errorf("got: %v; want: %v", result, test.expect)
+			}
+		})
+	}
+}
+
+func Test_watchedWebsites_reload(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := repository.NewInMemRepo(nil, []model.UserWebsite{{UserUUID: "abc", WebsiteUUID: "watched"}}, nil, nil)
+	watched, err := newWatchedWebsites(r, "abc")
+	if err != nil {
+		t.Fatalf("load watched websites fail: %v", err)
+	}
+
+	r.CreateUserWebsite(&model.UserWebsite{UserUUID: "abc", WebsiteUUID: "new"})
+	updated := events.Event{Type: events.WebsiteUpdated, WebsiteUUID: "new"}
+	if watched.visible(context.Background(), updated, now) {
+		t.Errorf("update of website created after load is visible before create event")
+	}
+
+	watched.visible(context.Background(), events.Event{Type: events.WebsiteCreated, WebsiteUUID: "new", UserUUID: "abc"}, now)
+	if !watched.visible(context.Background(), updated, now) {
+		t.Errorf("update of created website is not visible")
+	}
+
+	r.DeleteUserWebsite(&model.UserWebsite{UserUUID: "abc", WebsiteUUID: "watched"})
+	watched.visible(context.Background(), events.Event{Type: events.WebsiteDeleted, WebsiteUUID: "watched", UserUUID: "abc"}, now)
+	if watched.visible(context.Background(), events.Event{Type: events.WebsiteUpdated, WebsiteUUID: "watched"}, now) {
+		t.Errorf("update of deleted website is visible")
+	}
+}
+
+func Test_websiteEventsHandler(t *testing.T) {
+	t.Parallel()
+	r := repository.NewInMemRepo(nil, []model.UserWebsite{
+		{UserUUID: "abc", WebsiteUUID: "watched"},
+		{UserUUID: "abc", WebsiteUUID: "muted", Muted: true},
+	}, nil, nil)
+	pubsub := events.NewInMemPubSub()
+
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		req = req.WithContext(context.WithValue(req.Context(), ContextKeyUserUUID, "abc"))
+		websiteEventsHandler(r, pubsub).ServeHTTP(res, req)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Errorf("got content type: %v", resp.Header.Get("Content-Type"))
+	}
+
+	eventTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, event := range []events.Event{
+		{Type: events.WebsiteUpdated, WebsiteUUID: "muted", Time: eventTime},
+		{Type: events.WebsiteCreated, WebsiteUUID: "new", UserUUID: "def", Time: eventTime},
+		{Type: events.WebsiteUpdated, WebsiteUUID: "watched", Time: eventTime},
+	} {
+		pubsub.Publish(context.Background(), event)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for len(lines) < 2 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read event fail: %v", err)
+		}
+		if line = strings.TrimRight(line, "\n"); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	expect := []string{
+		"event: website_updated",
+		`data: {"type":"website_updated","website_uuid":"watched","time":"2020-01-01T00:00:00Z"}`,
+	}
+	if !cmp.Equal(lines, expect) {
+		t.Errorf("got lines: %v; want: %v", lines, expect)
+	}
+}
+
+// newTestLocalAuthenticator hashes password, tests using it are not run in
+// parallel with other tests to keep the time sensitive tests stable
+func newTestLocalAuthenticator(t *testing.T) *auth.LocalAuthenticator {
+	t.Helper()
+
+	authenticator, err := auth.NewLocalAuthenticator(
+		&config.UserServiceConfig{JWTSecret: "secret", JWTTTL: time.Hour},
+		repository.NewInMemRepo(nil, nil, nil, nil),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := authenticator.Register("user", "password"); err != nil {
+		t.Fatal(err)
+	}
+
+	return authenticator
+}
+
+func Test_loginHandler(t *testing.T) {
+	authenticator := newTestLocalAuthenticator(t)
+	tests := []struct {
+		name         string
+		form         string
+		expectStatus int
+		expectRes    string
+	}{
+		{
+			name:         "happy flow",
+			form:         "username=user&password=password",
+			expectStatus: 200,
+		},
+		{
+			name:         "wrong password",
+			form:         "username=user&password=wrong",
+			expectStatus: 401,
+			expectRes:    `{"code":"invalid_credentials","error":"invalid username or password"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest("POST", "/auth/login", strings.NewReader(test.form))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			rr := httptest.NewRecorder()
+			loginHandler(authenticator).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Errorf("got code: %v; want: %v", rr.Code, test.expectStatus)
+			}
+
+			if test.expectStatus != 200 {
+				if strings.Trim(rr.Body.String(), "\n") != test.expectRes {
+					t.Errorf("got res: %v; want: %v", rr.Body.String(), test.expectRes)
+				}
+				return
+			}
+
+			var body struct{ Token string }
+			if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := authenticator.Authenticate(context.Background(), body.Token); err != nil {
+				t.Errorf("login token cannot authenticate: %v", err)
+			}
+		})
+	}
+}
+
+func Test_registerHandler(t *testing.T) {
+	authenticator := newTestLocalAuthenticator(t)
+	tests := []struct {
+		name         string
+		contentType  string
+		form         string
+		expectStatus int
+		expectRes    string
+	}{
+		{
+			name:         "happy flow",
+			form:         "username=new_user&password=password",
+			expectStatus: 201,
+		},
+		{
+			name:         "happy flow with json body",
+			contentType:  "application/json",
+			form:         `{"username":"json_user","password":"password"}`,
+			expectStatus: 201,
+		},
+		{
+			name:         "malformed json body",
+			contentType:  "application/json",
+			form:         `{"username":`,
+			expectStatus: 400,
+			expectRes:    `{"code":"bad_request","error":"malformed request"}`,
+		},
+		{
+			name:         "existing username",
+			form:         "username=user&password=password",
+			expectStatus: 409,
+			expectRes:    `{"code":"user_exists","error":"user already exists"}`,
+		},
+		{
+			name:         "short password",
+			form:         "username=another_user&password=short",
+			expectStatus: 422,
+			expectRes:    `{"code":"invalid_password","error":"invalid password","field":"password"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest("POST", "/auth/register", strings.NewReader(test.form))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if test.contentType != "" {
+				req.Header.Set("Content-Type", test.contentType)
+			}
+			rr := httptest.NewRecorder()
+			registerHandler(authenticator).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Errorf("got code: %v; want: %v", rr.Code, test.expectStatus)
+			}
+
+			if test.expectRes != "" && strings.Trim(rr.Body.String(), "\n") != test.expectRes {
+				t.Errorf("got res: %v; want: %v", rr.Body.String(), test.expectRes)
+			}
+		})
+	}
+}
+
+func Test_apiTokenHandlers(t *testing.T) {
+	authenticator := newTestLocalAuthenticator(t)
+	serve := func(handler http.HandlerFunc, method, form, tokenUUID string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(method, "/auth/tokens", strings.NewReader(form))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("tokenUUID", tokenUUID)
+		ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+		ctx = context.WithValue(ctx, ContextKeyUserUUID, "abc")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req.WithContext(ctx))
+		return rr
+	}
+
+	rr := serve(createAPITokenHandler(authenticator), "POST", "name=cli", "")
+	if rr.Code != 201 {
+		t.Fatalf("create token got code: %v", rr.Code)
+	}
+	var created struct {
+		Token    string         `json:"token"`
+		APIToken model.APIToken `json:"api_token"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+	if userUUID, err := authenticator.Authenticate(context.Background(), created.Token); err != nil || userUUID != "abc" {
+		t.Errorf("authenticate created token got: %v, %v", userUUID, err)
+	}
+
+	rr = serve(getAPITokensHandler(authenticator), "GET", "", "")
+	expectRes := fmt.Sprintf(
+		`{"tokens":[{"uuid":"%v","name":"cli","create_time":"%v"}]}`,
+		created.APIToken.UUID, created.APIToken.CreateTime.Format(time.RFC3339),
+	)
+	if rr.Code != 200 || strings.Trim(rr.Body.String(), "\n") != expectRes {
+		t.Errorf("list tokens got: %v %v; want: %v", rr.Code, rr.Body.String(), expectRes)
+	}
+
+	rr = serve(deleteAPITokenHandler(authenticator), "DELETE", "", created.APIToken.UUID)
+	if rr.Code != 200 {
+		t.Errorf("delete token got code: %v", rr.Code)
+	}
+	if _, err := authenticator.Authenticate(context.Background(), created.Token); err == nil {
+		t.Error("deleted token still authenticates")
+	}
+
+	rr = serve(getAPITokensHandler(authenticator), "GET", "", "")
+	if strings.Trim(rr.Body.String(), "\n") != `{"tokens":[]}` {
+		t.Errorf("list tokens after delete got: %v", rr.Body.String())
+	}
+}
+
+// newShareRepo returns repo with group "shared" of owner shared to sub
+func newShareRepo(mode model.GroupShareMode) *repository.InMemRepo {
+	r := repository.NewInMemRepo(
+		[]model.Website{{UUID: "1", URL: "http://example.com/1", Title: "one"}},
+		[]model.UserWebsite{
+			{WebsiteUUID: "1", UserUUID: "owner", GroupName: "shared", Website: model.Website{UUID: "1", URL: "http://example.com/1", Title: "one"}},
+			{WebsiteUUID: "1", UserUUID: "sub", GroupName: "mirror", ShareUUID: "share", Website: model.Website{UUID: "1", URL: "http://example.com/1", Title: "one"}},
+		},
+		nil, nil,
+	)
+	r.CreateGroupShare(&model.GroupShare{UUID: "share", OwnerUUID: "owner", GroupName: "shared", Mode: mode})
+	r.CreateGroupShareSubscription(&model.GroupShareSubscription{ShareUUID: "share", UserUUID: "sub", GroupName: "mirror"})
+
+	return r
+}
+
+func Test_getGroupSharesHandler(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		r            repository.Repostory
+		userUUID     string
+		expectStatus int
+		expectRes    string
+	}{
+		{
+			name:         "list shares of owner",
+			r:            newShareRepo(model.GroupShareReadOnly),
+			userUUID:     "owner",
+			expectStatus: 200,
+			expectRes:    `{"shares":[{"uuid":"share","owner_uuid":"owner","group_name":"shared","mode":"read_only","create_time":"0001-01-01T00:00:00Z"}],"subscriptions":[]}`,
+		},
+		{
+			name:         "list subscriptions of subscriber",
+			r:            newShareRepo(model.GroupShareReadOnly),
+			userUUID:     "sub",
+			expectStatus: 200,
+			expectRes:    `{"shares":[],"subscriptions":[{"share_uuid":"share","group_name":"mirror","create_time":"0001-01-01T00:00:00Z"}]}`,
+		},
+		{
+			name:         "return error if repo return error",
+			r:            repository.NewInMemRepo(nil, nil, nil, errors.New("some error")),
+			userUUID:     "owner",
+			expectStatus: 500,
+			expectRes:    `{"code":"internal_error","error":"internal server error"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest("GET", "/websites/shares", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req = req.WithContext(context.WithValue(req.Context(), ContextKeyUserUUID, test.userUUID))
+			rr := httptest.NewRecorder()
+			getGroupSharesHandler(test.r).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Errorf("got code: %v; want: %v", rr.Code, test.expectStatus)
+			}
+
+			if strings.Trim(rr.Body.String(), "\n") != test.expectRes {
+				t.Errorf("got res: %v; want: %v", rr.Body.String(), test.expectRes)
+			}
+		})
+	}
+}
+
+func Test_shareWebsiteGroupHandler(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		r            *repository.InMemRepo
+		groupName    string
+		mode         model.GroupShareMode
+		expectStatus int
+		expectMode   model.GroupShareMode
+		expectShares int
+	}{
+		{
+			name:         "share group",
+			r:            repository.NewInMemRepo(nil, []model.UserWebsite{{WebsiteUUID: "1", UserUUID: "owner", GroupName: "group"}}, nil, nil),
+			groupName:    "group",
+			mode:         model.GroupShareReadOnly,
+			expectStatus: 200,
+			expectMode:   model.GroupShareReadOnly,
+			expectShares: 1,
+		},
+		{
+			name:         "update mode of shared group",
+			r:            newShareRepo(model.GroupShareReadOnly),
+			groupName:    "shared",
+			mode:         model.GroupShareCollaborative,
+			expectStatus: 200,
+			expectMode:   model.GroupShareCollaborative,
+			expectShares: 1,
+		},
+		{
+			name:         "return not found for empty group",
+			r:            repository.NewInMemRepo(nil, nil, nil, nil),
+			groupName:    "group",
+			mode:         model.GroupShareReadOnly,
+			expectStatus: 404,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest("POST", "/websites/groups/{groupName}/share", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("groupName", test.groupName)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, ContextKeyUserUUID, "owner")
+			ctx = context.WithValue(ctx, ContextKeyShareMode, test.mode)
+			rr := httptest.NewRecorder()
+			shareWebsiteGroupHandler(test.r).ServeHTTP(rr, req.WithContext(ctx))
+
+			if rr.Code != test.expectStatus {
+				t.Errorf("got code: %v; want: %v", rr.Code, test.expectStatus)
+			}
+
+			shares, _ := test.r.FindGroupShares("owner")
+			if len(shares) != test.expectShares {
+				t.Fatalf("got shares: %v; want count: %v", shares, test.expectShares)
+			}
+			if test.expectShares > 0 && shares[0].Mode != test.expectMode {
+				t.Errorf("got mode: %v; want: %v", shares[0].Mode, test.expectMode)
+			}
+		})
+	}
+}
+
+func Test_unshareWebsiteGroupHandler(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		r            *repository.InMemRepo
+		groupName    string
+		expectStatus int
+		expectRes    string
+	}{
+		{
+			name:         "unshare group",
+			r:            newShareRepo(model.GroupShareReadOnly),
+			groupName:    "shared",
+			expectStatus: 200,
+			expectRes:    `{"message":"group \u003cshared\u003e unshared"}`,
+		},
+		{
+			name:         "return not found for group not shared",
+			r:            newShareRepo(model.GroupShareReadOnly),
+			groupName:    "other",
+			expectStatus: 404,
+			expectRes:    `{"code":"not_found","error":"record not found"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest("DELETE", "/websites/groups/{groupName}/share", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("groupName", test.groupName)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, ContextKeyUserUUID, "owner")
+			rr := httptest.NewRecorder()
+			unshareWebsiteGroupHandler(test.r).ServeHTTP(rr, req.WithContext(ctx))
+
+			if rr.Code != test.expectStatus {
+				t.Errorf("got code: %v; want: %v", rr.Code, test.expectStatus)
+			}
+
+			if strings.Trim(rr.Body.String(), "\n") != test.expectRes {
+				t.Errorf("got res: %v; want: %v", rr.Body.String(), test.expectRes)
+			}
+
+			if test.expectStatus == 200 {
+				if subs, _ := test.r.FindUserGroupShareSubscriptions("sub"); len(subs) != 0 {
+					t.Errorf("got subscriptions: %v; want none", subs)
+				}
+			}
+		})
+	}
+}
+
+func Test_subscribeGroupShareHandler(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		r            *repository.InMemRepo
+		userUUID     string
+		shareUUID    string
+		groupName    string
+		expectStatus int
+		expectGroup  string
+	}{
+		{
+			name:         "subscribe into group named after share",
+			r:            newShareRepo(model.GroupShareReadOnly),
+			userUUID:     "new",
+			shareUUID:    "share",
+			expectStatus: 200,
+			expectGroup:  "shared",
+		},
+		{
+			name:         "subscribe into given group",
+			r:            newShareRepo(model.GroupShareReadOnly),
+			userUUID:     "new",
+			shareUUID:    "share",
+			groupName:    "mine",
+			expectStatus: 200,
+			expectGroup:  "mine",
+		},
+		{
+			name:         "return not found for unknown share",
+			r:            newShareRepo(model.GroupShareReadOnly),
+			userUUID:     "new",
+			shareUUID:    "unknown",
+			expectStatus: 404,
+		},
+		{
+			name:         "return invalid params for owner",
+			r:            newShareRepo(model.GroupShareReadOnly),
+			userUUID:     "owner",
+			shareUUID:    "share",
+			expectStatus: 422,
+		},
+		{
+			name:         "return conflict for existing group",
+			r:            newShareRepo(model.GroupShareReadOnly),
+			userUUID:     "sub",
+			shareUUID:    "share",
+			groupName:    "mirror",
+			expectStatus: 409,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest("POST", "/websites/shares/{shareUUID}/subscription", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("shareUUID", test.shareUUID)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, ContextKeyUserUUID, test.userUUID)
+			ctx = context.WithValue(ctx, ContextKeyGroup, test.groupName)
+			rr := httptest.NewRecorder()
+			subscribeGroupShareHandler(test.r).ServeHTTP(rr, req.WithContext(ctx))
+
+			if rr.Code != test.expectStatus {
+				t.Errorf("got code: %v; want: %v", rr.Code, test.expectStatus)
+			}
+
+			if test.expectGroup != "" {
+				webs, _ := test.r.FindUserWebsitesByGroup(test.userUUID, test.expectGroup)
+				if len(webs) != 1 || webs[0].WebsiteUUID != "1" {
+					t.Errorf("got synced websites: %v; want website 1", webs)
+				}
+			}
+		})
+	}
+}
+
+func Test_unsubscribeGroupShareHandler(t *testing.T) {
+	t.Parallel()
+	r := newShareRepo(model.GroupShareReadOnly)
+
+	req, err := http.NewRequest("DELETE", "/websites/shares/{shareUUID}/subscription", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("shareUUID", "share")
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	ctx = context.WithValue(ctx, ContextKeyUserUUID, "sub")
+	rr := httptest.NewRecorder()
+	unsubscribeGroupShareHandler(r).ServeHTTP(rr, req.WithContext(ctx))
+
+	if rr.Code != 200 {
+		t.Errorf("got code: %v; want: %v", rr.Code, 200)
+	}
+	if subs, _ := r.FindUserGroupShareSubscriptions("sub"); len(subs) != 0 {
+		t.Errorf("got subscriptions: %v; want none", subs)
+	}
+	if webs, _ := r.FindUserWebsitesByGroup("sub", "mirror"); len(webs) != 1 {
+		t.Errorf("got websites: %v; want synced website kept", webs)
+	}
+}
+
+func Test_addSharedWebsiteHandler(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		r             *repository.InMemRepo
+		userUUID      string
+		shareUUID     string
+		expectStatus  int
+		expectPending int
+	}{
+		{
+			name:          "owner add website to read only share",
+			r:             newShareRepo(model.GroupShareReadOnly),
+			userUUID:      "owner",
+			shareUUID:     "share",
+			expectStatus:  202,
+			expectPending: 1,
+		},
+		{
+			name:          "subscriber add website to collaborative share",
+			r:             newShareRepo(model.GroupShareCollaborative),
+			userUUID:      "sub",
+			shareUUID:     "share",
+			expectStatus:  202,
+			expectPending: 1,
+		},
+		{
+			name:         "subscriber cannot change read only share",
+			r:            newShareRepo(model.GroupShareReadOnly),
+			userUUID:     "sub",
+			shareUUID:    "share",
+			expectStatus: 403,
+		},
+		{
+			name:         "other user cannot change collaborative share",
+			r:            newShareRepo(model.GroupShareCollaborative),
+			userUUID:     "other",
+			shareUUID:    "share",
+			expectStatus: 403,
+		},
+		{
+			name:         "return not found for unknown share",
+			r:            newShareRepo(model.GroupShareCollaborative),
+			userUUID:     "owner",
+			shareUUID:    "unknown",
+			expectStatus: 404,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest("POST", "/websites/shares/{shareUUID}/websites", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("shareUUID", test.shareUUID)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, ContextKeyUserUUID, test.userUUID)
+			ctx = context.WithValue(ctx, ContextKeyWebURL, "http://example.com/2")
+			rr := httptest.NewRecorder()
+			addSharedWebsiteHandler(test.r, &config.WebsiteConfig{}).ServeHTTP(rr, req.WithContext(ctx))
+
+			if rr.Code != test.expectStatus {
+				t.Errorf("got code: %v; want: %v", rr.Code, test.expectStatus)
+			}
+
+			if pending, _ := test.r.CountWebsiteUpdateRequests(); pending != test.expectPending {
+				t.Errorf("got pending requests: %v; want: %v", pending, test.expectPending)
+			}
+
+			expectCount := 1
+			if test.expectStatus == 202 {
+				expectCount = 2
+			}
+			for user, group := range map[string]string{"owner": "shared", "sub": "mirror"} {
+				if webs, _ := test.r.FindUserWebsitesByGroup(user, group); len(webs) != expectCount {
+					t.Errorf("got %v websites: %v; want count: %v", user, webs, expectCount)
+				}
+			}
+		})
+	}
+}
+
+func Test_removeSharedWebsiteHandler(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		r            *repository.InMemRepo
+		userUUID     string
+		webUUID      string
+		expectStatus int
+		expectCount  int
+	}{
+		{
+			name:         "subscriber remove website from collaborative share",
+			r:            newShareRepo(model.GroupShareCollaborative),
+			userUUID:     "sub",
+			webUUID:      "1",
+			expectStatus: 200,
+			expectCount:  0,
+		},
+		{
+			name:         "subscriber cannot change read only share",
+			r:            newShareRepo(model.GroupShareReadOnly),
+			userUUID:     "sub",
+			webUUID:      "1",
+			expectStatus: 403,
+			expectCount:  1,
+		},
+		{
+			name:         "return not found for website not shared",
+			r:            newShareRepo(model.GroupShareCollaborative),
+			userUUID:     "owner",
+			webUUID:      "2",
+			expectStatus: 404,
+			expectCount:  1,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest("DELETE", "/websites/shares/{shareUUID}/websites/{webUUID}", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("shareUUID", "share")
+			rctx.URLParams.Add("webUUID", test.webUUID)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, ContextKeyUserUUID, test.userUUID)
+			rr := httptest.NewRecorder()
+			removeSharedWebsiteHandler(test.r).ServeHTTP(rr, req.WithContext(ctx))
+
+			if rr.Code != test.expectStatus {
+				t.Errorf("got code: %v; want: %v", rr.Code, test.expectStatus)
+			}
+
+			for user, group := range map[string]string{"owner": "shared", "sub": "mirror"} {
+				if webs, _ := test.r.FindUserWebsitesByGroup(user, group); len(webs) != test.expectCount {
+					t.Errorf("got %v websites: %v; want count: %v", user, webs, test.expectCount)
+				}
+			}
+		})
+	}
+}
+
+func Test_groupManagementHandlers_sharedGroup(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name             string
+		handler          func(repository.Repostory, bool) http.HandlerFunc
+		userUUID         string
+		groupName        string
+		newGroupName     string
+		webUUIDs         []string
+		expectStatus     int
+		expectShareGroup string
+	}{
+		{
+			name:             "share follows renamed group",
+			handler:          renameWebsiteGroupHandler,
+			userUUID:         "owner",
+			groupName:        "shared",
+			newGroupName:     "renamed",
+			expectStatus:     200,
+			expectShareGroup: "renamed",
+		},
+		{
+			name:         "share is dropped if group is emptied",
+			handler:      moveWebsitesHandler,
+			userUUID:     "owner",
+			groupName:    "other",
+			webUUIDs:     []string{"1"},
+			expectStatus: 200,
+		},
+		{
+			name:             "subscriber cannot move synced website",
+			handler:          moveWebsitesHandler,
+			userUUID:         "sub",
+			groupName:        "other",
+			webUUIDs:         []string{"1"},
+			expectStatus:     403,
+			expectShareGroup: "shared",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			r := newShareRepo(model.GroupShareReadOnly)
+			req, err := http.NewRequest("PUT", "/websites/groups/{groupName}", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("groupName", test.groupName)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, ContextKeyUserUUID, test.userUUID)
+			ctx = context.WithValue(ctx, ContextKeyGroup, test.newGroupName)
+			ctx = context.WithValue(ctx, ContextKeyWebsiteUUIDs, test.webUUIDs)
+			rr := httptest.NewRecorder()
+			test.handler(r, false).ServeHTTP(rr, req.WithContext(ctx))
+
+			if rr.Code != test.expectStatus {
+				t.Errorf("got code: %v; want: %v", rr.Code, test.expectStatus)
+			}
+
+			var shareGroup string
+			if share, err := r.FindGroupShare("share"); err == nil {
+				shareGroup = share.GroupName
+			}
+			if shareGroup != test.expectShareGroup {
+				t.Errorf("got share of group: %q; want: %q", shareGroup, test.expectShareGroup)
+			}
+
+			if webs, _ := r.FindUserWebsitesByGroup("sub", "mirror"); len(webs) != 1 {
+				t.Errorf("got sub websites: %v; want synced website kept", webs)
+			}
+		})
+	}
+}
+
+func newPublicLinkRepo() *repository.InMemRepo {
+	r := repository.NewInMemRepo(nil, []model.UserWebsite{
+		{
+			WebsiteUUID: "1", UserUUID: "owner", GroupName: "comics", LastReadItem: "secret",
+			Website: model.Website{UUID: "1", URL: "http://example.com/1", Title: "one", UpdateTime: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)},
+		},
+	}, nil, nil)
+	r.CreateGroupPublicLink(&model.GroupPublicLink{Token: "token", OwnerUUID: "owner", GroupName: "comics"})
+
+	return r
+}
+
+func Test_groupPublicLinkHandlers(t *testing.T) {
+	t.Parallel()
+
+	serve := func(handler http.HandlerFunc, method, groupName string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/websites/groups/{groupName}/public-link", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("groupName", groupName)
+		ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+		ctx = context.WithValue(ctx, ContextKeyUserUUID, "owner")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req.WithContext(ctx))
+
+		return rr
+	}
+
+	t.Run("get existing link", func(t *testing.T) {
+		t.Parallel()
+		rr := serve(getGroupPublicLinkHandler(newPublicLinkRepo()), "GET", "comics")
+		expectRes := `{"public_link":{"token":"token","group_name":"comics","create_time":"0001-01-01T00:00:00Z"}}`
+		if rr.Code != 200 || strings.Trim(rr.Body.String(), "\n") != expectRes {
+			t.Errorf("got: %v %v; want: 200 %v", rr.Code, rr.Body.String(), expectRes)
+		}
+	})
+
+	t.Run("get link of unpublished group", func(t *testing.T) {
+		t.Parallel()
+		rr := serve(getGroupPublicLinkHandler(newPublicLinkRepo()), "GET", "other")
+		if rr.Code != 404 {
+			t.Errorf("got code: %v; want: 404", rr.Code)
+		}
+	})
+
+	t.Run("rotate token", func(t *testing.T) {
+		t.Parallel()
+		r := newPublicLinkRepo()
+		rr := serve(createGroupPublicLinkHandler(r), "POST", "comics")
+		if rr.Code != 200 {
+			t.Fatalf("got code: %v; want: 200", rr.Code)
+		}
+
+		if _, err := r.FindGroupPublicLink("token"); err == nil {
+			t.Errorf("old token still works")
+		}
+		link, err := r.FindGroupPublicLinkByGroup("owner", "comics")
+		if err != nil || link.Token == "token" || !strings.Contains(rr.Body.String(), link.Token) {
+			t.Errorf("got link: %v, res: %v", link, rr.Body.String())
+		}
+	})
+
+	t.Run("create link of empty group", func(t *testing.T) {
+		t.Parallel()
+		rr := serve(createGroupPublicLinkHandler(newPublicLinkRepo()), "POST", "other")
+		if rr.Code != 404 {
+			t.Errorf("got code: %v; want: 404", rr.Code)
+		}
+	})
+
+	t.Run("revoke link", func(t *testing.T) {
+		t.Parallel()
+		r := newPublicLinkRepo()
+		rr := serve(deleteGroupPublicLinkHandler(r), "DELETE", "comics")
+		if rr.Code != 200 {
+			t.Errorf("got code: %v; want: 200", rr.Code)
+		}
+		if _, err := r.FindGroupPublicLink("token"); err == nil {
+			t.Errorf("revoked token still works")
+		}
+
+		rr = serve(deleteGroupPublicLinkHandler(r), "DELETE", "comics")
+		if rr.Code != 404 {
+			t.Errorf("got code: %v; want: 404", rr.Code)
+		}
+	})
+}
+
+func Test_publicGroupHandler_groupChanged(t *testing.T) {
+	t.Parallel()
+
+	changeGroup := func(r repository.Repostory, handler func(repository.Repostory, bool) http.HandlerFunc, groupName, newGroupName string, webUUIDs []string) {
+		req := httptest.NewRequest("PUT", "/websites/groups/{groupName}", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("groupName", groupName)
+		ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+		ctx = context.WithValue(ctx, ContextKeyUserUUID, "owner")
+		ctx = context.WithValue(ctx, ContextKeyGroup, newGroupName)
+		ctx = context.WithValue(ctx, ContextKeyWebsiteUUIDs, webUUIDs)
+		rr := httptest.NewRecorder()
+		handler(r, false).ServeHTTP(rr, req.WithContext(ctx))
+		if rr.Code != 200 {
+			t.Fatalf("got code of group change: %v; res: %v", rr.Code, rr.Body.String())
+		}
+	}
+	addWebsite := func(r repository.Repostory, webUUID, groupName string) {
+		web := model.UserWebsite{
+			WebsiteUUID: webUUID, UserUUID: "owner", GroupName: groupName,
+			Website: model.Website{UUID: webUUID, URL: "http://example.com/" + webUUID, Title: webUUID},
+		}
+		r.CreateUserWebsite(&web)
+	}
+	getPublicGroup := func(r repository.Repostory) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/public/groups/token", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("token", "token")
+		rr := httptest.NewRecorder()
+		publicGroupHandler(r).ServeHTTP(rr, req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx)))
+
+		return rr
+	}
+
+	t.Run("link follows renamed group and old name is not published", func(t *testing.T) {
+		t.Parallel()
+		r := newPublicLinkRepo()
+		changeGroup(r, renameWebsiteGroupHandler, "comics", "manga", nil)
+		addWebsite(r, "2", "comics")
+
+		rr := getPublicGroup(r)
+		if rr.Code != 200 || !strings.Contains(rr.Body.String(), `"group_name":"manga"`) ||
+			strings.Contains(rr.Body.String(), "example.com/2") {
+			t.Errorf("got: %v %v; want websites of renamed group", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("link is revoked if group is merged", func(t *testing.T) {
+		t.Parallel()
+		r := newPublicLinkRepo()
+		addWebsite(r, "2", "manga")
+		changeGroup(r, mergeWebsiteGroupHandler, "comics", "manga", nil)
+
+		if rr := getPublicGroup(r); rr.Code != 404 {
+			t.Errorf("got: %v %v; want: 404", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("link is revoked if group is emptied", func(t *testing.T) {
+		t.Parallel()
+		r := newPublicLinkRepo()
+		changeGroup(r, moveWebsitesHandler, "manga", "", []string{"1"})
+		addWebsite(r, "2", "comics")
+
+		if rr := getPublicGroup(r); rr.Code != 404 {
+			t.Errorf("got: %v %v; want: 404", rr.Code, rr.Body.String())
+		}
+	})
+}
+
+func Test_publicGroupHandler(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name              string
+		r                 repository.Repostory
+		token             string
+		target            string
+		accept            string
+		expectStatus      int
+		expectContentType string
+		expectRes         string
+	}{
+		{
+			name:         "list websites of group",
+			r:            newPublicLinkRepo(),
+			token:        "token",
+			target:       "/public/groups/token",
+			expectStatus: 200,
+			expectRes:    `{"group_name":"comics","websites":[{"title":"one","update_time":"2020-01-02T03:04:05 UTC","url":"http://example.com/1"}]}`,
+		},
+		{
+			name:              "atom feed by format",
+			r:                 newPublicLinkRepo(),
+			token:             "token",
+			target:            "/public/groups/token?format=atom",
+			expectStatus:      200,
+			expectContentType: "application/atom+xml; charset=utf-8",
+		},
+		{
+			name:              "atom feed by accept header",
+			r:                 newPublicLinkRepo(),
+			token:             "token",
+			target:            "/public/groups/token",
+			accept:            "application/atom+xml",
+			expectStatus:      200,
+			expectContentType: "application/atom+xml; charset=utf-8",
+		},
+		{
+			name:         "return not found for unknown token",
+			r:            newPublicLinkRepo(),
+			token:        "unknown",
+			target:       "/public/groups/unknown",
+			expectStatus: 404,
+			expectRes:    `{"code":"not_found","error":"record not found"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest("GET", test.target, nil)
+			req.Header.Set("Accept", test.accept)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("token", test.token)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			rr := httptest.NewRecorder()
+			publicGroupHandler(test.r).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Errorf("got code: %v; want: %v", rr.Code, test.expectStatus)
+			}
+
+			if test.expectContentType != "" {
+				if contentType := rr.Header().Get("Content-Type"); contentType != test.expectContentType {
+					t.Errorf("got content type: %v; want: %v", contentType, test.expectContentType)
+				}
+				if !strings.Contains(rr.Body.String(), "<entry>") || strings.Contains(rr.Body.String(), "secret") {
+					t.Errorf("got feed: %v", rr.Body.String())
+				}
+				return
+			}
+
+			if strings.Trim(rr.Body.String(), "\n") != test.expectRes {
+				t.Errorf("got res: %v; want: %v", rr.Body.String(), test.expectRes)
+			}
+		})
+	}
+}
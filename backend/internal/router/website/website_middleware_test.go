@@ -1,17 +1,327 @@
 package website
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/htchan/WebHistory/internal/auth"
+	"github.com/htchan/WebHistory/internal/config"
+	"github.com/htchan/WebHistory/internal/model"
+	"github.com/htchan/WebHistory/internal/repository"
+)
 
 func Test_writeError(t *testing.T) {
+	t.Parallel()
+
+	requestID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	tests := []struct {
+		name              string
+		err               error
+		accept            string
+		requestID         interface{}
+		expectStatus      int
+		expectContentType string
+		expectRes         string
+	}{
+		{
+			name:              "api error",
+			err:               RecordNotFoundError,
+			expectStatus:      404,
+			expectContentType: "application/json; charset=utf-8",
+			expectRes:         `{"code":"not_found","error":"record not found"}`,
+		},
+		{
+			name:              "known error of other package",
+			err:               fmt.Errorf("get user website fail: %w", repository.ErrNotFound),
+			requestID:         requestID,
+			expectStatus:      404,
+			expectContentType: "application/json; charset=utf-8",
+			expectRes:         `{"code":"not_found","error":"record not found","request_id":"00000000-0000-0000-0000-000000000001"}`,
+		},
+		{
+			name:              "duplicated record",
+			err:               fmt.Errorf("create user: %w", repository.ErrDuplicate),
+			expectStatus:      409,
+			expectContentType: "application/json; charset=utf-8",
+			expectRes:         `{"code":"duplicate","error":"record already exists"}`,
+		},
+		{
+			name:              "validation error",
+			err:               model.ErrInvalidGroupName,
+			expectStatus:      422,
+			expectContentType: "application/json; charset=utf-8",
+			expectRes:         `{"code":"invalid_group_name","error":"invalid group name","field":"group_name"}`,
+		},
+		{
+			name:              "hide unknown error",
+			err:               errors.New(`pq: relation "websites" does not exist`),
+			expectStatus:      500,
+			expectContentType: "application/json; charset=utf-8",
+			expectRes:         `{"code":"internal_error","error":"internal server error"}`,
+		},
+		{
+			name:              "problem details",
+			err:               InvalidParamsError,
+			accept:            "application/problem+json",
+			requestID:         requestID,
+			expectStatus:      422,
+			expectContentType: "application/problem+json",
+			expectRes:         `{"type":"about:blank","title":"Unprocessable Entity","status":422,"detail":"invalid params","instance":"/websites/1","code":"invalid_params","request_id":"00000000-0000-0000-0000-000000000001"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest("GET", "/websites/1", nil)
+			req.Header.Set("Accept", test.accept)
+			if test.requestID != nil {
+				req = req.WithContext(context.WithValue(req.Context(), ContextKeyReqID, test.requestID))
+			}
+			rr := httptest.NewRecorder()
 
+			writeError(rr, req, test.err)
+
+			if rr.Code != test.expectStatus {
+				t.Errorf("got code: %v; want: %v", rr.Code, test.expectStatus)
+			}
+			if contentType := rr.Header().Get("Content-Type"); contentType != test.expectContentType {
+				t.Errorf("got content type: %v; want: %v", contentType, test.expectContentType)
+			}
+			if res := strings.Trim(rr.Body.String(), "\n"); res != test.expectRes {
+				t.Errorf("got res: %v; want: %v", res, test.expectRes)
+			}
+		})
+	}
 }
 
 func Test_redirectLogin(t *testing.T) {
 
 }
 
+type stubAuthenticator map[string]string
+
+func (stub stubAuthenticator) Authenticate(ctx context.Context, token string) (string, error) {
+	if token == "outage" {
+		return "", fmt.Errorf("%w: connection refused", auth.ErrUnavailable)
+	}
+	if userUUID, ok := stub[token]; ok {
+		return userUUID, nil
+	}
+	return "", auth.ErrUnauthorized
+}
+
 func Test_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	authenticator := stubAuthenticator{"valid": "abc", "admin": "admin_uuid"}
+	roles := auth.NewRoles([]string{"admin_uuid"})
+	tests := []struct {
+		name         string
+		method       string
+		token        string
+		expectStatus int
+		expectUser   string
+		expectRole   auth.Role
+	}{
+		{name: "valid token", method: "GET", token: "valid", expectStatus: 200, expectUser: "abc", expectRole: auth.RoleUser},
+		{name: "admin token", method: "GET", token: "admin", expectStatus: 200, expectUser: "admin_uuid", expectRole: auth.RoleAdmin},
+		{name: "invalid token", method: "GET", token: "invalid", expectStatus: 401},
+		{name: "missing token", method: "GET", expectStatus: 401},
+		{name: "authentication service unavailable", method: "GET", token: "outage", expectStatus: 503},
+		{name: "options request skip authenticate", method: "OPTIONS", expectStatus: 200},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(test.method, "/websites", nil)
+			req.Header.Set("Authorization", test.token)
+			rr := httptest.NewRecorder()
 
+			var userUUID string
+			var role auth.Role
+			next := http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				userUUID, _ = req.Context().Value(ContextKeyUserUUID).(string)
+				role, _ = req.Context().Value(ContextKeyRole).(auth.Role)
+			})
+			AuthenticateMiddleware(authenticator, roles)(next).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Errorf("got code: %v; want: %v", rr.Code, test.expectStatus)
+			}
+			if userUUID != test.expectUser {
+				t.Errorf("got user uuid: %v; want: %v", userUUID, test.expectUser)
+			}
+			if role != test.expectRole {
+				t.Errorf("got role: %v; want: %v", role, test.expectRole)
+			}
+		})
+	}
+}
+
+func Test_RequireRole(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		method       string
+		role         interface{}
+		expectStatus int
+	}{
+		{name: "admin", method: "GET", role: auth.RoleAdmin, expectStatus: 200},
+		{name: "normal user", method: "GET", role: auth.RoleUser, expectStatus: 403},
+		{name: "no role", method: "GET", expectStatus: 403},
+		{name: "options request skip authorize", method: "OPTIONS", expectStatus: 200},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(test.method, "/admin/websites", nil)
+			if test.role != nil {
+				req = req.WithContext(context.WithValue(req.Context(), ContextKeyRole, test.role))
+			}
+			rr := httptest.NewRecorder()
+
+			next := http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {})
+			RequireRole(auth.RoleAdmin)(next).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Errorf("got code: %v; want: %v", rr.Code, test.expectStatus)
+			}
+		})
+	}
+}
+
+func Test_ShareModeParams(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		body         string
+		expectStatus int
+		expectMode   model.GroupShareMode
+	}{
+		{name: "default read only", expectStatus: 200, expectMode: model.GroupShareReadOnly},
+		{name: "collaborative", body: "mode=collaborative", expectStatus: 200, expectMode: model.GroupShareCollaborative},
+		{name: "invalid mode", body: "mode=unknown", expectStatus: 422},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest("POST", "/websites/groups/group/share", strings.NewReader(test.body))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			rr := httptest.NewRecorder()
+
+			var mode model.GroupShareMode
+			next := http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				mode = req.Context().Value(ContextKeyShareMode).(model.GroupShareMode)
+			})
+			ShareModeParams(next).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Errorf("got code: %v; want: %v", rr.Code, test.expectStatus)
+			}
+			if mode != test.expectMode {
+				t.Errorf("got mode: %v; want: %v", mode, test.expectMode)
+			}
+		})
+	}
+}
+
+func Test_RejectSyncedWebsite(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		web          model.UserWebsite
+		expectStatus int
+	}{
+		{
+			name:         "allow website added by user",
+			web:          model.UserWebsite{WebsiteUUID: "1", UserUUID: "sub", GroupName: "mirror"},
+			expectStatus: 200,
+		},
+		{
+			name:         "reject website synced from subscribed share",
+			web:          model.UserWebsite{WebsiteUUID: "1", UserUUID: "sub", GroupName: "mirror", ShareUUID: "share"},
+			expectStatus: 403,
+		},
+		{
+			name:         "allow website synced from unsubscribed share",
+			web:          model.UserWebsite{WebsiteUUID: "1", UserUUID: "sub", GroupName: "mirror", ShareUUID: "old share"},
+			expectStatus: 200,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			r := repository.NewInMemRepo(nil, nil, nil, nil)
+			r.CreateGroupShareSubscription(&model.GroupShareSubscription{ShareUUID: "share", UserUUID: "sub", GroupName: "mirror"})
+
+			req := httptest.NewRequest("DELETE", "/websites/1", nil)
+			req = req.WithContext(context.WithValue(req.Context(), ContextKeyWebsite, test.web))
+			rr := httptest.NewRecorder()
+
+			next := http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {})
+			RejectSyncedWebsite(r)(next).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Errorf("got code: %v; want: %v", rr.Code, test.expectStatus)
+			}
+		})
+	}
+}
+
+func Test_RejectSubscribedGroup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		groupName    string
+		expectStatus int
+	}{
+		{name: "allow group of user", groupName: "mine", expectStatus: 200},
+		{name: "reject subscribed group", groupName: "mirror", expectStatus: 403},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			r := repository.NewInMemRepo(nil, nil, nil, nil)
+			r.CreateGroupShareSubscription(&model.GroupShareSubscription{ShareUUID: "share", UserUUID: "sub", GroupName: "mirror"})
+
+			req := httptest.NewRequest("PUT", "/websites/groups/"+test.groupName, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("groupName", test.groupName)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, ContextKeyUserUUID, "sub")
+			rr := httptest.NewRecorder()
+
+			next := http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {})
+			RejectSubscribedGroup(r)(next).ServeHTTP(rr, req.WithContext(ctx))
+
+			if rr.Code != test.expectStatus {
+				t.Errorf("got code: %v; want: %v", rr.Code, test.expectStatus)
+			}
+		})
+	}
 }
 
 func Test_SetContentType(t *testing.T) {
@@ -19,15 +329,236 @@ func Test_SetContentType(t *testing.T) {
 }
 
 func Test_WebsiteParams(t *testing.T) {
+	t.Parallel()
+
+	conf := &config.WebsiteConfig{
+		AllowedURLSchemes: []string{"http", "https"},
+		URLAllowList:      []string{"example.com"},
+		URLDenyList:       []string{"*.internal"},
+	}
+	tests := []struct {
+		name         string
+		contentType  string
+		body         string
+		expectStatus int
+		expectURL    string
+		expectRes    string
+	}{
+		{
+			name:         "parse url of form body",
+			contentType:  "application/x-www-form-urlencoded",
+			body:         "url=https://example.com",
+			expectStatus: 200,
+			expectURL:    "https://example.com",
+		},
+		{
+			name:         "parse url of json body",
+			contentType:  "application/json",
+			body:         `{"url":" https://example.com "}`,
+			expectStatus: 200,
+			expectURL:    "https://example.com",
+		},
+		{
+			name:         "return invalid url if url is missing",
+			contentType:  "application/json",
+			body:         `{}`,
+			expectStatus: 422,
+			expectRes:    `{"code":"invalid_url","error":"url: invalid url","field":"url"}`,
+		},
+		{
+			name:         "return invalid url if scheme is not allowed",
+			contentType:  "application/json",
+			body:         `{"url":"ftp://example.com"}`,
+			expectStatus: 422,
+			expectRes:    `{"code":"invalid_url","error":"url: invalid url: scheme must be one of http, https","field":"url"}`,
+		},
+		{
+			name:         "return invalid url if host is missing",
+			contentType:  "application/x-www-form-urlencoded",
+			body:         "url=https:///path",
+			expectStatus: 422,
+			expectRes:    `{"code":"invalid_url","error":"url: invalid url: host is required","field":"url"}`,
+		},
+		{
+			name:         "return blocked url if host is loopback address",
+			contentType:  "application/json",
+			body:         `{"url":"http://127.0.0.1:8080/admin"}`,
+			expectStatus: 422,
+			expectRes:    `{"code":"blocked_url","error":"url: blocked url: host resolves to non-public address","field":"url"}`,
+		},
+		{
+			name:         "return blocked url if host is cloud metadata address",
+			contentType:  "application/x-www-form-urlencoded",
+			body:         "url=http://169.254.169.254/latest/meta-data",
+			expectStatus: 422,
+			expectRes:    `{"code":"blocked_url","error":"url: blocked url: host resolves to non-public address","field":"url"}`,
+		},
+		{
+			name:         "return blocked url if host is in deny list",
+			contentType:  "application/json",
+			body:         `{"url":"http://webhistory_backend.internal"}`,
+			expectStatus: 422,
+			expectRes:    `{"code":"blocked_url","error":"url: blocked url: host is denied","field":"url"}`,
+		},
+		{
+			name:         "return bad request if json is malformed",
+			contentType:  "application/json",
+			body:         `{"url"`,
+			expectStatus: 400,
+			expectRes:    `{"code":"bad_request","error":"malformed request"}`,
+		},
+	}
 
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest("POST", "/websites", strings.NewReader(test.body))
+			req.Header.Set("Content-Type", test.contentType)
+			rr := httptest.NewRecorder()
+
+			var webURL string
+			next := http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				webURL = req.Context().Value(ContextKeyWebURL).(string)
+			})
+			WebsiteParams(conf)(next).ServeHTTP(rr, req)
+
+			if rr.Code != test.expectStatus {
+				t.Errorf("got code: %v; want: %v", rr.Code, test.expectStatus)
+			}
+			if webURL != test.expectURL {
+				t.Errorf("got url: %v; want: %v", webURL, test.expectURL)
+			}
+			if res := strings.TrimSpace(rr.Body.String()); res != test.expectRes {
+				t.Errorf("got res: %v; want: %v", res, test.expectRes)
+			}
+		})
+	}
 }
 
 func Test_QueryWebsite(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		r            repository.Repostory
+		webUUID      string
+		expectStatus int
+	}{
+		{
+			name:         "found website",
+			r:            repository.NewInMemRepo(nil, []model.UserWebsite{{WebsiteUUID: "1", UserUUID: "abc"}}, nil, nil),
+			webUUID:      "1",
+			expectStatus: 200,
+		},
+		{
+			name:         "return not found for unknown website",
+			r:            repository.NewInMemRepo(nil, nil, nil, nil),
+			webUUID:      "unknown",
+			expectStatus: 404,
+		},
+	}
 
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest("GET", "/websites/"+test.webUUID, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("webUUID", test.webUUID)
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, ContextKeyUserUUID, "abc")
+			rr := httptest.NewRecorder()
+
+			next := http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {})
+			QueryWebsite(test.r)(next).ServeHTTP(rr, req.WithContext(ctx))
+
+			if rr.Code != test.expectStatus {
+				t.Errorf("got code: %v; want: %v", rr.Code, test.expectStatus)
+			}
+		})
+	}
 }
 
 func Test_GroupNameParams(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		contentType     string
+		body            string
+		expectStatus    int
+		expectGroupName string
+		expectRes       string
+	}{
+		{
+			name:            "parse group name of form body",
+			contentType:     "application/x-www-form-urlencoded",
+			body:            "group_name=%20comics%20",
+			expectStatus:    200,
+			expectGroupName: "comics",
+		},
+		{
+			name:            "parse group name of json body",
+			contentType:     "application/json",
+			body:            `{"group_name":"漫畫"}`,
+			expectStatus:    200,
+			expectGroupName: "漫畫",
+		},
+		{
+			name:         "allow empty group name",
+			contentType:  "application/json",
+			body:         `{}`,
+			expectStatus: 200,
+		},
+		{
+			name:         "return invalid group name if group name is too long",
+			contentType:  "application/json",
+			body:         `{"group_name":"` + strings.Repeat("a", model.MaxGroupNameLength+1) + `"}`,
+			expectStatus: 422,
+			expectRes:    `{"code":"invalid_group_name","error":"group_name: invalid group name: longer than 255 characters","field":"group_name"}`,
+		},
+		{
+			name:         "return invalid group name if group name contains control character",
+			contentType:  "application/json",
+			body:         `{"group_name":"a\u0007b"}`,
+			expectStatus: 422,
+			expectRes:    `{"code":"invalid_group_name","error":"group_name: invalid group name: contains unprintable character","field":"group_name"}`,
+		},
+		{
+			name:         "return invalid params if group name is not string",
+			contentType:  "application/json",
+			body:         `{"group_name":{}}`,
+			expectStatus: 422,
+			expectRes:    `{"code":"invalid_params","error":"group_name: value must be string, number, boolean or array of them","field":"group_name"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest("PUT", "/websites/groups/group", strings.NewReader(test.body))
+			req.Header.Set("Content-Type", test.contentType)
+			rr := httptest.NewRecorder()
+
+			var groupName string
+			next := http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				groupName = req.Context().Value(ContextKeyGroup).(string)
+			})
+			GroupNameParams(next).ServeHTTP(rr, req)
 
+			if rr.Code != test.expectStatus {
+				t.Errorf("got code: %v; want: %v", rr.Code, test.expectStatus)
+			}
+			if groupName != test.expectGroupName {
+				t.Errorf("got group name: %v; want: %v", groupName, test.expectGroupName)
+			}
+			if res := strings.TrimSpace(rr.Body.String()); res != test.expectRes {
+				t.Errorf("got res: %v; want: %v", res, test.expectRes)
+			}
+		})
+	}
 }
 
 func Test_validGroupName(t *testing.T) {
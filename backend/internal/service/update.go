@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -12,11 +13,15 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/htchan/WebHistory/internal/config"
+	"github.com/htchan/WebHistory/internal/events"
+	"github.com/htchan/WebHistory/internal/metrics"
 	"github.com/htchan/WebHistory/internal/model"
 	"github.com/htchan/WebHistory/internal/repository"
+	"github.com/htchan/WebHistory/internal/telemetry"
 	"github.com/rs/zerolog"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -28,11 +33,55 @@ type HTTPClient interface {
 	Get(string) (*http.Response, error)
 }
 
-var client HTTPClient = &http.Client{
-	Timeout: 30 * time.Second,
-	Transport: &http.Transport{
-		DisableKeepAlives: true,
-	},
+var client HTTPClient = newHTTPClient(NewURLPolicy(&config.WebsiteConfig{}))
+
+func newHTTPClient(policy *URLPolicy) *http.Client {
+	return &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			DisableKeepAlives: true,
+			DialContext:       policy.DialContext,
+		},
+	}
+}
+
+// SetURLPolicy sets the policy checked when connecting to website, websites
+// of private address are blocked by default
+func SetURLPolicy(policy *URLPolicy) {
+	client = newHTTPClient(policy)
+}
+
+type ResponseArchive interface {
+	Save(ctx context.Context, domain string, body []byte) (string, error)
+}
+
+// responseArchive keeps fetched responses for replaying parse, it is disabled if nil
+var responseArchive ResponseArchive
+
+func SetResponseArchive(archive ResponseArchive) {
+	responseArchive = archive
+}
+
+// eventPublisher notifies api about website changes, it is disabled if nil
+var eventPublisher events.Publisher
+
+func SetEventPublisher(publisher events.Publisher) {
+	eventPublisher = publisher
+}
+
+func publishEvent(ctx context.Context, web *model.Website, eventType events.Type) {
+	if eventPublisher == nil || web.UUID == "" {
+		return
+	}
+
+	event := events.NewEvent(eventType, web.UUID)
+	if eventType == events.WebsiteHealthChanged {
+		event.Health = web.Health
+	}
+
+	if err := eventPublisher.Publish(ctx, event); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Str("event", string(eventType)).Msg("publish website event failed")
+	}
 }
 
 func pruneResponse(resp *http.Response, conf *config.WebsiteConfig) string {
@@ -57,6 +106,15 @@ func pruneResponse(resp *http.Response, conf *config.WebsiteConfig) string {
 	return bodyStr
 }
 
+func hostname(web *model.Website) string {
+	u, err := url.Parse(web.URL)
+	if err != nil {
+		return ""
+	}
+
+	return u.Hostname()
+}
+
 func getWebsiteSetting(r repository.Repostory, web *model.Website) (*model.WebsiteSetting, error) {
 	u, err := url.Parse(web.URL)
 	if err != nil {
@@ -71,12 +129,85 @@ func getWebsiteSetting(r repository.Repostory, web *model.Website) (*model.Websi
 	return r.FindWebsiteSetting("default")
 }
 
-func parseAPI(r repository.Repostory, web *model.Website, resp string) (string, []string) {
+// SupportedHost reports if there is website setting to parse website with
+func SupportedHost(r repository.Repostory, web *model.Website) bool {
+	_, err := getWebsiteSetting(r, web)
+	return err == nil
+}
+
+// CreatePendingWebsite creates website of url, or returns the existing one
+// of same url. New website is created pending without title and update
+// time, and is queued to be fetched by worker
+func CreatePendingWebsite(ctx context.Context, r repository.Repostory, conf *config.WebsiteConfig, url string) (model.Website, error) {
+	web := model.NewWebsite(url, conf)
+	web.UpdateTime = time.Time{}
+	web.Health = model.WebsitePending
+
+	err := r.CreateWebsite(&web)
+	if err != nil {
+		return web, err
+	}
+
+	if web.Health == model.WebsitePending {
+		updateReq := model.NewWebsiteUpdateRequest(web.UUID, telemetry.InjectTraceContext(ctx))
+		err = r.CreateWebsiteUpdateRequest(&updateReq)
+	}
+
+	return web, err
+}
+
+// recordParseResult counts the parse result against website setting, and
+// alerts once the setting is flagged broken or recovered. The count is
+// updated in database, so that concurrent workers do not lose any result
+func recordParseResult(ctx context.Context, r repository.Repostory, web *model.Website, setting *model.WebsiteSetting, success bool) {
+	if web.Conf == nil {
+		return
+	}
+
+	// nothing to record if setting has no failure to reset
+	if success && setting.ConsecutiveFailures == 0 && !setting.IsBroken() {
+		return
+	}
+
+	updated, wasBroken, err := r.RecordWebsiteSettingParse(
+		setting.Domain, success, web.Conf.BreakageThreshold, time.Now().UTC().Truncate(time.Second),
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Str("domain", setting.Domain).Msg("fail to update website setting")
+		return
+	}
+
+	if updated.IsBroken() {
+		metrics.WebsiteSettingBroken.WithLabelValues(setting.Domain).Set(1)
+	} else {
+		metrics.WebsiteSettingBroken.WithLabelValues(setting.Domain).Set(0)
+	}
+
+	if !wasBroken && updated.IsBroken() {
+		zerolog.Ctx(ctx).Error().
+			Str("domain", setting.Domain).
+			Int("consecutive failures", updated.ConsecutiveFailures).
+			Str("url", web.URL).
+			Msg("website setting broken")
+	} else if wasBroken && !updated.IsBroken() {
+		zerolog.Ctx(ctx).Info().Str("domain", setting.Domain).Msg("website setting recovered")
+	}
+}
+
+func parseAPI(ctx context.Context, r repository.Repostory, web *model.Website, resp string) (string, []string, []model.WebsiteItem) {
 	setting, err := getWebsiteSetting(r, web)
 	if err != nil {
-		return "", nil
+		return "", nil, nil
 	}
-	return setting.Parse(resp)
+
+	title, dates := setting.Parse(resp)
+	success := title != "" && len(dates) > 0
+	if !success {
+		metrics.WebsiteParseFailuresTotal.WithLabelValues(setting.Domain).Inc()
+	}
+	recordParseResult(ctx, r, web, setting, success)
+
+	return title, dates, setting.ParseItems(resp, web.URL)
 }
 
 func fetchWebsite(ctx context.Context, web *model.Website, maxRetry int, retryInterval time.Duration) (string, error) {
@@ -89,13 +220,23 @@ func fetchWebsite(ctx context.Context, web *model.Website, maxRetry int, retryIn
 		err  error
 	)
 	for i := 0; i < maxRetry; i++ {
+		start := time.Now()
 		resp, err = client.Get(web.URL)
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		metrics.ObserveFetch(hostname(web), statusCode, time.Since(start))
+
 		if err != nil {
 			zerolog.Ctx(ctx).Warn().
 				Err(err).
 				Int("trial", i).
 				Str("url", web.URL).
 				Msg("fail to fetch website")
+			if errors.Is(err, ErrBlockedURL) {
+				break
+			}
 			time.Sleep(retryInterval)
 		} else {
 			break
@@ -112,9 +253,18 @@ func fetchWebsite(ctx context.Context, web *model.Website, maxRetry int, retryIn
 
 	// body := pruneResponse(resp, web.Conf)
 	data, _ := io.ReadAll(resp.Body)
-	body := string(data)
-	span.SetAttributes(attribute.String("raw response", body))
-	return body, nil
+	span.SetAttributes(attribute.Int("body size", len(data)))
+
+	if responseArchive != nil {
+		key, err := responseArchive.Save(ctx, hostname(web), data)
+		if err != nil {
+			zerolog.Ctx(ctx).Warn().Err(err).Str("url", web.URL).Msg("fail to archive response")
+		} else {
+			span.SetAttributes(attribute.String("archive key", key))
+		}
+	}
+
+	return string(data), nil
 }
 
 func checkTimeUpdated(ctx context.Context, web *model.Website, timeStr string) bool {
@@ -148,6 +298,7 @@ func checkContentUpdated(ctx context.Context, web *model.Website, content []stri
 	)
 
 	if len(content) > 0 && !cmp.Equal(web.Content(), content) {
+		web.PreviousRawContent = web.RawContent
 		web.RawContent = strings.Join(content, web.Conf.Separator)
 		web.UpdateTime = time.Now().UTC().Truncate(time.Second)
 		return true
@@ -174,25 +325,82 @@ func checkTitleUpdated(ctx context.Context, web *model.Website, title string) bo
 	return false
 }
 
-func checkWeb(ctx context.Context, r repository.Repostory, web *model.Website, title string, content []string) {
+func checkItemsUpdated(ctx context.Context, web *model.Website, items []model.WebsiteItem) bool {
+	tr := otel.Tracer("htchan/WebHistory/update-jobs")
+	_, span := tr.Start(ctx, "Check Items")
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int("old items", len(web.Items)),
+		attribute.Int("new items", len(items)),
+	)
+
+	if len(items) == 0 {
+		return false
+	}
+
+	return web.MergeItems(items, time.Now().UTC().Truncate(time.Second))
+}
+
+// checkHealthUpdated records whether website is fetched and parsed successfully
+func checkHealthUpdated(ctx context.Context, web *model.Website, healthy bool) bool {
+	health := model.WebsiteUnhealthy
+	if healthy {
+		health = model.WebsiteHealthy
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("health", health))
+
+	if web.Health == health {
+		return false
+	}
+
+	web.Health = health
+	return true
+}
+
+func checkWeb(ctx context.Context, r repository.Repostory, web *model.Website, title string, content []string, items []model.WebsiteItem) {
 	tr := otel.Tracer("htchan/WebHistory/update-jobs")
 	ctx, span := tr.Start(ctx, "Checking")
 	defer span.End()
 
 	titleUpdated := checkTitleUpdated(ctx, web, title)
 	contentUpadted := checkContentUpdated(ctx, web, content)
+	itemsUpdated := checkItemsUpdated(ctx, web, items)
+	healthUpdated := checkHealthUpdated(ctx, web, title != "" && len(content) > 0)
 	span.SetAttributes(
 		attribute.Bool("title updated", titleUpdated),
 		attribute.Bool("content updated", contentUpadted),
+		attribute.Bool("items updated", itemsUpdated),
+		attribute.Bool("health updated", healthUpdated),
 	)
 
 	if titleUpdated || contentUpadted {
+		metrics.WebsiteUpdatesTotal.WithLabelValues(hostname(web)).Inc()
+	}
+
+	if titleUpdated || contentUpadted || itemsUpdated || healthUpdated {
 		_, span = tr.Start(ctx, "Updated")
 		defer span.End()
 
 		err := r.UpdateWebsite(web)
 		if err != nil {
 			span.SetAttributes(attribute.String("error", err.Error()))
+			return
+		}
+
+		// websites added before the first fetch are grouped by placeholder
+		if titleUpdated {
+			err = r.RenameWebsitePlaceholderGroups(web.UUID, web.PlaceholderGroupName(), web.Title)
+			if err != nil {
+				zerolog.Ctx(ctx).Warn().Err(err).Str("website_uuid", web.UUID).Msg("fail to rename placeholder groups")
+			}
+		}
+
+		if titleUpdated || contentUpadted || itemsUpdated {
+			publishEvent(ctx, web, events.WebsiteUpdated)
+		}
+		if healthUpdated {
+			publishEvent(ctx, web, events.WebsiteHealthChanged)
 		}
 	}
 }
@@ -200,11 +408,16 @@ func checkWeb(ctx context.Context, r repository.Repostory, web *model.Website, t
 func Update(ctx context.Context, r repository.Repostory, web *model.Website) error {
 	content, err := fetchWebsite(ctx, web, MaxRetryCount, RetryInterval)
 	if err != nil {
+		if checkHealthUpdated(ctx, web, false) && web.UUID != "" {
+			if r.UpdateWebsite(web) == nil {
+				publishEvent(ctx, web, events.WebsiteHealthChanged)
+			}
+		}
 		return err
 	}
 
-	title, dates := parseAPI(r, web, content)
-	checkWeb(ctx, r, web, title, dates)
+	title, dates, items := parseAPI(ctx, r, web, content)
+	checkWeb(ctx, r, web, title, dates, items)
 
 	return nil
 }
@@ -14,6 +14,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/htchan/WebHistory/internal/config"
+	"github.com/htchan/WebHistory/internal/events"
 	"github.com/htchan/WebHistory/internal/model"
 	"github.com/htchan/WebHistory/internal/repository"
 )
@@ -128,38 +129,151 @@ func Test_getWebsiteSetting(t *testing.T) {
 	}
 }
 
+func TestSupportedHost(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		r      repository.Repostory
+		web    *model.Website
+		expect bool
+	}{
+		{
+			name: "host with setting",
+			r: repository.NewInMemRepo(
+				nil, nil,
+				[]model.WebsiteSetting{{Domain: "hello"}}, nil,
+			),
+			web:    &model.Website{URL: "https://hello/data"},
+			expect: true,
+		},
+		{
+			name: "host without setting",
+			r: repository.NewInMemRepo(
+				nil, nil,
+				[]model.WebsiteSetting{{Domain: "world"}}, nil,
+			),
+			web:    &model.Website{URL: "https://hello/data"},
+			expect: false,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if result := SupportedHost(test.r, test.web); result != test.expect {
+				t.Errorf("got: %v; want: %v", result, test.expect)
+			}
+		})
+	}
+}
+
 func Test_parseAPI(t *testing.T) {
 	t.Parallel()
 	setting := model.WebsiteSetting{Domain: "hello", TitleGoquerySelector: "head>title", DatesGoquerySelector: "dates>date"}
+	conf := &config.WebsiteConfig{Separator: "\n", MaxDateLength: 2, BreakageThreshold: 3}
+	brokenTime := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
 
 	tests := []struct {
-		name          string
-		r             repository.Repostory
-		web           *model.Website
-		resp          string
-		expectTitle   string
-		expectContent []string
+		name           string
+		r              repository.Repostory
+		web            *model.Website
+		resp           string
+		expectTitle    string
+		expectContent  []string
+		expectItems    []model.WebsiteItem
+		expectFailures int
+		expectBroken   bool
 	}{
 		{
 			name: "works with selector",
 			r: repository.NewInMemRepo(nil, nil, []model.WebsiteSetting{
 				setting,
 			}, nil),
-			web: &model.Website{URL: "http://hello/data"},
+			web: &model.Website{URL: "http://hello/data", Conf: conf},
 			resp: `<html><head>
 			<title>title-1</title>
 			<dates><date>date-1</date><date>date-2</date><date>date-3</date><date>date-4</date></dates>
 			</head></html>`,
+			expectTitle:    "title-1",
+			expectContent:  []string{"date-1", "date-2", "date-3", "date-4"},
+			expectFailures: 0,
+			expectBroken:   false,
+		},
+		{
+			name: "count failure if nothing parsed",
+			r: repository.NewInMemRepo(nil, nil, []model.WebsiteSetting{
+				setting,
+			}, nil),
+			web:            &model.Website{URL: "http://hello/data", Conf: conf},
+			resp:           `<html><head></head></html>`,
+			expectTitle:    "",
+			expectContent:  nil,
+			expectFailures: 1,
+			expectBroken:   false,
+		},
+		{
+			name: "flag setting broken if failures reach threshold",
+			r: repository.NewInMemRepo(nil, nil, []model.WebsiteSetting{
+				{Domain: "hello", TitleGoquerySelector: "head>title", DatesGoquerySelector: "dates>date", ConsecutiveFailures: 2},
+			}, nil),
+			web:            &model.Website{URL: "http://hello/data", Conf: conf},
+			resp:           `<html><head><title>title-1</title></head></html>`,
+			expectTitle:    "title-1",
+			expectContent:  nil,
+			expectFailures: 3,
+			expectBroken:   true,
+		},
+		{
+			name: "recover broken setting if parse success",
+			r: repository.NewInMemRepo(nil, nil, []model.WebsiteSetting{
+				{Domain: "hello", TitleGoquerySelector: "head>title", DatesGoquerySelector: "dates>date", ConsecutiveFailures: 5, BrokenAt: brokenTime},
+			}, nil),
+			web: &model.Website{URL: "http://hello/data", Conf: conf},
+			resp: `<html><head>
+			<title>title-1</title>
+			<dates><date>date-1</date></dates>
+			</head></html>`,
+			expectTitle:    "title-1",
+			expectContent:  []string{"date-1"},
+			expectFailures: 0,
+			expectBroken:   false,
+		},
+		{
+			name: "parse items with item selectors",
+			r: repository.NewInMemRepo(nil, nil, []model.WebsiteSetting{
+				{
+					Domain:                  "hello",
+					TitleGoquerySelector:    "head>title",
+					DatesGoquerySelector:    "dates>date",
+					ItemNameGoquerySelector: "items>a",
+					ItemLinkGoquerySelector: "items>a",
+				},
+			}, nil),
+			web: &model.Website{URL: "http://hello/book/data", Conf: conf},
+			resp: `<html><head>
+			<title>title-1</title>
+			<dates><date>date-1</date><date>date-2</date></dates>
+			<items><a href="chapter-2">chapter 2</a><a href="/chapter-1">chapter 1</a></items>
+			</head></html>`,
 			expectTitle:   "title-1",
-			expectContent: []string{"date-1", "date-2", "date-3", "date-4"},
+			expectContent: []string{"date-1", "date-2"},
+			expectItems: []model.WebsiteItem{
+				{Name: "chapter 2", Link: "http://hello/book/chapter-2", Date: "date-1"},
+				{Name: "chapter 1", Link: "http://hello/chapter-1", Date: "date-2"},
+			},
+			expectFailures: 0,
+			expectBroken:   false,
 		},
 	}
 
 	for _, test := range tests {
+		test := test
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
 
-			title, content := parseAPI(test.r, test.web, test.resp)
+			title, content, items := parseAPI(context.Background(), test.r, test.web, test.resp)
 
 			if title != test.expectTitle {
 				t.Errorf("got title: %v; want title: %v", title, test.expectTitle)
@@ -167,6 +281,20 @@ func Test_parseAPI(t *testing.T) {
 			if !cmp.Equal(content, test.expectContent) {
 				t.Errorf("content diff: %v", cmp.Diff(content, test.expectContent))
 			}
+			if !cmp.Equal(items, test.expectItems) {
+				t.Errorf("items diff: %v", cmp.Diff(items, test.expectItems))
+			}
+
+			setting, err := test.r.FindWebsiteSetting("hello")
+			if err != nil {
+				t.Fatalf("find website setting fail: %v", err)
+			}
+			if setting.ConsecutiveFailures != test.expectFailures {
+				t.Errorf("got failures: %v; want failures: %v", setting.ConsecutiveFailures, test.expectFailures)
+			}
+			if setting.IsBroken() != test.expectBroken {
+				t.Errorf("got broken: %v; want broken: %v", setting.IsBroken(), test.expectBroken)
+			}
 		})
 	}
 }
@@ -233,6 +361,70 @@ func Test_fetchWebsite(t *testing.T) {
 	}
 }
 
+type MockArchive struct {
+	save func(context.Context, string, []byte) (string, error)
+}
+
+func (m MockArchive) Save(ctx context.Context, domain string, body []byte) (string, error) {
+	return m.save(ctx, domain, body)
+}
+
+func Test_fetchWebsite_archiveResponse(t *testing.T) {
+	client = MockClient{
+		get: func(url string) (*http.Response, error) {
+			return &http.Response{Body: io.NopCloser(strings.NewReader("response"))}, nil
+		},
+	}
+
+	var savedDomain, savedBody string
+	SetResponseArchive(MockArchive{save: func(ctx context.Context, domain string, body []byte) (string, error) {
+		savedDomain, savedBody = domain, string(body)
+		return "key", nil
+	}})
+	t.Cleanup(func() { SetResponseArchive(nil) })
+
+	resp, err := fetchWebsite(context.Background(), &model.Website{URL: "http://hello.com/data"}, 1, 0)
+	if err != nil {
+		t.Errorf("got error: %v", err)
+	}
+
+	if resp != "response" {
+		t.Errorf("got resp: %v; want resp: %v", resp, "response")
+	}
+
+	if savedDomain != "hello.com" || savedBody != "response" {
+		t.Errorf("got archived domain: %v, body: %v", savedDomain, savedBody)
+	}
+}
+
+func Test_checkWeb_publishEvents(t *testing.T) {
+	pubsub := events.NewInMemPubSub()
+	SetEventPublisher(pubsub)
+	t.Cleanup(func() { SetEventPublisher(nil) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	eventChan, _ := pubsub.Subscribe(ctx)
+
+	web := model.Website{UUID: "uuid", URL: "http://hello.com/data", Conf: &config.WebsiteConfig{Separator: "\n"}}
+	r := repository.NewInMemRepo([]model.Website{web}, nil, nil, nil)
+	checkWeb(context.Background(), r, &web, "title", []string{"content"}, nil)
+	cancel()
+
+	var got []events.Type
+	for event := range eventChan {
+		if event.WebsiteUUID != "uuid" {
+			t.Errorf("got event of website: %v", event.WebsiteUUID)
+		}
+		got = append(got, event.Type)
+	}
+
+	want := []events.Type{events.WebsiteUpdated, events.WebsiteHealthChanged}
+	if !cmp.Equal(got, want) {
+		t.Errorf("got events: %v; want: %v", got, want)
+	}
+}
+
 func Test_checkTimeUpdated(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -373,6 +565,56 @@ func Test_checkTitleUpdated(t *testing.T) {
 	}
 }
 
+func Test_checkHealthUpdated(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		web          model.Website
+		healthy      bool
+		expect       bool
+		expectHealth string
+	}{
+		{
+			name:         "healthy for unchecked website",
+			web:          model.Website{},
+			healthy:      true,
+			expect:       true,
+			expectHealth: model.WebsiteHealthy,
+		},
+		{
+			name:         "unhealthy for healthy website",
+			web:          model.Website{Health: model.WebsiteHealthy},
+			healthy:      false,
+			expect:       true,
+			expectHealth: model.WebsiteUnhealthy,
+		},
+		{
+			name:         "same health",
+			web:          model.Website{Health: model.WebsiteHealthy},
+			healthy:      true,
+			expect:       false,
+			expectHealth: model.WebsiteHealthy,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			result := checkHealthUpdated(context.Background(), &test.web, test.healthy)
+			if result != test.expect {
+				t.Errorf("got different result as expect")
+				t.Error(result)
+				t.Error(test.expect)
+			}
+			if test.web.Health != test.expectHealth {
+				t.Errorf("got health: %s; want: %s", test.web.Health, test.expectHealth)
+			}
+		})
+	}
+}
+
 type MockClient struct {
 	get func(string) (*http.Response, error)
 }
@@ -419,7 +661,7 @@ func Test_Update(t *testing.T) {
 			mockClient: MockClient{get: func(s string) (*http.Response, error) {
 				return &http.Response{Body: io.NopCloser(strings.NewReader(mockRespWithoutDates))}, nil
 			}},
-			expectWeb: model.Website{UUID: "uuid", URL: "http://domain", Title: "original title"},
+			expectWeb: model.Website{UUID: "uuid", URL: "http://domain", Title: "original title", Health: model.WebsiteUnhealthy},
 		},
 		{
 			name: "updated title of web not having title",
@@ -436,6 +678,7 @@ func Test_Update(t *testing.T) {
 			expectWeb: model.Website{
 				UUID: "uuid", URL: "http://domain", Title: "new title",
 				UpdateTime: time.Now().UTC().Truncate(time.Second),
+				Health:     model.WebsiteUnhealthy,
 			},
 		},
 		{
@@ -452,8 +695,10 @@ func Test_Update(t *testing.T) {
 			}},
 			expectWeb: model.Website{
 				UUID: "uuid", URL: "http://domain", Title: "new title",
-				RawContent: "date-1,date-2,date-3,date-4",
-				UpdateTime: time.Now().UTC().Truncate(time.Second),
+				RawContent:         "date-1,date-2,date-3,date-4",
+				PreviousRawContent: "date-1,date-2",
+				UpdateTime:         time.Now().UTC().Truncate(time.Second),
+				Health:             model.WebsiteHealthy,
 			},
 		},
 		{
@@ -470,8 +715,10 @@ func Test_Update(t *testing.T) {
 			}},
 			expectWeb: model.Website{
 				UUID: "uuid", URL: "http://domain", Title: "new title",
-				RawContent: "date-1,date-2,date-3,date-4",
-				UpdateTime: time.Now().UTC().Truncate(time.Second),
+				RawContent:         "date-1,date-2,date-3,date-4",
+				PreviousRawContent: "11-1-1,22-2-2",
+				UpdateTime:         time.Now().UTC().Truncate(time.Second),
+				Health:             model.WebsiteHealthy,
 			},
 		},
 	}
@@ -503,3 +750,42 @@ func Test_Update(t *testing.T) {
 		})
 	}
 }
+
+func Test_Update_renamePlaceholderGroups(t *testing.T) {
+	conf := &config.WebsiteConfig{Separator: ","}
+	r := repository.NewInMemRepo(nil, nil, []model.WebsiteSetting{
+		{Domain: "domain", TitleGoquerySelector: "head>title", DatesGoquerySelector: "dates>date"},
+	}, nil)
+
+	web, err := CreatePendingWebsite(context.Background(), r, conf, "http://domain")
+	if err != nil {
+		t.Fatalf("create website fail: %v", err)
+	}
+	for _, userWeb := range []model.UserWebsite{
+		model.NewUserWebsite(web, "placeholder"),
+		{WebsiteUUID: web.UUID, UserUUID: "grouped", GroupName: "group", Website: web},
+	} {
+		userWeb := userWeb
+		if err := r.CreateUserWebsite(&userWeb); err != nil {
+			t.Fatalf("create user website fail: %v", err)
+		}
+	}
+
+	client = MockClient{get: func(s string) (*http.Response, error) {
+		return &http.Response{Body: io.NopCloser(strings.NewReader(
+			`<html><head><title>new title</title><dates><date>date-1</date></dates></head></html>`,
+		))}, nil
+	}}
+	if err := Update(context.Background(), r, &web); err != nil {
+		t.Fatalf("update fail: %v", err)
+	}
+
+	for userUUID, expect := range map[string]string{"placeholder": "new title", "grouped": "group"} {
+		userWeb, err := r.FindUserWebsite(userUUID, web.UUID)
+		if err != nil {
+			t.Errorf("find user website of %s fail: %v", userUUID, err)
+		} else if userWeb.GroupName != expect {
+			t.Errorf("got group name of %s: %v; want: %v", userUUID, userWeb.GroupName, expect)
+		}
+	}
+}
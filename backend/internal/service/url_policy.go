@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/htchan/WebHistory/internal/config"
+)
+
+var ErrBlockedURL = errors.New("blocked url")
+
+// reservedNets are not routed to public internet but are not covered by
+// the checks of net.IP
+var reservedNets = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),
+	mustParseCIDR("100.64.0.0/10"),
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, ipNet, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return ipNet
+}
+
+// publicIP reports if ip is a public unicast address, private, loopback,
+// link-local and other internal addresses are not public
+func publicIP(ip net.IP) bool {
+	if ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+
+	for _, ipNet := range reservedNets {
+		if ipNet.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func normalizeHost(host string) string {
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
+
+// hostList matches host by exact name, by domain suffix if entry starts
+// with "*." or ".", or by address if entry is ip or cidr
+type hostList struct {
+	hosts    []string
+	suffixes []string
+	nets     []*net.IPNet
+}
+
+func newHostList(entries []string) hostList {
+	var list hostList
+	for _, entry := range entries {
+		entry = normalizeHost(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+
+		if _, ipNet, err := net.ParseCIDR(entry); err == nil {
+			list.nets = append(list.nets, ipNet)
+		} else if ip := net.ParseIP(entry); ip != nil {
+			if ip4 := ip.To4(); ip4 != nil {
+				ip = ip4
+			}
+			list.nets = append(list.nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+		} else if strings.HasPrefix(entry, "*.") || strings.HasPrefix(entry, ".") {
+			list.suffixes = append(list.suffixes, "."+strings.TrimLeft(entry, "*."))
+		} else {
+			list.hosts = append(list.hosts, entry)
+		}
+	}
+
+	return list
+}
+
+func (list hostList) matchHost(host string) bool {
+	host = normalizeHost(host)
+	if ip := net.ParseIP(host); ip != nil {
+		return list.matchIP(ip)
+	}
+
+	for _, entry := range list.hosts {
+		if host == entry {
+			return true
+		}
+	}
+	for _, suffix := range list.suffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (list hostList) matchIP(ip net.IP) bool {
+	for _, ipNet := range list.nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// defaultLookupTimeout limits the time of resolving host, so that slow dns
+// server does not hold the caller
+const defaultLookupTimeout = 5 * time.Second
+
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// URLPolicy decides which url is allowed to be fetched. Host is allowed if
+// all of its addresses are public. Host or address in deny list is always
+// blocked, while host or address in allow list skips the public address
+// check, e.g. for website hosted in intranet
+type URLPolicy struct {
+	allow         hostList
+	deny          hostList
+	resolver      Resolver
+	lookupTimeout time.Duration
+}
+
+func NewURLPolicy(conf *config.WebsiteConfig) *URLPolicy {
+	return &URLPolicy{
+		allow:         newHostList(conf.URLAllowList),
+		deny:          newHostList(conf.URLDenyList),
+		resolver:      net.DefaultResolver,
+		lookupTimeout: defaultLookupTimeout,
+	}
+}
+
+// Check resolves host of rawURL and checks if host and all of its addresses
+// are allowed
+func (policy *URLPolicy) Check(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: malformed url", ErrBlockedURL)
+	}
+
+	host := normalizeHost(u.Hostname())
+	if policy.deny.matchHost(host) {
+		return fmt.Errorf("%w: host is denied", ErrBlockedURL)
+	}
+	if policy.allow.matchHost(host) {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return policy.checkIP(ip)
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, policy.lookupTimeout)
+	defer cancel()
+
+	addrs, err := policy.resolver.LookupIPAddr(lookupCtx, host)
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("%w: cannot resolve host", ErrBlockedURL)
+	}
+	for _, addr := range addrs {
+		if err := policy.checkIP(addr.IP); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (policy *URLPolicy) checkIP(ip net.IP) error {
+	if policy.deny.matchIP(ip) {
+		return fmt.Errorf("%w: address is denied", ErrBlockedURL)
+	}
+	if policy.allow.matchIP(ip) || publicIP(ip) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: host resolves to non-public address", ErrBlockedURL)
+}
+
+// DialContext dials addr, the resolved address is checked right before
+// connecting, so that host passed Check cannot be rebound to blocked address
+func (policy *URLPolicy) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if policy.deny.matchHost(host) {
+		return nil, fmt.Errorf("%w: host is denied", ErrBlockedURL)
+	}
+
+	dialer := net.Dialer{Timeout: 30 * time.Second}
+	if !policy.allow.matchHost(host) {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			ip := net.ParseIP(host)
+			if ip == nil {
+				return fmt.Errorf("%w: unknown address %s", ErrBlockedURL, address)
+			}
+
+			return policy.checkIP(ip)
+		}
+	}
+
+	return dialer.DialContext(ctx, network, addr)
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/htchan/WebHistory/internal/config"
+	"github.com/htchan/WebHistory/internal/model"
+)
+
+type MockResolver map[string][]string
+
+func (m MockResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	ips, ok := m[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+
+	addrs := make([]net.IPAddr, len(ips))
+	for i, ip := range ips {
+		addrs[i] = net.IPAddr{IP: net.ParseIP(ip)}
+	}
+
+	return addrs, nil
+}
+
+func Test_publicIP(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		ip     string
+		expect bool
+	}{
+		{ip: "93.184.216.34", expect: true},
+		{ip: "2606:2800:220:1:248:1893:25c8:1946", expect: true},
+		{ip: "127.0.0.1", expect: false},
+		{ip: "::1", expect: false},
+		{ip: "10.0.0.1", expect: false},
+		{ip: "172.16.0.1", expect: false},
+		{ip: "192.168.1.1", expect: false},
+		{ip: "fd00::1", expect: false},
+		{ip: "169.254.169.254", expect: false},
+		{ip: "fe80::1", expect: false},
+		{ip: "0.0.0.0", expect: false},
+		{ip: "0.1.2.3", expect: false},
+		{ip: "100.64.0.1", expect: false},
+		{ip: "224.0.0.1", expect: false},
+		{ip: "::ffff:127.0.0.1", expect: false},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.ip, func(t *testing.T) {
+			t.Parallel()
+			if result := publicIP(net.ParseIP(test.ip)); result != test.expect {
+				t.Errorf("got: %v; want: %v", result, test.expect)
+			}
+		})
+	}
+}
+
+func TestURLPolicy_Check(t *testing.T) {
+	t.Parallel()
+	resolver := MockResolver{
+		"example.com":        {"93.184.216.34"},
+		"localhost":          {"127.0.0.1"},
+		"webhistory_backend": {"172.18.0.3"},
+		"mixed.example.com":  {"93.184.216.34", "10.0.0.1"},
+		"intranet.local":     {"192.168.1.10"},
+		"blog.intranet.corp": {"192.168.1.11"},
+		"nas.local":          {"192.168.1.12"},
+	}
+	conf := &config.WebsiteConfig{
+		URLAllowList: []string{"intranet.local", "*.intranet.corp", "192.168.1.12", "10.1.0.0/16"},
+		URLDenyList:  []string{"*.example.com", "93.184.216.35"},
+	}
+	tests := []struct {
+		name      string
+		url       string
+		expectErr error
+	}{
+		{name: "allow public host", url: "https://example.com/path"},
+		{name: "allow public ip", url: "http://93.184.216.34"},
+		{name: "block localhost", url: "http://localhost:8080", expectErr: ErrBlockedURL},
+		{name: "block loopback ip", url: "http://127.0.0.1", expectErr: ErrBlockedURL},
+		{name: "block loopback ipv6", url: "http://[::1]/", expectErr: ErrBlockedURL},
+		{name: "block cloud metadata", url: "http://169.254.169.254/latest/meta-data", expectErr: ErrBlockedURL},
+		{name: "block docker host", url: "http://webhistory_backend:9105", expectErr: ErrBlockedURL},
+		{name: "block host with any private address", url: "https://MIXED.example.com", expectErr: ErrBlockedURL},
+		{name: "block unresolvable host", url: "https://unknown.com", expectErr: ErrBlockedURL},
+		{name: "allow host in allow list", url: "http://intranet.local"},
+		{name: "allow subdomain in allow list", url: "http://blog.intranet.corp"},
+		{name: "allow address in allow list", url: "http://nas.local"},
+		{name: "allow cidr in allow list", url: "http://10.1.2.3"},
+		{name: "block cidr out of allow list", url: "http://10.2.2.3", expectErr: ErrBlockedURL},
+		{name: "block subdomain in deny list", url: "https://www.example.com.", expectErr: ErrBlockedURL},
+		{name: "block address in deny list", url: "https://93.184.216.35", expectErr: ErrBlockedURL},
+	}
+
+	policy := NewURLPolicy(conf)
+	policy.resolver = resolver
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			err := policy.Check(context.Background(), test.url)
+			if !errors.Is(err, test.expectErr) {
+				t.Errorf("got error: %v; want: %v", err, test.expectErr)
+			}
+		})
+	}
+}
+
+type slowResolver struct{}
+
+func (slowResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestURLPolicy_Check_lookupTimeout(t *testing.T) {
+	t.Parallel()
+	policy := NewURLPolicy(&config.WebsiteConfig{})
+	policy.resolver = slowResolver{}
+	policy.lookupTimeout = 10 * time.Millisecond
+
+	start := time.Now()
+	err := policy.Check(context.Background(), "https://slow.example.com")
+	if !errors.Is(err, ErrBlockedURL) {
+		t.Errorf("got error: %v; want: %v", err, ErrBlockedURL)
+	}
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Errorf("lookup is not timed out, took %v", elapsed)
+	}
+}
+
+func TestURLPolicy_DialContext(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {}))
+	t.Cleanup(server.Close)
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+
+	tests := []struct {
+		name      string
+		conf      *config.WebsiteConfig
+		addr      string
+		expectErr error
+	}{
+		{
+			name:      "block loopback address",
+			conf:      &config.WebsiteConfig{},
+			addr:      net.JoinHostPort("127.0.0.1", port),
+			expectErr: ErrBlockedURL,
+		},
+		{
+			name:      "block host resolved to loopback address",
+			conf:      &config.WebsiteConfig{},
+			addr:      net.JoinHostPort("localhost", port),
+			expectErr: ErrBlockedURL,
+		},
+		{
+			name:      "block host in deny list",
+			conf:      &config.WebsiteConfig{URLAllowList: []string{"127.0.0.1"}, URLDenyList: []string{"localhost"}},
+			addr:      net.JoinHostPort("localhost", port),
+			expectErr: ErrBlockedURL,
+		},
+		{
+			name: "allow address in allow list",
+			conf: &config.WebsiteConfig{URLAllowList: []string{"127.0.0.0/8"}},
+			addr: net.JoinHostPort("127.0.0.1", port),
+		},
+		{
+			name: "allow host in allow list",
+			conf: &config.WebsiteConfig{URLAllowList: []string{"localhost"}},
+			addr: net.JoinHostPort("localhost", port),
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			conn, err := NewURLPolicy(test.conf).DialContext(context.Background(), "tcp4", test.addr)
+			if conn != nil {
+				conn.Close()
+			}
+			if !errors.Is(err, test.expectErr) {
+				t.Errorf("got error: %v; want: %v", err, test.expectErr)
+			}
+		})
+	}
+}
+
+func Test_fetchWebsite_blockedURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.Write([]byte("internal"))
+	}))
+	defer server.Close()
+
+	SetURLPolicy(NewURLPolicy(&config.WebsiteConfig{}))
+	t.Cleanup(func() { SetURLPolicy(NewURLPolicy(&config.WebsiteConfig{})) })
+
+	start := time.Now()
+	resp, err := fetchWebsite(context.Background(), &model.Website{URL: server.URL}, 3, time.Second)
+	if err == nil {
+		t.Errorf("got resp: %v; want error", resp)
+	}
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Errorf("blocked url is retried, took %v", elapsed)
+	}
+
+	SetURLPolicy(NewURLPolicy(&config.WebsiteConfig{URLAllowList: []string{"127.0.0.1"}}))
+	resp, err = fetchWebsite(context.Background(), &model.Website{URL: server.URL}, 1, 0)
+	if err != nil {
+		t.Errorf("got error: %v", err)
+	}
+	if resp != "internal" {
+		t.Errorf("got resp: %v; want resp: %v", resp, "internal")
+	}
+}
@@ -0,0 +1,197 @@
+package settingcheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/htchan/WebHistory/internal/archive"
+	"github.com/htchan/WebHistory/internal/model"
+)
+
+var ErrSettingNotFound = errors.New("website setting not found")
+
+// Expectation is an entry of golden file, describing the parse result of a stored snapshot
+type Expectation struct {
+	Domain   string   `json:"domain"`
+	Snapshot string   `json:"snapshot"`
+	Title    string   `json:"title"`
+	Dates    []string `json:"dates"`
+}
+
+type Status string
+
+const (
+	StatusPassed    Status = "passed"
+	StatusRegressed Status = "regressed"
+	StatusUntested  Status = "untested"
+	StatusError     Status = "error"
+)
+
+type Result struct {
+	Domain   string
+	Snapshot string
+	Status   Status
+	Title    string
+	Dates    []string
+	Expect   *Expectation
+	Err      error
+}
+
+// Diff describes the difference between parse result and expectation
+func (result Result) Diff() string {
+	if result.Expect == nil {
+		return ""
+	}
+
+	return cmp.Diff(
+		Expectation{Title: result.Expect.Title, Dates: result.Expect.Dates},
+		Expectation{Title: result.Title, Dates: result.Dates},
+	)
+}
+
+// Loader loads the content of a snapshot referenced in golden file
+type Loader func(ctx context.Context, snapshot string) ([]byte, error)
+
+// FileLoader loads snapshots from files, relative path is resolved against dir
+func FileLoader(dir string) Loader {
+	return func(ctx context.Context, snapshot string) ([]byte, error) {
+		if !filepath.IsAbs(snapshot) {
+			snapshot = filepath.Join(dir, snapshot)
+		}
+
+		return os.ReadFile(snapshot)
+	}
+}
+
+// ArchiveLoader loads snapshots from response archive by archive key
+func ArchiveLoader(a *archive.Archive) Loader {
+	return a.Load
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+func LoadGolden(path string) ([]Expectation, error) {
+	var expectations []Expectation
+	if err := readJSON(path, &expectations); err != nil {
+		return nil, fmt.Errorf("load golden file fail: %w", err)
+	}
+
+	return expectations, nil
+}
+
+func SaveGolden(path string, expectations []Expectation) error {
+	data, err := json.MarshalIndent(expectations, "", "  ")
+	if err != nil {
+		return fmt.Errorf("save golden file fail: %w", err)
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+func LoadSettings(path string) ([]model.WebsiteSetting, error) {
+	var settings []model.WebsiteSetting
+	if err := readJSON(path, &settings); err != nil {
+		return nil, fmt.Errorf("load website settings fail: %w", err)
+	}
+
+	return settings, nil
+}
+
+func check(ctx context.Context, setting *model.WebsiteSetting, expect Expectation, load Loader) Result {
+	result := Result{Domain: expect.Domain, Snapshot: expect.Snapshot, Expect: &expect}
+	if setting == nil {
+		result.Status, result.Err = StatusError, ErrSettingNotFound
+		return result
+	}
+
+	data, err := load(ctx, expect.Snapshot)
+	if err != nil {
+		result.Status, result.Err = StatusError, fmt.Errorf("load snapshot fail: %w", err)
+		return result
+	}
+
+	result.Title, result.Dates = setting.Parse(string(data))
+	if result.Title == expect.Title && cmp.Equal(result.Dates, expect.Dates) {
+		result.Status = StatusPassed
+	} else {
+		result.Status = StatusRegressed
+	}
+
+	return result
+}
+
+// Check parses the snapshot of every expectation with website setting of its
+// domain, and reports domains of settings without any expectation as untested
+func Check(ctx context.Context, settings []model.WebsiteSetting, expectations []Expectation, load Loader) []Result {
+	settingMap := make(map[string]*model.WebsiteSetting)
+	for i := range settings {
+		settingMap[settings[i].Domain] = &settings[i]
+	}
+
+	var results []Result
+	tested := make(map[string]bool)
+	for _, expect := range expectations {
+		tested[expect.Domain] = true
+		results = append(results, check(ctx, settingMap[expect.Domain], expect, load))
+	}
+
+	for _, setting := range settings {
+		if !tested[setting.Domain] {
+			results = append(results, Result{Domain: setting.Domain, Status: StatusUntested})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Domain < results[j].Domain
+	})
+
+	return results
+}
+
+// Regressed returns results of domains which parse result differ from expectation
+func Regressed(results []Result) []Result {
+	var regressed []Result
+	for _, result := range results {
+		if result.Status == StatusRegressed || result.Status == StatusError {
+			regressed = append(regressed, result)
+		}
+	}
+
+	return regressed
+}
+
+// Updated returns expectations rewritten by the parse result of results
+func Updated(results []Result) []Expectation {
+	var expectations []Expectation
+	for _, result := range results {
+		if result.Expect == nil || result.Status == StatusError {
+			if result.Expect != nil {
+				expectations = append(expectations, *result.Expect)
+			}
+
+			continue
+		}
+
+		expectations = append(expectations, Expectation{
+			Domain:   result.Domain,
+			Snapshot: result.Snapshot,
+			Title:    result.Title,
+			Dates:    result.Dates,
+		})
+	}
+
+	return expectations
+}
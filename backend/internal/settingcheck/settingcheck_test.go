@@ -0,0 +1,116 @@
+package settingcheck
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/htchan/WebHistory/internal/model"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	leak := flag.Bool("leak", false, "check for memory leaks")
+	flag.Parse()
+
+	if *leak {
+		goleak.VerifyTestMain(m)
+	} else {
+		os.Exit(m.Run())
+	}
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	snapshots := map[string]string{
+		"a.html": "<html><head><title>title a</title></head><body><ul><li>1</li><li>2</li></ul></body></html>",
+		"b.html": "<html><head><title>title b</title></head><body><div>redesigned</div></body></html>",
+	}
+	load := func(ctx context.Context, snapshot string) ([]byte, error) {
+		data, ok := snapshots[snapshot]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+		return []byte(data), nil
+	}
+
+	settings := []model.WebsiteSetting{
+		{Domain: "a.com", TitleGoquerySelector: "head>title", DatesGoquerySelector: "ul>li"},
+		{Domain: "b.com", TitleGoquerySelector: "head>title", DatesGoquerySelector: "ul>li"},
+		{Domain: "c.com", TitleGoquerySelector: "head>title", DatesGoquerySelector: "ul>li"},
+		{Domain: "d.com", TitleGoquerySelector: "head>title", DatesGoquerySelector: "ul>li"},
+	}
+	expectations := []Expectation{
+		{Domain: "a.com", Snapshot: "a.html", Title: "title a", Dates: []string{"1", "2"}},
+		{Domain: "b.com", Snapshot: "b.html", Title: "title b", Dates: []string{"1"}},
+		{Domain: "c.com", Snapshot: "c.html", Title: "title c", Dates: []string{"1"}},
+		{Domain: "e.com", Snapshot: "a.html", Title: "title a", Dates: []string{"1", "2"}},
+	}
+
+	results := Check(context.Background(), settings, expectations, load)
+
+	var got []Status
+	for _, result := range results {
+		got = append(got, result.Status)
+	}
+	assert.Equal(t, []Status{StatusPassed, StatusRegressed, StatusError, StatusUntested, StatusError}, got)
+
+	var regressedDomains []string
+	for _, result := range Regressed(results) {
+		regressedDomains = append(regressedDomains, result.Domain)
+	}
+	assert.Equal(t, []string{"b.com", "c.com", "e.com"}, regressedDomains)
+	assert.ErrorIs(t, results[4].Err, ErrSettingNotFound)
+	assert.NotEmpty(t, results[1].Diff())
+}
+
+func TestUpdated(t *testing.T) {
+	t.Parallel()
+
+	results := []Result{
+		{
+			Domain: "a.com", Snapshot: "a.html", Status: StatusRegressed,
+			Title: "new title", Dates: []string{"2"},
+			Expect: &Expectation{Domain: "a.com", Snapshot: "a.html", Title: "title", Dates: []string{"1"}},
+		},
+		{
+			Domain: "b.com", Snapshot: "b.html", Status: StatusError,
+			Expect: &Expectation{Domain: "b.com", Snapshot: "b.html", Title: "title"},
+		},
+		{Domain: "c.com", Status: StatusUntested},
+	}
+
+	assert.Equal(t, []Expectation{
+		{Domain: "a.com", Snapshot: "a.html", Title: "new title", Dates: []string{"2"}},
+		{Domain: "b.com", Snapshot: "b.html", Title: "title"},
+	}, Updated(results))
+}
+
+func TestSaveLoadGolden(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "golden.json")
+	expectations := []Expectation{{Domain: "a.com", Snapshot: "a.html", Title: "title", Dates: []string{"1"}}}
+
+	assert.NoError(t, SaveGolden(path, expectations))
+
+	got, err := LoadGolden(path)
+	assert.NoError(t, err)
+	assert.Equal(t, expectations, got)
+}
+
+func TestAssertGolden(t *testing.T) {
+	t.Parallel()
+
+	settings, err := LoadSettings("../../test/website_settings.json")
+	if err != nil {
+		t.Fatalf("load settings fail: %v", err)
+	}
+
+	AssertGolden(t, settings, "../../test/settings_golden.json")
+}
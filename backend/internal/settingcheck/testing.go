@@ -0,0 +1,32 @@
+package settingcheck
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/htchan/WebHistory/internal/model"
+)
+
+// AssertGolden fails t for every domain which parse result of snapshots
+// differ from the golden file, snapshots are resolved relative to golden file
+func AssertGolden(t testing.TB, settings []model.WebsiteSetting, goldenPath string) {
+	t.Helper()
+
+	expectations, err := LoadGolden(goldenPath)
+	if err != nil {
+		t.Fatalf("load golden fail: %v", err)
+	}
+
+	results := Check(context.Background(), settings, expectations, FileLoader(filepath.Dir(goldenPath)))
+	for _, result := range results {
+		switch result.Status {
+		case StatusRegressed:
+			t.Errorf("domain %s regressed on snapshot %s (-want +got):\n%s", result.Domain, result.Snapshot, result.Diff())
+		case StatusError:
+			t.Errorf("domain %s fail to check snapshot %s: %v", result.Domain, result.Snapshot, result.Err)
+		case StatusUntested:
+			t.Logf("domain %s has no snapshot in golden file", result.Domain)
+		}
+	}
+}
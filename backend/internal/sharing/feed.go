@@ -0,0 +1,83 @@
+package sharing
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/htchan/WebHistory/internal/model"
+)
+
+const AtomContentType = "application/atom+xml; charset=utf-8"
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Link    atomLink `xml:"link"`
+}
+
+// EncodeAtom writes websites of group as atom feed, the most recently
+// updated website comes first
+func EncodeAtom(w io.Writer, feedURL, title string, webs model.WebsiteGroup) error {
+	sorted := make(model.WebsiteGroup, len(webs))
+	copy(sorted, webs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Website.UpdateTime.After(sorted[j].Website.UpdateTime)
+	})
+
+	var updated time.Time
+	entries := make([]atomEntry, len(sorted))
+	for i, web := range sorted {
+		if web.Website.UpdateTime.After(updated) {
+			updated = web.Website.UpdateTime
+		}
+
+		entryTitle := web.Website.Title
+		if entryTitle == "" {
+			entryTitle = web.Website.URL
+		}
+		entries[i] = atomEntry{
+			ID:      "urn:uuid:" + web.WebsiteUUID,
+			Title:   entryTitle,
+			Updated: web.Website.UpdateTime.UTC().Format(time.RFC3339),
+			Link:    atomLink{Href: web.Website.URL},
+		}
+	}
+
+	feed := atomFeed{
+		ID:      feedURL,
+		Title:   title,
+		Updated: updated.UTC().Format(time.RFC3339),
+		Link:    atomLink{Href: feedURL, Rel: "self"},
+		Entries: entries,
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("write atom header fail: %w", err)
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(feed); err != nil {
+		return fmt.Errorf("encode atom feed fail: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package sharing
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/htchan/WebHistory/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeAtom(t *testing.T) {
+	t.Parallel()
+
+	webs := model.WebsiteGroup{
+		{WebsiteUUID: "1", Website: model.Website{URL: "http://example.com/1", Title: "old", UpdateTime: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}},
+		{WebsiteUUID: "2", Website: model.Website{URL: "http://example.com/2?a=1&b=2", UpdateTime: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}},
+	}
+
+	var buf bytes.Buffer
+	err := EncodeAtom(&buf, "http://localhost/public/groups/token", "comics", webs)
+	assert.NoError(t, err)
+
+	var feed atomFeed
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), &feed))
+	assert.Equal(t, "http://www.w3.org/2005/Atom", feed.XMLName.Space)
+	assert.Equal(t, "comics", feed.Title)
+	assert.Equal(t, "http://localhost/public/groups/token", feed.ID)
+	assert.Equal(t, "2021-01-01T00:00:00Z", feed.Updated)
+	assert.Equal(t, []atomEntry{
+		{ID: "urn:uuid:2", Title: "http://example.com/2?a=1&b=2", Updated: "2021-01-01T00:00:00Z", Link: atomLink{Href: "http://example.com/2?a=1&b=2"}},
+		{ID: "urn:uuid:1", Title: "old", Updated: "2020-01-01T00:00:00Z", Link: atomLink{Href: "http://example.com/1"}},
+	}, feed.Entries)
+}
@@ -0,0 +1,160 @@
+// Package sharing keeps groups subscribed from a group share in sync with the
+// group of the share owner
+package sharing
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/htchan/WebHistory/internal/model"
+	"github.com/htchan/WebHistory/internal/repository"
+)
+
+// SyncSubscription makes group of subscriber contain the same websites as
+// the shared group. Only websites synced from the share are removed, so
+// websites subscriber added by themselves or follows in other groups are left
+// untouched
+func SyncSubscription(r repository.Repostory, share model.GroupShare, sub model.GroupShareSubscription) error {
+	ownerWebs, err := r.FindUserWebsitesByGroup(share.OwnerUUID, share.GroupName)
+	if err != nil {
+		return fmt.Errorf("find shared group fail: %w", err)
+	}
+
+	return syncSubscription(r, share, ownerWebs, sub)
+}
+
+func syncSubscription(r repository.Repostory, share model.GroupShare, ownerWebs model.WebsiteGroup, sub model.GroupShareSubscription) error {
+	// empty shared group is never synced, otherwise a failed or stale lookup
+	// removes everything subscriber has in the group
+	if len(ownerWebs) == 0 {
+		return nil
+	}
+
+	subWebs, err := r.FindUserWebsitesByGroup(sub.UserUUID, sub.GroupName)
+	if err != nil {
+		return fmt.Errorf("find subscribed group fail: %w", err)
+	}
+
+	shared := make(map[string]bool)
+	for _, ownerWeb := range ownerWebs {
+		shared[ownerWeb.WebsiteUUID] = true
+	}
+
+	subscribed := make(map[string]bool)
+	for _, subWeb := range subWebs {
+		subscribed[subWeb.WebsiteUUID] = true
+		if shared[subWeb.WebsiteUUID] || subWeb.ShareUUID != share.UUID {
+			continue
+		}
+
+		subWeb := subWeb
+		if err := r.DeleteUserWebsite(&subWeb); err != nil {
+			return fmt.Errorf("remove unshared website fail: %w", err)
+		}
+	}
+
+	for _, ownerWeb := range ownerWebs {
+		if subscribed[ownerWeb.WebsiteUUID] {
+			continue
+		}
+
+		web := model.NewUserWebsite(ownerWeb.Website, sub.UserUUID)
+		web.WebsiteUUID = ownerWeb.WebsiteUUID
+		web.GroupName = sub.GroupName
+		web.ShareUUID = share.UUID
+		if err := r.CreateUserWebsite(&web); err != nil {
+			return fmt.Errorf("add shared website fail: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Sync updates groups of all subscribers of share, the share is dropped if
+// the shared group no longer has any website
+func Sync(r repository.Repostory, share model.GroupShare) error {
+	ownerWebs, err := r.FindUserWebsitesByGroup(share.OwnerUUID, share.GroupName)
+	if err != nil {
+		return fmt.Errorf("find shared group fail: %w", err)
+	}
+
+	if len(ownerWebs) == 0 {
+		if err := r.DeleteGroupShare(share.UUID); err != nil {
+			return fmt.Errorf("drop share of empty group fail: %w", err)
+		}
+		return nil
+	}
+
+	subs, err := r.FindGroupShareSubscriptions(share.UUID)
+	if err != nil {
+		return fmt.Errorf("find subscriptions fail: %w", err)
+	}
+
+	var errs []error
+	for _, sub := range subs {
+		if err := syncSubscription(r, share, ownerWebs, sub); err != nil {
+			errs = append(errs, fmt.Errorf("sync subscriber %s: %w", sub.UserUUID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// RemoveWebsite removes website synced from share from groups of all
+// subscribers, it is used when website is removed from the shared group
+// explicitly, so that the last website of the group is removed as well
+func RemoveWebsite(r repository.Repostory, share model.GroupShare, websiteUUID string) error {
+	subs, err := r.FindGroupShareSubscriptions(share.UUID)
+	if err != nil {
+		return fmt.Errorf("find subscriptions fail: %w", err)
+	}
+
+	var errs []error
+	for _, sub := range subs {
+		subWeb, err := r.FindUserWebsite(sub.UserUUID, websiteUUID)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		} else if err != nil {
+			errs = append(errs, fmt.Errorf("find website of subscriber %s: %w", sub.UserUUID, err))
+			continue
+		}
+
+		if subWeb.ShareUUID != share.UUID || subWeb.GroupName != sub.GroupName {
+			continue
+		}
+		if err := r.DeleteUserWebsite(subWeb); err != nil {
+			errs = append(errs, fmt.Errorf("remove website of subscriber %s: %w", sub.UserUUID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// SyncGroup updates subscribers of group of owner if the group is shared
+func SyncGroup(r repository.Repostory, ownerUUID, groupName string) error {
+	share, err := r.FindGroupShareByGroup(ownerUUID, groupName)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("find share fail: %w", err)
+	}
+
+	return Sync(r, *share)
+}
+
+// SyncOwner updates subscribers of all groups shared by owner
+func SyncOwner(r repository.Repostory, ownerUUID string) error {
+	shares, err := r.FindGroupShares(ownerUUID)
+	if err != nil {
+		return fmt.Errorf("find shares fail: %w", err)
+	}
+
+	var errs []error
+	for _, share := range shares {
+		if err := Sync(r, share); err != nil {
+			errs = append(errs, fmt.Errorf("sync share %s: %w", share.UUID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
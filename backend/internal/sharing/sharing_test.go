@@ -0,0 +1,220 @@
+package sharing
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/htchan/WebHistory/internal/model"
+	"github.com/htchan/WebHistory/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	leak := flag.Bool("leak", false, "check for memory leaks")
+	flag.Parse()
+
+	if *leak {
+		goleak.VerifyTestMain(m)
+	} else {
+		os.Exit(m.Run())
+	}
+}
+
+func groupWebsiteUUIDs(t *testing.T, r repository.Repostory, userUUID, group string) []string {
+	t.Helper()
+
+	webs, err := r.FindUserWebsitesByGroup(userUUID, group)
+	assert.NoError(t, err)
+
+	uuids := make([]string, len(webs))
+	for i, web := range webs {
+		uuids[i] = web.WebsiteUUID
+	}
+	sort.Strings(uuids)
+
+	return uuids
+}
+
+func TestSyncSubscription(t *testing.T) {
+	t.Parallel()
+
+	share := model.GroupShare{UUID: "share", OwnerUUID: "owner", GroupName: "shared"}
+	sub := model.GroupShareSubscription{ShareUUID: "share", UserUUID: "sub", GroupName: "local"}
+
+	tests := []struct {
+		name          string
+		r             *repository.InMemRepo
+		wantErr       bool
+		wantGroup     []string
+		wantElsewhere []string
+	}{
+		{
+			name: "add missing and remove unshared websites",
+			r: repository.NewInMemRepo(nil, []model.UserWebsite{
+				{WebsiteUUID: "1", UserUUID: "owner", GroupName: "shared"},
+				{WebsiteUUID: "2", UserUUID: "owner", GroupName: "shared"},
+				{WebsiteUUID: "3", UserUUID: "owner", GroupName: "other"},
+				{WebsiteUUID: "2", UserUUID: "sub", GroupName: "local", ShareUUID: "share"},
+				{WebsiteUUID: "3", UserUUID: "sub", GroupName: "local", ShareUUID: "share"},
+			}, nil, nil),
+			wantGroup: []string{"1", "2"},
+		},
+		{
+			name: "keep website added to group by subscriber",
+			r: repository.NewInMemRepo(nil, []model.UserWebsite{
+				{WebsiteUUID: "1", UserUUID: "owner", GroupName: "shared"},
+				{WebsiteUUID: "2", UserUUID: "sub", GroupName: "local"},
+				{WebsiteUUID: "3", UserUUID: "sub", GroupName: "local", ShareUUID: "other share"},
+			}, nil, nil),
+			wantGroup: []string{"1", "2", "3"},
+		},
+		{
+			name: "keep websites of subscriber if shared group is empty",
+			r: repository.NewInMemRepo(nil, []model.UserWebsite{
+				{WebsiteUUID: "1", UserUUID: "sub", GroupName: "local", ShareUUID: "share"},
+			}, nil, nil),
+			wantGroup: []string{"1"},
+		},
+		{
+			name: "website followed in other group is kept there",
+			r: repository.NewInMemRepo(nil, []model.UserWebsite{
+				{WebsiteUUID: "1", UserUUID: "owner", GroupName: "shared"},
+				{WebsiteUUID: "1", UserUUID: "sub", GroupName: "mine"},
+			}, nil, nil),
+			wantGroup:     []string{},
+			wantElsewhere: []string{"1"},
+		},
+		{
+			name:      "repo error",
+			r:         repository.NewInMemRepo(nil, nil, nil, errors.New("some error")),
+			wantErr:   true,
+			wantGroup: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := SyncSubscription(test.r, share, sub)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("SyncSubscription() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+
+			assert.Equal(t, test.wantGroup, groupWebsiteUUIDs(t, test.r, "sub", "local"))
+			if test.wantElsewhere != nil {
+				assert.Equal(t, test.wantElsewhere, groupWebsiteUUIDs(t, test.r, "sub", "mine"))
+			}
+		})
+	}
+}
+
+func TestSync(t *testing.T) {
+	t.Parallel()
+
+	r := repository.NewInMemRepo(nil, []model.UserWebsite{
+		{WebsiteUUID: "1", UserUUID: "owner", GroupName: "shared"},
+	}, nil, nil)
+	share := model.GroupShare{UUID: "share", OwnerUUID: "owner", GroupName: "shared"}
+	assert.NoError(t, r.CreateGroupShare(&share))
+	assert.NoError(t, r.CreateGroupShareSubscription(&model.GroupShareSubscription{ShareUUID: "share", UserUUID: "a", GroupName: "a group"}))
+	assert.NoError(t, r.CreateGroupShareSubscription(&model.GroupShareSubscription{ShareUUID: "share", UserUUID: "b", GroupName: "b group"}))
+
+	assert.NoError(t, Sync(r, share))
+	assert.Equal(t, []string{"1"}, groupWebsiteUUIDs(t, r, "a", "a group"))
+	assert.Equal(t, []string{"1"}, groupWebsiteUUIDs(t, r, "b", "b group"))
+}
+
+func TestSync_EmptyGroup(t *testing.T) {
+	t.Parallel()
+
+	r := repository.NewInMemRepo(nil, []model.UserWebsite{
+		{WebsiteUUID: "1", UserUUID: "sub", GroupName: "mirror", ShareUUID: "share"},
+	}, nil, nil)
+	share := model.GroupShare{UUID: "share", OwnerUUID: "owner", GroupName: "shared"}
+	assert.NoError(t, r.CreateGroupShare(&share))
+	assert.NoError(t, r.CreateGroupShareSubscription(&model.GroupShareSubscription{ShareUUID: "share", UserUUID: "sub", GroupName: "mirror"}))
+
+	assert.NoError(t, Sync(r, share))
+	_, err := r.FindGroupShare("share")
+	assert.ErrorIs(t, err, repository.ErrNotFound, "share of empty group is dropped")
+	assert.Equal(t, []string{"1"}, groupWebsiteUUIDs(t, r, "sub", "mirror"))
+}
+
+func TestRemoveWebsite(t *testing.T) {
+	t.Parallel()
+
+	r := repository.NewInMemRepo(nil, []model.UserWebsite{
+		{WebsiteUUID: "1", UserUUID: "a", GroupName: "a group", ShareUUID: "share"},
+		{WebsiteUUID: "1", UserUUID: "b", GroupName: "b group"},
+		{WebsiteUUID: "1", UserUUID: "c", GroupName: "elsewhere", ShareUUID: "share"},
+	}, nil, nil)
+	share := model.GroupShare{UUID: "share", OwnerUUID: "owner", GroupName: "shared"}
+	for _, sub := range []model.GroupShareSubscription{
+		{ShareUUID: "share", UserUUID: "a", GroupName: "a group"},
+		{ShareUUID: "share", UserUUID: "b", GroupName: "b group"},
+		{ShareUUID: "share", UserUUID: "c", GroupName: "c group"},
+	} {
+		sub := sub
+		assert.NoError(t, r.CreateGroupShareSubscription(&sub))
+	}
+
+	assert.NoError(t, RemoveWebsite(r, share, "1"))
+	assert.Equal(t, []string{}, groupWebsiteUUIDs(t, r, "a", "a group"))
+	assert.Equal(t, []string{"1"}, groupWebsiteUUIDs(t, r, "b", "b group"), "website added by subscriber is kept")
+	assert.Equal(t, []string{"1"}, groupWebsiteUUIDs(t, r, "c", "elsewhere"), "website moved out of subscribed group is kept")
+}
+
+func TestSyncGroup(t *testing.T) {
+	t.Parallel()
+
+	r := repository.NewInMemRepo(nil, []model.UserWebsite{
+		{WebsiteUUID: "1", UserUUID: "owner", GroupName: "shared"},
+		{WebsiteUUID: "2", UserUUID: "owner", GroupName: "other"},
+	}, nil, nil)
+	for _, share := range []model.GroupShare{
+		{UUID: "shared", OwnerUUID: "owner", GroupName: "shared"},
+		{UUID: "other", OwnerUUID: "owner", GroupName: "other"},
+	} {
+		share := share
+		assert.NoError(t, r.CreateGroupShare(&share))
+		assert.NoError(t, r.CreateGroupShareSubscription(&model.GroupShareSubscription{ShareUUID: share.UUID, UserUUID: "sub", GroupName: share.GroupName}))
+	}
+
+	assert.NoError(t, SyncGroup(r, "owner", "shared"))
+	assert.NoError(t, SyncGroup(r, "owner", "not shared"))
+	assert.Equal(t, []string{"1"}, groupWebsiteUUIDs(t, r, "sub", "shared"))
+	assert.Equal(t, []string{}, groupWebsiteUUIDs(t, r, "sub", "other"), "only changed group is synced")
+}
+
+func TestSyncOwner(t *testing.T) {
+	t.Parallel()
+
+	r := repository.NewInMemRepo(nil, []model.UserWebsite{
+		{WebsiteUUID: "1", UserUUID: "owner", GroupName: "first"},
+		{WebsiteUUID: "2", UserUUID: "owner", GroupName: "second"},
+		{WebsiteUUID: "3", UserUUID: "other", GroupName: "third"},
+	}, nil, nil)
+	for _, share := range []model.GroupShare{
+		{UUID: "first", OwnerUUID: "owner", GroupName: "first"},
+		{UUID: "second", OwnerUUID: "owner", GroupName: "second"},
+		{UUID: "third", OwnerUUID: "other", GroupName: "third"},
+	} {
+		share := share
+		assert.NoError(t, r.CreateGroupShare(&share))
+		assert.NoError(t, r.CreateGroupShareSubscription(&model.GroupShareSubscription{ShareUUID: share.UUID, UserUUID: "sub", GroupName: share.GroupName}))
+	}
+
+	assert.NoError(t, SyncOwner(r, "owner"))
+	assert.Equal(t, []string{"1"}, groupWebsiteUUIDs(t, r, "sub", "first"))
+	assert.Equal(t, []string{"2"}, groupWebsiteUUIDs(t, r, "sub", "second"))
+	assert.Equal(t, []string{}, groupWebsiteUUIDs(t, r, "sub", "third"), "share of other owner not synced")
+}
@@ -8,25 +8,112 @@ import (
 	"database/sql"
 )
 
+type ApiToken struct {
+	Uuid       sql.NullString
+	UserUuid   sql.NullString
+	Name       sql.NullString
+	TokenHash  sql.NullString
+	CreateTime sql.NullTime
+}
+
+type GroupPublicLink struct {
+	Token      sql.NullString
+	OwnerUuid  sql.NullString
+	GroupName  sql.NullString
+	CreateTime sql.NullTime
+}
+
+type GroupShare struct {
+	Uuid       sql.NullString
+	OwnerUuid  sql.NullString
+	GroupName  sql.NullString
+	Mode       sql.NullString
+	CreateTime sql.NullTime
+}
+
+type GroupShareSubscription struct {
+	ShareUuid  sql.NullString
+	UserUuid   sql.NullString
+	GroupName  sql.NullString
+	CreateTime sql.NullTime
+}
+
+type ImportJob struct {
+	Uuid       sql.NullString
+	UserUuid   sql.NullString
+	Status     sql.NullString
+	Total      sql.NullInt32
+	Results    sql.NullString
+	Error      sql.NullString
+	CreateTime sql.NullTime
+	FinishTime sql.NullTime
+}
+
+type User struct {
+	Uuid         sql.NullString
+	Username     sql.NullString
+	PasswordHash sql.NullString
+	CreateTime   sql.NullTime
+}
+
 type UserWebsite struct {
-	WebsiteUuid sql.NullString
+	WebsiteUuid  sql.NullString
+	UserUuid     sql.NullString
+	AccessTime   sql.NullTime
+	GroupName    sql.NullString
+	LastReadItem sql.NullString
+	SnoozeUntil  sql.NullTime
+	Muted        sql.NullBool
+	ShareUuid    sql.NullString
+}
+
+type UserWebsiteGroup struct {
+	UserUuid  sql.NullString
+	GroupName sql.NullString
+	Position  sql.NullInt32
+}
+
+type UserWebsiteTag struct {
 	UserUuid    sql.NullString
-	AccessTime  sql.NullTime
-	GroupName   sql.NullString
+	WebsiteUuid sql.NullString
+	Tag         sql.NullString
 }
 
 type Website struct {
-	Uuid       sql.NullString
-	Url        sql.NullString
-	Title      sql.NullString
-	Content    sql.NullString
-	UpdateTime sql.NullTime
+	Uuid            sql.NullString
+	Url             sql.NullString
+	Title           sql.NullString
+	Content         sql.NullString
+	UpdateTime      sql.NullTime
+	PreviousContent sql.NullString
+	Items           sql.NullString
+	Health          sql.NullString
 }
 
 type WebsiteSetting struct {
-	Domain               sql.NullString
-	FocusIndexFrom       sql.NullInt32
-	FocusIndexTo         sql.NullInt32
-	TitleGoquerySelector sql.NullString
-	DateGoquerySelector  sql.NullString
+	Domain                  sql.NullString
+	FocusIndexFrom          sql.NullInt32
+	FocusIndexTo            sql.NullInt32
+	TitleGoquerySelector    sql.NullString
+	DateGoquerySelector     sql.NullString
+	ConsecutiveFailures     sql.NullInt32
+	BrokenAt                sql.NullTime
+	ItemNameGoquerySelector sql.NullString
+	ItemLinkGoquerySelector sql.NullString
+}
+
+type WebsiteUpdateRequest struct {
+	Uuid         sql.NullString
+	WebsiteUuid  sql.NullString
+	TraceContext sql.NullString
+	CreateTime   sql.NullTime
+}
+
+type WorkerStatus struct {
+	WorkerID      sql.NullString
+	ExecutorCount sql.NullInt32
+	BusyExecutors sql.NullInt32
+	QueueDepth    sql.NullInt32
+	StartTime     sql.NullTime
+	HeartbeatTime sql.NullTime
 }
@@ -8,16 +8,275 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const claimWebsiteUpdateRequests = `-- name: ClaimWebsiteUpdateRequests :many
+DELETE FROM website_update_requests
+WHERE uuid IN (
+  SELECT uuid FROM website_update_requests
+  ORDER BY create_time
+  LIMIT $1
+  FOR UPDATE SKIP LOCKED
 )
+RETURNING uuid, website_uuid, trace_context, create_time
+`
+
+func (q *Queries) ClaimWebsiteUpdateRequests(ctx context.Context, limit int32) ([]WebsiteUpdateRequest, error) {
+	rows, err := q.db.QueryContext(ctx, claimWebsiteUpdateRequests, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebsiteUpdateRequest
+	for rows.Next() {
+		var i WebsiteUpdateRequest
+		if err := rows.Scan(
+			&i.Uuid,
+			&i.WebsiteUuid,
+			&i.TraceContext,
+			&i.CreateTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countWebsiteSubscribers = `-- name: CountWebsiteSubscribers :many
+SELECT website_uuid, count(*) AS subscriber_count FROM user_websites
+GROUP BY website_uuid
+`
+
+type CountWebsiteSubscribersRow struct {
+	WebsiteUuid     sql.NullString
+	SubscriberCount int64
+}
+
+func (q *Queries) CountWebsiteSubscribers(ctx context.Context) ([]CountWebsiteSubscribersRow, error) {
+	rows, err := q.db.QueryContext(ctx, countWebsiteSubscribers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountWebsiteSubscribersRow
+	for rows.Next() {
+		var i CountWebsiteSubscribersRow
+		if err := rows.Scan(&i.WebsiteUuid, &i.SubscriberCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countWebsiteUpdateRequests = `-- name: CountWebsiteUpdateRequests :one
+SELECT count(*) FROM website_update_requests
+`
+
+func (q *Queries) CountWebsiteUpdateRequests(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countWebsiteUpdateRequests)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createAPIToken = `-- name: CreateAPIToken :exec
+INSERT INTO api_tokens
+(uuid, user_uuid, name, token_hash, create_time)
+VALUES
+($1, $2, $3, $4, $5)
+`
+
+type CreateAPITokenParams struct {
+	Uuid       sql.NullString
+	UserUuid   sql.NullString
+	Name       sql.NullString
+	TokenHash  sql.NullString
+	CreateTime sql.NullTime
+}
+
+func (q *Queries) CreateAPIToken(ctx context.Context, arg CreateAPITokenParams) error {
+	_, err := q.db.ExecContext(ctx, createAPIToken,
+		arg.Uuid,
+		arg.UserUuid,
+		arg.Name,
+		arg.TokenHash,
+		arg.CreateTime,
+	)
+	return err
+}
+
+const createGroupPublicLink = `-- name: CreateGroupPublicLink :one
+INSERT INTO group_public_links
+(token, owner_uuid, group_name, create_time)
+VALUES
+($1, $2, $3, $4)
+ON CONFLICT (owner_uuid, group_name) DO
+UPDATE SET token=$1, create_time=$4
+RETURNING token, owner_uuid, group_name, create_time
+`
+
+type CreateGroupPublicLinkParams struct {
+	Token      sql.NullString
+	OwnerUuid  sql.NullString
+	GroupName  sql.NullString
+	CreateTime sql.NullTime
+}
+
+func (q *Queries) CreateGroupPublicLink(ctx context.Context, arg CreateGroupPublicLinkParams) (GroupPublicLink, error) {
+	row := q.db.QueryRowContext(ctx, createGroupPublicLink,
+		arg.Token,
+		arg.OwnerUuid,
+		arg.GroupName,
+		arg.CreateTime,
+	)
+	var i GroupPublicLink
+	err := row.Scan(
+		&i.Token,
+		&i.OwnerUuid,
+		&i.GroupName,
+		&i.CreateTime,
+	)
+	return i, err
+}
+
+const createGroupShare = `-- name: CreateGroupShare :one
+INSERT INTO group_shares
+(uuid, owner_uuid, group_name, mode, create_time)
+VALUES
+($1, $2, $3, $4, $5)
+ON CONFLICT (owner_uuid, group_name) DO
+UPDATE SET mode=$4
+RETURNING uuid, owner_uuid, group_name, mode, create_time
+`
+
+type CreateGroupShareParams struct {
+	Uuid       sql.NullString
+	OwnerUuid  sql.NullString
+	GroupName  sql.NullString
+	Mode       sql.NullString
+	CreateTime sql.NullTime
+}
+
+func (q *Queries) CreateGroupShare(ctx context.Context, arg CreateGroupShareParams) (GroupShare, error) {
+	row := q.db.QueryRowContext(ctx, createGroupShare,
+		arg.Uuid,
+		arg.OwnerUuid,
+		arg.GroupName,
+		arg.Mode,
+		arg.CreateTime,
+	)
+	var i GroupShare
+	err := row.Scan(
+		&i.Uuid,
+		&i.OwnerUuid,
+		&i.GroupName,
+		&i.Mode,
+		&i.CreateTime,
+	)
+	return i, err
+}
+
+const createGroupShareSubscription = `-- name: CreateGroupShareSubscription :exec
+INSERT INTO group_share_subscriptions
+(share_uuid, user_uuid, group_name, create_time)
+VALUES
+($1, $2, $3, $4)
+ON CONFLICT (share_uuid, user_uuid) DO
+UPDATE SET group_name=$3
+`
+
+type CreateGroupShareSubscriptionParams struct {
+	ShareUuid  sql.NullString
+	UserUuid   sql.NullString
+	GroupName  sql.NullString
+	CreateTime sql.NullTime
+}
+
+func (q *Queries) CreateGroupShareSubscription(ctx context.Context, arg CreateGroupShareSubscriptionParams) error {
+	_, err := q.db.ExecContext(ctx, createGroupShareSubscription,
+		arg.ShareUuid,
+		arg.UserUuid,
+		arg.GroupName,
+		arg.CreateTime,
+	)
+	return err
+}
+
+const createImportJob = `-- name: CreateImportJob :exec
+INSERT INTO import_jobs
+(uuid, user_uuid, status, total, create_time)
+VALUES
+($1, $2, $3, $4, $5)
+`
+
+type CreateImportJobParams struct {
+	Uuid       sql.NullString
+	UserUuid   sql.NullString
+	Status     sql.NullString
+	Total      sql.NullInt32
+	CreateTime sql.NullTime
+}
+
+func (q *Queries) CreateImportJob(ctx context.Context, arg CreateImportJobParams) error {
+	_, err := q.db.ExecContext(ctx, createImportJob,
+		arg.Uuid,
+		arg.UserUuid,
+		arg.Status,
+		arg.Total,
+		arg.CreateTime,
+	)
+	return err
+}
+
+const createUser = `-- name: CreateUser :exec
+INSERT INTO users
+(uuid, username, password_hash, create_time)
+VALUES
+($1, $2, $3, $4)
+`
+
+type CreateUserParams struct {
+	Uuid         sql.NullString
+	Username     sql.NullString
+	PasswordHash sql.NullString
+	CreateTime   sql.NullTime
+}
+
+func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) error {
+	_, err := q.db.ExecContext(ctx, createUser,
+		arg.Uuid,
+		arg.Username,
+		arg.PasswordHash,
+		arg.CreateTime,
+	)
+	return err
+}
 
 const createUserWebsite = `-- name: CreateUserWebsite :one
 INSERT INTO user_websites
-(user_uuid, website_uuid, access_time, group_name)
+(user_uuid, website_uuid, access_time, group_name, share_uuid)
 VALUES
-($1, $2, $3, $4)
+($1, $2, $3, $4, $5)
 ON CONFLICT(user_uuid, website_uuid) DO
 UPDATE SET user_uuid=$1, website_uuid=$2
-RETURNing website_uuid, user_uuid, access_time, group_name
+RETURNing website_uuid, user_uuid, access_time, group_name, last_read_item, snooze_until, muted, share_uuid
 `
 
 type CreateUserWebsiteParams struct {
@@ -25,6 +284,7 @@ type CreateUserWebsiteParams struct {
 	WebsiteUuid sql.NullString
 	AccessTime  sql.NullTime
 	GroupName   sql.NullString
+	ShareUuid   sql.NullString
 }
 
 func (q *Queries) CreateUserWebsite(ctx context.Context, arg CreateUserWebsiteParams) (UserWebsite, error) {
@@ -33,6 +293,7 @@ func (q *Queries) CreateUserWebsite(ctx context.Context, arg CreateUserWebsitePa
 		arg.WebsiteUuid,
 		arg.AccessTime,
 		arg.GroupName,
+		arg.ShareUuid,
 	)
 	var i UserWebsite
 	err := row.Scan(
@@ -40,18 +301,59 @@ func (q *Queries) CreateUserWebsite(ctx context.Context, arg CreateUserWebsitePa
 		&i.UserUuid,
 		&i.AccessTime,
 		&i.GroupName,
+		&i.LastReadItem,
+		&i.SnoozeUntil,
+		&i.Muted,
+		&i.ShareUuid,
 	)
 	return i, err
 }
 
+const createUserWebsiteGroup = `-- name: CreateUserWebsiteGroup :exec
+INSERT INTO user_website_groups
+(user_uuid, group_name, position)
+VALUES
+($1, $2, $3)
+`
+
+type CreateUserWebsiteGroupParams struct {
+	UserUuid  sql.NullString
+	GroupName sql.NullString
+	Position  sql.NullInt32
+}
+
+func (q *Queries) CreateUserWebsiteGroup(ctx context.Context, arg CreateUserWebsiteGroupParams) error {
+	_, err := q.db.ExecContext(ctx, createUserWebsiteGroup, arg.UserUuid, arg.GroupName, arg.Position)
+	return err
+}
+
+const createUserWebsiteTag = `-- name: CreateUserWebsiteTag :exec
+INSERT INTO user_website_tags
+(user_uuid, website_uuid, tag)
+VALUES
+($1, $2, $3)
+ON CONFLICT(user_uuid, website_uuid, tag) DO NOTHING
+`
+
+type CreateUserWebsiteTagParams struct {
+	UserUuid    sql.NullString
+	WebsiteUuid sql.NullString
+	Tag         sql.NullString
+}
+
+func (q *Queries) CreateUserWebsiteTag(ctx context.Context, arg CreateUserWebsiteTagParams) error {
+	_, err := q.db.ExecContext(ctx, createUserWebsiteTag, arg.UserUuid, arg.WebsiteUuid, arg.Tag)
+	return err
+}
+
 const createWebsite = `-- name: CreateWebsite :one
 INSERT INTO websites
-(uuid, url, title, content, update_time)
+(uuid, url, title, content, update_time, items, health)
 VALUES
-($1, $2, $3, $4, $5)
+($1, $2, $3, $4, $5, $6, $7)
 ON CONFLICT (url) DO
 UPDATE SET url=$2
-RETURNING uuid, url, title, content, update_time
+RETURNING uuid, url, title, content, update_time, previous_content, items, health
 `
 
 type CreateWebsiteParams struct {
@@ -60,6 +362,8 @@ type CreateWebsiteParams struct {
 	Title      sql.NullString
 	Content    sql.NullString
 	UpdateTime sql.NullTime
+	Items      sql.NullString
+	Health     sql.NullString
 }
 
 func (q *Queries) CreateWebsite(ctx context.Context, arg CreateWebsiteParams) (Website, error) {
@@ -69,6 +373,8 @@ func (q *Queries) CreateWebsite(ctx context.Context, arg CreateWebsiteParams) (W
 		arg.Title,
 		arg.Content,
 		arg.UpdateTime,
+		arg.Items,
+		arg.Health,
 	)
 	var i Website
 	err := row.Scan(
@@ -77,10 +383,133 @@ func (q *Queries) CreateWebsite(ctx context.Context, arg CreateWebsiteParams) (W
 		&i.Title,
 		&i.Content,
 		&i.UpdateTime,
+		&i.PreviousContent,
+		&i.Items,
+		&i.Health,
 	)
 	return i, err
 }
 
+const createWebsiteUpdateRequest = `-- name: CreateWebsiteUpdateRequest :exec
+INSERT INTO website_update_requests
+(uuid, website_uuid, trace_context, create_time)
+VALUES
+($1, $2, $3, $4)
+`
+
+type CreateWebsiteUpdateRequestParams struct {
+	Uuid         sql.NullString
+	WebsiteUuid  sql.NullString
+	TraceContext sql.NullString
+	CreateTime   sql.NullTime
+}
+
+func (q *Queries) CreateWebsiteUpdateRequest(ctx context.Context, arg CreateWebsiteUpdateRequestParams) error {
+	_, err := q.db.ExecContext(ctx, createWebsiteUpdateRequest,
+		arg.Uuid,
+		arg.WebsiteUuid,
+		arg.TraceContext,
+		arg.CreateTime,
+	)
+	return err
+}
+
+const deleteAPIToken = `-- name: DeleteAPIToken :exec
+DELETE FROM api_tokens
+WHERE user_uuid=$1 and uuid=$2
+`
+
+type DeleteAPITokenParams struct {
+	UserUuid sql.NullString
+	Uuid     sql.NullString
+}
+
+func (q *Queries) DeleteAPIToken(ctx context.Context, arg DeleteAPITokenParams) error {
+	_, err := q.db.ExecContext(ctx, deleteAPIToken, arg.UserUuid, arg.Uuid)
+	return err
+}
+
+const deleteGroupPublicLink = `-- name: DeleteGroupPublicLink :exec
+DELETE FROM group_public_links
+WHERE owner_uuid=$1 and group_name=$2
+`
+
+type DeleteGroupPublicLinkParams struct {
+	OwnerUuid sql.NullString
+	GroupName sql.NullString
+}
+
+func (q *Queries) DeleteGroupPublicLink(ctx context.Context, arg DeleteGroupPublicLinkParams) error {
+	_, err := q.db.ExecContext(ctx, deleteGroupPublicLink,
+		arg.OwnerUuid,
+		arg.GroupName,
+	)
+	return err
+}
+
+const deleteGroupShare = `-- name: DeleteGroupShare :exec
+DELETE FROM group_shares
+WHERE uuid=$1
+`
+
+func (q *Queries) DeleteGroupShare(ctx context.Context, uuid sql.NullString) error {
+	_, err := q.db.ExecContext(ctx, deleteGroupShare, uuid)
+	return err
+}
+
+const deleteGroupShareSubscription = `-- name: DeleteGroupShareSubscription :exec
+DELETE FROM group_share_subscriptions
+WHERE share_uuid=$1 and user_uuid=$2
+`
+
+type DeleteGroupShareSubscriptionParams struct {
+	ShareUuid sql.NullString
+	UserUuid  sql.NullString
+}
+
+func (q *Queries) DeleteGroupShareSubscription(ctx context.Context, arg DeleteGroupShareSubscriptionParams) error {
+	_, err := q.db.ExecContext(ctx, deleteGroupShareSubscription,
+		arg.ShareUuid,
+		arg.UserUuid,
+	)
+	return err
+}
+
+const deleteGroupShareSubscriptions = `-- name: DeleteGroupShareSubscriptions :exec
+DELETE FROM group_share_subscriptions
+WHERE share_uuid=$1
+`
+
+func (q *Queries) DeleteGroupShareSubscriptions(ctx context.Context, shareUuid sql.NullString) error {
+	_, err := q.db.ExecContext(ctx, deleteGroupShareSubscriptions, shareUuid)
+	return err
+}
+
+const deleteImportJobs = `-- name: DeleteImportJobs :exec
+DELETE FROM import_jobs
+WHERE create_time < $1
+`
+
+func (q *Queries) DeleteImportJobs(ctx context.Context, createTime sql.NullTime) error {
+	_, err := q.db.ExecContext(ctx, deleteImportJobs, createTime)
+	return err
+}
+
+const deleteUserTag = `-- name: DeleteUserTag :exec
+DELETE FROM user_website_tags
+WHERE user_uuid=$1 and tag=$2
+`
+
+type DeleteUserTagParams struct {
+	UserUuid sql.NullString
+	Tag      sql.NullString
+}
+
+func (q *Queries) DeleteUserTag(ctx context.Context, arg DeleteUserTagParams) error {
+	_, err := q.db.ExecContext(ctx, deleteUserTag, arg.UserUuid, arg.Tag)
+	return err
+}
+
 const deleteUserWebsite = `-- name: DeleteUserWebsite :exec
 DELETE FROM user_websites
 where user_uuid=$1 and website_uuid=$2
@@ -96,6 +525,62 @@ func (q *Queries) DeleteUserWebsite(ctx context.Context, arg DeleteUserWebsitePa
 	return err
 }
 
+const deleteUserWebsiteGroup = `-- name: DeleteUserWebsiteGroup :exec
+DELETE FROM user_website_groups
+WHERE user_uuid=$1 and group_name=$2
+`
+
+type DeleteUserWebsiteGroupParams struct {
+	UserUuid  sql.NullString
+	GroupName sql.NullString
+}
+
+func (q *Queries) DeleteUserWebsiteGroup(ctx context.Context, arg DeleteUserWebsiteGroupParams) error {
+	_, err := q.db.ExecContext(ctx, deleteUserWebsiteGroup, arg.UserUuid, arg.GroupName)
+	return err
+}
+
+const deleteUserWebsiteGroups = `-- name: DeleteUserWebsiteGroups :exec
+DELETE FROM user_website_groups
+WHERE user_uuid=$1
+`
+
+func (q *Queries) DeleteUserWebsiteGroups(ctx context.Context, userUuid sql.NullString) error {
+	_, err := q.db.ExecContext(ctx, deleteUserWebsiteGroups, userUuid)
+	return err
+}
+
+const deleteUserWebsiteTag = `-- name: DeleteUserWebsiteTag :exec
+DELETE FROM user_website_tags
+WHERE user_uuid=$1 and website_uuid=$2 and tag=$3
+`
+
+type DeleteUserWebsiteTagParams struct {
+	UserUuid    sql.NullString
+	WebsiteUuid sql.NullString
+	Tag         sql.NullString
+}
+
+func (q *Queries) DeleteUserWebsiteTag(ctx context.Context, arg DeleteUserWebsiteTagParams) error {
+	_, err := q.db.ExecContext(ctx, deleteUserWebsiteTag, arg.UserUuid, arg.WebsiteUuid, arg.Tag)
+	return err
+}
+
+const deleteUserWebsiteTags = `-- name: DeleteUserWebsiteTags :exec
+DELETE FROM user_website_tags
+WHERE user_uuid=$1 and website_uuid=$2
+`
+
+type DeleteUserWebsiteTagsParams struct {
+	UserUuid    sql.NullString
+	WebsiteUuid sql.NullString
+}
+
+func (q *Queries) DeleteUserWebsiteTags(ctx context.Context, arg DeleteUserWebsiteTagsParams) error {
+	_, err := q.db.ExecContext(ctx, deleteUserWebsiteTags, arg.UserUuid, arg.WebsiteUuid)
+	return err
+}
+
 const deleteWebsite = `-- name: DeleteWebsite :exec
 DELETE FROM websites WHERE uuid=$1
 `
@@ -105,9 +590,160 @@ func (q *Queries) DeleteWebsite(ctx context.Context, uuid sql.NullString) error
 	return err
 }
 
+const deleteWorkerStatus = `-- name: DeleteWorkerStatus :exec
+DELETE FROM worker_statuses
+WHERE worker_id=$1
+`
+
+func (q *Queries) DeleteWorkerStatus(ctx context.Context, workerID sql.NullString) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkerStatus, workerID)
+	return err
+}
+
+const getAPITokenByHash = `-- name: GetAPITokenByHash :one
+SELECT uuid, user_uuid, name, token_hash, create_time FROM api_tokens
+WHERE token_hash=$1
+`
+
+func (q *Queries) GetAPITokenByHash(ctx context.Context, tokenHash sql.NullString) (ApiToken, error) {
+	row := q.db.QueryRowContext(ctx, getAPITokenByHash, tokenHash)
+	var i ApiToken
+	err := row.Scan(
+		&i.Uuid,
+		&i.UserUuid,
+		&i.Name,
+		&i.TokenHash,
+		&i.CreateTime,
+	)
+	return i, err
+}
+
+const getGroupPublicLink = `-- name: GetGroupPublicLink :one
+SELECT token, owner_uuid, group_name, create_time FROM group_public_links
+WHERE token=$1
+`
+
+func (q *Queries) GetGroupPublicLink(ctx context.Context, token sql.NullString) (GroupPublicLink, error) {
+	row := q.db.QueryRowContext(ctx, getGroupPublicLink, token)
+	var i GroupPublicLink
+	err := row.Scan(
+		&i.Token,
+		&i.OwnerUuid,
+		&i.GroupName,
+		&i.CreateTime,
+	)
+	return i, err
+}
+
+const getGroupPublicLinkByGroup = `-- name: GetGroupPublicLinkByGroup :one
+SELECT token, owner_uuid, group_name, create_time FROM group_public_links
+WHERE owner_uuid=$1 and group_name=$2
+`
+
+type GetGroupPublicLinkByGroupParams struct {
+	OwnerUuid sql.NullString
+	GroupName sql.NullString
+}
+
+func (q *Queries) GetGroupPublicLinkByGroup(ctx context.Context, arg GetGroupPublicLinkByGroupParams) (GroupPublicLink, error) {
+	row := q.db.QueryRowContext(ctx, getGroupPublicLinkByGroup, arg.OwnerUuid, arg.GroupName)
+	var i GroupPublicLink
+	err := row.Scan(
+		&i.Token,
+		&i.OwnerUuid,
+		&i.GroupName,
+		&i.CreateTime,
+	)
+	return i, err
+}
+
+const getGroupShare = `-- name: GetGroupShare :one
+SELECT uuid, owner_uuid, group_name, mode, create_time FROM group_shares
+WHERE uuid=$1
+`
+
+func (q *Queries) GetGroupShare(ctx context.Context, uuid sql.NullString) (GroupShare, error) {
+	row := q.db.QueryRowContext(ctx, getGroupShare, uuid)
+	var i GroupShare
+	err := row.Scan(
+		&i.Uuid,
+		&i.OwnerUuid,
+		&i.GroupName,
+		&i.Mode,
+		&i.CreateTime,
+	)
+	return i, err
+}
+
+const getGroupShareByGroup = `-- name: GetGroupShareByGroup :one
+SELECT uuid, owner_uuid, group_name, mode, create_time FROM group_shares
+WHERE owner_uuid=$1 and group_name=$2
+`
+
+type GetGroupShareByGroupParams struct {
+	OwnerUuid sql.NullString
+	GroupName sql.NullString
+}
+
+func (q *Queries) GetGroupShareByGroup(ctx context.Context, arg GetGroupShareByGroupParams) (GroupShare, error) {
+	row := q.db.QueryRowContext(ctx, getGroupShareByGroup, arg.OwnerUuid, arg.GroupName)
+	var i GroupShare
+	err := row.Scan(
+		&i.Uuid,
+		&i.OwnerUuid,
+		&i.GroupName,
+		&i.Mode,
+		&i.CreateTime,
+	)
+	return i, err
+}
+
+const getImportJob = `-- name: GetImportJob :one
+SELECT uuid, user_uuid, status, total, results, error, create_time, finish_time FROM import_jobs
+WHERE uuid=$1 and user_uuid=$2
+`
+
+type GetImportJobParams struct {
+	Uuid     sql.NullString
+	UserUuid sql.NullString
+}
+
+func (q *Queries) GetImportJob(ctx context.Context, arg GetImportJobParams) (ImportJob, error) {
+	row := q.db.QueryRowContext(ctx, getImportJob, arg.Uuid, arg.UserUuid)
+	var i ImportJob
+	err := row.Scan(
+		&i.Uuid,
+		&i.UserUuid,
+		&i.Status,
+		&i.Total,
+		&i.Results,
+		&i.Error,
+		&i.CreateTime,
+		&i.FinishTime,
+	)
+	return i, err
+}
+
+const getUserByUsername = `-- name: GetUserByUsername :one
+SELECT uuid, username, password_hash, create_time FROM users
+WHERE username=$1
+`
+
+func (q *Queries) GetUserByUsername(ctx context.Context, username sql.NullString) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByUsername, username)
+	var i User
+	err := row.Scan(
+		&i.Uuid,
+		&i.Username,
+		&i.PasswordHash,
+		&i.CreateTime,
+	)
+	return i, err
+}
+
 const getUserWebsite = `-- name: GetUserWebsite :one
-SELECT website_uuid, user_uuid, access_time, group_name ,
-uuid, url, title, update_time 
+SELECT website_uuid, user_uuid, access_time, group_name, last_read_item, snooze_until, muted, share_uuid ,
+uuid, url, title, update_time, items, health 
 FROM user_websites JOIN websites ON user_websites.website_uuid=websites.uuid 
 WHERE user_uuid=$1 and website_uuid=$2
 `
@@ -118,14 +754,20 @@ type GetUserWebsiteParams struct {
 }
 
 type GetUserWebsiteRow struct {
-	WebsiteUuid sql.NullString
-	UserUuid    sql.NullString
-	AccessTime  sql.NullTime
-	GroupName   sql.NullString
-	Uuid        sql.NullString
-	Url         sql.NullString
-	Title       sql.NullString
-	UpdateTime  sql.NullTime
+	WebsiteUuid  sql.NullString
+	UserUuid     sql.NullString
+	AccessTime   sql.NullTime
+	GroupName    sql.NullString
+	LastReadItem sql.NullString
+	SnoozeUntil  sql.NullTime
+	Muted        sql.NullBool
+	ShareUuid    sql.NullString
+	Uuid         sql.NullString
+	Url          sql.NullString
+	Title        sql.NullString
+	UpdateTime   sql.NullTime
+	Items        sql.NullString
+	Health       sql.NullString
 }
 
 func (q *Queries) GetUserWebsite(ctx context.Context, arg GetUserWebsiteParams) (GetUserWebsiteRow, error) {
@@ -136,16 +778,22 @@ func (q *Queries) GetUserWebsite(ctx context.Context, arg GetUserWebsiteParams)
 		&i.UserUuid,
 		&i.AccessTime,
 		&i.GroupName,
+		&i.LastReadItem,
+		&i.SnoozeUntil,
+		&i.Muted,
+		&i.ShareUuid,
 		&i.Uuid,
 		&i.Url,
 		&i.Title,
 		&i.UpdateTime,
+		&i.Items,
+		&i.Health,
 	)
 	return i, err
 }
 
 const getWebsite = `-- name: GetWebsite :one
-SELECT uuid, url, title, content, update_time from websites WHERE uuid=$1
+SELECT uuid, url, title, content, update_time, previous_content, items, health from websites WHERE uuid=$1
 `
 
 func (q *Queries) GetWebsite(ctx context.Context, uuid sql.NullString) (Website, error) {
@@ -157,46 +805,288 @@ func (q *Queries) GetWebsite(ctx context.Context, uuid sql.NullString) (Website,
 		&i.Title,
 		&i.Content,
 		&i.UpdateTime,
+		&i.PreviousContent,
+		&i.Items,
+		&i.Health,
 	)
 	return i, err
 }
 
-const getWebsiteSetting = `-- name: GetWebsiteSetting :one
-SELECT domain, focus_index_from, focus_index_to, title_goquery_selector, date_goquery_selector
-FROM website_settings 
-WHERE domain=$1
+const getWebsiteSetting = `-- name: GetWebsiteSetting :one
+SELECT domain, focus_index_from, focus_index_to, title_goquery_selector, date_goquery_selector, consecutive_failures, broken_at, item_name_goquery_selector, item_link_goquery_selector
+FROM website_settings 
+WHERE domain=$1
+`
+
+func (q *Queries) GetWebsiteSetting(ctx context.Context, domain sql.NullString) (WebsiteSetting, error) {
+	row := q.db.QueryRowContext(ctx, getWebsiteSetting, domain)
+	var i WebsiteSetting
+	err := row.Scan(
+		&i.Domain,
+		&i.FocusIndexFrom,
+		&i.FocusIndexTo,
+		&i.TitleGoquerySelector,
+		&i.DateGoquerySelector,
+		&i.ConsecutiveFailures,
+		&i.BrokenAt,
+		&i.ItemNameGoquerySelector,
+		&i.ItemLinkGoquerySelector,
+	)
+	return i, err
+}
+
+const listAPITokens = `-- name: ListAPITokens :many
+SELECT uuid, user_uuid, name, token_hash, create_time FROM api_tokens
+WHERE user_uuid=$1
+ORDER BY create_time
+`
+
+func (q *Queries) ListAPITokens(ctx context.Context, userUuid sql.NullString) ([]ApiToken, error) {
+	rows, err := q.db.QueryContext(ctx, listAPITokens, userUuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ApiToken
+	for rows.Next() {
+		var i ApiToken
+		if err := rows.Scan(
+			&i.Uuid,
+			&i.UserUuid,
+			&i.Name,
+			&i.TokenHash,
+			&i.CreateTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGroupShareSubscriptions = `-- name: ListGroupShareSubscriptions :many
+SELECT share_uuid, user_uuid, group_name, create_time FROM group_share_subscriptions
+WHERE share_uuid=$1
+ORDER BY create_time
+`
+
+func (q *Queries) ListGroupShareSubscriptions(ctx context.Context, shareUuid sql.NullString) ([]GroupShareSubscription, error) {
+	rows, err := q.db.QueryContext(ctx, listGroupShareSubscriptions, shareUuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GroupShareSubscription
+	for rows.Next() {
+		var i GroupShareSubscription
+		if err := rows.Scan(
+			&i.ShareUuid,
+			&i.UserUuid,
+			&i.GroupName,
+			&i.CreateTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGroupShares = `-- name: ListGroupShares :many
+SELECT uuid, owner_uuid, group_name, mode, create_time FROM group_shares
+WHERE owner_uuid=$1
+ORDER BY group_name
+`
+
+func (q *Queries) ListGroupShares(ctx context.Context, ownerUuid sql.NullString) ([]GroupShare, error) {
+	rows, err := q.db.QueryContext(ctx, listGroupShares, ownerUuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GroupShare
+	for rows.Next() {
+		var i GroupShare
+		if err := rows.Scan(
+			&i.Uuid,
+			&i.OwnerUuid,
+			&i.GroupName,
+			&i.Mode,
+			&i.CreateTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserGroupShareSubscriptions = `-- name: ListUserGroupShareSubscriptions :many
+SELECT share_uuid, user_uuid, group_name, create_time FROM group_share_subscriptions
+WHERE user_uuid=$1
+ORDER BY create_time
+`
+
+func (q *Queries) ListUserGroupShareSubscriptions(ctx context.Context, userUuid sql.NullString) ([]GroupShareSubscription, error) {
+	rows, err := q.db.QueryContext(ctx, listUserGroupShareSubscriptions, userUuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GroupShareSubscription
+	for rows.Next() {
+		var i GroupShareSubscription
+		if err := rows.Scan(
+			&i.ShareUuid,
+			&i.UserUuid,
+			&i.GroupName,
+			&i.CreateTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserTags = `-- name: ListUserTags :many
+SELECT DISTINCT tag FROM user_website_tags
+WHERE user_uuid=$1
+ORDER BY tag
+`
+
+func (q *Queries) ListUserTags(ctx context.Context, userUuid sql.NullString) ([]sql.NullString, error) {
+	rows, err := q.db.QueryContext(ctx, listUserTags, userUuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []sql.NullString
+	for rows.Next() {
+		var tag sql.NullString
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		items = append(items, tag)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserWebsiteGroups = `-- name: ListUserWebsiteGroups :many
+SELECT group_name FROM user_website_groups
+WHERE user_uuid=$1
+ORDER BY position
 `
 
-func (q *Queries) GetWebsiteSetting(ctx context.Context, domain sql.NullString) (WebsiteSetting, error) {
-	row := q.db.QueryRowContext(ctx, getWebsiteSetting, domain)
-	var i WebsiteSetting
-	err := row.Scan(
-		&i.Domain,
-		&i.FocusIndexFrom,
-		&i.FocusIndexTo,
-		&i.TitleGoquerySelector,
-		&i.DateGoquerySelector,
-	)
-	return i, err
+func (q *Queries) ListUserWebsiteGroups(ctx context.Context, userUuid sql.NullString) ([]sql.NullString, error) {
+	rows, err := q.db.QueryContext(ctx, listUserWebsiteGroups, userUuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []sql.NullString
+	for rows.Next() {
+		var group_name sql.NullString
+		if err := rows.Scan(&group_name); err != nil {
+			return nil, err
+		}
+		items = append(items, group_name)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserWebsiteTags = `-- name: ListUserWebsiteTags :many
+SELECT user_uuid, website_uuid, tag FROM user_website_tags
+WHERE user_uuid=$1
+ORDER BY tag
+`
+
+func (q *Queries) ListUserWebsiteTags(ctx context.Context, userUuid sql.NullString) ([]UserWebsiteTag, error) {
+	rows, err := q.db.QueryContext(ctx, listUserWebsiteTags, userUuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserWebsiteTag
+	for rows.Next() {
+		var i UserWebsiteTag
+		if err := rows.Scan(&i.UserUuid, &i.WebsiteUuid, &i.Tag); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const listUserWebsites = `-- name: ListUserWebsites :many
-SELECT website_uuid, user_uuid, access_time, group_name,
-uuid, url, title, update_time 
+SELECT website_uuid, user_uuid, access_time, group_name, last_read_item, snooze_until, muted, share_uuid,
+uuid, url, title, update_time, items, health 
 FROM user_websites JOIN websites ON user_websites.website_uuid=websites.uuid 
 WHERE user_uuid=$1
-ORDER BY (update_time > access_time) DESC, update_time DESC, access_time DESC
+ORDER BY (
+  update_time > access_time
+  AND NOT coalesce(muted, false)
+  AND (snooze_until IS NULL OR snooze_until <= (now() at time zone 'utc'))
+) DESC, update_time DESC, access_time DESC
 `
 
 type ListUserWebsitesRow struct {
-	WebsiteUuid sql.NullString
-	UserUuid    sql.NullString
-	AccessTime  sql.NullTime
-	GroupName   sql.NullString
-	Uuid        sql.NullString
-	Url         sql.NullString
-	Title       sql.NullString
-	UpdateTime  sql.NullTime
+	WebsiteUuid  sql.NullString
+	UserUuid     sql.NullString
+	AccessTime   sql.NullTime
+	GroupName    sql.NullString
+	LastReadItem sql.NullString
+	SnoozeUntil  sql.NullTime
+	Muted        sql.NullBool
+	ShareUuid    sql.NullString
+	Uuid         sql.NullString
+	Url          sql.NullString
+	Title        sql.NullString
+	UpdateTime   sql.NullTime
+	Items        sql.NullString
+	Health       sql.NullString
 }
 
 func (q *Queries) ListUserWebsites(ctx context.Context, userUuid sql.NullString) ([]ListUserWebsitesRow, error) {
@@ -213,10 +1103,16 @@ func (q *Queries) ListUserWebsites(ctx context.Context, userUuid sql.NullString)
 			&i.UserUuid,
 			&i.AccessTime,
 			&i.GroupName,
+			&i.LastReadItem,
+			&i.SnoozeUntil,
+			&i.Muted,
+			&i.ShareUuid,
 			&i.Uuid,
 			&i.Url,
 			&i.Title,
 			&i.UpdateTime,
+			&i.Items,
+			&i.Health,
 		); err != nil {
 			return nil, err
 		}
@@ -232,8 +1128,8 @@ func (q *Queries) ListUserWebsites(ctx context.Context, userUuid sql.NullString)
 }
 
 const listUserWebsitesByGroup = `-- name: ListUserWebsitesByGroup :many
-SELECT website_uuid, user_uuid, access_time, group_name ,
-uuid, url, title, update_time 
+SELECT website_uuid, user_uuid, access_time, group_name, last_read_item, snooze_until, muted, share_uuid ,
+uuid, url, title, update_time, items, health 
 FROM user_websites JOIN websites ON user_websites.website_uuid=websites.uuid 
 WHERE user_uuid=$1 and group_name=$2
 `
@@ -244,14 +1140,20 @@ type ListUserWebsitesByGroupParams struct {
 }
 
 type ListUserWebsitesByGroupRow struct {
-	WebsiteUuid sql.NullString
-	UserUuid    sql.NullString
-	AccessTime  sql.NullTime
-	GroupName   sql.NullString
-	Uuid        sql.NullString
-	Url         sql.NullString
-	Title       sql.NullString
-	UpdateTime  sql.NullTime
+	WebsiteUuid  sql.NullString
+	UserUuid     sql.NullString
+	AccessTime   sql.NullTime
+	GroupName    sql.NullString
+	LastReadItem sql.NullString
+	SnoozeUntil  sql.NullTime
+	Muted        sql.NullBool
+	ShareUuid    sql.NullString
+	Uuid         sql.NullString
+	Url          sql.NullString
+	Title        sql.NullString
+	UpdateTime   sql.NullTime
+	Items        sql.NullString
+	Health       sql.NullString
 }
 
 func (q *Queries) ListUserWebsitesByGroup(ctx context.Context, arg ListUserWebsitesByGroupParams) ([]ListUserWebsitesByGroupRow, error) {
@@ -268,10 +1170,16 @@ func (q *Queries) ListUserWebsitesByGroup(ctx context.Context, arg ListUserWebsi
 			&i.UserUuid,
 			&i.AccessTime,
 			&i.GroupName,
+			&i.LastReadItem,
+			&i.SnoozeUntil,
+			&i.Muted,
+			&i.ShareUuid,
 			&i.Uuid,
 			&i.Url,
 			&i.Title,
 			&i.UpdateTime,
+			&i.Items,
+			&i.Health,
 		); err != nil {
 			return nil, err
 		}
@@ -287,7 +1195,7 @@ func (q *Queries) ListUserWebsitesByGroup(ctx context.Context, arg ListUserWebsi
 }
 
 const listWebsiteSettings = `-- name: ListWebsiteSettings :many
-SELECT domain, focus_index_from, focus_index_to, title_goquery_selector, date_goquery_selector
+SELECT domain, focus_index_from, focus_index_to, title_goquery_selector, date_goquery_selector, consecutive_failures, broken_at, item_name_goquery_selector, item_link_goquery_selector
 FROM website_settings
 `
 
@@ -306,6 +1214,10 @@ func (q *Queries) ListWebsiteSettings(ctx context.Context) ([]WebsiteSetting, er
 			&i.FocusIndexTo,
 			&i.TitleGoquerySelector,
 			&i.DateGoquerySelector,
+			&i.ConsecutiveFailures,
+			&i.BrokenAt,
+			&i.ItemNameGoquerySelector,
+			&i.ItemLinkGoquerySelector,
 		); err != nil {
 			return nil, err
 		}
@@ -321,7 +1233,7 @@ func (q *Queries) ListWebsiteSettings(ctx context.Context) ([]WebsiteSetting, er
 }
 
 const listWebsites = `-- name: ListWebsites :many
-SELECT uuid, url, title, content, update_time FROM websites
+SELECT uuid, url, title, content, update_time, previous_content, items, health FROM websites
 `
 
 func (q *Queries) ListWebsites(ctx context.Context) ([]Website, error) {
@@ -339,6 +1251,9 @@ func (q *Queries) ListWebsites(ctx context.Context) ([]Website, error) {
 			&i.Title,
 			&i.Content,
 			&i.UpdateTime,
+			&i.PreviousContent,
+			&i.Items,
+			&i.Health,
 		); err != nil {
 			return nil, err
 		}
@@ -353,24 +1268,532 @@ func (q *Queries) ListWebsites(ctx context.Context) ([]Website, error) {
 	return items, nil
 }
 
-const updateUserWebsite = `-- name: UpdateUserWebsite :one
-UPDATE user_websites SET
-access_time=$1, group_name=$2
-WHERE user_uuid=$3 and website_uuid=$4
-RETURNING website_uuid, user_uuid, access_time, group_name
+const listWorkerStatuses = `-- name: ListWorkerStatuses :many
+SELECT worker_id, executor_count, busy_executors, queue_depth, start_time, heartbeat_time FROM worker_statuses
+ORDER BY worker_id
 `
 
-type UpdateUserWebsiteParams struct {
-	AccessTime  sql.NullTime
+func (q *Queries) ListWorkerStatuses(ctx context.Context) ([]WorkerStatus, error) {
+	rows, err := q.db.QueryContext(ctx, listWorkerStatuses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkerStatus
+	for rows.Next() {
+		var i WorkerStatus
+		if err := rows.Scan(
+			&i.WorkerID,
+			&i.ExecutorCount,
+			&i.BusyExecutors,
+			&i.QueueDepth,
+			&i.StartTime,
+			&i.HeartbeatTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveUserWebsites = `-- name: MoveUserWebsites :exec
+UPDATE user_websites SET group_name=$2
+WHERE user_uuid=$1 and website_uuid = ANY($3::varchar[])
+`
+
+type MoveUserWebsitesParams struct {
+	UserUuid     sql.NullString
+	GroupName    sql.NullString
+	WebsiteUuids []string
+}
+
+func (q *Queries) MoveUserWebsites(ctx context.Context, arg MoveUserWebsitesParams) error {
+	_, err := q.db.ExecContext(ctx, moveUserWebsites, arg.UserUuid, arg.GroupName, pq.Array(arg.WebsiteUuids))
+	return err
+}
+
+const notifyWebsiteEvent = `-- name: NotifyWebsiteEvent :exec
+SELECT pg_notify('website_events', $1::text)
+`
+
+func (q *Queries) NotifyWebsiteEvent(ctx context.Context, payload string) error {
+	_, err := q.db.ExecContext(ctx, notifyWebsiteEvent, payload)
+	return err
+}
+
+const recordWebsiteSettingParse = `-- name: RecordWebsiteSettingParse :one
+UPDATE website_settings SET
+consecutive_failures=CASE WHEN $1::boolean THEN 0
+  ELSE coalesce(website_settings.consecutive_failures, 0) + 1 END,
+broken_at=CASE WHEN $1::boolean THEN NULL
+  WHEN website_settings.broken_at IS NULL AND $2::integer > 0
+    AND coalesce(website_settings.consecutive_failures, 0) + 1 >= $2::integer
+  THEN $3::timestamp
+  ELSE website_settings.broken_at END
+FROM (
+  SELECT domain, broken_at FROM website_settings
+  WHERE domain=$4
+  FOR UPDATE
+) AS previous
+WHERE website_settings.domain=previous.domain
+RETURNING website_settings.domain, website_settings.focus_index_from, website_settings.focus_index_to, website_settings.title_goquery_selector, website_settings.date_goquery_selector, website_settings.consecutive_failures, website_settings.broken_at, website_settings.item_name_goquery_selector, website_settings.item_link_goquery_selector, previous.broken_at AS previous_broken_at
+`
+
+type RecordWebsiteSettingParseParams struct {
+	Success   bool
+	Threshold int32
+	Now       time.Time
+	Domain    sql.NullString
+}
+
+type RecordWebsiteSettingParseRow struct {
+	Domain                  sql.NullString
+	FocusIndexFrom          sql.NullInt32
+	FocusIndexTo            sql.NullInt32
+	TitleGoquerySelector    sql.NullString
+	DateGoquerySelector     sql.NullString
+	ConsecutiveFailures     sql.NullInt32
+	BrokenAt                sql.NullTime
+	ItemNameGoquerySelector sql.NullString
+	ItemLinkGoquerySelector sql.NullString
+	PreviousBrokenAt        sql.NullTime
+}
+
+func (q *Queries) RecordWebsiteSettingParse(ctx context.Context, arg RecordWebsiteSettingParseParams) (RecordWebsiteSettingParseRow, error) {
+	row := q.db.QueryRowContext(ctx, recordWebsiteSettingParse,
+		arg.Success,
+		arg.Threshold,
+		arg.Now,
+		arg.Domain,
+	)
+	var i RecordWebsiteSettingParseRow
+	err := row.Scan(
+		&i.Domain,
+		&i.FocusIndexFrom,
+		&i.FocusIndexTo,
+		&i.TitleGoquerySelector,
+		&i.DateGoquerySelector,
+		&i.ConsecutiveFailures,
+		&i.BrokenAt,
+		&i.ItemNameGoquerySelector,
+		&i.ItemLinkGoquerySelector,
+		&i.PreviousBrokenAt,
+	)
+	return i, err
+}
+
+const renameGroupPublicLink = `-- name: RenameGroupPublicLink :exec
+UPDATE group_public_links SET group_name=$3
+WHERE owner_uuid=$1 and group_name=$2 and NOT EXISTS (
+  SELECT 1 FROM group_public_links WHERE owner_uuid=$1 and group_name=$3
+)
+`
+
+type RenameGroupPublicLinkParams struct {
+	OwnerUuid   sql.NullString
+	GroupName   sql.NullString
+	GroupName_2 sql.NullString
+}
+
+func (q *Queries) RenameGroupPublicLink(ctx context.Context, arg RenameGroupPublicLinkParams) error {
+	_, err := q.db.ExecContext(ctx, renameGroupPublicLink,
+		arg.OwnerUuid,
+		arg.GroupName,
+		arg.GroupName_2,
+	)
+	return err
+}
+
+const renameGroupShare = `-- name: RenameGroupShare :exec
+UPDATE group_shares SET group_name=$3
+WHERE owner_uuid=$1 and group_name=$2 and NOT EXISTS (
+  SELECT 1 FROM group_shares WHERE owner_uuid=$1 and group_name=$3
+)
+`
+
+type RenameGroupShareParams struct {
+	OwnerUuid   sql.NullString
 	GroupName   sql.NullString
+	GroupName_2 sql.NullString
+}
+
+func (q *Queries) RenameGroupShare(ctx context.Context, arg RenameGroupShareParams) error {
+	_, err := q.db.ExecContext(ctx, renameGroupShare,
+		arg.OwnerUuid,
+		arg.GroupName,
+		arg.GroupName_2,
+	)
+	return err
+}
+
+const renameUserTag = `-- name: RenameUserTag :exec
+UPDATE user_website_tags SET tag=$3
+WHERE user_uuid=$1 and tag=$2 and website_uuid NOT IN (
+  SELECT website_uuid FROM user_website_tags WHERE user_uuid=$1 and tag=$3
+)
+`
+
+type RenameUserTagParams struct {
+	UserUuid sql.NullString
+	Tag      sql.NullString
+	Tag_2    sql.NullString
+}
+
+func (q *Queries) RenameUserTag(ctx context.Context, arg RenameUserTagParams) error {
+	_, err := q.db.ExecContext(ctx, renameUserTag, arg.UserUuid, arg.Tag, arg.Tag_2)
+	return err
+}
+
+const renameUserWebsiteGroup = `-- name: RenameUserWebsiteGroup :exec
+UPDATE user_website_groups SET group_name=$3
+WHERE user_uuid=$1 and group_name=$2 and NOT EXISTS (
+  SELECT 1 FROM user_website_groups WHERE user_uuid=$1 and group_name=$3
+)
+`
+
+type RenameUserWebsiteGroupParams struct {
 	UserUuid    sql.NullString
+	GroupName   sql.NullString
+	GroupName_2 sql.NullString
+}
+
+func (q *Queries) RenameUserWebsiteGroup(ctx context.Context, arg RenameUserWebsiteGroupParams) error {
+	_, err := q.db.ExecContext(ctx, renameUserWebsiteGroup, arg.UserUuid, arg.GroupName, arg.GroupName_2)
+	return err
+}
+
+const renameWebsitePlaceholderGroups = `-- name: RenameWebsitePlaceholderGroups :exec
+UPDATE user_websites SET group_name=$1
+WHERE website_uuid=$2 AND group_name=$3
+`
+
+type RenameWebsitePlaceholderGroupsParams struct {
+	GroupName   sql.NullString
 	WebsiteUuid sql.NullString
+	Placeholder sql.NullString
+}
+
+func (q *Queries) RenameWebsitePlaceholderGroups(ctx context.Context, arg RenameWebsitePlaceholderGroupsParams) error {
+	_, err := q.db.ExecContext(ctx, renameWebsitePlaceholderGroups, arg.GroupName, arg.WebsiteUuid, arg.Placeholder)
+	return err
+}
+
+const searchUserWebsiteGroups = `-- name: SearchUserWebsiteGroups :many
+SELECT group_name
+FROM (
+  SELECT group_name, row_number() OVER (
+    ORDER BY
+      CASE WHEN $1::text = '' THEN sort_unread END DESC,
+      CASE WHEN $1::text = '' THEN sort_update_time END DESC,
+      CASE WHEN $1::text = '' THEN sort_access_time END DESC,
+      CASE WHEN $1::text = 'update_time' AND NOT $2::boolean THEN sort_update_time END,
+      CASE WHEN $1::text = 'update_time' AND $2::boolean THEN sort_update_time END DESC,
+      CASE WHEN $1::text = 'access_time' AND NOT $2::boolean THEN sort_access_time END,
+      CASE WHEN $1::text = 'access_time' AND $2::boolean THEN sort_access_time END DESC,
+      CASE WHEN $1::text = 'title' AND NOT $2::boolean THEN sort_title END,
+      CASE WHEN $1::text = 'title' AND $2::boolean THEN sort_title END DESC,
+      sort_uuid
+  ) AS sort_position
+  FROM (
+    SELECT group_name,
+    coalesce(
+      update_time > access_time
+      AND NOT coalesce(muted, false)
+      AND (snooze_until IS NULL OR snooze_until <= (now() at time zone 'utc')),
+      false
+    ) AS sort_unread,
+    coalesce(update_time, '0001-01-01') AS sort_update_time,
+    coalesce(access_time, '0001-01-01') AS sort_access_time,
+    lower(coalesce(title, '')) COLLATE "C" AS sort_title,
+    website_uuid COLLATE "C" AS sort_uuid
+    FROM user_websites JOIN websites ON user_websites.website_uuid=websites.uuid
+    WHERE user_uuid=$3
+    AND ($4::text IS NULL OR title ILIKE $4 OR url ILIKE $4)
+    AND ($5::text IS NULL OR substring(url from '://([^/:]+)') = $5)
+    AND (NOT $6::boolean OR update_time > access_time)
+    AND ($7::text IS NULL OR coalesce(health, 'unknown') = $7)
+    AND (
+      cardinality($8::text[]) = 0
+      OR (
+        SELECT count(DISTINCT tag) FROM user_website_tags
+        WHERE user_website_tags.user_uuid=user_websites.user_uuid
+        AND user_website_tags.website_uuid=user_websites.website_uuid
+        AND tag = ANY($8::text[])
+      ) >= CASE WHEN $9::boolean THEN cardinality($8::text[]) ELSE 1 END
+    )
+    AND (cardinality($10::text[]) = 0 OR group_name = ANY($10::text[]))
+  ) AS searched
+) AS positioned
+GROUP BY group_name
+ORDER BY min(sort_position)
+`
+
+type SearchUserWebsiteGroupsParams struct {
+	SortKey      string
+	SortDesc     bool
+	UserUuid     sql.NullString
+	Search       sql.NullString
+	Host         sql.NullString
+	UpdatedOnly  bool
+	Health       sql.NullString
+	Tags         []string
+	MatchAllTags bool
+	GroupNames   []string
+}
+
+func (q *Queries) SearchUserWebsiteGroups(ctx context.Context, arg SearchUserWebsiteGroupsParams) ([]sql.NullString, error) {
+	rows, err := q.db.QueryContext(ctx, searchUserWebsiteGroups,
+		arg.SortKey,
+		arg.SortDesc,
+		arg.UserUuid,
+		arg.Search,
+		arg.Host,
+		arg.UpdatedOnly,
+		arg.Health,
+		pq.Array(arg.Tags),
+		arg.MatchAllTags,
+		pq.Array(arg.GroupNames),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []sql.NullString
+	for rows.Next() {
+		var group_name sql.NullString
+		if err := rows.Scan(&group_name); err != nil {
+			return nil, err
+		}
+		items = append(items, group_name)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchUserWebsites = `-- name: SearchUserWebsites :many
+SELECT website_uuid, user_uuid, access_time, group_name, last_read_item, snooze_until, muted, share_uuid,
+uuid, url, title, update_time, items, health
+FROM (
+  SELECT website_uuid, user_uuid, access_time, group_name, last_read_item, snooze_until, muted, share_uuid,
+  uuid, url, title, update_time, items, health,
+  coalesce(
+    update_time > access_time
+    AND NOT coalesce(muted, false)
+    AND (snooze_until IS NULL OR snooze_until <= (now() at time zone 'utc')),
+    false
+  ) AS sort_unread,
+  coalesce(update_time, '0001-01-01') AS sort_update_time,
+  coalesce(access_time, '0001-01-01') AS sort_access_time,
+  lower(coalesce(title, '')) COLLATE "C" AS sort_title,
+  website_uuid COLLATE "C" AS sort_uuid
+  FROM user_websites JOIN websites ON user_websites.website_uuid=websites.uuid
+  WHERE user_uuid=$1
+  AND ($2::text IS NULL OR title ILIKE $2 OR url ILIKE $2)
+  AND ($3::text IS NULL OR substring(url from '://([^/:]+)') = $3)
+  AND (NOT $4::boolean OR update_time > access_time)
+  AND ($5::text IS NULL OR coalesce(health, 'unknown') = $5)
+  AND (
+    cardinality($6::text[]) = 0
+    OR (
+      SELECT count(DISTINCT tag) FROM user_website_tags
+      WHERE user_website_tags.user_uuid=user_websites.user_uuid
+      AND user_website_tags.website_uuid=user_websites.website_uuid
+      AND tag = ANY($6::text[])
+    ) >= CASE WHEN $7::boolean THEN cardinality($6::text[]) ELSE 1 END
+  )
+  AND (cardinality($8::text[]) = 0 OR group_name = ANY($8::text[]))
+) AS searched
+WHERE $9::text IS NULL
+OR ($10::text = 'update_time' AND (
+  CASE WHEN $11::boolean THEN sort_update_time < $12::timestamp
+  ELSE sort_update_time > $12::timestamp END
+  OR (sort_update_time = $12::timestamp AND sort_uuid > $9)
+))
+OR ($10::text = 'access_time' AND (
+  CASE WHEN $11::boolean THEN sort_access_time < $13::timestamp
+  ELSE sort_access_time > $13::timestamp END
+  OR (sort_access_time = $13::timestamp AND sort_uuid > $9)
+))
+OR ($10::text = 'title' AND (
+  CASE WHEN $11::boolean THEN sort_title < lower($14::text)
+  ELSE sort_title > lower($14::text) END
+  OR (sort_title = lower($14::text) AND sort_uuid > $9)
+))
+OR ($10::text = '' AND (
+  sort_unread < $15::boolean
+  OR (sort_unread = $15::boolean AND (
+    sort_update_time < $12::timestamp
+    OR (sort_update_time = $12::timestamp AND (
+      sort_access_time < $13::timestamp
+      OR (sort_access_time = $13::timestamp AND sort_uuid > $9)
+    ))
+  ))
+))
+ORDER BY
+  CASE WHEN $10::text = '' THEN sort_unread END DESC,
+  CASE WHEN $10::text = '' THEN sort_update_time END DESC,
+  CASE WHEN $10::text = '' THEN sort_access_time END DESC,
+  CASE WHEN $10::text = 'update_time' AND NOT $11::boolean THEN sort_update_time END,
+  CASE WHEN $10::text = 'update_time' AND $11::boolean THEN sort_update_time END DESC,
+  CASE WHEN $10::text = 'access_time' AND NOT $11::boolean THEN sort_access_time END,
+  CASE WHEN $10::text = 'access_time' AND $11::boolean THEN sort_access_time END DESC,
+  CASE WHEN $10::text = 'title' AND NOT $11::boolean THEN sort_title END,
+  CASE WHEN $10::text = 'title' AND $11::boolean THEN sort_title END DESC,
+  sort_uuid
+LIMIT $16
+`
+
+type SearchUserWebsitesParams struct {
+	UserUuid         sql.NullString
+	Search           sql.NullString
+	Host             sql.NullString
+	UpdatedOnly      bool
+	Health           sql.NullString
+	Tags             []string
+	MatchAllTags     bool
+	GroupNames       []string
+	CursorUuid       sql.NullString
+	SortKey          string
+	SortDesc         bool
+	CursorUpdateTime time.Time
+	CursorAccessTime time.Time
+	CursorTitle      string
+	CursorUnread     bool
+	PageLimit        sql.NullInt32
+}
+
+type SearchUserWebsitesRow struct {
+	WebsiteUuid  sql.NullString
+	UserUuid     sql.NullString
+	AccessTime   sql.NullTime
+	GroupName    sql.NullString
+	LastReadItem sql.NullString
+	SnoozeUntil  sql.NullTime
+	Muted        sql.NullBool
+	ShareUuid    sql.NullString
+	Uuid         sql.NullString
+	Url          sql.NullString
+	Title        sql.NullString
+	UpdateTime   sql.NullTime
+	Items        sql.NullString
+	Health       sql.NullString
+}
+
+func (q *Queries) SearchUserWebsites(ctx context.Context, arg SearchUserWebsitesParams) ([]SearchUserWebsitesRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchUserWebsites,
+		arg.UserUuid,
+		arg.Search,
+		arg.Host,
+		arg.UpdatedOnly,
+		arg.Health,
+		pq.Array(arg.Tags),
+		arg.MatchAllTags,
+		pq.Array(arg.GroupNames),
+		arg.CursorUuid,
+		arg.SortKey,
+		arg.SortDesc,
+		arg.CursorUpdateTime,
+		arg.CursorAccessTime,
+		arg.CursorTitle,
+		arg.CursorUnread,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchUserWebsitesRow
+	for rows.Next() {
+		var i SearchUserWebsitesRow
+		if err := rows.Scan(
+			&i.WebsiteUuid,
+			&i.UserUuid,
+			&i.AccessTime,
+			&i.GroupName,
+			&i.LastReadItem,
+			&i.SnoozeUntil,
+			&i.Muted,
+			&i.ShareUuid,
+			&i.Uuid,
+			&i.Url,
+			&i.Title,
+			&i.UpdateTime,
+			&i.Items,
+			&i.Health,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateImportJob = `-- name: UpdateImportJob :exec
+UPDATE import_jobs SET status=$2, results=$3, error=$4, finish_time=$5
+WHERE uuid=$1
+`
+
+type UpdateImportJobParams struct {
+	Uuid       sql.NullString
+	Status     sql.NullString
+	Results    sql.NullString
+	Error      sql.NullString
+	FinishTime sql.NullTime
+}
+
+func (q *Queries) UpdateImportJob(ctx context.Context, arg UpdateImportJobParams) error {
+	_, err := q.db.ExecContext(ctx, updateImportJob,
+		arg.Uuid,
+		arg.Status,
+		arg.Results,
+		arg.Error,
+		arg.FinishTime,
+	)
+	return err
+}
+
+const updateUserWebsite = `-- name: UpdateUserWebsite :one
+UPDATE user_websites SET
+access_time=$1, group_name=$2, last_read_item=$3, snooze_until=$4, muted=$5
+WHERE user_uuid=$6 and website_uuid=$7
+RETURNING website_uuid, user_uuid, access_time, group_name, last_read_item, snooze_until, muted, share_uuid
+`
+
+type UpdateUserWebsiteParams struct {
+	AccessTime   sql.NullTime
+	GroupName    sql.NullString
+	LastReadItem sql.NullString
+	SnoozeUntil  sql.NullTime
+	Muted        sql.NullBool
+	UserUuid     sql.NullString
+	WebsiteUuid  sql.NullString
 }
 
 func (q *Queries) UpdateUserWebsite(ctx context.Context, arg UpdateUserWebsiteParams) (UserWebsite, error) {
 	row := q.db.QueryRowContext(ctx, updateUserWebsite,
 		arg.AccessTime,
 		arg.GroupName,
+		arg.LastReadItem,
+		arg.SnoozeUntil,
+		arg.Muted,
 		arg.UserUuid,
 		arg.WebsiteUuid,
 	)
@@ -380,23 +1803,46 @@ func (q *Queries) UpdateUserWebsite(ctx context.Context, arg UpdateUserWebsitePa
 		&i.UserUuid,
 		&i.AccessTime,
 		&i.GroupName,
+		&i.LastReadItem,
+		&i.SnoozeUntil,
+		&i.Muted,
+		&i.ShareUuid,
 	)
 	return i, err
 }
 
+const updateUserWebsitesGroupName = `-- name: UpdateUserWebsitesGroupName :exec
+UPDATE user_websites SET group_name=$3
+WHERE user_uuid=$1 and group_name=$2
+`
+
+type UpdateUserWebsitesGroupNameParams struct {
+	UserUuid    sql.NullString
+	GroupName   sql.NullString
+	GroupName_2 sql.NullString
+}
+
+func (q *Queries) UpdateUserWebsitesGroupName(ctx context.Context, arg UpdateUserWebsitesGroupNameParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserWebsitesGroupName, arg.UserUuid, arg.GroupName, arg.GroupName_2)
+	return err
+}
+
 const updateWebsite = `-- name: UpdateWebsite :one
 UPDATE websites SET
-url=$1, title=$2, content=$3, update_time=$4
-WHERE uuid=$5
-RETURNING uuid, url, title, content, update_time
+url=$1, title=$2, content=$3, previous_content=$4, items=$5, update_time=$6, health=$7
+WHERE uuid=$8
+RETURNING uuid, url, title, content, update_time, previous_content, items, health
 `
 
 type UpdateWebsiteParams struct {
-	Url        sql.NullString
-	Title      sql.NullString
-	Content    sql.NullString
-	UpdateTime sql.NullTime
-	Uuid       sql.NullString
+	Url             sql.NullString
+	Title           sql.NullString
+	Content         sql.NullString
+	PreviousContent sql.NullString
+	Items           sql.NullString
+	UpdateTime      sql.NullTime
+	Health          sql.NullString
+	Uuid            sql.NullString
 }
 
 func (q *Queries) UpdateWebsite(ctx context.Context, arg UpdateWebsiteParams) (Website, error) {
@@ -404,7 +1850,10 @@ func (q *Queries) UpdateWebsite(ctx context.Context, arg UpdateWebsiteParams) (W
 		arg.Url,
 		arg.Title,
 		arg.Content,
+		arg.PreviousContent,
+		arg.Items,
 		arg.UpdateTime,
+		arg.Health,
 		arg.Uuid,
 	)
 	var i Website
@@ -414,6 +1863,39 @@ func (q *Queries) UpdateWebsite(ctx context.Context, arg UpdateWebsiteParams) (W
 		&i.Title,
 		&i.Content,
 		&i.UpdateTime,
+		&i.PreviousContent,
+		&i.Items,
+		&i.Health,
 	)
 	return i, err
 }
+
+const upsertWorkerStatus = `-- name: UpsertWorkerStatus :exec
+INSERT INTO worker_statuses
+(worker_id, executor_count, busy_executors, queue_depth, start_time, heartbeat_time)
+VALUES
+($1, $2, $3, $4, $5, $6)
+ON CONFLICT (worker_id) DO
+UPDATE SET executor_count=$2, busy_executors=$3, queue_depth=$4, heartbeat_time=$6
+`
+
+type UpsertWorkerStatusParams struct {
+	WorkerID      sql.NullString
+	ExecutorCount sql.NullInt32
+	BusyExecutors sql.NullInt32
+	QueueDepth    sql.NullInt32
+	StartTime     sql.NullTime
+	HeartbeatTime sql.NullTime
+}
+
+func (q *Queries) UpsertWorkerStatus(ctx context.Context, arg UpsertWorkerStatusParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkerStatus,
+		arg.WorkerID,
+		arg.ExecutorCount,
+		arg.BusyExecutors,
+		arg.QueueDepth,
+		arg.StartTime,
+		arg.HeartbeatTime,
+	)
+	return err
+}
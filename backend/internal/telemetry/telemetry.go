@@ -0,0 +1,146 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/htchan/WebHistory/internal/config"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterOTLPGRPC = "otlp-grpc"
+	ExporterOTLPHTTP = "otlp-http"
+	ExporterStdout   = "stdout"
+	ExporterNone     = "none"
+)
+
+var (
+	ErrUnknownExporter = errors.New("unknown trace exporter")
+	ErrInvalidTraceURL = errors.New("invalid trace url")
+)
+
+// checkOTLPURL rejects url of jaeger collector, which was the trace url
+// before traces are exported by otlp. OTLP exporters take host:port only
+func checkOTLPURL(traceURL string) error {
+	if strings.Contains(traceURL, "://") {
+		return fmt.Errorf(
+			"%w: %s, TRACE_URL must be host:port of otlp collector, jaeger collector url is no longer supported",
+			ErrInvalidTraceURL, traceURL,
+		)
+	}
+
+	return nil
+}
+
+func newExporter(ctx context.Context, conf config.TraceConfig) (tracesdk.SpanExporter, error) {
+	switch conf.TraceExporter {
+	case ExporterOTLPGRPC:
+		if err := checkOTLPURL(conf.TraceURL); err != nil {
+			return nil, err
+		}
+
+		opts := []otlptracegrpc.Option{}
+		if conf.TraceURL != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(conf.TraceURL))
+		}
+		if conf.TraceInsecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+
+		return otlptracegrpc.New(ctx, opts...)
+	case ExporterOTLPHTTP:
+		if err := checkOTLPURL(conf.TraceURL); err != nil {
+			return nil, err
+		}
+
+		opts := []otlptracehttp.Option{}
+		if conf.TraceURL != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(conf.TraceURL))
+		}
+		if conf.TraceInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+
+		return otlptracehttp.New(ctx, opts...)
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownExporter, conf.TraceExporter)
+	}
+}
+
+// SetupTracerProvider registers a global tracer provider exporting spans
+// with the exporter in conf, and W3C trace context as the global propagator
+func SetupTracerProvider(ctx context.Context, conf config.TraceConfig) (*tracesdk.TracerProvider, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	opts := []tracesdk.TracerProviderOption{
+		tracesdk.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String(conf.TraceServiceName),
+		)),
+	}
+
+	if conf.TraceExporter != ExporterNone {
+		exp, err := newExporter(ctx, conf)
+		if err != nil {
+			return nil, fmt.Errorf("create trace exporter fail: %w", err)
+		}
+
+		opts = append(opts, tracesdk.WithBatcher(exp))
+	}
+
+	tp := tracesdk.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+
+	return tp, nil
+}
+
+// Middleware starts a server span for each request, continuing the trace
+// context sent by client, and names the span by the matched chi route
+func Middleware(serviceName string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		renameSpan := http.HandlerFunc(
+			func(res http.ResponseWriter, req *http.Request) {
+				next.ServeHTTP(res, req)
+
+				if rctx := chi.RouteContext(req.Context()); rctx != nil && rctx.RoutePattern() != "" {
+					trace.SpanFromContext(req.Context()).SetName(req.Method + " " + rctx.RoutePattern())
+				}
+			},
+		)
+
+		return otelhttp.NewHandler(renameSpan, serviceName)
+	}
+}
+
+// InjectTraceContext serializes the trace context of ctx, so that it can be
+// carried to jobs executed in other process
+func InjectTraceContext(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+
+	return carrier
+}
+
+// ExtractTraceContext returns ctx continuing the trace context serialized by InjectTraceContext
+func ExtractTraceContext(ctx context.Context, carrier map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(carrier))
+}
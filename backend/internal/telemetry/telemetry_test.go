@@ -0,0 +1,118 @@
+package telemetry
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/htchan/WebHistory/internal/config"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	leak := flag.Bool("leak", false, "check for memory leaks")
+	flag.Parse()
+
+	if *leak {
+		goleak.VerifyTestMain(m)
+	} else {
+		os.Exit(m.Run())
+	}
+}
+
+func TestSetupTracerProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    config.TraceConfig
+		wantErr error
+	}{
+		{
+			name: "stdout exporter",
+			conf: config.TraceConfig{TraceExporter: ExporterStdout},
+		},
+		{
+			name: "otlp http exporter",
+			conf: config.TraceConfig{TraceExporter: ExporterOTLPHTTP, TraceURL: "localhost:4318", TraceInsecure: true},
+		},
+		{
+			name: "disabled exporter",
+			conf: config.TraceConfig{TraceExporter: ExporterNone},
+		},
+		{
+			name:    "jaeger collector url of otlp exporter",
+			conf:    config.TraceConfig{TraceExporter: ExporterOTLPGRPC, TraceURL: "http://jaeger:14268/api/traces"},
+			wantErr: ErrInvalidTraceURL,
+		},
+		{
+			name:    "unknown exporter",
+			conf:    config.TraceConfig{TraceExporter: "jaeger"},
+			wantErr: ErrUnknownExporter,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			tp, err := SetupTracerProvider(context.Background(), test.conf)
+			assert.ErrorIs(t, err, test.wantErr)
+
+			if err == nil {
+				assert.NoError(t, tp.Shutdown(context.Background()))
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp, err := SetupTracerProvider(context.Background(), config.TraceConfig{TraceExporter: ExporterNone})
+	if err != nil {
+		t.Fatalf("setup tracer provider fail: %v", err)
+	}
+	tp.RegisterSpanProcessor(recorder)
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+
+	r := chi.NewRouter()
+	r.Use(Middleware("test"))
+	r.Get("/websites/{webUUID}", func(res http.ResponseWriter, req *http.Request) {})
+
+	req := httptest.NewRequest(http.MethodGet, "/websites/some-uuid", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "GET /websites/{webUUID}", spans[0].Name())
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+	}
+}
+
+func TestInjectExtractTraceContext(t *testing.T) {
+	_, err := SetupTracerProvider(context.Background(), config.TraceConfig{TraceExporter: ExporterNone})
+	if err != nil {
+		t.Fatalf("setup tracer provider fail: %v", err)
+	}
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	carrier := InjectTraceContext(ctx)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", carrier["traceparent"])
+
+	got := trace.SpanContextFromContext(ExtractTraceContext(context.Background(), carrier))
+	assert.Equal(t, traceID, got.TraceID())
+	assert.Equal(t, spanID, got.SpanID())
+}
@@ -22,14 +22,17 @@ func openSqliteDatabase(conf *config.DatabaseConfig) (*sql.DB, error) {
 	return database, err
 }
 
-// open database for psql
-func openPostgresDatabase(conf *config.DatabaseConfig) (*sql.DB, error) {
-	conn := fmt.Sprintf(
+// PostgresConnString returns the connection string of psql in conf
+func PostgresConnString(conf *config.DatabaseConfig) string {
+	return fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		conf.Host, conf.Port, conf.User, conf.Password, conf.Database,
 	)
+}
 
-	database, err := sql.Open(conf.Driver, conn)
+// open database for psql
+func openPostgresDatabase(conf *config.DatabaseConfig) (*sql.DB, error) {
+	database, err := sql.Open(conf.Driver, PostgresConnString(conf))
 	if err != nil {
 		return database, err
 	}
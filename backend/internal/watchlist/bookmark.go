@@ -0,0 +1,101 @@
+package watchlist
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// DecodeBookmarks reads bookmarks exported by browsers in netscape bookmark
+// html format, bookmark is grouped by the innermost folder containing it
+func DecodeBookmarks(r io.Reader) ([]Entry, error) {
+	tokenizer := html.NewTokenizer(r)
+
+	var (
+		entries []Entry
+		folders []string
+		// folder is the name of the latest folder heading, which is pushed to
+		// folders once its bookmark list starts
+		folder  string
+		inTitle bool
+		current *Entry
+	)
+
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			if tokenizer.Err() == io.EOF {
+				return entries, nil
+			}
+			return nil, tokenizer.Err()
+
+		case html.StartTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "h3":
+				inTitle = true
+				folder = ""
+			case "dl":
+				folders = append(folders, folder)
+				folder = ""
+			case "a":
+				entry := Entry{URL: strings.TrimSpace(attr(token, "href"))}
+				if len(folders) > 0 {
+					entry.GroupName = folders[len(folders)-1]
+				}
+				entry.AccessTime = unixAttr(token, "add_date")
+				entry.UpdateTime = unixAttr(token, "last_modified")
+				current = &entry
+			}
+
+		case html.EndTagToken:
+			switch tokenizer.Token().Data {
+			case "h3":
+				inTitle = false
+				folder = strings.TrimSpace(folder)
+			case "dl":
+				if len(folders) > 0 {
+					folders = folders[:len(folders)-1]
+				}
+			case "a":
+				if current != nil {
+					current.Title = strings.TrimSpace(current.Title)
+					entries = append(entries, *current)
+					current = nil
+				}
+			}
+
+		case html.TextToken:
+			text := string(tokenizer.Text())
+			if inTitle {
+				folder += text
+			} else if current != nil {
+				current.Title += text
+			}
+		}
+	}
+}
+
+func attr(token html.Token, key string) string {
+	for _, a := range token.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+
+	return ""
+}
+
+// unixAttr parses attribute in unix seconds, zero time is returned if
+// attribute is missing or malformed
+func unixAttr(token html.Token, key string) time.Time {
+	sec, err := strconv.ParseInt(attr(token, key), 10, 64)
+	if err != nil || sec <= 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(sec, 0).UTC()
+}
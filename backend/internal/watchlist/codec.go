@@ -0,0 +1,257 @@
+package watchlist
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+var csvHeader = []string{"url", "title", "group_name", "access_time", "update_time"}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return t.UTC(), nil
+}
+
+// Encode writes entries to w in format
+func Encode(w io.Writer, format Format, entries []Entry) error {
+	switch format {
+	case FormatJSON:
+		return json.NewEncoder(w).Encode(entries)
+	case FormatCSV:
+		return encodeCSV(w, entries)
+	case FormatOPML:
+		return encodeOPML(w, entries)
+	}
+
+	return ErrUnsupportedFormat
+}
+
+// Decode reads entries in format from r
+func Decode(r io.Reader, format Format) ([]Entry, error) {
+	switch format {
+	case FormatJSON:
+		var entries []Entry
+		if err := json.NewDecoder(r).Decode(&entries); err != nil {
+			return nil, fmt.Errorf("decode json fail: %w", err)
+		}
+		return entries, nil
+	case FormatCSV:
+		return decodeCSV(r)
+	case FormatOPML:
+		return decodeOPML(r)
+	}
+
+	return nil, ErrUnsupportedFormat
+}
+
+func encodeCSV(w io.Writer, entries []Entry) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		err := writer.Write([]string{
+			entry.URL, entry.Title, entry.GroupName,
+			formatTime(entry.AccessTime), formatTime(entry.UpdateTime),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// decodeCSV reads csv with header row, columns are located by header name
+// and only the url column is required
+func decodeCSV(r io.Reader) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read csv header fail: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["url"]; !ok {
+		return nil, fmt.Errorf("csv header missing url column")
+	}
+
+	var entries []Entry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("read csv fail: %w", err)
+		}
+
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		entry := Entry{URL: field("url"), Title: field("title"), GroupName: field("group_name")}
+		if entry.AccessTime, err = parseTime(field("access_time")); err != nil {
+			return nil, fmt.Errorf("parse access time of %s fail: %w", entry.URL, err)
+		}
+		if entry.UpdateTime, err = parseTime(field("update_time")); err != nil {
+			return nil, fmt.Errorf("parse update time of %s fail: %w", entry.URL, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+type opml struct {
+	XMLName xml.Name  `xml:"opml"`
+	Version string    `xml:"version,attr"`
+	Title   string    `xml:"head>title"`
+	Body    []outline `xml:"body>outline"`
+}
+
+// outline is a group if it has child outlines, otherwise a website
+type outline struct {
+	Text       string    `xml:"text,attr"`
+	Title      string    `xml:"title,attr,omitempty"`
+	Type       string    `xml:"type,attr,omitempty"`
+	URL        string    `xml:"url,attr,omitempty"`
+	HTMLURL    string    `xml:"htmlUrl,attr,omitempty"`
+	XMLURL     string    `xml:"xmlUrl,attr,omitempty"`
+	AccessTime string    `xml:"accessTime,attr,omitempty"`
+	UpdateTime string    `xml:"updateTime,attr,omitempty"`
+	Outlines   []outline `xml:"outline"`
+}
+
+func encodeOPML(w io.Writer, entries []Entry) error {
+	doc := opml{Version: "2.0", Title: "WebHistory watchlist"}
+	groupIndex := make(map[string]int)
+	for _, entry := range entries {
+		item := outline{
+			Text:       entry.Title,
+			Title:      entry.Title,
+			Type:       "link",
+			URL:        entry.URL,
+			AccessTime: formatTime(entry.AccessTime),
+			UpdateTime: formatTime(entry.UpdateTime),
+		}
+		if item.Text == "" {
+			item.Text = entry.URL
+		}
+
+		if entry.GroupName == "" {
+			doc.Body = append(doc.Body, item)
+			continue
+		}
+
+		i, ok := groupIndex[entry.GroupName]
+		if !ok {
+			i = len(doc.Body)
+			groupIndex[entry.GroupName] = i
+			doc.Body = append(doc.Body, outline{Text: entry.GroupName, Title: entry.GroupName})
+		}
+		doc.Body[i].Outlines = append(doc.Body[i].Outlines, item)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func decodeOPML(r io.Reader) ([]Entry, error) {
+	var doc opml
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode opml fail: %w", err)
+	}
+
+	var entries []Entry
+	var walk func(outlines []outline, groupName string) error
+	walk = func(outlines []outline, groupName string) error {
+		for _, item := range outlines {
+			url := item.URL
+			if url == "" {
+				url = item.HTMLURL
+			}
+			if url == "" {
+				url = item.XMLURL
+			}
+
+			if url == "" {
+				// outline without url groups the nested websites
+				name := item.Title
+				if name == "" {
+					name = item.Text
+				}
+				if err := walk(item.Outlines, name); err != nil {
+					return err
+				}
+				continue
+			}
+
+			entry := Entry{URL: url, Title: item.Title, GroupName: groupName}
+			if entry.Title == "" && item.Text != url {
+				entry.Title = item.Text
+			}
+
+			var err error
+			if entry.AccessTime, err = parseTime(item.AccessTime); err != nil {
+				return fmt.Errorf("parse access time of %s fail: %w", url, err)
+			}
+			if entry.UpdateTime, err = parseTime(item.UpdateTime); err != nil {
+				return fmt.Errorf("parse update time of %s fail: %w", url, err)
+			}
+
+			entries = append(entries, entry)
+		}
+
+		return nil
+	}
+
+	if err := walk(doc.Body, ""); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,193 @@
+package watchlist
+
+import (
+	"context"
+	"strings"
+
+	"github.com/htchan/WebHistory/internal/config"
+	"github.com/htchan/WebHistory/internal/model"
+	"github.com/htchan/WebHistory/internal/repository"
+	"github.com/htchan/WebHistory/internal/service"
+	"github.com/htchan/WebHistory/pkg/api"
+)
+
+type Status string
+
+const (
+	StatusCreated   Status = "created"
+	StatusDuplicate Status = "duplicate"
+	StatusInvalid   Status = "invalid"
+	StatusError     Status = "error"
+	// StatusUnsupportedHost is reported for websites without setting to parse them
+	StatusUnsupportedHost Status = "unsupported_host"
+	// StatusBlocked is reported for websites of host blocked by url policy
+	StatusBlocked Status = "blocked"
+)
+
+// Result reports the import of an entry, row starts from 1
+type Result struct {
+	Row         int    `json:"row"`
+	URL         string `json:"url"`
+	Status      Status `json:"status"`
+	WebsiteUUID string `json:"website_uuid,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+func (result Result) ToAPI() api.ImportResult {
+	return api.ImportResult{
+		Row:         result.Row,
+		URL:         result.URL,
+		Status:      string(result.Status),
+		WebsiteUUID: result.WebsiteUUID,
+		Error:       result.Error,
+	}
+}
+
+// ResultsToAPI converts results to the representation in API response
+func ResultsToAPI(results []Result) []api.ImportResult {
+	if results == nil {
+		return nil
+	}
+
+	converted := make([]api.ImportResult, len(results))
+	for i, result := range results {
+		converted[i] = result.ToAPI()
+	}
+
+	return converted
+}
+
+// SummaryToAPI converts summary to the representation in API response
+func SummaryToAPI(summary map[Status]int) map[string]int {
+	if summary == nil {
+		return nil
+	}
+
+	converted := make(map[string]int, len(summary))
+	for status, count := range summary {
+		converted[string(status)] = count
+	}
+
+	return converted
+}
+
+func validURL(conf *config.WebsiteConfig, s string) bool {
+	_, err := model.NormalizeURL(s, conf.AllowedURLSchemes)
+	return err == nil
+}
+
+// Import creates websites of entries for user, entries of url which user
+// already watches are skipped as duplicate. Websites are not fetched here,
+// new websites are queued to be fetched by worker. Import stops once ctx is
+// done
+func Import(ctx context.Context, r repository.Repostory, conf *config.WebsiteConfig, userUUID string, entries []Entry) ([]Result, error) {
+	userWebs, err := r.FindUserWebsites(userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	watched := make(map[string]bool)
+	for _, web := range userWebs {
+		if web.UserUUID == userUUID {
+			watched[web.Website.URL] = true
+		}
+	}
+
+	policy := service.NewURLPolicy(conf)
+	results := make([]Result, len(entries))
+	for i, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		entry.URL = strings.TrimSpace(entry.URL)
+		results[i] = importEntry(ctx, r, conf, policy, userUUID, entry, watched)
+		results[i].Row = i + 1
+		results[i].URL = entry.URL
+	}
+
+	return results, nil
+}
+
+// ImportSupportedHosts imports entries like Import, except entries of host
+// without website setting are skipped as unsupported host
+func ImportSupportedHosts(ctx context.Context, r repository.Repostory, conf *config.WebsiteConfig, userUUID string, entries []Entry) ([]Result, error) {
+	var (
+		supported []Entry
+		rows      []int
+	)
+	results := make([]Result, len(entries))
+	for i, entry := range entries {
+		entry.URL = strings.TrimSpace(entry.URL)
+		web := model.Website{URL: entry.URL}
+		if validURL(conf, entry.URL) && !service.SupportedHost(r, &web) {
+			results[i] = Result{Row: i + 1, URL: entry.URL, Status: StatusUnsupportedHost}
+			continue
+		}
+
+		supported = append(supported, entry)
+		rows = append(rows, i)
+	}
+
+	imported, err := Import(ctx, r, conf, userUUID, supported)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, result := range imported {
+		result.Row = rows[i] + 1
+		results[rows[i]] = result
+	}
+
+	return results, nil
+}
+
+func importEntry(ctx context.Context, r repository.Repostory, conf *config.WebsiteConfig, policy *service.URLPolicy, userUUID string, entry Entry, watched map[string]bool) Result {
+	if !validURL(conf, entry.URL) {
+		return Result{Status: StatusInvalid, Error: "invalid url"}
+	}
+
+	if watched[entry.URL] {
+		return Result{Status: StatusDuplicate}
+	}
+
+	if err := policy.Check(ctx, entry.URL); err != nil {
+		return Result{Status: StatusBlocked, Error: err.Error()}
+	}
+
+	// website row is shared by all users, so title and update time of the
+	// entry are not trusted. They are filled in once worker fetches it
+	web, err := service.CreatePendingWebsite(ctx, r, conf, entry.URL)
+	if err != nil {
+		return Result{Status: StatusError, Error: err.Error()}
+	}
+
+	userWeb := model.NewUserWebsite(web, userUUID)
+	if groupName, err := model.NormalizeGroupName(entry.GroupName); err == nil {
+		userWeb.GroupName = groupName
+	}
+	if !entry.AccessTime.IsZero() {
+		userWeb.AccessTime = entry.AccessTime
+	}
+
+	if err := r.CreateUserWebsite(&userWeb); err != nil {
+		return Result{Status: StatusError, Error: err.Error()}
+	}
+
+	watched[entry.URL] = true
+
+	return Result{Status: StatusCreated, WebsiteUUID: web.UUID}
+}
+
+// Summary counts results by status
+func Summary(results []Result) map[Status]int {
+	summary := map[Status]int{
+		StatusCreated: 0, StatusDuplicate: 0, StatusInvalid: 0, StatusError: 0,
+		StatusUnsupportedHost: 0, StatusBlocked: 0,
+	}
+	for _, result := range results {
+		summary[result.Status]++
+	}
+
+	return summary
+}
@@ -0,0 +1,170 @@
+package watchlist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/htchan/WebHistory/internal/model"
+	"github.com/htchan/WebHistory/internal/repository"
+	"github.com/htchan/WebHistory/pkg/api"
+	"github.com/rs/zerolog"
+)
+
+type JobStatus string
+
+const (
+	JobRunning   JobStatus = "running"
+	JobCompleted JobStatus = "completed"
+	JobFailed    JobStatus = "failed"
+)
+
+// jobRetention is how long jobs are kept for status query
+const jobRetention = 24 * time.Hour
+
+// ErrJobNotFound is returned when job is not found for the user
+var ErrJobNotFound = errors.New("import job not found")
+
+// Job is an import running in background
+type Job struct {
+	ID         string         `json:"id"`
+	UserUUID   string         `json:"-"`
+	Status     JobStatus      `json:"status"`
+	Total      int            `json:"total"`
+	Summary    map[Status]int `json:"summary,omitempty"`
+	Results    []Result       `json:"results,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	FinishedAt time.Time      `json:"finished_at,omitempty"`
+}
+
+func jobFromModel(jobModel model.ImportJob) (Job, error) {
+	job := Job{
+		ID:         jobModel.ID,
+		UserUUID:   jobModel.UserUUID,
+		Status:     JobStatus(jobModel.Status),
+		Total:      jobModel.Total,
+		Error:      jobModel.Error,
+		CreatedAt:  jobModel.CreateTime,
+		FinishedAt: jobModel.FinishTime,
+	}
+	if jobModel.Results != "" {
+		if err := json.Unmarshal([]byte(jobModel.Results), &job.Results); err != nil {
+			return Job{}, fmt.Errorf("decode import job results fail: %w", err)
+		}
+		job.Summary = Summary(job.Results)
+	}
+
+	return job, nil
+}
+
+func (job Job) ToAPI() api.ImportJob {
+	return api.ImportJob{
+		ID:         job.ID,
+		Status:     string(job.Status),
+		Total:      job.Total,
+		Summary:    SummaryToAPI(job.Summary),
+		Results:    ResultsToAPI(job.Results),
+		Error:      job.Error,
+		CreatedAt:  job.CreatedAt,
+		FinishedAt: job.FinishedAt,
+	}
+}
+
+type ImportFunc func(ctx context.Context) ([]Result, error)
+
+// JobStore runs import jobs in background and stores them through repository,
+// so that job started by one api instance can be queried from the others.
+// Job interrupted by restart of its instance stays running until it is pruned
+type JobStore struct {
+	r    repository.Repostory
+	lock sync.Mutex
+	done map[string]chan struct{}
+}
+
+func NewJobStore(r repository.Repostory) *JobStore {
+	return &JobStore{r: r, done: make(map[string]chan struct{})}
+}
+
+// Start runs importFunc in background and returns the id of job,
+// importFunc runs with ctx detached from cancellation of caller
+func (store *JobStore) Start(ctx context.Context, userUUID string, total int, importFunc ImportFunc) (string, error) {
+	job := model.ImportJob{
+		ID:         uuid.New().String(),
+		UserUUID:   userUUID,
+		Status:     string(JobRunning),
+		Total:      total,
+		CreateTime: time.Now().UTC().Truncate(time.Second),
+	}
+
+	if err := store.r.DeleteImportJobs(job.CreateTime.Add(-jobRetention)); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("prune import jobs failed")
+	}
+
+	if err := store.r.CreateImportJob(&job); err != nil {
+		return "", err
+	}
+
+	done := make(chan struct{})
+	store.lock.Lock()
+	store.done[job.ID] = done
+	store.lock.Unlock()
+
+	ctx = zerolog.Ctx(ctx).With().Str("import_job_id", job.ID).Logger().WithContext(context.Background())
+	go func() {
+		defer func() {
+			store.lock.Lock()
+			delete(store.done, job.ID)
+			store.lock.Unlock()
+			close(done)
+		}()
+
+		results, err := importFunc(ctx)
+		if err == nil {
+			var data []byte
+			data, err = json.Marshal(results)
+			job.Results = string(data)
+		}
+
+		job.FinishTime = time.Now().UTC().Truncate(time.Second)
+		job.Status = string(JobCompleted)
+		if err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("import job failed")
+			job.Status, job.Results, job.Error = string(JobFailed), "", err.Error()
+		}
+
+		if err := store.r.UpdateImportJob(&job); err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("update import job failed")
+		}
+	}()
+
+	return job.ID, nil
+}
+
+// Get returns job of user, ErrJobNotFound is returned if job is not found or
+// it is pruned already
+func (store *JobStore) Get(userUUID, id string) (Job, error) {
+	jobModel, err := store.r.FindImportJob(userUUID, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return Job{}, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	} else if err != nil {
+		return Job{}, err
+	}
+
+	return jobFromModel(*jobModel)
+}
+
+// Wait blocks until job started by this store finishes
+func (store *JobStore) Wait(id string) {
+	store.lock.Lock()
+	done, ok := store.done[id]
+	store.lock.Unlock()
+
+	if ok {
+		<-done
+	}
+}
@@ -0,0 +1,69 @@
+package watchlist
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/htchan/WebHistory/internal/model"
+)
+
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatCSV  Format = "csv"
+	FormatOPML Format = "opml"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported format")
+
+// Entry is a website in watchlist of user
+type Entry struct {
+	URL        string    `json:"url"`
+	Title      string    `json:"title,omitempty"`
+	GroupName  string    `json:"group_name,omitempty"`
+	AccessTime time.Time `json:"access_time"`
+	UpdateTime time.Time `json:"update_time"`
+}
+
+// ParseFormat parses format name or media type of watchlist
+func ParseFormat(s string) (Format, error) {
+	s = strings.ToLower(strings.TrimSpace(strings.Split(s, ";")[0]))
+	switch s {
+	case "json", "application/json":
+		return FormatJSON, nil
+	case "csv", "text/csv":
+		return FormatCSV, nil
+	case "opml", "text/x-opml", "text/xml", "application/xml":
+		return FormatOPML, nil
+	}
+
+	return "", ErrUnsupportedFormat
+}
+
+func (format Format) ContentType() string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatOPML:
+		return "text/x-opml; charset=utf-8"
+	}
+
+	return "application/json; charset=utf-8"
+}
+
+func FromUserWebsites(webs model.UserWebsites) []Entry {
+	entries := make([]Entry, len(webs))
+	for i, web := range webs {
+		entries[i] = Entry{
+			URL:        web.Website.URL,
+			Title:      web.Website.Title,
+			GroupName:  web.GroupName,
+			AccessTime: web.AccessTime.UTC(),
+			UpdateTime: web.Website.UpdateTime.UTC(),
+		}
+	}
+
+	return entries
+}
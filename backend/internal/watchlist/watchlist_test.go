@@ -0,0 +1,433 @@
+package watchlist
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/htchan/WebHistory/internal/config"
+	"github.com/htchan/WebHistory/internal/model"
+	"github.com/htchan/WebHistory/internal/repository"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	leak := flag.Bool("leak", false, "check for memory leaks")
+	flag.Parse()
+
+	if *leak {
+		goleak.VerifyTestMain(m)
+	} else {
+		os.Exit(m.Run())
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		format    string
+		expect    Format
+		expectErr error
+	}{
+		{name: "format name", format: "CSV", expect: FormatCSV},
+		{name: "media type with params", format: "application/json; charset=utf-8", expect: FormatJSON},
+		{name: "opml media type", format: "text/x-opml", expect: FormatOPML},
+		{name: "unsupported format", format: "yaml", expectErr: ErrUnsupportedFormat},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := ParseFormat(test.format)
+			if err != test.expectErr {
+				t.Errorf("got error: %v; want: %v", err, test.expectErr)
+			}
+			if result != test.expect {
+				t.Errorf("got: %v; want: %v", result, test.expect)
+			}
+		})
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	entries := []Entry{
+		{
+			URL: "https://example.com/1", Title: "title, 1", GroupName: "group",
+			AccessTime: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			UpdateTime: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{URL: "https://example.com/2", GroupName: "group"},
+		{URL: "https://example.com/3", Title: "title 3"},
+	}
+
+	for _, format := range []Format{FormatJSON, FormatCSV, FormatOPML} {
+		format := format
+		t.Run(string(format), func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			if err := Encode(&buf, format, entries); err != nil {
+				t.Fatalf("encode fail: %v", err)
+			}
+
+			result, err := Decode(&buf, format)
+			if err != nil {
+				t.Fatalf("decode fail: %v", err)
+			}
+			if !cmp.Equal(result, entries) {
+				t.Errorf("got different entries: %s", cmp.Diff(entries, result))
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		format    Format
+		data      string
+		expect    []Entry
+		expectErr bool
+	}{
+		{
+			name:   "csv with columns in any order",
+			format: FormatCSV,
+			data:   "group_name,url\ngroup,https://example.com\n",
+			expect: []Entry{{URL: "https://example.com", GroupName: "group"}},
+		},
+		{
+			name:      "csv without url column",
+			format:    FormatCSV,
+			data:      "title\ntitle\n",
+			expectErr: true,
+		},
+		{
+			name:   "opml of feed reader",
+			format: FormatOPML,
+			data: `<opml version="1.0"><body>` +
+				`<outline text="news"><outline text="feed" xmlUrl="https://example.com/rss" htmlUrl="https://example.com"/></outline>` +
+				`</body></opml>`,
+			expect: []Entry{{URL: "https://example.com", Title: "feed", GroupName: "news"}},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := Decode(strings.NewReader(test.data), test.format)
+			if (err != nil) != test.expectErr {
+				t.Errorf("got error: %v; want error: %v", err, test.expectErr)
+			}
+			if !cmp.Equal(result, test.expect) {
+				t.Errorf("got: %v; want: %v", result, test.expect)
+			}
+		})
+	}
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	r := repository.NewInMemRepo(
+		[]model.Website{
+			{UUID: "watched", URL: "https://example.com/watched", Title: "watched"},
+			{UUID: "shared", URL: "https://example.com/shared", Title: "shared"},
+		},
+		[]model.UserWebsite{
+			{UserUUID: "user", WebsiteUUID: "watched", Website: model.Website{UUID: "watched", URL: "https://example.com/watched"}},
+		},
+		nil, nil,
+	)
+
+	results, err := Import(context.Background(), r, &config.WebsiteConfig{URLAllowList: []string{"example.com"}}, "user", []Entry{
+		{URL: "https://example.com/watched"},
+		{URL: "https://example.com/shared", Title: "poisoned", GroupName: "group", UpdateTime: time.Now()},
+		{URL: "https://example.com/new", Title: "new", AccessTime: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), UpdateTime: time.Now()},
+		{URL: " https://example.com/new "},
+		{URL: "ftp://example.com"},
+		{URL: "http://169.254.169.254/latest/meta-data"},
+	})
+	if err != nil {
+		t.Fatalf("import fail: %v", err)
+	}
+
+	statuses := make([]Status, len(results))
+	for i, result := range results {
+		statuses[i] = result.Status
+	}
+	expectStatuses := []Status{StatusDuplicate, StatusCreated, StatusCreated, StatusDuplicate, StatusInvalid, StatusBlocked}
+	if !cmp.Equal(statuses, expectStatuses) {
+		t.Errorf("got statuses: %v; want: %v", statuses, expectStatuses)
+	}
+	if results[1].WebsiteUUID != "shared" {
+		t.Errorf("existing website not reused: %v", results[1])
+	}
+
+	webs, _ := r.FindUserWebsites("user")
+	groups := make(map[string]string)
+	for _, web := range webs {
+		groups[web.Website.URL] = web.GroupName
+	}
+	expectGroups := map[string]string{
+		"https://example.com/watched": "",
+		"https://example.com/shared":  "group",
+		"https://example.com/new":     "https://example.com/new",
+	}
+	if !cmp.Equal(groups, expectGroups) {
+		t.Errorf("got groups: %v; want: %v", groups, expectGroups)
+	}
+
+	for _, web := range webs {
+		if web.Website.URL == "https://example.com/watched" {
+			continue
+		}
+		stored, _ := r.FindWebsite(web.WebsiteUUID)
+		if web.Website.URL == "https://example.com/shared" && stored.Title != "shared" {
+			t.Errorf("title of shared website is overwritten: %v", stored.Title)
+		}
+		if web.Website.URL == "https://example.com/new" && (stored.Title != "" || !stored.UpdateTime.IsZero() || stored.Health != model.WebsitePending) {
+			t.Errorf("new website is not created pending: %v", stored)
+		}
+	}
+
+	summary := Summary(results)
+	expectSummary := map[Status]int{
+		StatusCreated: 2, StatusDuplicate: 2, StatusInvalid: 1, StatusError: 0,
+		StatusUnsupportedHost: 0, StatusBlocked: 1,
+	}
+	if !cmp.Equal(summary, expectSummary) {
+		t.Errorf("got summary: %v; want: %v", summary, expectSummary)
+	}
+}
+
+func TestImport_canceled(t *testing.T) {
+	t.Parallel()
+
+	r := repository.NewInMemRepo(nil, nil, nil, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Import(ctx, r, &config.WebsiteConfig{}, "user", []Entry{{URL: "https://example.com"}})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error: %v; want: %v", err, context.Canceled)
+	}
+	if webs, _ := r.FindUserWebsites("user"); len(webs) != 0 {
+		t.Errorf("got websites: %v; want: none", webs)
+	}
+}
+
+func TestDecodeBookmarks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		data      string
+		expect    []Entry
+		expectErr bool
+	}{
+		{
+			name: "bookmarks in nested folders",
+			data: `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks</H1>
+<DL><p>
+    <DT><A HREF="https://example.com/root" ADD_DATE="1577836800">root</A>
+    <DT><H3 ADD_DATE="1577836800">news</H3>
+    <DL><p>
+        <DT><A HREF="https://example.com/news" ADD_DATE="1577836800" LAST_MODIFIED="1577923200">news &amp; more</A>
+        <DT><H3>daily</H3>
+        <DL><p>
+            <DT><A HREF="https://example.com/daily">daily</A>
+        </DL><p>
+        <DT><A HREF=" https://example.com/after ">after</A>
+    </DL><p>
+</DL><p>`,
+			expect: []Entry{
+				{URL: "https://example.com/root", Title: "root", AccessTime: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+				{
+					URL: "https://example.com/news", Title: "news & more", GroupName: "news",
+					AccessTime: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+					UpdateTime: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+				},
+				{URL: "https://example.com/daily", Title: "daily", GroupName: "daily"},
+				{URL: "https://example.com/after", Title: "after", GroupName: "news"},
+			},
+		},
+		{
+			name:   "no bookmarks",
+			data:   `<H1>Bookmarks</H1><DL><p></DL><p>`,
+			expect: nil,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := DecodeBookmarks(strings.NewReader(test.data))
+			if (err != nil) != test.expectErr {
+				t.Errorf("got error: %v; want error: %v", err, test.expectErr)
+			}
+			if !cmp.Equal(result, test.expect) {
+				t.Errorf("result diff: %v", cmp.Diff(result, test.expect))
+			}
+		})
+	}
+}
+
+func TestImportSupportedHosts(t *testing.T) {
+	t.Parallel()
+
+	r := repository.NewInMemRepo(
+		[]model.Website{{UUID: "watched", URL: "https://supported.com/watched"}},
+		[]model.UserWebsite{
+			{UserUUID: "user", WebsiteUUID: "watched", Website: model.Website{UUID: "watched", URL: "https://supported.com/watched"}},
+		},
+		[]model.WebsiteSetting{{Domain: "supported.com"}},
+		nil,
+	)
+
+	results, err := ImportSupportedHosts(context.Background(), r, &config.WebsiteConfig{URLAllowList: []string{"supported.com"}}, "user", []Entry{
+		{URL: "https://unsupported.com/new"},
+		{URL: "https://supported.com/watched"},
+		{URL: "https://supported.com/new", Title: "poisoned", GroupName: "group", UpdateTime: time.Now()},
+		{URL: "not a url"},
+	})
+	if err != nil {
+		t.Fatalf("import fail: %v", err)
+	}
+
+	expect := []Result{
+		{Row: 1, URL: "https://unsupported.com/new", Status: StatusUnsupportedHost},
+		{Row: 2, URL: "https://supported.com/watched", Status: StatusDuplicate},
+		{Row: 3, URL: "https://supported.com/new", Status: StatusCreated},
+		{Row: 4, URL: "not a url", Status: StatusInvalid, Error: "invalid url"},
+	}
+	if len(results) == len(expect) {
+		expect[2].WebsiteUUID = results[2].WebsiteUUID
+	}
+	if !cmp.Equal(results, expect) {
+		t.Errorf("result diff: %v", cmp.Diff(results, expect))
+	}
+
+	web, err := r.FindWebsite(results[2].WebsiteUUID)
+	if err != nil || web.Title != "" || !web.UpdateTime.IsZero() || web.Health != model.WebsitePending {
+		t.Errorf("new website is not created pending: %v, %v", web, err)
+	}
+	if count, _ := r.CountWebsiteUpdateRequests(); count != 1 {
+		t.Errorf("got %d update requests; want: 1", count)
+	}
+}
+
+func TestJobStore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("completed job", func(t *testing.T) {
+		t.Parallel()
+		store := NewJobStore(repository.NewInMemRepo(nil, nil, nil, nil))
+		results := []Result{{Row: 1, URL: "https://example.com", Status: StatusCreated}}
+		id, err := store.Start(context.Background(), "user", 1, func(ctx context.Context) ([]Result, error) {
+			return results, nil
+		})
+		if err != nil {
+			t.Fatalf("start job fail: %v", err)
+		}
+		store.Wait(id)
+
+		job, err := store.Get("user", id)
+		if err != nil {
+			t.Fatalf("get job %s fail: %v", id, err)
+		}
+		if job.Status != JobCompleted || !cmp.Equal(job.Results, results) || job.Summary[StatusCreated] != 1 {
+			t.Errorf("got unexpected job: %+v", job)
+		}
+
+		if _, err := store.Get("other user", id); !errors.Is(err, ErrJobNotFound) {
+			t.Errorf("got error: %v; want: %v", err, ErrJobNotFound)
+		}
+	})
+
+	t.Run("failed job", func(t *testing.T) {
+		t.Parallel()
+		store := NewJobStore(repository.NewInMemRepo(nil, nil, nil, nil))
+		id, err := store.Start(context.Background(), "user", 1, func(ctx context.Context) ([]Result, error) {
+			return nil, errors.New("some error")
+		})
+		if err != nil {
+			t.Fatalf("start job fail: %v", err)
+		}
+		store.Wait(id)
+
+		job, _ := store.Get("user", id)
+		if job.Status != JobFailed || job.Error != "some error" {
+			t.Errorf("got unexpected job: %+v", job)
+		}
+	})
+
+	t.Run("job is visible to other store of the same repository", func(t *testing.T) {
+		t.Parallel()
+		r := repository.NewInMemRepo(nil, nil, nil, nil)
+		store := NewJobStore(r)
+		id, err := store.Start(context.Background(), "user", 0, func(ctx context.Context) ([]Result, error) {
+			return nil, nil
+		})
+		if err != nil {
+			t.Fatalf("start job fail: %v", err)
+		}
+		store.Wait(id)
+
+		job, err := NewJobStore(r).Get("user", id)
+		if err != nil || job.Status != JobCompleted {
+			t.Errorf("got job: %+v, error: %v", job, err)
+		}
+	})
+
+	t.Run("return error if job cannot be stored", func(t *testing.T) {
+		t.Parallel()
+		store := NewJobStore(repository.NewInMemRepo(nil, nil, nil, errors.New("some error")))
+		_, err := store.Start(context.Background(), "user", 0, func(ctx context.Context) ([]Result, error) {
+			return nil, nil
+		})
+		if err == nil {
+			t.Errorf("got nil error")
+		}
+	})
+
+	t.Run("prune expired jobs", func(t *testing.T) {
+		t.Parallel()
+		r := repository.NewInMemRepo(nil, nil, nil, nil)
+		r.CreateImportJob(&model.ImportJob{
+			ID: "old", UserUUID: "user", Status: string(JobCompleted),
+			CreateTime: time.Now().UTC().Add(-2 * jobRetention),
+		})
+		store := NewJobStore(r)
+		id, err := store.Start(context.Background(), "user", 0, func(ctx context.Context) ([]Result, error) {
+			return nil, nil
+		})
+		if err != nil {
+			t.Fatalf("start job fail: %v", err)
+		}
+		store.Wait(id)
+
+		if _, err := store.Get("user", "old"); !errors.Is(err, ErrJobNotFound) {
+			t.Errorf("old job is not pruned: %v", err)
+		}
+		if _, err := store.Get("user", id); err != nil {
+			t.Errorf("new job is pruned: %v", err)
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package workerstatus
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/htchan/WebHistory/internal/config"
+	"github.com/htchan/WebHistory/internal/executor"
+	"github.com/htchan/WebHistory/internal/model"
+	"github.com/htchan/WebHistory/internal/repository"
+	"github.com/rs/zerolog/log"
+)
+
+// Reporter keeps the status of worker up to date in database, so api can
+// show status of workers running in other processes
+type Reporter struct {
+	rpo       repository.Repostory
+	exec      executor.Executor
+	workerID  string
+	interval  time.Duration
+	startTime time.Time
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+func NewReporter(rpo repository.Repostory, exec executor.Executor, conf *config.WorkerBinConfig) *Reporter {
+	return &Reporter{
+		rpo:       rpo,
+		exec:      exec,
+		workerID:  newWorkerID(),
+		interval:  conf.HeartbeatInterval,
+		startTime: time.Now().UTC().Truncate(time.Second),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// newWorkerID identifies worker by host name, with random suffix for
+// workers sharing the same host
+func newWorkerID() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "worker"
+	}
+
+	return fmt.Sprintf("%s-%s", hostname, uuid.New().String()[:8])
+}
+
+func (reporter *Reporter) Start() {
+	defer close(reporter.done)
+
+	ticker := time.NewTicker(reporter.interval)
+	defer ticker.Stop()
+
+	reporter.report()
+	for {
+		select {
+		case <-reporter.stop:
+			return
+		case <-ticker.C:
+			reporter.report()
+		}
+	}
+}
+
+func (reporter *Reporter) report() {
+	status := reporter.exec.Status()
+	err := reporter.rpo.UpsertWorkerStatus(&model.WorkerStatus{
+		WorkerID:      reporter.workerID,
+		ExecutorCount: status.ExecutorCount,
+		BusyExecutors: status.BusyExecutors,
+		QueueDepth:    status.QueueDepth,
+		StartTime:     reporter.startTime,
+		HeartbeatTime: time.Now().UTC().Truncate(time.Second),
+	})
+	if err != nil {
+		log.Error().Err(err).Str("worker_id", reporter.workerID).Msg("failed to report worker status")
+	}
+}
+
+// Stop stops reporting and removes status of worker
+func (reporter *Reporter) Stop() error {
+	close(reporter.stop)
+	<-reporter.done
+
+	return reporter.rpo.DeleteWorkerStatus(reporter.workerID)
+}
@@ -0,0 +1,77 @@
+package workerstatus
+
+import (
+	"flag"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/htchan/WebHistory/internal/config"
+	"github.com/htchan/WebHistory/internal/executor"
+	"github.com/htchan/WebHistory/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	leak := flag.Bool("leak", false, "check for memory leaks")
+	flag.Parse()
+
+	if *leak {
+		goleak.VerifyTestMain(m)
+	} else {
+		os.Exit(m.Run())
+	}
+}
+
+type stubExecutor struct {
+	executor.Executor
+	status executor.Status
+}
+
+func (exec stubExecutor) Status() executor.Status {
+	return exec.status
+}
+
+func TestNewReporter(t *testing.T) {
+	t.Parallel()
+
+	reporter := NewReporter(nil, nil, &config.WorkerBinConfig{HeartbeatInterval: time.Second})
+	assert.Equal(t, time.Second, reporter.interval)
+	assert.NotEmpty(t, reporter.workerID)
+	assert.NotEqual(t, reporter.workerID, NewReporter(nil, nil, &config.WorkerBinConfig{}).workerID)
+}
+
+func TestReporter_report(t *testing.T) {
+	t.Parallel()
+
+	rpo := repository.NewInMemRepo(nil, nil, nil, nil)
+	exec := stubExecutor{status: executor.Status{ExecutorCount: 3, BusyExecutors: 2, QueueDepth: 1}}
+	reporter := NewReporter(rpo, exec, &config.WorkerBinConfig{HeartbeatInterval: time.Hour})
+
+	reporter.report()
+	reporter.report()
+
+	statuses, _ := rpo.FindWorkerStatuses()
+	if assert.Len(t, statuses, 1) {
+		assert.Equal(t, reporter.workerID, statuses[0].WorkerID)
+		assert.Equal(t, 3, statuses[0].ExecutorCount)
+		assert.Equal(t, 2, statuses[0].BusyExecutors)
+		assert.Equal(t, 1, statuses[0].QueueDepth)
+		assert.Equal(t, reporter.startTime, statuses[0].StartTime)
+		assert.WithinDuration(t, time.Now(), statuses[0].HeartbeatTime, time.Minute)
+	}
+}
+
+func TestReporter_StartStop(t *testing.T) {
+	t.Parallel()
+
+	rpo := repository.NewInMemRepo(nil, nil, nil, nil)
+	reporter := NewReporter(rpo, stubExecutor{}, &config.WorkerBinConfig{HeartbeatInterval: time.Hour})
+
+	go reporter.Start()
+	assert.NoError(t, reporter.Stop())
+
+	statuses, _ := rpo.FindWorkerStatuses()
+	assert.Empty(t, statuses, "status removed after stop")
+}
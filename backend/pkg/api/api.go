@@ -0,0 +1,261 @@
+// Package api defines the request and response bodies of the web watcher API,
+// it is shared by the server and the Go client so that both sides agree on
+// the shape of every payload
+package api
+
+import "time"
+
+// fields of responses are declared in the alphabetical order of their json
+// keys unless stated otherwise, to keep the output of responses which were
+// encoded from map
+
+// ErrorResponse is body of failed response, Code is stable for client to
+// match on while Error is for human to read
+type ErrorResponse struct {
+	Code      string `json:"code"`
+	Error     string `json:"error"`
+	Field     string `json:"field,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// ProblemContentType is media type of Problem
+const ProblemContentType = "application/problem+json"
+
+// Problem is body of failed response in RFC 7807 format, it is responded
+// when client accepts ProblemContentType
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail"`
+	Instance  string `json:"instance"`
+	Code      string `json:"code"`
+	Field     string `json:"field,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+type WebsiteItem struct {
+	Name      string    `json:"name,omitempty"`
+	Link      string    `json:"link,omitempty"`
+	Date      string    `json:"date,omitempty"`
+	FirstSeen time.Time `json:"first_seen"`
+}
+
+// Website is a website shared by all users watching it
+type Website struct {
+	UUID       string        `json:"uuid"`
+	URL        string        `json:"url"`
+	Title      string        `json:"title"`
+	UpdateTime string        `json:"update_time"`
+	Items      []WebsiteItem `json:"items,omitempty"`
+}
+
+// UserWebsite is a website watched by user with the state of the user
+type UserWebsite struct {
+	UUID         string        `json:"uuid"`
+	UserUUID     string        `json:"user_uuid"`
+	URL          string        `json:"url"`
+	Title        string        `json:"title"`
+	GroupName    string        `json:"group_name"`
+	UpdateTime   string        `json:"update_time"`
+	AccessTime   string        `json:"access_time"`
+	Health       string        `json:"health,omitempty"`
+	LastReadItem string        `json:"last_read_item,omitempty"`
+	SnoozeUntil  string        `json:"snooze_until,omitempty"`
+	Muted        bool          `json:"muted,omitempty"`
+	Tags         []string      `json:"tags,omitempty"`
+	UnreadCount  int           `json:"unread_count"`
+	Items        []WebsiteItem `json:"items,omitempty"`
+}
+
+type ContentDiff struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+type WebsiteGroupsResponse struct {
+	NextCursor    string          `json:"next_cursor,omitempty"`
+	UnreadCounts  map[string]int  `json:"unread_counts"`
+	WebsiteGroups [][]UserWebsite `json:"website_groups"`
+}
+
+type WebsiteGroupResponse struct {
+	UnreadCount  int           `json:"unread_count"`
+	WebsiteGroup []UserWebsite `json:"website_group"`
+}
+
+type CreateWebsiteResponse struct {
+	Message     string `json:"message"`
+	WebsiteUUID string `json:"website_uuid"`
+}
+
+type WebsiteResponse struct {
+	Website UserWebsite `json:"website"`
+}
+
+type WebsiteDiffResponse struct {
+	Diff    ContentDiff `json:"diff"`
+	Website UserWebsite `json:"website"`
+}
+
+type ImportResult struct {
+	Row         int    `json:"row"`
+	URL         string `json:"url"`
+	Status      string `json:"status"`
+	WebsiteUUID string `json:"website_uuid,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+type ImportResponse struct {
+	Results []ImportResult `json:"results"`
+	Summary map[string]int `json:"summary"`
+}
+
+type ImportJobCreatedResponse struct {
+	JobID string `json:"job_id"`
+}
+
+// ImportJob is an import running in background
+type ImportJob struct {
+	ID         string         `json:"id"`
+	Status     string         `json:"status"`
+	Total      int            `json:"total"`
+	Summary    map[string]int `json:"summary,omitempty"`
+	Results    []ImportResult `json:"results,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	FinishedAt time.Time      `json:"finished_at,omitempty"`
+}
+
+type ImportJobResponse struct {
+	Job ImportJob `json:"job"`
+}
+
+type TagsResponse struct {
+	Tags []string `json:"tags"`
+}
+
+type GroupOrderResponse struct {
+	GroupOrder []string `json:"group_order"`
+}
+
+type GroupShare struct {
+	UUID       string    `json:"uuid"`
+	OwnerUUID  string    `json:"owner_uuid"`
+	GroupName  string    `json:"group_name"`
+	Mode       string    `json:"mode"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+type GroupShareSubscription struct {
+	ShareUUID  string    `json:"share_uuid"`
+	GroupName  string    `json:"group_name"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+type GroupSharesResponse struct {
+	Shares        []GroupShare             `json:"shares"`
+	Subscriptions []GroupShareSubscription `json:"subscriptions"`
+}
+
+type GroupShareResponse struct {
+	Share GroupShare `json:"share"`
+}
+
+type GroupShareSubscriptionResponse struct {
+	Subscription GroupShareSubscription `json:"subscription"`
+}
+
+type GroupPublicLink struct {
+	Token      string    `json:"token"`
+	GroupName  string    `json:"group_name"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+type GroupPublicLinkResponse struct {
+	PublicLink GroupPublicLink `json:"public_link"`
+}
+
+// PublicWebsite is a website listed by public link of group, it does not
+// contain anything specific to the owner
+type PublicWebsite struct {
+	Title      string `json:"title"`
+	UpdateTime string `json:"update_time"`
+	URL        string `json:"url"`
+}
+
+type PublicGroupResponse struct {
+	GroupName string          `json:"group_name"`
+	Websites  []PublicWebsite `json:"websites"`
+}
+
+type WebsiteSetting struct {
+	Domain                  string     `json:"domain"`
+	TitleGoquerySelector    string     `json:"title_goquery_selector"`
+	DatesGoquerySelector    string     `json:"dates_goquery_selector"`
+	FocusIndexFrom          int        `json:"focus_index_from"`
+	FocusIndexTo            int        `json:"focus_index_to"`
+	ItemNameGoquerySelector string     `json:"item_name_goquery_selector,omitempty"`
+	ItemLinkGoquerySelector string     `json:"item_link_goquery_selector,omitempty"`
+	ConsecutiveFailures     int        `json:"consecutive_failures,omitempty"`
+	BrokenAt                *time.Time `json:"broken_at,omitempty"`
+}
+
+type WebsiteSettingsResponse struct {
+	WebsiteSettings []WebsiteSetting `json:"website_settings"`
+}
+
+type AdminWebsite struct {
+	Health          string  `json:"health"`
+	SubscriberCount int     `json:"subscriber_count"`
+	Website         Website `json:"website"`
+}
+
+type AdminWebsitesResponse struct {
+	Websites []AdminWebsite `json:"websites"`
+}
+
+type WorkerStatus struct {
+	Alive         bool      `json:"alive"`
+	BusyExecutors int       `json:"busy_executors"`
+	ExecutorCount int       `json:"executor_count"`
+	HeartbeatTime time.Time `json:"heartbeat_time"`
+	QueueDepth    int       `json:"queue_depth"`
+	StartTime     time.Time `json:"start_time"`
+	WorkerID      string    `json:"worker_id"`
+}
+
+type WorkerStatusResponse struct {
+	PendingUpdateRequests int            `json:"pending_update_requests"`
+	Workers               []WorkerStatus `json:"workers"`
+}
+
+type LoginResponse struct {
+	Token string `json:"token"`
+}
+
+type RegisterResponse struct {
+	UserUUID string `json:"user_uuid"`
+	Username string `json:"username"`
+}
+
+type APIToken struct {
+	UUID       string    `json:"uuid"`
+	Name       string    `json:"name"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+type APITokensResponse struct {
+	Tokens []APIToken `json:"tokens"`
+}
+
+// CreateAPITokenResponse contains the plain token, which cannot be
+// retrieved again
+type CreateAPITokenResponse struct {
+	APIToken APIToken `json:"api_token"`
+	Token    string   `json:"token"`
+}
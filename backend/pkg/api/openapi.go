@@ -0,0 +1,306 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Parameter is a query parameter of operation
+type Parameter struct {
+	Name        string
+	Description string
+	Array       bool
+}
+
+// Operation describes an endpoint, paths are relative to the route prefix
+// of the API. Request is sent as form, Body lists media types of raw body
+// for operations uploading file instead
+type Operation struct {
+	ID       string
+	Method   string
+	Path     string
+	Summary  string
+	Public   bool
+	Query    []Parameter
+	Request  Request
+	Body     []string
+	Status   int
+	Response interface{}
+	Produces []string
+}
+
+var websiteListQuery = []Parameter{
+	{Name: "q", Description: "keyword in title or url"},
+	{Name: "host", Description: "host of website"},
+	{Name: "updated_only", Description: "list websites updated after last access only"},
+	{Name: "health", Description: "health of website"},
+	{Name: "tag", Description: "tag of website", Array: true},
+	{Name: "tag_mode", Description: "and / or, how multiple tags are matched"},
+	{Name: "sort", Description: "order of websites"},
+	{Name: "limit", Description: "page size"},
+	{Name: "cursor", Description: "cursor of next page"},
+}
+
+var watchlistFormats = []string{"application/json", "text/csv", "text/x-opml"}
+
+// Operations lists all endpoints of the API
+var Operations = []Operation{
+	{ID: "login", Method: http.MethodPost, Path: "/auth/login", Summary: "login with local account", Public: true, Request: CredentialsRequest{}, Status: http.StatusOK, Response: LoginResponse{}},
+	{ID: "register", Method: http.MethodPost, Path: "/auth/register", Summary: "register local account", Public: true, Request: CredentialsRequest{}, Status: http.StatusCreated, Response: RegisterResponse{}},
+	{ID: "listAPITokens", Method: http.MethodGet, Path: "/auth/tokens", Summary: "list api tokens", Status: http.StatusOK, Response: APITokensResponse{}},
+	{ID: "createAPIToken", Method: http.MethodPost, Path: "/auth/tokens", Summary: "create api token", Request: CreateAPITokenRequest{}, Status: http.StatusCreated, Response: CreateAPITokenResponse{}},
+	{ID: "deleteAPIToken", Method: http.MethodDelete, Path: "/auth/tokens/{tokenUUID}", Summary: "delete api token", Status: http.StatusOK, Response: MessageResponse{}},
+
+	{ID: "listWebsiteGroups", Method: http.MethodGet, Path: "/websites/groups/", Summary: "list websites in groups", Query: websiteListQuery, Status: http.StatusOK, Response: WebsiteGroupsResponse{}},
+	{ID: "getWebsiteGroup", Method: http.MethodGet, Path: "/websites/groups/{groupName}", Summary: "get websites of group", Status: http.StatusOK, Response: WebsiteGroupResponse{}},
+	{ID: "renameWebsiteGroup", Method: http.MethodPut, Path: "/websites/groups/{groupName}", Summary: "rename group", Request: GroupNameRequest{}, Status: http.StatusOK, Response: WebsiteGroupResponse{}},
+	{ID: "mergeWebsiteGroup", Method: http.MethodPost, Path: "/websites/groups/{groupName}/merge", Summary: "merge group into another group", Request: GroupNameRequest{}, Status: http.StatusOK, Response: WebsiteGroupResponse{}},
+	{ID: "splitWebsiteGroup", Method: http.MethodPost, Path: "/websites/groups/{groupName}/split", Summary: "move websites of group to another group", Request: SplitGroupRequest{}, Status: http.StatusOK, Response: WebsiteGroupResponse{}},
+	{ID: "moveWebsites", Method: http.MethodPut, Path: "/websites/groups/{groupName}/websites", Summary: "move websites to group", Request: MoveWebsitesRequest{}, Status: http.StatusOK, Response: WebsiteGroupResponse{}},
+	{ID: "shareWebsiteGroup", Method: http.MethodPost, Path: "/websites/groups/{groupName}/share", Summary: "share group with other users", Request: ShareGroupRequest{}, Status: http.StatusOK, Response: GroupShareResponse{}},
+	{ID: "unshareWebsiteGroup", Method: http.MethodDelete, Path: "/websites/groups/{groupName}/share", Summary: "stop sharing group", Status: http.StatusOK, Response: MessageResponse{}},
+	{ID: "getGroupPublicLink", Method: http.MethodGet, Path: "/websites/groups/{groupName}/public-link", Summary: "get public link of group", Status: http.StatusOK, Response: GroupPublicLinkResponse{}},
+	{ID: "createGroupPublicLink", Method: http.MethodPost, Path: "/websites/groups/{groupName}/public-link", Summary: "create or rotate public link of group", Status: http.StatusOK, Response: GroupPublicLinkResponse{}},
+	{ID: "deleteGroupPublicLink", Method: http.MethodDelete, Path: "/websites/groups/{groupName}/public-link", Summary: "revoke public link of group", Status: http.StatusOK, Response: MessageResponse{}},
+	{ID: "listGroupShares", Method: http.MethodGet, Path: "/websites/shares/", Summary: "list shared and subscribed groups", Status: http.StatusOK, Response: GroupSharesResponse{}},
+	{ID: "subscribeGroupShare", Method: http.MethodPost, Path: "/websites/shares/{shareUUID}/subscription", Summary: "subscribe shared group", Request: GroupNameRequest{}, Status: http.StatusOK, Response: GroupShareSubscriptionResponse{}},
+	{ID: "unsubscribeGroupShare", Method: http.MethodDelete, Path: "/websites/shares/{shareUUID}/subscription", Summary: "unsubscribe shared group", Status: http.StatusOK, Response: MessageResponse{}},
+	{ID: "addSharedWebsite", Method: http.MethodPost, Path: "/websites/shares/{shareUUID}/websites", Summary: "add website to shared group", Request: CreateWebsiteRequest{}, Status: http.StatusAccepted, Response: CreateWebsiteResponse{}},
+	{ID: "removeSharedWebsite", Method: http.MethodDelete, Path: "/websites/shares/{shareUUID}/websites/{webUUID}", Summary: "remove website from shared group", Status: http.StatusOK, Response: MessageResponse{}},
+	{ID: "getGroupOrder", Method: http.MethodGet, Path: "/websites/group-order", Summary: "get order of groups", Status: http.StatusOK, Response: GroupOrderResponse{}},
+	{ID: "updateGroupOrder", Method: http.MethodPut, Path: "/websites/group-order", Summary: "update order of groups", Request: GroupOrderRequest{}, Status: http.StatusOK, Response: GroupOrderResponse{}},
+	{ID: "listTags", Method: http.MethodGet, Path: "/websites/tags/", Summary: "list tags", Status: http.StatusOK, Response: TagsResponse{}},
+	{ID: "renameTag", Method: http.MethodPut, Path: "/websites/tags/{tag}", Summary: "rename tag", Request: TagRequest{}, Status: http.StatusOK, Response: MessageResponse{}},
+	{ID: "deleteTag", Method: http.MethodDelete, Path: "/websites/tags/{tag}", Summary: "delete tag", Status: http.StatusOK, Response: MessageResponse{}},
+	{ID: "createWebsite", Method: http.MethodPost, Path: "/websites/", Summary: "watch website", Request: CreateWebsiteRequest{}, Status: http.StatusAccepted, Response: CreateWebsiteResponse{}},
+	{ID: "websiteEvents", Method: http.MethodGet, Path: "/websites/events", Summary: "stream website events", Status: http.StatusOK, Produces: []string{"text/event-stream"}},
+	{ID: "importWebsites", Method: http.MethodPost, Path: "/websites/import", Summary: "import watchlist", Query: []Parameter{{Name: "format", Description: "json / csv / opml"}}, Body: watchlistFormats, Status: http.StatusOK, Response: ImportResponse{}},
+	{ID: "importBookmarks", Method: http.MethodPost, Path: "/websites/import/bookmarks", Summary: "import bookmarks exported by browser", Body: []string{"text/html"}, Status: http.StatusAccepted, Response: ImportJobCreatedResponse{}},
+	{ID: "getImportJob", Method: http.MethodGet, Path: "/websites/import/jobs/{jobID}", Summary: "get import job", Status: http.StatusOK, Response: ImportJobResponse{}},
+	{ID: "exportWebsites", Method: http.MethodGet, Path: "/websites/export", Summary: "export watchlist", Query: []Parameter{{Name: "format", Description: "json / csv / opml"}}, Status: http.StatusOK, Produces: watchlistFormats},
+	{ID: "getWebsite", Method: http.MethodGet, Path: "/websites/{webUUID}/", Summary: "get website", Status: http.StatusOK, Response: WebsiteResponse{}},
+	{ID: "getWebsiteDiff", Method: http.MethodGet, Path: "/websites/{webUUID}/diff", Summary: "get content changed by latest update", Status: http.StatusOK, Response: WebsiteDiffResponse{}},
+	{ID: "deleteWebsite", Method: http.MethodDelete, Path: "/websites/{webUUID}/", Summary: "stop watching website", Status: http.StatusOK, Response: MessageResponse{}},
+	{ID: "refreshWebsite", Method: http.MethodPut, Path: "/websites/{webUUID}/refresh", Summary: "mark website as accessed", Status: http.StatusOK, Response: WebsiteResponse{}},
+	{ID: "readWebsite", Method: http.MethodPut, Path: "/websites/{webUUID}/read", Summary: "mark item of website as read", Request: ReadWebsiteRequest{}, Status: http.StatusOK, Response: WebsiteResponse{}},
+	{ID: "snoozeWebsite", Method: http.MethodPut, Path: "/websites/{webUUID}/snooze", Summary: "snooze website", Request: SnoozeWebsiteRequest{}, Status: http.StatusOK, Response: WebsiteResponse{}},
+	{ID: "muteWebsite", Method: http.MethodPut, Path: "/websites/{webUUID}/mute", Summary: "mute or unmute website", Request: MuteWebsiteRequest{}, Status: http.StatusOK, Response: WebsiteResponse{}},
+	{ID: "changeWebsiteGroup", Method: http.MethodPut, Path: "/websites/{webUUID}/change-group", Summary: "move website to group", Request: GroupNameRequest{}, Status: http.StatusOK, Response: WebsiteResponse{}},
+	{ID: "addWebsiteTag", Method: http.MethodPost, Path: "/websites/{webUUID}/tags", Summary: "tag website", Request: TagRequest{}, Status: http.StatusOK, Response: WebsiteResponse{}},
+	{ID: "removeWebsiteTag", Method: http.MethodDelete, Path: "/websites/{webUUID}/tags/{tag}", Summary: "untag website", Status: http.StatusOK, Response: WebsiteResponse{}},
+
+	{ID: "getPublicGroup", Method: http.MethodGet, Path: "/public/groups/{token}", Summary: "get group published by public link", Public: true, Query: []Parameter{{Name: "format", Description: "atom for atom feed"}}, Status: http.StatusOK, Response: PublicGroupResponse{}, Produces: []string{"application/atom+xml"}},
+
+	{ID: "adminListWebsites", Method: http.MethodGet, Path: "/admin/websites", Summary: "list all websites", Status: http.StatusOK, Response: AdminWebsitesResponse{}},
+	{ID: "adminCheckWebsite", Method: http.MethodPost, Path: "/admin/websites/{webUUID}/check", Summary: "queue website for check", Status: http.StatusAccepted, Response: MessageResponse{}},
+	{ID: "adminWorkerStatus", Method: http.MethodGet, Path: "/admin/workers", Summary: "list worker statuses", Status: http.StatusOK, Response: WorkerStatusResponse{}},
+	{ID: "adminBrokenWebsiteSettings", Method: http.MethodGet, Path: "/admin/website-settings/broken", Summary: "list broken website settings", Status: http.StatusOK, Response: WebsiteSettingsResponse{}},
+	{ID: "adminDBStats", Method: http.MethodGet, Path: "/admin/db-stats", Summary: "get database statistics", Status: http.StatusOK, Response: sql.DBStats{}},
+
+	{ID: "getOpenAPI", Method: http.MethodGet, Path: "/openapi.json", Summary: "get this specification", Public: true, Status: http.StatusOK, Produces: []string{"application/json"}},
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	pathParamReg = regexp.MustCompile(`\{(\w+)\}`)
+)
+
+// schemas collects schema of struct types referred by operations
+type schemas map[string]interface{}
+
+func (s schemas) of(t reflect.Type) map[string]interface{} {
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return s.of(t.Elem())
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	// nil slice and map are encoded as null
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": s.of(t.Elem()), "nullable": true}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": s.of(t.Elem()), "nullable": true}
+	case reflect.Struct:
+		if _, ok := s[t.Name()]; !ok {
+			// registered before fields to stop recursion of self referring type
+			s[t.Name()] = nil
+			s[t.Name()] = s.object(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+func (s schemas) object(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = s.of(field.Type)
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+func content(schema map[string]interface{}, mediaTypes ...string) map[string]interface{} {
+	result := make(map[string]interface{})
+	for _, mediaType := range mediaTypes {
+		if schema == nil {
+			result[mediaType] = map[string]interface{}{}
+		} else {
+			result[mediaType] = map[string]interface{}{"schema": schema}
+		}
+	}
+
+	return result
+}
+
+func (s schemas) operation(op Operation) map[string]interface{} {
+	parameters := []interface{}{}
+	for _, match := range pathParamReg.FindAllStringSubmatch(op.Path, -1) {
+		parameters = append(parameters, map[string]interface{}{
+			"name": match[1], "in": "path", "required": true,
+			"schema": map[string]interface{}{"type": "string"},
+		})
+	}
+	for _, param := range op.Query {
+		schema := map[string]interface{}{"type": "string"}
+		if param.Array {
+			schema = map[string]interface{}{"type": "array", "items": schema}
+		}
+		parameters = append(parameters, map[string]interface{}{
+			"name": param.Name, "in": "query", "description": param.Description,
+			"schema": schema,
+		})
+	}
+
+	success := map[string]interface{}{"description": http.StatusText(op.Status)}
+	var responseContent map[string]interface{}
+	if op.Response != nil {
+		responseContent = content(s.of(reflect.TypeOf(op.Response)), "application/json")
+	}
+	if len(op.Produces) > 0 {
+		if responseContent == nil {
+			responseContent = make(map[string]interface{})
+		}
+		for mediaType, value := range content(nil, op.Produces...) {
+			if _, ok := responseContent[mediaType]; !ok {
+				responseContent[mediaType] = value
+			}
+		}
+	}
+	if responseContent != nil {
+		success["content"] = responseContent
+	}
+
+	errorContent := content(s.of(reflect.TypeOf(ErrorResponse{})), "application/json")
+	errorContent[ProblemContentType] = content(s.of(reflect.TypeOf(Problem{})), ProblemContentType)[ProblemContentType]
+
+	result := map[string]interface{}{
+		"operationId": op.ID,
+		"summary":     op.Summary,
+		"parameters":  parameters,
+		"responses": map[string]interface{}{
+			fmt.Sprint(op.Status): success,
+			"default": map[string]interface{}{
+				"description": "error",
+				"content":     errorContent,
+			},
+		},
+	}
+
+	if op.Request != nil {
+		result["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content":  content(s.of(reflect.TypeOf(op.Request)), "application/x-www-form-urlencoded", "application/json"),
+		}
+	} else if len(op.Body) > 0 {
+		result["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content":  content(map[string]interface{}{"type": "string", "format": "binary"}, op.Body...),
+		}
+	}
+
+	if !op.Public {
+		result["security"] = []interface{}{map[string]interface{}{"bearerAuth": []string{}}}
+	}
+
+	return result
+}
+
+// OpenAPI builds the OpenAPI 3 document of operations served under
+// serverURL
+func OpenAPI(serverURL string) map[string]interface{} {
+	s := make(schemas)
+	paths := make(map[string]interface{})
+	for _, op := range Operations {
+		item, ok := paths[op.Path].(map[string]interface{})
+		if !ok {
+			item = make(map[string]interface{})
+			paths[op.Path] = item
+		}
+		item[strings.ToLower(op.Method)] = s.operation(op)
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "Web Watcher API",
+			"version": "1.0.0",
+		},
+		"servers": []interface{}{map[string]interface{}{"url": serverURL}},
+		"paths":   paths,
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}(s),
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{"type": "http", "scheme": "bearer"},
+			},
+		},
+	}
+}
+
+// FindOperation finds operation of method and path
+func FindOperation(method, path string) (Operation, bool) {
+	for _, op := range Operations {
+		if op.Method == method && op.Path == path {
+			return op, true
+		}
+	}
+
+	return Operation{}, false
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// collectRefs collects all $ref in the document
+func collectRefs(value interface{}, refs map[string]bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if ref, ok := item.(string); ok && key == "$ref" {
+				refs[ref] = true
+			}
+			collectRefs(item, refs)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectRefs(item, refs)
+		}
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	// round trip through json to inspect the document as served
+	data, err := json.Marshal(OpenAPI("/api/web-watcher"))
+	require.NoError(t, err)
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &doc))
+
+	assert.Equal(t, "3.0.3", doc["openapi"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"url": "/api/web-watcher"}}, doc["servers"])
+
+	paths := doc["paths"].(map[string]interface{})
+	schemas := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+
+	t.Run("every operation is documented", func(t *testing.T) {
+		ids := make(map[string]bool)
+		for _, op := range Operations {
+			assert.False(t, ids[op.ID], "duplicated operation id %v", op.ID)
+			ids[op.ID] = true
+
+			item, ok := paths[op.Path].(map[string]interface{})
+			require.True(t, ok, "missing path %v", op.Path)
+			operation, ok := item[strings.ToLower(op.Method)].(map[string]interface{})
+			require.True(t, ok, "missing operation %v %v", op.Method, op.Path)
+			assert.Equal(t, op.ID, operation["operationId"])
+			assert.Contains(t, operation["responses"], strconv.Itoa(op.Status), "status of %v", op.ID)
+			_, secured := operation["security"]
+			assert.Equal(t, !op.Public, secured, "security of %v", op.ID)
+		}
+	})
+
+	t.Run("every reference is resolvable", func(t *testing.T) {
+		refs := make(map[string]bool)
+		collectRefs(doc, refs)
+		assert.NotEmpty(t, refs)
+		for ref := range refs {
+			name := strings.TrimPrefix(ref, "#/components/schemas/")
+			assert.Contains(t, schemas, name, "unresolved reference %v", ref)
+		}
+	})
+
+	t.Run("optional field is not required", func(t *testing.T) {
+		userWebsite := schemas["UserWebsite"].(map[string]interface{})
+		assert.Contains(t, userWebsite["required"], "uuid")
+		assert.NotContains(t, userWebsite["required"], "tags")
+		assert.Contains(t, userWebsite["properties"], "tags")
+	})
+
+	t.Run("request body accepts form and json", func(t *testing.T) {
+		operation := paths["/websites/"].(map[string]interface{})["post"].(map[string]interface{})
+		requestContent := operation["requestBody"].(map[string]interface{})["content"].(map[string]interface{})
+		assert.Contains(t, requestContent, "application/x-www-form-urlencoded")
+		assert.Contains(t, requestContent, "application/json")
+	})
+}
+
+func TestFindOperation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		wantID string
+		wantOK bool
+	}{
+		{name: "found", method: http.MethodPost, path: "/websites/", wantID: "createWebsite", wantOK: true},
+		{name: "same path different method", method: http.MethodDelete, path: "/websites/{webUUID}/", wantID: "deleteWebsite", wantOK: true},
+		{name: "not found", method: http.MethodPatch, path: "/websites/"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			op, ok := FindOperation(test.method, test.path)
+			assert.Equal(t, test.wantOK, ok)
+			assert.Equal(t, test.wantID, op.ID)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Request is body of request, which is sent as form
+type Request interface {
+	Form() url.Values
+}
+
+type CreateWebsiteRequest struct {
+	URL string `json:"url"`
+}
+
+func (req CreateWebsiteRequest) Form() url.Values {
+	return url.Values{"url": {req.URL}}
+}
+
+// GroupNameRequest names the group to rename, merge or move website into,
+// or the group to subscribe share into
+type GroupNameRequest struct {
+	GroupName string `json:"group_name"`
+}
+
+func (req GroupNameRequest) Form() url.Values {
+	return url.Values{"group_name": {req.GroupName}}
+}
+
+type SplitGroupRequest struct {
+	GroupName    string   `json:"group_name"`
+	WebsiteUUIDs []string `json:"website_uuid"`
+}
+
+func (req SplitGroupRequest) Form() url.Values {
+	return url.Values{"group_name": {req.GroupName}, "website_uuid": req.WebsiteUUIDs}
+}
+
+type MoveWebsitesRequest struct {
+	WebsiteUUIDs []string `json:"website_uuid"`
+}
+
+func (req MoveWebsitesRequest) Form() url.Values {
+	return url.Values{"website_uuid": req.WebsiteUUIDs}
+}
+
+type GroupOrderRequest struct {
+	Groups []string `json:"group"`
+}
+
+func (req GroupOrderRequest) Form() url.Values {
+	return url.Values{"group": req.Groups}
+}
+
+// ReadWebsiteRequest marks item as read, the latest item is marked if item
+// is empty
+type ReadWebsiteRequest struct {
+	Item string `json:"item,omitempty"`
+}
+
+func (req ReadWebsiteRequest) Form() url.Values {
+	return url.Values{"item": {req.Item}}
+}
+
+// SnoozeWebsiteRequest snoozes website until the time, zero time cancels
+// the snooze
+type SnoozeWebsiteRequest struct {
+	Until time.Time `json:"until"`
+}
+
+func (req SnoozeWebsiteRequest) Form() url.Values {
+	if req.Until.IsZero() {
+		return url.Values{"until": {""}}
+	}
+
+	return url.Values{"until": {req.Until.Format(time.RFC3339)}}
+}
+
+type MuteWebsiteRequest struct {
+	Muted bool `json:"muted"`
+}
+
+func (req MuteWebsiteRequest) Form() url.Values {
+	return url.Values{"muted": {strconv.FormatBool(req.Muted)}}
+}
+
+type TagRequest struct {
+	Tag string `json:"tag"`
+}
+
+func (req TagRequest) Form() url.Values {
+	return url.Values{"tag": {req.Tag}}
+}
+
+// ShareGroupRequest shares group in mode, group is shared read only if mode
+// is empty
+type ShareGroupRequest struct {
+	Mode string `json:"mode,omitempty"`
+}
+
+func (req ShareGroupRequest) Form() url.Values {
+	return url.Values{"mode": {req.Mode}}
+}
+
+type CredentialsRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func (req CredentialsRequest) Form() url.Values {
+	return url.Values{"username": {req.Username}, "password": {req.Password}}
+}
+
+type CreateAPITokenRequest struct {
+	Name string `json:"name"`
+}
+
+func (req CreateAPITokenRequest) Form() url.Values {
+	return url.Values{"name": {req.Name}}
+}
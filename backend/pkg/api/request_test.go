@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequest_Form(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		req  Request
+		want url.Values
+	}{
+		{
+			name: "create website",
+			req:  CreateWebsiteRequest{URL: "https://example.com"},
+			want: url.Values{"url": {"https://example.com"}},
+		},
+		{
+			name: "split group",
+			req:  SplitGroupRequest{GroupName: "new", WebsiteUUIDs: []string{"1", "2"}},
+			want: url.Values{"group_name": {"new"}, "website_uuid": {"1", "2"}},
+		},
+		{
+			name: "snooze website",
+			req:  SnoozeWebsiteRequest{Until: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)},
+			want: url.Values{"until": {"2020-01-02T03:04:05Z"}},
+		},
+		{
+			name: "cancel snooze",
+			req:  SnoozeWebsiteRequest{},
+			want: url.Values{"until": {""}},
+		},
+		{
+			name: "mute website",
+			req:  MuteWebsiteRequest{Muted: true},
+			want: url.Values{"muted": {"true"}},
+		},
+		{
+			name: "credentials",
+			req:  CredentialsRequest{Username: "user", Password: "pass"},
+			want: url.Values{"username": {"user"}, "password": {"pass"}},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, test.want, test.req.Form())
+		})
+	}
+}
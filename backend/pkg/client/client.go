@@ -0,0 +1,446 @@
+// Package client is a Go client of the web watcher API
+package client
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/htchan/WebHistory/pkg/api"
+)
+
+// Error is returned when server responds with non 2xx status, Code is the
+// stable error code responded by server and Field names the invalid param
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Field      string
+	RequestID  string
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("web watcher api: %d %s", err.StatusCode, err.Message)
+}
+
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+type Option func(*Client)
+
+// WithToken authenticates requests by the session or api token
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// New creates client of API served at baseURL, which includes the route
+// prefix (e.g. http://localhost/api/web-watcher)
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	return req, nil
+}
+
+// responseError decodes error of non 2xx response
+func responseError(res *http.Response) error {
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return nil
+	}
+
+	var errRes api.ErrorResponse
+	if err := json.NewDecoder(res.Body).Decode(&errRes); err != nil || errRes.Error == "" {
+		errRes.Error = http.StatusText(res.StatusCode)
+	}
+
+	return &Error{
+		StatusCode: res.StatusCode,
+		Code:       errRes.Code,
+		Message:    errRes.Error,
+		Field:      errRes.Field,
+		RequestID:  errRes.RequestID,
+	}
+}
+
+func (c *Client) send(req *http.Request, result interface{}) error {
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if err := responseError(res); err != nil {
+		return err
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.NewDecoder(res.Body).Decode(result)
+}
+
+// do sends request as form and decodes json response into result
+func (c *Client) do(ctx context.Context, method, path string, request api.Request, result interface{}) error {
+	var body io.Reader
+	if request != nil {
+		body = strings.NewReader(request.Form().Encode())
+	}
+
+	req, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	if request != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	return c.send(req, result)
+}
+
+func (c *Client) Login(ctx context.Context, username, password string) (string, error) {
+	var res api.LoginResponse
+	err := c.do(ctx, http.MethodPost, "/auth/login", api.CredentialsRequest{Username: username, Password: password}, &res)
+	return res.Token, err
+}
+
+func (c *Client) Register(ctx context.Context, username, password string) (api.RegisterResponse, error) {
+	var res api.RegisterResponse
+	err := c.do(ctx, http.MethodPost, "/auth/register", api.CredentialsRequest{Username: username, Password: password}, &res)
+	return res, err
+}
+
+func (c *Client) ListAPITokens(ctx context.Context) ([]api.APIToken, error) {
+	var res api.APITokensResponse
+	err := c.do(ctx, http.MethodGet, "/auth/tokens", nil, &res)
+	return res.Tokens, err
+}
+
+func (c *Client) CreateAPIToken(ctx context.Context, name string) (api.CreateAPITokenResponse, error) {
+	var res api.CreateAPITokenResponse
+	err := c.do(ctx, http.MethodPost, "/auth/tokens", api.CreateAPITokenRequest{Name: name}, &res)
+	return res, err
+}
+
+func (c *Client) DeleteAPIToken(ctx context.Context, tokenUUID string) error {
+	return c.do(ctx, http.MethodDelete, "/auth/tokens/"+url.PathEscape(tokenUUID), nil, nil)
+}
+
+// ListWebsiteGroups lists websites in groups, query accepts the filter,
+// sort and paging parameters of the endpoint
+func (c *Client) ListWebsiteGroups(ctx context.Context, query url.Values) (api.WebsiteGroupsResponse, error) {
+	path := "/websites/groups/"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var res api.WebsiteGroupsResponse
+	err := c.do(ctx, http.MethodGet, path, nil, &res)
+	return res, err
+}
+
+func groupPath(groupName string) string {
+	return "/websites/groups/" + url.PathEscape(groupName)
+}
+
+func (c *Client) GetWebsiteGroup(ctx context.Context, groupName string) (api.WebsiteGroupResponse, error) {
+	var res api.WebsiteGroupResponse
+	err := c.do(ctx, http.MethodGet, groupPath(groupName), nil, &res)
+	return res, err
+}
+
+func (c *Client) RenameWebsiteGroup(ctx context.Context, groupName, newName string) (api.WebsiteGroupResponse, error) {
+	var res api.WebsiteGroupResponse
+	err := c.do(ctx, http.MethodPut, groupPath(groupName), api.GroupNameRequest{GroupName: newName}, &res)
+	return res, err
+}
+
+func (c *Client) MergeWebsiteGroup(ctx context.Context, groupName, target string) (api.WebsiteGroupResponse, error) {
+	var res api.WebsiteGroupResponse
+	err := c.do(ctx, http.MethodPost, groupPath(groupName)+"/merge", api.GroupNameRequest{GroupName: target}, &res)
+	return res, err
+}
+
+func (c *Client) SplitWebsiteGroup(ctx context.Context, groupName string, request api.SplitGroupRequest) (api.WebsiteGroupResponse, error) {
+	var res api.WebsiteGroupResponse
+	err := c.do(ctx, http.MethodPost, groupPath(groupName)+"/split", request, &res)
+	return res, err
+}
+
+func (c *Client) MoveWebsites(ctx context.Context, groupName string, webUUIDs []string) (api.WebsiteGroupResponse, error) {
+	var res api.WebsiteGroupResponse
+	err := c.do(ctx, http.MethodPut, groupPath(groupName)+"/websites", api.MoveWebsitesRequest{WebsiteUUIDs: webUUIDs}, &res)
+	return res, err
+}
+
+func (c *Client) ShareWebsiteGroup(ctx context.Context, groupName, mode string) (api.GroupShare, error) {
+	var res api.GroupShareResponse
+	err := c.do(ctx, http.MethodPost, groupPath(groupName)+"/share", api.ShareGroupRequest{Mode: mode}, &res)
+	return res.Share, err
+}
+
+func (c *Client) UnshareWebsiteGroup(ctx context.Context, groupName string) error {
+	return c.do(ctx, http.MethodDelete, groupPath(groupName)+"/share", nil, nil)
+}
+
+func (c *Client) GetGroupPublicLink(ctx context.Context, groupName string) (api.GroupPublicLink, error) {
+	var res api.GroupPublicLinkResponse
+	err := c.do(ctx, http.MethodGet, groupPath(groupName)+"/public-link", nil, &res)
+	return res.PublicLink, err
+}
+
+// CreateGroupPublicLink creates public link of group, existing link is
+// replaced by new token
+func (c *Client) CreateGroupPublicLink(ctx context.Context, groupName string) (api.GroupPublicLink, error) {
+	var res api.GroupPublicLinkResponse
+	err := c.do(ctx, http.MethodPost, groupPath(groupName)+"/public-link", nil, &res)
+	return res.PublicLink, err
+}
+
+func (c *Client) DeleteGroupPublicLink(ctx context.Context, groupName string) error {
+	return c.do(ctx, http.MethodDelete, groupPath(groupName)+"/public-link", nil, nil)
+}
+
+func (c *Client) ListGroupShares(ctx context.Context) (api.GroupSharesResponse, error) {
+	var res api.GroupSharesResponse
+	err := c.do(ctx, http.MethodGet, "/websites/shares/", nil, &res)
+	return res, err
+}
+
+func sharePath(shareUUID string) string {
+	return "/websites/shares/" + url.PathEscape(shareUUID)
+}
+
+func (c *Client) SubscribeGroupShare(ctx context.Context, shareUUID, groupName string) (api.GroupShareSubscription, error) {
+	var res api.GroupShareSubscriptionResponse
+	err := c.do(ctx, http.MethodPost, sharePath(shareUUID)+"/subscription", api.GroupNameRequest{GroupName: groupName}, &res)
+	return res.Subscription, err
+}
+
+func (c *Client) UnsubscribeGroupShare(ctx context.Context, shareUUID string) error {
+	return c.do(ctx, http.MethodDelete, sharePath(shareUUID)+"/subscription", nil, nil)
+}
+
+func (c *Client) AddSharedWebsite(ctx context.Context, shareUUID, websiteURL string) (api.CreateWebsiteResponse, error) {
+	var res api.CreateWebsiteResponse
+	err := c.do(ctx, http.MethodPost, sharePath(shareUUID)+"/websites", api.CreateWebsiteRequest{URL: websiteURL}, &res)
+	return res, err
+}
+
+func (c *Client) RemoveSharedWebsite(ctx context.Context, shareUUID, webUUID string) error {
+	return c.do(ctx, http.MethodDelete, sharePath(shareUUID)+"/websites/"+url.PathEscape(webUUID), nil, nil)
+}
+
+func (c *Client) GetGroupOrder(ctx context.Context) ([]string, error) {
+	var res api.GroupOrderResponse
+	err := c.do(ctx, http.MethodGet, "/websites/group-order", nil, &res)
+	return res.GroupOrder, err
+}
+
+func (c *Client) UpdateGroupOrder(ctx context.Context, groups []string) ([]string, error) {
+	var res api.GroupOrderResponse
+	err := c.do(ctx, http.MethodPut, "/websites/group-order", api.GroupOrderRequest{Groups: groups}, &res)
+	return res.GroupOrder, err
+}
+
+func (c *Client) ListTags(ctx context.Context) ([]string, error) {
+	var res api.TagsResponse
+	err := c.do(ctx, http.MethodGet, "/websites/tags/", nil, &res)
+	return res.Tags, err
+}
+
+func (c *Client) RenameTag(ctx context.Context, tag, newTag string) error {
+	return c.do(ctx, http.MethodPut, "/websites/tags/"+url.PathEscape(tag), api.TagRequest{Tag: newTag}, nil)
+}
+
+func (c *Client) DeleteTag(ctx context.Context, tag string) error {
+	return c.do(ctx, http.MethodDelete, "/websites/tags/"+url.PathEscape(tag), nil, nil)
+}
+
+func (c *Client) CreateWebsite(ctx context.Context, websiteURL string) (api.CreateWebsiteResponse, error) {
+	var res api.CreateWebsiteResponse
+	err := c.do(ctx, http.MethodPost, "/websites/", api.CreateWebsiteRequest{URL: websiteURL}, &res)
+	return res, err
+}
+
+// ImportWebsites imports watchlist in format (json / csv / opml)
+func (c *Client) ImportWebsites(ctx context.Context, format string, body io.Reader) (api.ImportResponse, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/websites/import?"+url.Values{"format": {format}}.Encode(), body)
+	if err != nil {
+		return api.ImportResponse{}, err
+	}
+
+	var res api.ImportResponse
+	err = c.send(req, &res)
+	return res, err
+}
+
+// ImportBookmarks imports bookmarks html exported by browser in background,
+// progress of the returned job is reported by GetImportJob
+func (c *Client) ImportBookmarks(ctx context.Context, body io.Reader) (string, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, "/websites/import/bookmarks", body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "text/html")
+
+	var res api.ImportJobCreatedResponse
+	err = c.send(req, &res)
+	return res.JobID, err
+}
+
+func (c *Client) GetImportJob(ctx context.Context, jobID string) (api.ImportJob, error) {
+	var res api.ImportJobResponse
+	err := c.do(ctx, http.MethodGet, "/websites/import/jobs/"+url.PathEscape(jobID), nil, &res)
+	return res.Job, err
+}
+
+// ExportWebsites exports watchlist in format (json / csv / opml), caller
+// should close the returned reader
+func (c *Client) ExportWebsites(ctx context.Context, format string) (io.ReadCloser, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/websites/export?"+url.Values{"format": {format}}.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := responseError(res); err != nil {
+		res.Body.Close()
+		return nil, err
+	}
+
+	return res.Body, nil
+}
+
+func websitePath(webUUID string) string {
+	return "/websites/" + url.PathEscape(webUUID)
+}
+
+func (c *Client) GetWebsite(ctx context.Context, webUUID string) (api.UserWebsite, error) {
+	var res api.WebsiteResponse
+	err := c.do(ctx, http.MethodGet, websitePath(webUUID)+"/", nil, &res)
+	return res.Website, err
+}
+
+func (c *Client) GetWebsiteDiff(ctx context.Context, webUUID string) (api.WebsiteDiffResponse, error) {
+	var res api.WebsiteDiffResponse
+	err := c.do(ctx, http.MethodGet, websitePath(webUUID)+"/diff", nil, &res)
+	return res, err
+}
+
+func (c *Client) DeleteWebsite(ctx context.Context, webUUID string) error {
+	return c.do(ctx, http.MethodDelete, websitePath(webUUID)+"/", nil, nil)
+}
+
+func (c *Client) updateWebsite(ctx context.Context, method, path string, request api.Request) (api.UserWebsite, error) {
+	var res api.WebsiteResponse
+	err := c.do(ctx, method, path, request, &res)
+	return res.Website, err
+}
+
+func (c *Client) RefreshWebsite(ctx context.Context, webUUID string) (api.UserWebsite, error) {
+	return c.updateWebsite(ctx, http.MethodPut, websitePath(webUUID)+"/refresh", nil)
+}
+
+func (c *Client) ReadWebsite(ctx context.Context, webUUID, item string) (api.UserWebsite, error) {
+	return c.updateWebsite(ctx, http.MethodPut, websitePath(webUUID)+"/read", api.ReadWebsiteRequest{Item: item})
+}
+
+func (c *Client) SnoozeWebsite(ctx context.Context, webUUID string, until time.Time) (api.UserWebsite, error) {
+	return c.updateWebsite(ctx, http.MethodPut, websitePath(webUUID)+"/snooze", api.SnoozeWebsiteRequest{Until: until})
+}
+
+func (c *Client) MuteWebsite(ctx context.Context, webUUID string, muted bool) (api.UserWebsite, error) {
+	return c.updateWebsite(ctx, http.MethodPut, websitePath(webUUID)+"/mute", api.MuteWebsiteRequest{Muted: muted})
+}
+
+func (c *Client) ChangeWebsiteGroup(ctx context.Context, webUUID, groupName string) (api.UserWebsite, error) {
+	return c.updateWebsite(ctx, http.MethodPut, websitePath(webUUID)+"/change-group", api.GroupNameRequest{GroupName: groupName})
+}
+
+func (c *Client) AddWebsiteTag(ctx context.Context, webUUID, tag string) (api.UserWebsite, error) {
+	return c.updateWebsite(ctx, http.MethodPost, websitePath(webUUID)+"/tags", api.TagRequest{Tag: tag})
+}
+
+func (c *Client) RemoveWebsiteTag(ctx context.Context, webUUID, tag string) (api.UserWebsite, error) {
+	return c.updateWebsite(ctx, http.MethodDelete, websitePath(webUUID)+"/tags/"+url.PathEscape(tag), nil)
+}
+
+// GetPublicGroup gets group published by public link, no token is needed
+func (c *Client) GetPublicGroup(ctx context.Context, token string) (api.PublicGroupResponse, error) {
+	var res api.PublicGroupResponse
+	err := c.do(ctx, http.MethodGet, "/public/groups/"+url.PathEscape(token), nil, &res)
+	return res, err
+}
+
+func (c *Client) AdminListWebsites(ctx context.Context) ([]api.AdminWebsite, error) {
+	var res api.AdminWebsitesResponse
+	err := c.do(ctx, http.MethodGet, "/admin/websites", nil, &res)
+	return res.Websites, err
+}
+
+func (c *Client) AdminCheckWebsite(ctx context.Context, webUUID string) error {
+	return c.do(ctx, http.MethodPost, "/admin/websites/"+url.PathEscape(webUUID)+"/check", nil, nil)
+}
+
+func (c *Client) AdminWorkerStatus(ctx context.Context) (api.WorkerStatusResponse, error) {
+	var res api.WorkerStatusResponse
+	err := c.do(ctx, http.MethodGet, "/admin/workers", nil, &res)
+	return res, err
+}
+
+func (c *Client) AdminBrokenWebsiteSettings(ctx context.Context) ([]api.WebsiteSetting, error) {
+	var res api.WebsiteSettingsResponse
+	err := c.do(ctx, http.MethodGet, "/admin/website-settings/broken", nil, &res)
+	return res.WebsiteSettings, err
+}
+
+func (c *Client) AdminDBStats(ctx context.Context) (sql.DBStats, error) {
+	var res sql.DBStats
+	err := c.do(ctx, http.MethodGet, "/admin/db-stats", nil, &res)
+	return res, err
+}
+
+// OpenAPI gets the OpenAPI specification served by the API
+func (c *Client) OpenAPI(ctx context.Context) (map[string]interface{}, error) {
+	var res map[string]interface{}
+	err := c.do(ctx, http.MethodGet, "/openapi.json", nil, &res)
+	return res, err
+}
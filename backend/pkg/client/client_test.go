@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/htchan/WebHistory/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	httpClient := &http.Client{}
+	c := New("http://localhost/api/web-watcher/", WithToken("token"), WithHTTPClient(httpClient))
+
+	assert.Equal(t, "http://localhost/api/web-watcher", c.baseURL)
+	assert.Equal(t, "token", c.token)
+	assert.Same(t, httpClient, c.httpClient)
+}
+
+func TestClient_do(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		token       string
+		request     api.Request
+		handler     http.HandlerFunc
+		wantAuth    string
+		wantForm    url.Values
+		wantMessage string
+		wantErr     error
+	}{
+		{
+			name:    "send form with token",
+			token:   "token",
+			request: api.GroupNameRequest{GroupName: "comics"},
+			handler: func(res http.ResponseWriter, req *http.Request) {
+				json.NewEncoder(res).Encode(api.MessageResponse{Message: "ok"})
+			},
+			wantAuth:    "Bearer token",
+			wantForm:    url.Values{"group_name": {"comics"}},
+			wantMessage: "ok",
+		},
+		{
+			name: "error response",
+			handler: func(res http.ResponseWriter, req *http.Request) {
+				res.WriteHeader(http.StatusNotFound)
+				json.NewEncoder(res).Encode(api.ErrorResponse{Code: "not_found", Error: "record not found", RequestID: "req"})
+			},
+			wantForm: url.Values{},
+			wantErr:  &Error{StatusCode: http.StatusNotFound, Code: "not_found", Message: "record not found", RequestID: "req"},
+		},
+		{
+			name: "validation error response",
+			handler: func(res http.ResponseWriter, req *http.Request) {
+				res.WriteHeader(http.StatusUnprocessableEntity)
+				json.NewEncoder(res).Encode(api.ErrorResponse{Code: "invalid_url", Error: "url: invalid url", Field: "url"})
+			},
+			wantForm: url.Values{},
+			wantErr:  &Error{StatusCode: http.StatusUnprocessableEntity, Code: "invalid_url", Message: "url: invalid url", Field: "url"},
+		},
+		{
+			name: "error response without body",
+			handler: func(res http.ResponseWriter, req *http.Request) {
+				res.WriteHeader(http.StatusBadGateway)
+			},
+			wantForm: url.Values{},
+			wantErr:  &Error{StatusCode: http.StatusBadGateway, Message: "Bad Gateway"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var auth string
+			var form url.Values
+			server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				auth = req.Header.Get("Authorization")
+				req.ParseForm()
+				form = req.PostForm
+				test.handler(res, req)
+			}))
+			defer server.Close()
+
+			var result api.MessageResponse
+			err := New(server.URL, WithToken(test.token)).do(context.Background(), http.MethodPost, "/path", test.request, &result)
+
+			assert.Equal(t, test.wantErr, err)
+			assert.Equal(t, test.wantAuth, auth)
+			assert.Equal(t, test.wantForm, form)
+			assert.Equal(t, test.wantMessage, result.Message)
+		})
+	}
+}
+
+func TestClient_ImportWebsites(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/websites/import", req.URL.Path)
+		assert.Equal(t, "csv", req.URL.Query().Get("format"))
+		json.NewEncoder(res).Encode(api.ImportResponse{Summary: map[string]int{"created": 1}})
+	}))
+	defer server.Close()
+
+	res, err := New(server.URL).ImportWebsites(context.Background(), "csv", strings.NewReader("url\nhttps://example.com\n"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"created": 1}, res.Summary)
+}
+
+func TestError_Error(t *testing.T) {
+	t.Parallel()
+
+	var err error = &Error{StatusCode: 404, Message: "record not found"}
+	var apiErr *Error
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "web watcher api: 404 record not found", err.Error())
+}